@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"slices"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+)
+
+// advanceDependents opens the tickets in the advance_from status whose
+// last open dep s just closed, so chains of waiting tickets move along on
+// their own. It returns the IDs it opened; failures are warnings.
+func advanceDependents(s *store.Store) []string {
+	waiting := ticket.Status(projectConfig().AdvanceFrom)
+	if waiting == "" || s == nil {
+		return nil
+	}
+	var closed []string
+	for _, c := range s.Changes() {
+		if c.After != nil && c.After.Status == ticket.StatusClosed &&
+			(c.Before == nil || c.Before.Status != ticket.StatusClosed) {
+			closed = append(closed, c.After.ID)
+		}
+	}
+	if len(closed) == 0 {
+		return nil
+	}
+	tickets, err := s.List()
+	if err != nil {
+		Warnf("advance dependents: %v", err)
+		return nil
+	}
+	var advanced []string
+	for _, id := range newlyUnblocked(tickets, closed) {
+		i := slices.IndexFunc(tickets, func(t *ticket.Ticket) bool { return t.ID == id })
+		if tickets[i].Status != waiting {
+			continue
+		}
+		err := s.Update(id, func(t *ticket.Ticket) error {
+			if t.Status == waiting {
+				t.SetStatus(ticket.StatusOpen)
+			}
+			return nil
+		})
+		if err != nil {
+			Warnf("advance %s: %v", id, err)
+			continue
+		}
+		advanced = append(advanced, id)
+	}
+	return advanced
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// registerAliases adds a command for each alias in config.yml. Running it
+// runs kt with the alias's arguments followed by the ones given. An alias
+// may not shadow a command and must expand to one (not another alias).
+func registerAliases(aliases map[string]string) {
+	var cmds []*cobra.Command
+	for _, name := range slices.Sorted(maps.Keys(aliases)) {
+		args, err := splitArgs(aliases[name])
+		switch {
+		case err != nil:
+			Warnf("%s: alias %s: %v", config.ProjectFile, name, err)
+			continue
+		case len(args) == 0:
+			Warnf("%s: alias %s is empty", config.ProjectFile, name)
+			continue
+		case findCommand(name) != nil:
+			Warnf("%s: alias %s shadows a kt command; ignored", config.ProjectFile, name)
+			continue
+		case findCommand(args[0]) == nil:
+			Warnf("%s: alias %s does not start with a kt command; ignored", config.ProjectFile, name)
+			continue
+		}
+		cmds = append(cmds, aliasCommand(name, aliases[name], args))
+	}
+	rootCmd.AddCommand(cmds...)
+}
+
+// aliasCommand returns the command for an alias. Flags are passed through
+// unparsed, and the root hooks are left to the command the alias runs.
+func aliasCommand(name, value string, expansion []string) *cobra.Command {
+	return &cobra.Command{
+		Use:                name,
+		Short:              "Alias for kt " + strings.TrimSpace(value),
+		DisableFlagParsing: true,
+		SilenceErrors:      true, // reported by the command it runs
+		SilenceUsage:       true,
+		PersistentPreRun:   func(*cobra.Command, []string) {},
+		PersistentPostRun:  func(*cobra.Command, []string) {},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootCmd.SetArgs(append(slices.Clone(expansion), args...))
+			return rootCmd.Execute()
+		},
+	}
+}
+
+// findCommand returns the root subcommand called name (or with it as an
+// alias), or nil.
+func findCommand(name string) *cobra.Command {
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// splitArgs splits s into arguments at spaces, like a shell would for
+// simple cases: single or double quotes group words and are removed.
+func splitArgs(s string) ([]string, error) {
+	var (
+		args  []string
+		arg   strings.Builder
+		quote rune
+		inArg bool
+	)
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
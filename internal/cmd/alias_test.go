@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitArgs(t *testing.T) {
+	args, err := splitArgs(`ls --filter 'type=bug and priority=0'  --status "in progress" x""`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ls", "--filter", "type=bug and priority=0", "--status", "in progress", "x"}, args)
+
+	_, err = splitArgs(`ls 'x`)
+	assert.ErrorContains(t, err, "unterminated")
+}
+
+func TestAliases(t *testing.T) {
+	defer setupTestEnv(t)()
+	t.Setenv(config.EnvDir, Store.Dir) // for the store opened by rootCmd
+	mkTicket(t, "kt-b", "Bug", ticket.StatusOpen)
+
+	registerAliases(map[string]string{
+		"openlist": "ls --status open",
+		"show":     "ls",       // shadows a command
+		"loop":     "openlist", // expands to an alias
+	})
+	defer func() {
+		for _, c := range rootCmd.Commands() {
+			if c.Name() == "openlist" || c.Name() == "loop" {
+				rootCmd.RemoveCommand(c)
+			}
+		}
+		listStatus = ""
+	}()
+
+	alias := findCommand("openlist")
+	require.NotNil(t, alias)
+	assert.Equal(t, "Alias for kt ls --status open", alias.Short)
+	assert.Nil(t, findCommand("loop"))
+
+	rootCmd.SetArgs([]string{"openlist", "--parent", ""})
+	require.NoError(t, rootCmd.Execute())
+	assert.Equal(t, "open", listStatus, "ran ls with the alias's flags")
+}
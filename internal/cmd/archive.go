@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Move closed tickets to and from a tarball",
+	Long: `Bundle closed ticket files into a compressed tarball, and bring them back.
+Tickets kt gc moved to .ktickets/archive/ are bundled too, and go back
+there.
+
+  kt archive export 2025.tar.gz --closed-before 2026-01-01 --remove
+  kt archive import 2025.tar.gz`,
+}
+
+var archiveExportCmd = &cobra.Command{
+	Use:   "export <file.tar.gz>",
+	Short: "Write closed tickets to a .tar.gz",
+	Long: `Write the files of closed tickets, unchanged, to a gzipped tarball ("-"
+writes stdout). --closed-before limits this to tickets closed (or, lacking
+a close time, created) before a date, e.g. 2026-01-01, or longer ago than an
+age, e.g. 180d.
+
+Tickets in .ktickets/archive/ (see kt gc) are exported alongside, under
+archive/ in the tarball.
+
+With --remove, the exported tickets are then deleted from the store and
+its archive. That is refused if an open ticket has one as parent, depends
+on it, or links to it; references from other closed tickets are removed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runArchiveExport,
+}
+
+var archiveImportCmd = &cobra.Command{
+	Use:   "import <file.tar.gz>",
+	Short: "Bring back tickets from a kt archive export tarball",
+	Long: `Recreate the tickets in a tarball written by kt archive export ("-" reads
+stdin). Tickets exported from .ktickets/archive/ go back there. Tickets
+identical to an existing one, live or archived, are left alone. When a
+ticket's ID is already taken by a different ticket, --on-conflict decides:
+
+  skip       keep the existing ticket (default)
+  rename     give the imported ticket a new ID and update references to it
+             among the imported tickets
+  overwrite  replace the existing ticket`,
+	Args: cobra.ExactArgs(1),
+	RunE: runArchiveImport,
+}
+
+var (
+	archiveClosedBefore string
+	archiveRemove       bool
+	archiveOnConflict   string
+)
+
+func init() {
+	archiveExportCmd.Flags().StringVar(&archiveClosedBefore, "closed-before", "", "Only tickets closed before this date (2026-01-01) or longer ago than this age (180d)")
+	archiveExportCmd.Flags().BoolVar(&archiveRemove, "remove", false, "Delete the exported tickets from the store")
+	archiveImportCmd.Flags().StringVar(&archiveOnConflict, "on-conflict", "skip", "ID collision handling: skip|rename|overwrite")
+	archiveCmd.AddCommand(archiveExportCmd, archiveImportCmd)
+	rootCmd.AddCommand(archiveCmd)
+}
+
+type archiveExportResult struct {
+	File     string   `json:"file"`
+	Exported []string `json:"exported"`
+	Removed  bool     `json:"removed"`
+}
+
+func runArchiveExport(cmd *cobra.Command, args []string) error {
+	var cutoff time.Time
+	if archiveClosedBefore != "" {
+		var err error
+		if cutoff, err = parseCutoff(archiveClosedBefore, time.Now()); err != nil {
+			return fmt.Errorf("invalid --closed-before %q (expected a date like 2026-01-01 or an age like 180d)", archiveClosedBefore)
+		}
+	}
+
+	tickets, err := Store.List()
+	if err != nil {
+		return fmt.Errorf("list tickets: %w", err)
+	}
+	archived, err := Store.ListArchived()
+	if err != nil {
+		return fmt.Errorf("list archived tickets: %w", err)
+	}
+	closedBefore := func(list []*ticket.Ticket) []string {
+		var ids []string
+		for _, t := range list {
+			if t.Status == ticket.StatusClosed && (cutoff.IsZero() || closedAt(t).Before(cutoff)) {
+				ids = append(ids, t.ID)
+			}
+		}
+		return ids
+	}
+	ids, archivedIDs := closedBefore(tickets), closedBefore(archived)
+	all := slices.Concat(ids, archivedIDs)
+	if archiveRemove {
+		// Fail before writing anything rather than leave a half-done export
+		if err := store.CheckRefs("remove", tickets, all); err != nil {
+			return err
+		}
+	}
+
+	if err := writeArchive(Store, args[0], ids, archivedIDs); err != nil {
+		return err
+	}
+	if archiveRemove && len(ids) > 0 {
+		if err := Store.Delete(ids...); err != nil {
+			return fmt.Errorf("archive written, but removing the tickets failed: %w", err)
+		}
+	}
+	if archiveRemove {
+		for _, id := range archivedIDs {
+			if err := Store.RemoveArchived(id); err != nil {
+				return fmt.Errorf("archive written, but removing archived %s failed: %w", id, err)
+			}
+		}
+	}
+
+	result := archiveExportResult{File: args[0], Exported: all, Removed: archiveRemove && len(all) > 0}
+	if result.Exported == nil {
+		result.Exported = []string{}
+	}
+	if args[0] == "-" {
+		return nil // stdout holds the archive
+	}
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	verb := "Exported"
+	if result.Removed {
+		verb = "Moved"
+	}
+	fmt.Printf("%s %d tickets to %s\n", verb, len(all), args[0])
+	return nil
+}
+
+// parseCutoff parses a date (2006-01-02 or RFC 3339) or an age before now
+// (see parseSince).
+func parseCutoff(s string, now time.Time) (time.Time, error) {
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	age, err := parseSince(s)
+	if err != nil || age == 0 {
+		return time.Time{}, fmt.Errorf("invalid date or age %q", s)
+	}
+	return now.Add(-age), nil
+}
+
+// archivedPrefix is the directory in a tarball of the tickets exported
+// from the store's ArchiveDir.
+const archivedPrefix = "archive/"
+
+// writeArchive writes the files of the tickets with the given IDs, and of
+// the archived ones under archivedPrefix, to a gzipped tarball at path ("-"
+// for stdout). A failed export leaves no file.
+func writeArchive(s *store.Store, path string, ids, archived []string) (err error) {
+	out := os.Stdout
+	if path != "-" {
+		if out, err = os.Create(path); err != nil {
+			return fmt.Errorf("create archive: %w", err)
+		}
+		defer func() {
+			if cerr := out.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				_ = os.Remove(path)
+			}
+		}()
+	}
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	type entry struct{ name, file string }
+	var entries []entry
+	for _, id := range ids {
+		entries = append(entries, entry{id + ".md", s.Path(id)})
+	}
+	for _, id := range archived {
+		entries = append(entries, entry{archivedPrefix + id + ".md", s.ArchivePath(id)})
+	}
+	for _, e := range entries {
+		data, err := os.ReadFile(e.file)
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(e.file)
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{Name: e.name, Mode: 0644, Size: int64(len(data)), ModTime: info.ModTime()}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("write archive: %w", err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("write archive: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	return gz.Close()
+}
+
+func runArchiveImport(cmd *cobra.Command, args []string) error {
+	switch archiveOnConflict {
+	case "rename", "skip", "overwrite":
+	default:
+		return fmt.Errorf("invalid --on-conflict %q (use skip, rename, or overwrite)", archiveOnConflict)
+	}
+
+	tickets, fromArchive, err := readArchive(args[0])
+	if err != nil {
+		return err
+	}
+	stored, err := Store.ListArchived()
+	if err != nil {
+		return fmt.Errorf("list archived tickets: %w", err)
+	}
+	archived := make(map[string]*ticket.Ticket, len(stored))
+	for _, t := range stored {
+		archived[t.ID] = t
+	}
+
+	var result loadResult
+	err = Store.Transaction(func(tx *store.Tx) error {
+		r, err := loadTickets(tx, tickets, archived, archiveOnConflict)
+		result = *r
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Loading put every ticket in the store; move the archived ones back,
+	// and drop archived copies that live tickets overwrote
+	for _, t := range tickets {
+		if !slices.Contains(result.Loaded, t.ID) {
+			continue
+		}
+		if fromArchive[t] {
+			err = Store.Archive(t.ID)
+		} else if archived[t.ID] != nil {
+			err = Store.RemoveArchived(t.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", t.ID, err)
+		}
+	}
+	return printLoadResult(result)
+}
+
+// readArchive parses the ticket files in a tarball written by
+// writeArchive ("-" reads stdin), and tells which were archived.
+func readArchive(file string) ([]*ticket.Ticket, map[*ticket.Ticket]bool, error) {
+	in := os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open archive: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read archive: %w", err)
+	}
+
+	var tickets []*ticket.Ticket
+	archived := make(map[*ticket.Ticket]bool)
+	seen := make(map[string]bool)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !strings.HasSuffix(hdr.Name, ".md") {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read archive: %w", err)
+		}
+		t, err := ticket.Parse(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read archive: %s: %w", hdr.Name, err)
+		}
+		if t.ID == "" {
+			t.ID = strings.TrimSuffix(path.Base(hdr.Name), ".md")
+		}
+		if !validID.MatchString(t.ID) {
+			return nil, nil, fmt.Errorf("read archive: %s: invalid ticket ID %q", hdr.Name, t.ID)
+		}
+		if seen[t.ID] {
+			return nil, nil, fmt.Errorf("read archive: duplicate ticket ID %s", t.ID)
+		}
+		seen[t.ID] = true
+		archived[t] = strings.HasPrefix(hdr.Name, archivedPrefix)
+		tickets = append(tickets, t)
+	}
+	return tickets, archived, nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveExportImport(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { archiveClosedBefore, archiveRemove, archiveOnConflict = "", false, "skip" }()
+	old := mkTicket(t, "kt-old", "Closed long ago", ticket.StatusClosed)
+	old.Closed = "2025-03-01T00:00:00Z"
+	old.Description = "Kept verbatim."
+	require.NoError(t, Store.Save(old))
+	recent := mkTicket(t, "kt-recent", "Closed lately", ticket.StatusClosed)
+	recent.Closed = "2026-09-01T00:00:00Z"
+	recent.Deps = []string{"kt-old"}
+	require.NoError(t, Store.Save(recent))
+	mkTicket(t, "kt-open", "Open", ticket.StatusOpen)
+	want, err := os.ReadFile(Store.Path("kt-old"))
+	require.NoError(t, err)
+
+	out := filepath.Join(t.TempDir(), "old.tar.gz")
+	archiveClosedBefore, archiveRemove = "2026-01-01", true
+	require.NoError(t, runArchiveExport(nil, []string{out}))
+	_, err = Store.Get("kt-old")
+	assert.Error(t, err, "removed from the store")
+	_, err = Store.Get("kt-recent")
+	require.NoError(t, err)
+
+	tickets, fromArchive, err := readArchive(out)
+	require.NoError(t, err)
+	require.Len(t, tickets, 1)
+	assert.Equal(t, "kt-old", tickets[0].ID)
+	assert.False(t, fromArchive[tickets[0]])
+
+	require.NoError(t, runArchiveImport(nil, []string{out}))
+	got, err := os.ReadFile(Store.Path("kt-old"))
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+
+	changed, err := Store.Get("kt-old")
+	require.NoError(t, err)
+	changed.Title = "Reused ID"
+	require.NoError(t, Store.Save(changed))
+	require.NoError(t, runArchiveImport(nil, []string{out}))
+	changed, err = Store.Get("kt-old")
+	require.NoError(t, err)
+	assert.Equal(t, "Reused ID", changed.Title, "skip keeps the existing ticket")
+}
+
+func TestArchiveExportImportArchived(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { archiveRemove, archiveOnConflict = false, "skip" }()
+	for _, id := range []string{"kt-gc", "kt-live"} {
+		tk := mkTicket(t, id, "Closed "+id, ticket.StatusClosed)
+		tk.Closed = "2025-03-01T00:00:00Z"
+		require.NoError(t, Store.Save(tk))
+	}
+	require.NoError(t, Store.Archive("kt-gc"))
+	want, err := os.ReadFile(Store.ArchivePath("kt-gc"))
+	require.NoError(t, err)
+
+	out := filepath.Join(t.TempDir(), "all.tar.gz")
+	archiveRemove = true
+	require.NoError(t, runArchiveExport(nil, []string{out}))
+	assert.NoFileExists(t, Store.Path("kt-live"))
+	assert.NoFileExists(t, Store.ArchivePath("kt-gc"), "removed from the archive too")
+
+	tickets, fromArchive, err := readArchive(out)
+	require.NoError(t, err)
+	require.Len(t, tickets, 2)
+	assert.Equal(t, []string{"kt-live", "kt-gc"}, ticketIDs(tickets))
+	assert.True(t, fromArchive[tickets[1]])
+
+	require.NoError(t, runArchiveImport(nil, []string{out}))
+	assert.FileExists(t, Store.Path("kt-live"))
+	assert.NoFileExists(t, Store.Path("kt-gc"), "back in the archive, not the store")
+	got, err := os.ReadFile(Store.ArchivePath("kt-gc"))
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+
+	// An archived ticket with the same ID counts as a conflict
+	require.NoError(t, Store.Delete("kt-live"))
+	changed, err := ticket.ParseFile(Store.ArchivePath("kt-gc"))
+	require.NoError(t, err)
+	changed.Title = "Changed in the archive"
+	require.NoError(t, ticket.WriteFile(Store.ArchivePath("kt-gc"), changed))
+	archiveOnConflict = "rename"
+	require.NoError(t, runArchiveImport(nil, []string{out}))
+	archived, err := Store.ListArchived()
+	require.NoError(t, err)
+	require.Len(t, archived, 2, "the changed one and the renamed import")
+	live, err := Store.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-live"}, ticketIDs(live))
+}
+
+func TestArchiveExportRefused(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { archiveClosedBefore, archiveRemove = "", false }()
+	mkTicket(t, "kt-dep", "Closed", ticket.StatusClosed)
+	task := mkTicket(t, "kt-task", "Open", ticket.StatusOpen)
+	task.Deps = []string{"kt-dep"}
+	require.NoError(t, Store.Save(task))
+
+	out := filepath.Join(t.TempDir(), "out.tar.gz")
+	archiveRemove = true
+	assert.ErrorContains(t, runArchiveExport(nil, []string{out}), "cannot remove kt-dep")
+	assert.NoFileExists(t, out)
+
+	archiveClosedBefore = "soon"
+	assert.ErrorContains(t, runArchiveExport(nil, []string{out}), "invalid --closed-before")
+}
+
+func TestParseCutoff(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for in, want := range map[string]time.Time{
+		"2026-01-01":           time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		"2026-01-01T12:00:00Z": time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		"30d":                  time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		got, err := parseCutoff(in, now)
+		require.NoError(t, err, in)
+		assert.True(t, want.Equal(got), in)
+	}
+	_, err := parseCutoff("0", now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/store"
+)
+
+var autoCommitFlag bool
+
+// autoCommitEnabled reports whether ticket changes should be committed:
+// --commit, or else KTICKET_AUTO_COMMIT, or else integrations.auto_commit.
+func autoCommitEnabled() bool {
+	return autoCommitFlag || projectConfig().AutoCommit()
+}
+
+// autoCommit commits the ticket files s changed with a message like
+// "kt: close kt-a1b2". Failures are warnings: the ticket change itself
+// already succeeded.
+func autoCommit(s *store.Store) {
+	if !autoCommitEnabled() || s == nil {
+		return
+	}
+	changed := s.Changed()
+	if len(changed) == 0 {
+		return
+	}
+	dir, err := filepath.Abs(s.Dir)
+	if err != nil {
+		Warnf("auto-commit: %v", err)
+		return
+	}
+	if _, err := git.RunIn(dir, "rev-parse", "--git-dir"); err != nil {
+		return // tickets are not in a git repository
+	}
+	for i, p := range changed {
+		if abs, err := filepath.Abs(p); err == nil {
+			changed[i] = abs
+		}
+	}
+	if _, err := git.CommitPaths(dir, "kt: "+s.Operation(), changed); err != nil {
+		Warnf("auto-commit: %v", err)
+	}
+}
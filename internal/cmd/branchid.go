@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	for _, c := range []*cobra.Command{
+		showCmd, editCmd, startCmd, closeCmd, reopenCmd, statusCmd, passCmd,
+		setCmd, depAddCmd, depTreeCmd, depDependentsCmd, commitLinkCmd,
+		criticalPathCmd, splitCmd, waitCmd, prBodyCmd, worktreeCmd, worktreeRmCmd,
+		suggestAssigneeCmd,
+	} {
+		implicitID(c, false)
+	}
+	for _, c := range []*cobra.Command{addNoteCmd, addDesignCmd, addAcceptanceCmd, addTestCmd} {
+		implicitID(c, true)
+	}
+}
+
+// branchIDEnabled reports whether a missing ticket ID defaults to the
+// current branch's ticket (KTICKET_BRANCH_ID or integrations.branch_id).
+func branchIDEnabled() bool {
+	return projectConfig().BranchID()
+}
+
+// implicitID lets c be run without its leading <id> argument, using the
+// ticket named in the current git branch instead. textArg marks commands
+// whose optional trailing argument is free text (add-note "msg"): a lone
+// argument that is not a ticket ID is taken as the text.
+func implicitID(c *cobra.Command, textArg bool) {
+	validate, run := c.Args, c.RunE
+	if validate == nil {
+		validate = cobra.ArbitraryArgs
+	}
+
+	// Args are validated before PersistentPreRun opens the store, so only
+	// check the arity here and look up the branch ticket in RunE.
+	c.Args = func(cmd *cobra.Command, args []string) error {
+		err := validate(cmd, args)
+		if err != nil && branchIDEnabled() && validate(cmd, append([]string{""}, args...)) == nil {
+			return nil
+		}
+		return err
+	}
+	c.RunE = func(cmd *cobra.Command, args []string) error {
+		args, err := withBranchID(cmd, validate, args, textArg)
+		if err != nil {
+			return err
+		}
+		return run(cmd, args)
+	}
+}
+
+// withBranchID prepends the branch's ticket ID to args when they are
+// missing an ID. Returns args unchanged otherwise.
+func withBranchID(cmd *cobra.Command, validate cobra.PositionalArgs, args []string, textArg bool) ([]string, error) {
+	if !branchIDEnabled() {
+		return args, nil
+	}
+	invalid := validate(cmd, args)
+	missing := invalid != nil
+	if !missing && textArg && len(args) == 1 {
+		_, err := Store.Resolve(args[0])
+		missing = err != nil
+	}
+	if !missing {
+		return args, nil
+	}
+
+	tickets, err := Store.List()
+	if err != nil {
+		return nil, err
+	}
+	if id := branchTicketID(tickets); id != "" {
+		return append([]string{id}, args...), nil
+	}
+	if invalid != nil {
+		return nil, errors.New("no ticket ID given and none found in the current branch name")
+	}
+	return args, nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImplicitBranchID(t *testing.T) {
+	defer setupTestEnv(t)()
+	initGitRepo(t)
+	gitCommit(t, "initial")
+	tk := mkTicket(t, "kt-a1b2", "Login", ticket.StatusOpen)
+	_, err := git.Run("checkout", "-q", "-b", "kt-a1b2-login")
+	require.NoError(t, err)
+
+	// Off by default
+	require.Error(t, startCmd.Args(startCmd, nil))
+
+	t.Setenv(config.EnvBranchID, "true")
+	require.NoError(t, startCmd.Args(startCmd, nil))
+	require.NoError(t, startCmd.RunE(startCmd, nil))
+	u, _ := Store.Get(tk.ID)
+	assert.Equal(t, ticket.StatusInProgress, u.Status)
+
+	// A lone argument that is not a ticket is the note text
+	require.NoError(t, addNoteCmd.RunE(addNoteCmd, []string{"from branch"}))
+	u, _ = Store.Get(tk.ID)
+	assert.Contains(t, u.Notes, "from branch")
+
+	// Explicit IDs still win
+	other := mkTicket(t, "kt-c3d4", "Other", ticket.StatusOpen)
+	require.NoError(t, startCmd.RunE(startCmd, []string{other.ID}))
+	u, _ = Store.Get(other.ID)
+	assert.Equal(t, ticket.StatusInProgress, u.Status)
+
+	_, err = git.Run("checkout", "-q", "-b", "no-ticket")
+	require.NoError(t, err)
+	assert.ErrorContains(t, startCmd.RunE(startCmd, nil), "none found in the current branch")
+}
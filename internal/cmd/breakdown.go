@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var breakdownCmd = &cobra.Command{
+	Use:   "breakdown <plan.md>",
+	Short: "Create an epic and its child tickets from a markdown plan",
+	Long: `Create an epic and its children from one markdown plan ("-" reads stdin),
+all at once or not at all. The "# Title" is the epic, with the text under it
+as its description; the plan may start with frontmatter as in kt create
+--from-file. Each "## Title" is a child ticket, whose "### Design",
+"### Acceptance Criteria", and "### Tests" become its sections. A
+"Depends on:" line in a child lists the children (by title) or existing
+tickets (by ID) it waits for. Children inherit the epic's priority and
+assignee.
+
+  # Password reset
+  Let users reset a forgotten password.
+
+  ## Reset token API
+  ### Acceptance Criteria
+  - [ ] POST /reset issues a single-use token
+
+  ## Reset email
+  Depends on: Reset token API`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBreakdown,
+}
+
+func init() {
+	rootCmd.AddCommand(breakdownCmd)
+}
+
+type breakdownResult struct {
+	Epic     *ticket.Ticket   `json:"epic"`
+	Children []*ticket.Ticket `json:"children"`
+}
+
+// planChild is a child ticket parsed from a plan, with the references of
+// its "Depends on:" lines.
+type planChild struct {
+	ticket *ticket.Ticket
+	deps   []string
+}
+
+// dependsOnLine matches a child's "Depends on: a, b" line.
+var dependsOnLine = regexp.MustCompile(`(?i)^\s*depends on:\s*(.*)$`)
+
+// parsePlan splits a plan into the epic, with the frontmatter keys it
+// sets, and its children.
+func parsePlan(text string) (*ticket.Ticket, []string, []planChild, error) {
+	var epic []string
+	var children [][]string
+	var deps [][]string
+	fenced := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+		}
+		switch {
+		case fenced:
+		case strings.HasPrefix(trimmed, "## "):
+			// Shift the child's headings up a level so it parses as a ticket
+			children = append(children, []string{"#" + strings.TrimPrefix(trimmed, "##")})
+			deps = append(deps, nil)
+			continue
+		case len(children) == 0:
+		case strings.HasPrefix(trimmed, "### "):
+			line = strings.TrimPrefix(trimmed, "#")
+		default:
+			if m := dependsOnLine.FindStringSubmatch(line); m != nil {
+				for _, ref := range strings.Split(m[1], ",") {
+					if ref = strings.TrimSpace(ref); ref != "" {
+						deps[len(deps)-1] = append(deps[len(deps)-1], ref)
+					}
+				}
+				continue
+			}
+		}
+		if len(children) == 0 {
+			epic = append(epic, line)
+		} else {
+			children[len(children)-1] = append(children[len(children)-1], line)
+		}
+	}
+
+	e, keys, err := ticket.ParseDraft([]byte(strings.Join(epic, "\n")))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if e.Title == "" {
+		return nil, nil, nil, fmt.Errorf(`the plan needs a "# Title" line for the epic`)
+	}
+	if len(children) == 0 {
+		return nil, nil, nil, fmt.Errorf(`the plan has no child tickets (add a "## Title" heading for each)`)
+	}
+	parsed := make([]planChild, len(children))
+	for i, lines := range children {
+		c, _, err := ticket.ParseDraft([]byte(strings.Join(lines, "\n")))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if c.Title == "" {
+			return nil, nil, nil, fmt.Errorf("child %d has an empty title", i+1)
+		}
+		parsed[i] = planChild{ticket: c, deps: deps[i]}
+	}
+	return e, keys, parsed, nil
+}
+
+func runBreakdown(cmd *cobra.Command, args []string) error {
+	text, err := readTextInput(cmd, nil, args[0])
+	if err != nil {
+		return err
+	}
+	epic, keys, children, err := parsePlan(text)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	epic.Type = ticket.TypeEpic
+	epic.Assignee = cmp.Or(epic.Assignee, defaultAssignee())
+	if !slices.Contains(keys, "priority") {
+		epic.Priority = defaultPriority()
+	}
+	all := []*ticket.Ticket{epic}
+	for _, c := range children {
+		c.ticket.Type = ticket.TypeTask
+		c.ticket.Priority = epic.Priority
+		c.ticket.Assignee = epic.Assignee
+		all = append(all, c.ticket)
+	}
+	for _, t := range all {
+		if err := checkRequired(t); err != nil {
+			return fmt.Errorf("%s: %w", t.Title, err)
+		}
+	}
+	if epic.Parent, err = resolveID(epic.Parent); err != nil {
+		return fmt.Errorf("parent: %w", err)
+	}
+	if err := checkParentRule(Store, epic); err != nil {
+		return err
+	}
+
+	err = Store.Transaction(func(tx *store.Tx) error {
+		created := time.Now().UTC().Format(time.RFC3339)
+		for _, t := range all {
+			id, err := generateUniqueID(tx)
+			if err != nil {
+				return fmt.Errorf("generate ID: %w", err)
+			}
+			t.ID, t.Status, t.Created = id, ticket.StatusOpen, created
+			tx.Save(t) // now, so the next ID differs
+		}
+
+		if err := resolveRefs(tx, epic); err != nil {
+			return err
+		}
+		if err := linkBack(tx, epic); err != nil {
+			return err
+		}
+		byTitle := make(map[string]string, len(children))
+		byID := make(map[string]*ticket.Ticket, len(children))
+		for _, c := range children {
+			byTitle[strings.ToLower(c.ticket.Title)] = c.ticket.ID
+			byID[c.ticket.ID] = c.ticket
+		}
+		for _, c := range children {
+			c.ticket.Parent = epic.ID
+			for _, ref := range c.deps {
+				id, ok := byTitle[strings.ToLower(ref)]
+				if !ok {
+					dep, err := tx.Resolve(ref)
+					if err != nil {
+						return fmt.Errorf("%s: depends on: %w", c.ticket.Title, err)
+					}
+					id = dep.ID
+				}
+				if id == c.ticket.ID || dependsOn(byID, id, c.ticket.ID) {
+					return fmt.Errorf("%s: depending on %s would create a dependency cycle", c.ticket.Title, ref)
+				}
+				if !slices.Contains(c.ticket.Deps, id) {
+					c.ticket.Deps = append(c.ticket.Deps, id)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	result := breakdownResult{Epic: epic}
+	for _, c := range children {
+		result.Children = append(result.Children, c.ticket)
+	}
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	fmt.Printf("%s %s\n", epic.ID, epic.Title)
+	for _, c := range result.Children {
+		fmt.Printf("  %s %s\n", c.ID, c.Title)
+	}
+	return nil
+}
+
+// resolveRefs replaces the partial IDs a plan's frontmatter gives for the
+// epic's parent, deps, and links with full ones.
+func resolveRefs(tx *store.Tx, t *ticket.Ticket) error {
+	resolve := func(field string, ref *string) error {
+		r, err := tx.Resolve(*ref)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		*ref = r.ID
+		return nil
+	}
+	if t.Parent != "" {
+		if err := resolve("parent", &t.Parent); err != nil {
+			return err
+		}
+	}
+	for i := range t.Deps {
+		if err := resolve("deps", &t.Deps[i]); err != nil {
+			return err
+		}
+	}
+	for i := range t.Links {
+		if err := resolve("links", &t.Links[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPlan = `---
+priority: 1
+---
+# Password reset
+
+Let users reset a forgotten password.
+
+## Reset token API
+
+Issue single-use tokens.
+
+### Acceptance Criteria
+
+- [ ] POST /reset issues a token
+
+## Reset email
+Depends on: reset token API, kt-001
+
+` + "```md\n## Not a child\n```" + `
+
+### Tests
+
+- TestResetEmail
+`
+
+func TestParsePlan(t *testing.T) {
+	epic, keys, children, err := parsePlan(testPlan)
+	require.NoError(t, err)
+	assert.Equal(t, "Password reset", epic.Title)
+	assert.Equal(t, "Let users reset a forgotten password.", epic.Description)
+	assert.Equal(t, []string{"priority"}, keys)
+	require.Len(t, children, 2)
+	assert.Equal(t, "Reset token API", children[0].ticket.Title)
+	assert.Equal(t, "Issue single-use tokens.", children[0].ticket.Description)
+	assert.Equal(t, "- [ ] POST /reset issues a token", children[0].ticket.AcceptanceCriteria)
+	assert.Empty(t, children[0].deps)
+	assert.Equal(t, "Reset email", children[1].ticket.Title)
+	assert.Equal(t, "```md\n## Not a child\n```", children[1].ticket.Description)
+	assert.Equal(t, "- TestResetEmail", children[1].ticket.Tests)
+	assert.Equal(t, []string{"reset token API", "kt-001"}, children[1].deps)
+
+	_, _, _, err = parsePlan("Just text\n")
+	assert.ErrorContains(t, err, `"# Title"`)
+	_, _, _, err = parsePlan("# Epic only\n")
+	assert.ErrorContains(t, err, "no child tickets")
+}
+
+func TestRunBreakdown(t *testing.T) {
+	defer setupTestEnv(t)()
+	mkTicket(t, "kt-001", "Existing", ticket.StatusOpen)
+	mockStdin(t, testPlan)
+	require.NoError(t, runBreakdown(breakdownCmd, []string{"-"}))
+
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	require.Len(t, tickets, 4)
+	byTitle := map[string]*ticket.Ticket{}
+	for _, tk := range tickets {
+		byTitle[tk.Title] = tk
+	}
+	epic, api, email := byTitle["Password reset"], byTitle["Reset token API"], byTitle["Reset email"]
+	require.NotNil(t, epic)
+	assert.Equal(t, ticket.TypeEpic, epic.Type)
+	assert.Equal(t, 1, epic.Priority)
+	assert.Equal(t, epic.ID, api.Parent)
+	assert.Equal(t, epic.ID, email.Parent)
+	assert.Equal(t, 1, email.Priority)
+	assert.Equal(t, []string{api.ID, "kt-001"}, email.Deps)
+
+	mockStdin(t, "---\nlinks: [kt-001]\n---\n# Linked epic\n## Child\n")
+	require.NoError(t, runBreakdown(breakdownCmd, []string{"-"}))
+	existing, err := Store.Get("kt-001")
+	require.NoError(t, err)
+	assert.Len(t, existing.Links, 1, "the epic's links are symmetric")
+
+	// Nothing is created when a reference is bad
+	mockStdin(t, "# Epic\n## A\nDepends on: B\n## B\nDepends on: A\n")
+	assert.ErrorContains(t, runBreakdown(breakdownCmd, []string{"-"}), "dependency cycle")
+	mockStdin(t, "# Epic\n## A\nDepends on: kt-nope\n")
+	assert.Error(t, runBreakdown(breakdownCmd, []string{"-"}))
+	tickets, err = Store.List()
+	require.NoError(t, err)
+	assert.Len(t, tickets, 6)
+}
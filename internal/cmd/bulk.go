@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var bulkCmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "Apply a change to every ticket matching a filter",
+	Long: `Apply a change to every ticket matching a filter.
+
+Filter clauses are joined with "and" and use field=value or field!=value;
+comma-separated values match any alternative. Examples:
+
+  kt bulk --filter 'status=open and priority=4' --set priority=3
+  kt bulk --filter 'labels=backend' --assignee alice
+  kt bulk --filter 'parent=kt-a1b2 and status!=closed' --close
+
+With --dry-run, lists the ticket files that would change instead.`,
+	Args: cobra.NoArgs,
+	RunE: runBulk,
+}
+
+var (
+	bulkFilter   string
+	bulkSet      []string
+	bulkClose    bool
+	bulkAssignee string
+)
+
+func init() {
+	bulkCmd.Flags().StringVar(&bulkFilter, "filter", "", "Filter expression (required)")
+	bulkCmd.Flags().StringArrayVar(&bulkSet, "set", nil, "field=value to apply (repeatable)")
+	bulkCmd.Flags().BoolVar(&bulkClose, "close", false, "Close matching tickets (validates tests)")
+	bulkCmd.Flags().StringVar(&bulkAssignee, "assignee", "", "Set assignee on matching tickets")
+	addDryRunFlag(bulkCmd)
+	rootCmd.AddCommand(bulkCmd)
+}
+
+func runBulk(cmd *cobra.Command, args []string) error {
+	if bulkFilter == "" {
+		return fmt.Errorf("--filter is required")
+	}
+	filter, err := ticket.ParseFilter(bulkFilter)
+	if err != nil {
+		return err
+	}
+
+	assignments, err := parseAssignments(bulkSet)
+	if err != nil {
+		return err
+	}
+	if bulkAssignee != "" {
+		assignments = append(assignments, fieldAssignment{Field: "assignee", Value: bulkAssignee})
+	}
+	if len(assignments) == 0 && !bulkClose {
+		return fmt.Errorf("nothing to do (use --set, --assignee, or --close)")
+	}
+	if err := resolveAssignments(assignments); err != nil {
+		return err
+	}
+
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+
+	startDryRun(Store)
+	result := statusResult{}
+	for _, t := range tickets {
+		if !filter.Match(t) {
+			continue
+		}
+
+		lt, err := Store.GetForUpdate(t.ID)
+		if err != nil {
+			result.Errors = append(result.Errors, statusError{ID: t.ID, Error: err.Error()})
+			continue
+		}
+
+		// Re-check under lock: the ticket may have changed since List()
+		if !filter.Match(lt.Ticket) {
+			lt.Release()
+			continue
+		}
+
+		if err := applyAssignments(lt.Ticket, assignments); err != nil {
+			lt.Release()
+			result.Errors = append(result.Errors, statusError{ID: t.ID, Error: err.Error()})
+			continue
+		}
+
+		if bulkClose {
+			if err := lt.Ticket.CanClose(); err != nil {
+				lt.Release()
+				result.Errors = append(result.Errors, statusError{ID: t.ID, Error: err.Error()})
+				continue
+			}
+			lt.Ticket.SetStatus(ticket.StatusClosed)
+		}
+
+		if err := lt.SaveAndRelease(); err != nil {
+			result.Errors = append(result.Errors, statusError{ID: t.ID, Error: err.Error()})
+			continue
+		}
+
+		result.Updated = append(result.Updated, t.ID)
+	}
+
+	if dryRun {
+		if !IsJSON() {
+			for _, e := range result.Errors {
+				Errorf("%s: %s", e.ID, e.Error)
+			}
+		}
+		return printDryRun(Store, result)
+	}
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+
+	for _, id := range result.Updated {
+		fmt.Printf("%s updated\n", id)
+	}
+	for _, e := range result.Errors {
+		Errorf("%s: %s", e.ID, e.Error)
+	}
+	if len(result.Updated) == 0 && len(result.Errors) == 0 {
+		fmt.Println("No matching tickets")
+	}
+
+	return nil
+}
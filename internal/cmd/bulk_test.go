@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetBulkFlags() {
+	bulkFilter = ""
+	bulkSet = nil
+	bulkClose = false
+	bulkAssignee = ""
+}
+
+func TestRunBulkSet(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer resetBulkFlags()
+
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	c := mkTicket(t, "kt-c", "C", ticket.StatusClosed)
+	b.Priority = 4
+	require.NoError(t, Store.Save(b))
+
+	bulkFilter = "status=open and priority=4"
+	bulkSet = []string{"priority=3"}
+	bulkAssignee = "alice"
+	require.NoError(t, runBulk(nil, nil))
+
+	ua, _ := Store.Get(a.ID)
+	ub, _ := Store.Get(b.ID)
+	uc, _ := Store.Get(c.ID)
+	assert.Equal(t, 2, ua.Priority)
+	assert.Empty(t, ua.Assignee)
+	assert.Equal(t, 3, ub.Priority)
+	assert.Equal(t, "alice", ub.Assignee)
+	assert.Empty(t, uc.Assignee)
+}
+
+func TestRunBulkClose(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer resetBulkFlags()
+	jsonFlag = true
+	defer func() { jsonFlag = false }()
+
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-b", "B", ticket.StatusInProgress)
+	b.Tests = "- TestB"
+	require.NoError(t, Store.Save(b))
+
+	bulkFilter = "status=open,in_progress"
+	bulkClose = true
+	require.NoError(t, runBulk(nil, nil))
+
+	ua, _ := Store.Get(a.ID)
+	ub, _ := Store.Get(b.ID)
+	assert.Equal(t, ticket.StatusClosed, ua.Status)
+	assert.Equal(t, ticket.StatusInProgress, ub.Status, "tests not passed blocks close")
+}
+
+func TestRunBulkValidation(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer resetBulkFlags()
+
+	err := runBulk(nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--filter is required")
+
+	bulkFilter = "status=open"
+	err = runBulk(nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nothing to do")
+
+	bulkFilter = "wat"
+	bulkClose = true
+	err = runBulk(nil, nil)
+	require.Error(t, err)
+}
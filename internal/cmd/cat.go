@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var catCmd = &cobra.Command{
+	Use:   "cat <id>...",
+	Short: "Print ticket files exactly as stored",
+	Long: `Print each ticket's file, frontmatter and body, byte for byte, e.g. to
+pipe it into other markdown tools or check what kt show is reading. A file
+that no longer parses is still printed when given by its full ID.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCat,
+}
+
+func init() {
+	rootCmd.AddCommand(catCmd)
+}
+
+type catFile struct {
+	ID      string `json:"id"`
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+func runCat(cmd *cobra.Command, args []string) error {
+	files := make([]catFile, 0, len(args))
+	for _, arg := range args {
+		id := arg
+		if !validID.MatchString(arg) || !isFile(Store.Path(arg)) {
+			t, err := Store.Resolve(arg)
+			if err != nil {
+				return err
+			}
+			id = t.ID
+		}
+		data, err := os.ReadFile(Store.Path(id))
+		if err != nil {
+			return err
+		}
+		files = append(files, catFile{ID: id, Path: Store.Path(id), Content: string(data)})
+	}
+
+	if IsJSON() {
+		if len(files) == 1 {
+			return PrintJSON(files[0])
+		}
+		return PrintJSON(files)
+	}
+	for _, f := range files {
+		fmt.Print(f.Content)
+	}
+	return nil
+}
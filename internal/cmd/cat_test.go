@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCat(t *testing.T) {
+	defer setupTestEnv(t)()
+	tk := mkTicket(t, "kt-a1b2", "Cat me", ticket.StatusOpen)
+	tk.Description = "Body **as written**"
+	require.NoError(t, Store.Save(tk))
+	want, err := os.ReadFile(Store.Path("kt-a1b2"))
+	require.NoError(t, err)
+	broken := "---\nid: [kt-bad\n---\n# Broken\n"
+	require.NoError(t, os.WriteFile(Store.Path("kt-bad"), []byte(broken), 0644))
+
+	out := filepath.Join(t.TempDir(), "cat.out")
+	f, err := os.Create(out)
+	require.NoError(t, err)
+	old := os.Stdout
+	os.Stdout = f
+	err = runCat(nil, []string{"a1b2", "kt-bad"})
+	os.Stdout = old
+	require.NoError(t, f.Close())
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, string(want)+broken, string(got))
+
+	assert.Error(t, runCat(nil, []string{"../kt-a1b2"}))
+}
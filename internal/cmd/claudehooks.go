@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+// claudeHook is a Claude Code hook kt installs: command runs on event for
+// tools matching matcher.
+type claudeHook struct {
+	event   string
+	matcher string
+	command string
+}
+
+// claudeHooks load the tracker into context when a session starts, and
+// warn before the agent edits files with no ticket in progress.
+var claudeHooks = []claudeHook{
+	{"SessionStart", "", "kt prime"},
+	{"PreToolUse", "Edit|MultiEdit|Write|NotebookEdit", "kt hook pre-tool-use"},
+}
+
+var hookPreToolUseCmd = &cobra.Command{
+	Use:   "pre-tool-use",
+	Short: "Warn when an agent edits files with no ticket in progress",
+	Args:  cobra.NoArgs,
+	RunE:  runHookPreToolUse,
+}
+
+func init() {
+	hookCmd.AddCommand(hookPreToolUseCmd)
+}
+
+// claudeSettingsPath returns Claude's global settings.json, or the
+// project's settings.local.json.
+func claudeSettingsPath(global bool) string {
+	if global {
+		return filepath.Join(getClaudeConfigDir(), "settings.json")
+	}
+	return filepath.Join(".claude", "settings.local.json")
+}
+
+// registerClaudeHooks adds claudeHooks to Claude settings.
+func registerClaudeHooks(global bool) error {
+	scope := "project"
+	if global {
+		scope = "global"
+	}
+	for _, h := range claudeHooks {
+		added, err := addClaudeHook(claudeSettingsPath(global), h)
+		if err != nil {
+			return err
+		}
+		if added {
+			fmt.Printf("Registered %s hook: %s (%s)\n", h.event, h.command, scope)
+		} else {
+			fmt.Printf("%s hook already registered (%s)\n", h.event, scope)
+		}
+	}
+	return nil
+}
+
+// addClaudeHook adds h to the settings file unless a hook for the same
+// event already runs its command. It reports whether the file changed.
+func addClaudeHook(path string, h claudeHook) (bool, error) {
+	settings, err := readSettings(path)
+	if err != nil {
+		return false, err
+	}
+	for _, group := range settings.Search("hooks", h.event).Children() {
+		for _, hook := range group.Search("hooks").Children() {
+			if hook.Search("command").Data() == h.command {
+				return false, nil
+			}
+		}
+	}
+
+	group := map[string]any{
+		"hooks": []any{map[string]any{"type": "command", "command": h.command}},
+	}
+	if h.matcher != "" {
+		group["matcher"] = h.matcher
+	}
+	if settings.Search("hooks", h.event) == nil {
+		_, err = settings.Set([]any{group}, "hooks", h.event)
+	} else {
+		err = settings.ArrayAppend(group, "hooks", h.event)
+	}
+	if err != nil {
+		return false, fmt.Errorf("add %s hook: %w", h.event, err)
+	}
+	return true, writeSettings(path, settings)
+}
+
+// removeClaudeHook removes hooks running h's command from the settings
+// file, dropping groups, events, and the hooks object left empty. It
+// reports whether the file changed.
+func removeClaudeHook(path string, h claudeHook) (bool, error) {
+	settings, err := readSettings(path)
+	if err != nil {
+		return false, err
+	}
+	groups, ok := settings.Search("hooks", h.event).Data().([]any)
+	if !ok {
+		return false, nil
+	}
+
+	changed := false
+	groups = slices.DeleteFunc(slices.Clone(groups), func(g any) bool {
+		group := gabs.Wrap(g)
+		hooks, ok := group.Search("hooks").Data().([]any)
+		if !ok {
+			return false
+		}
+		kept := slices.DeleteFunc(slices.Clone(hooks), func(hook any) bool {
+			return gabs.Wrap(hook).Search("command").Data() == h.command
+		})
+		if len(kept) == len(hooks) {
+			return false
+		}
+		changed = true
+		if len(kept) == 0 {
+			return true
+		}
+		_, _ = group.Set(kept, "hooks")
+		return false
+	})
+	if !changed {
+		return false, nil
+	}
+
+	if len(groups) > 0 {
+		_, err = settings.Set(groups, "hooks", h.event)
+	} else {
+		err = settings.Delete("hooks", h.event)
+		if err == nil && len(settings.Search("hooks").ChildrenMap()) == 0 {
+			err = settings.Delete("hooks")
+		}
+	}
+	if err != nil {
+		return false, fmt.Errorf("remove %s hook: %w", h.event, err)
+	}
+	return true, writeSettings(path, settings)
+}
+
+func runHookPreToolUse(cmd *cobra.Command, args []string) error {
+	if msg := noTicketWarning(); msg != "" {
+		// A systemMessage warns without blocking the edit
+		return PrintJSON(map[string]string{"systemMessage": msg})
+	}
+	return nil
+}
+
+// noTicketWarning returns a warning if no ticket is in progress (for this
+// session, if it has one), or "" if there is one or the store is empty.
+func noTicketWarning() string {
+	tickets, err := Store.List()
+	if err != nil || len(tickets) == 0 {
+		return ""
+	}
+	for _, t := range tickets {
+		if t.Status == ticket.StatusInProgress && (sessionFlag == "" || t.ClaimedBy == "" || t.ClaimedBy == sessionFlag) {
+			return ""
+		}
+	}
+	return "kt: no ticket is in progress. Pick one with `kt ready` and `kt start` it before editing files."
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaudeHooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	mine := `{"matcher":"Bash","hooks":[{"type":"command","command":"./lint.sh"}]}`
+	require.NoError(t, os.WriteFile(path, []byte(`{"hooks":{"PreToolUse":[`+mine+`]}}`), 0644))
+
+	for _, h := range claudeHooks {
+		added, err := addClaudeHook(path, h)
+		require.NoError(t, err)
+		assert.True(t, added, h.event)
+		added, err = addClaudeHook(path, h)
+		require.NoError(t, err)
+		assert.False(t, added, "%s already registered", h.event)
+	}
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"hooks":{
+		"SessionStart":[{"hooks":[{"type":"command","command":"kt prime"}]}],
+		"PreToolUse":[`+mine+`,{"matcher":"Edit|MultiEdit|Write|NotebookEdit","hooks":[{"type":"command","command":"kt hook pre-tool-use"}]}]
+	}}`, string(data))
+
+	for _, h := range claudeHooks {
+		removed, err := removeClaudeHook(path, h)
+		require.NoError(t, err)
+		assert.True(t, removed, h.event)
+	}
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"hooks":{"PreToolUse":[`+mine+`]}}`, string(data), "other hooks kept")
+}
+
+func TestNoTicketWarning(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { sessionFlag = "" }()
+	assert.Empty(t, noTicketWarning(), "no tickets, no warning")
+
+	mkTicket(t, "kt-1", "Open", ticket.StatusOpen)
+	assert.Contains(t, noTicketWarning(), "no ticket is in progress")
+
+	tk := mkTicket(t, "kt-2", "Doing", ticket.StatusInProgress)
+	assert.Empty(t, noTicketWarning())
+
+	tk.ClaimedBy = "agent-1"
+	require.NoError(t, Store.Save(tk))
+	sessionFlag = "agent-2"
+	assert.NotEmpty(t, noTicketWarning(), "claimed by another session")
+	sessionFlag = "agent-1"
+	assert.Empty(t, noTicketWarning())
+}
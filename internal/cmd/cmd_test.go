@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"slices"
@@ -22,8 +23,16 @@ func setupTestEnv(t *testing.T) func() {
 	dir := t.TempDir()
 	Store = store.New(dir)
 	_ = Store.EnsureDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir()) // no user config
+	t.Setenv("CODEX_HOME", t.TempDir())
 	jsonFlag = false
-	return func() { Store = nil }
+	Config = nil
+	types, statuses := slices.Clone(ticket.Types), slices.Clone(ticket.Statuses)
+	return func() {
+		Store, Config = nil, nil
+		ticket.Types, ticket.Statuses = types, statuses
+		ticket.Strict = false
+	}
 }
 
 func mkTicket(t *testing.T, id, title string, status ticket.Status) *ticket.Ticket {
@@ -348,14 +357,22 @@ func TestRunShowJSON(t *testing.T) {
 	defer func() { jsonFlag = false }()
 
 	tk := mkTicket(t, "kt-001", "Show JSON", ticket.StatusOpen)
-
-	// Single ticket
-	err := runShow(nil, []string{tk.ID})
-	require.NoError(t, err)
+	user := mkTicket(t, "kt-user", "User", ticket.StatusOpen)
+	user.Deps = []string{tk.ID}
+	require.NoError(t, Store.Save(user))
+
+	// Single ticket, with the tickets that depend on it
+	var shown struct {
+		ID         string   `json:"id"`
+		Dependents []string `json:"dependents"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(showOutput(t, tk.ID)), &shown))
+	assert.Equal(t, tk.ID, shown.ID)
+	assert.Equal(t, []string{user.ID}, shown.Dependents)
 
 	// Multiple tickets
 	tk2 := mkTicket(t, "kt-002", "Show JSON 2", ticket.StatusOpen)
-	err = runShow(nil, []string{tk.ID, tk2.ID})
+	err := runShow(nil, []string{tk.ID, tk2.ID})
 	require.NoError(t, err)
 }
 
@@ -367,6 +384,94 @@ func TestRunShowNotFound(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// fakeEditor sets EDITOR to a script that replaces the edited file with
+// versions[0] the first time it runs, versions[1] the next, and so on.
+func fakeEditor(t *testing.T, versions ...string) {
+	t.Helper()
+	dir := t.TempDir()
+	for i, v := range versions {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprint(i+1)), []byte(v), 0644))
+	}
+	script := filepath.Join(dir, "editor")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+n=$(($(cat "`+dir+`/count" 2>/dev/null || echo 0) + 1))
+echo $n > "`+dir+`/count"
+cp "`+dir+`/$n" "$1"
+`), 0755))
+	t.Setenv("EDITOR", script)
+}
+
+func TestRunEditValidates(t *testing.T) {
+	defer setupTestEnv(t)()
+	mkTicket(t, "kt-001", "Old title", ticket.StatusOpen)
+	before, err := os.ReadFile(Store.Path("kt-001"))
+	require.NoError(t, err)
+	broken := "---\nid: kt-001\nstatus: [open\n---\n# Broken\n"
+	good := strings.Replace(string(before), "Old title", "New title", 1)
+
+	fakeEditor(t, broken)
+	mockStdin(t, "r\n")
+	assert.ErrorContains(t, runEdit(nil, []string{"kt-001"}), "previous version restored")
+	after, err := os.ReadFile(Store.Path("kt-001"))
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+
+	fakeEditor(t, strings.Replace(string(before), "id: kt-001", "id: kt-002", 1))
+	mockStdin(t, "")
+	assert.ErrorContains(t, runEdit(nil, []string{"kt-001"}), "previous version restored", "no answer restores")
+
+	fakeEditor(t, broken, good)
+	mockStdin(t, "e\n")
+	require.NoError(t, runEdit(nil, []string{"kt-001"}))
+	got, err := Store.Get("kt-001")
+	require.NoError(t, err)
+	assert.Equal(t, "New title", got.Title)
+}
+
+// showOutput runs kt show with stdout redirected and returns what it printed.
+func showOutput(t *testing.T, args ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "show.out")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	old := os.Stdout
+	os.Stdout = f
+	err = runShow(nil, args)
+	os.Stdout = old
+	require.NoError(t, f.Close())
+	require.NoError(t, err)
+	out, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestRunShowFields(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { showFields, jsonFlag, formatFlag = nil, false, "" }()
+	tk := mkTicket(t, "kt-001", "Fields", ticket.StatusOpen)
+	tk.AcceptanceCriteria = "- [ ] works"
+	tk.Labels = []string{"api"}
+	require.NoError(t, Store.Save(tk))
+
+	showFields = []string{"acceptance"}
+	assert.Equal(t, "- [ ] works\n", showOutput(t, "kt-001"))
+
+	showFields = []string{"title", "status", "acceptance_criteria"}
+	assert.Equal(t, "title: Fields\nstatus: open\n## Acceptance Criteria\n- [ ] works\n", showOutput(t, "kt-001"))
+
+	jsonFlag = true
+	showFields = []string{"id", "labels", "acceptance-criteria", "due"}
+	assert.JSONEq(t, `{"id": "kt-001", "labels": ["api"], "acceptance_criteria": "- [ ] works", "due": null}`,
+		showOutput(t, "kt-001"))
+
+	showFields = []string{"history"}
+	assert.ErrorContains(t, runShow(nil, []string{"kt-001"}), `unknown field "history"`)
+
+	jsonFlag, formatFlag = false, "{{.ID}}"
+	showFields = []string{"title"}
+	assert.ErrorContains(t, runShow(nil, []string{"kt-001"}), "cannot be used together")
+}
+
 func TestPrintTicket(t *testing.T) {
 	defer setupTestEnv(t)()
 
@@ -392,11 +497,11 @@ func TestPrintTicket(t *testing.T) {
 	}
 
 	// Just run it to ensure no panic
-	printTicket(tk)
+	printTicket(tk, []string{"kt-user"})
 
 	// Ticket with tests not passed
 	tk.TestsPassed = false
-	printTicket(tk)
+	printTicket(tk, nil)
 
 	// Minimal ticket
 	tk2 := &ticket.Ticket{
@@ -406,7 +511,7 @@ func TestPrintTicket(t *testing.T) {
 		Type:    ticket.TypeTask,
 		Title:   "Minimal",
 	}
-	printTicket(tk2)
+	printTicket(tk2, nil)
 }
 
 func TestRunDepAdd(t *testing.T) {
@@ -876,6 +981,243 @@ func TestRunCreateNoTitle(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestRunCreateFromFile(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { createFromFile, createType = "", "" }()
+	createDesc, createDesign, createAcceptance, createTests = "", "", "", ""
+	createType, createAssignee, createExtRef, createParent = "", "", "", ""
+	mkTicket(t, "kt-001", "Epic", ticket.StatusOpen)
+
+	path := filepath.Join(t.TempDir(), "ticket.md")
+	require.NoError(t, os.WriteFile(path, []byte(`---
+type: bug
+priority: 0
+parent: kt-001
+deps: [kt-001]
+---
+# Login fails
+
+Users get a 500.
+
+## Acceptance Criteria
+
+- [ ] Login works
+`), 0644))
+	createFromFile = path
+	require.NoError(t, runCreate(createCmd, nil))
+
+	createFromFile, createType = "-", "chore"
+	mockStdin(t, "# From stdin\n\nPiped.\n")
+	require.NoError(t, runCreate(createCmd, []string{"Argument wins"}))
+
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	byTitle := map[string]*ticket.Ticket{}
+	for _, tk := range tickets {
+		byTitle[tk.Title] = tk
+	}
+	got := byTitle["Login fails"]
+	require.NotNil(t, got)
+	assert.Equal(t, ticket.TypeBug, got.Type)
+	assert.Equal(t, 0, got.Priority)
+	assert.Equal(t, "kt-001", got.Parent)
+	assert.Equal(t, []string{"kt-001"}, got.Deps)
+	assert.Equal(t, "Users get a 500.", got.Description)
+	assert.Equal(t, "- [ ] Login works", got.AcceptanceCriteria)
+	assert.Equal(t, ticket.StatusOpen, got.Status)
+
+	got = byTitle["Argument wins"]
+	require.NotNil(t, got)
+	assert.Equal(t, ticket.TypeChore, got.Type)
+	assert.Equal(t, 2, got.Priority)
+	assert.Equal(t, "Piped.", got.Description)
+
+	createFromFile = "-"
+	mockStdin(t, "no title here\n")
+	assert.ErrorContains(t, runCreate(createCmd, nil), `a "# Title" line in -`)
+	mockStdin(t, "---\nid: kt-999\n---\n# Sneaky\n")
+	assert.ErrorContains(t, runCreate(createCmd, nil), `"id" cannot be set`)
+}
+
+func TestRunCreateFields(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { createLabels, createDue, createEstimate, createLinks = nil, "", "", nil }()
+	createDesc, createDesign, createAcceptance, createTests = "", "", "", ""
+	createType, createAssignee, createExtRef, createParent, createFromFile = "", "", "", "", ""
+	mkTicket(t, "kt-001", "Related", ticket.StatusOpen)
+
+	createLabels = []string{"api", "backend, urgent"}
+	createDue, createEstimate = "2026-03-01", "2.5"
+	createLinks = []string{"kt-001", "kt-001"}
+	require.NoError(t, runCreate(createCmd, []string{"Fully specified"}))
+
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	var created *ticket.Ticket
+	for _, tk := range tickets {
+		if tk.ID != "kt-001" {
+			created = tk
+		}
+	}
+	require.NotNil(t, created)
+	assert.Equal(t, []string{"api", "backend", "urgent"}, created.Labels)
+	assert.Equal(t, "2026-03-01", created.Due)
+	assert.Equal(t, 2.5, created.Estimate)
+	assert.Equal(t, []string{"kt-001"}, created.Links)
+	related, err := Store.Get("kt-001")
+	require.NoError(t, err)
+	assert.Equal(t, []string{created.ID}, related.Links, "links are symmetric")
+
+	createLinks = nil
+	createDue = "next week"
+	assert.ErrorContains(t, runCreate(createCmd, []string{"Bad due"}), "invalid due date")
+	createDue, createEstimate = "", "-1"
+	assert.ErrorContains(t, runCreate(createCmd, []string{"Bad estimate"}), "invalid estimate")
+	createEstimate, createLinks = "", []string{"kt-nope"}
+	assert.ErrorContains(t, runCreate(createCmd, []string{"Bad link"}), "links:")
+}
+
+func TestRunCreateChildOf(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { createChildOf, createBlocks, createParent = "", false, "" }()
+	createDesc, createDesign, createAcceptance, createTests = "", "", "", ""
+	createType, createAssignee, createExtRef, createParent, createFromFile = "", "", "", "", ""
+	epic := mkTicket(t, "kt-001", "Epic", ticket.StatusOpen)
+	epic.Type, epic.Priority, epic.Assignee = ticket.TypeEpic, 0, "ann"
+	require.NoError(t, Store.Save(epic))
+
+	createChildOf, createBlocks = "kt-001", true
+	require.NoError(t, runCreate(createCmd, []string{"Child"}))
+	createBlocks, createAssignee = false, "bob"
+	defer func() { createAssignee = "" }()
+	require.NoError(t, runCreate(createCmd, []string{"Loose child"}))
+
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	byTitle := map[string]*ticket.Ticket{}
+	for _, tk := range tickets {
+		byTitle[tk.Title] = tk
+	}
+	child, loose := byTitle["Child"], byTitle["Loose child"]
+	assert.Equal(t, "kt-001", child.Parent)
+	assert.Equal(t, 0, child.Priority)
+	assert.Equal(t, "ann", child.Assignee)
+	assert.Equal(t, "kt-001", loose.Parent)
+	assert.Equal(t, "bob", loose.Assignee, "flags win over the epic")
+	assert.Equal(t, []string{child.ID}, byTitle["Epic"].Deps)
+
+	createParent = "kt-001"
+	assert.ErrorContains(t, runCreate(createCmd, []string{"Both"}), "cannot be used together")
+	createChildOf, createParent, createBlocks = "", "", true
+	assert.ErrorContains(t, runCreate(createCmd, []string{"Orphan"}), "needs a parent")
+
+	mkTicket(t, "kt-done", "Done", ticket.StatusClosed)
+	createParent = "kt-done"
+	assert.EqualError(t, runCreate(createCmd, []string{"Too late"}), "--blocks-parent: parent kt-done is closed")
+	done, err := Store.Get("kt-done")
+	require.NoError(t, err)
+	assert.Empty(t, done.Deps)
+}
+
+func TestRunCreateDeps(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { createDeps, createChildOf, createBlocks = nil, "", false }()
+	createDesc, createDesign, createAcceptance, createTests = "", "", "", ""
+	createType, createAssignee, createExtRef, createParent, createFromFile = "", "", "", "", ""
+	mkTicket(t, "kt-001", "First", ticket.StatusOpen)
+	mkTicket(t, "kt-010", "Epic", ticket.StatusOpen)
+	waiting := mkTicket(t, "kt-002", "Waits for the epic", ticket.StatusOpen)
+	waiting.Deps = []string{"kt-010"}
+	require.NoError(t, Store.Save(waiting))
+
+	createDeps = []string{"kt-001", "kt-001", "kt-002"}
+	require.NoError(t, runCreate(createCmd, []string{"Wired"}))
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	assert.Len(t, tickets, 4)
+	for _, tk := range tickets {
+		if tk.Title == "Wired" {
+			assert.Equal(t, []string{"kt-001", "kt-002"}, tk.Deps)
+		}
+	}
+
+	createDeps = []string{"kt-nope"}
+	assert.ErrorContains(t, runCreate(createCmd, []string{"Unknown dep"}), "deps:")
+
+	// The epic would wait for the child, which waits for the epic
+	createChildOf, createBlocks = "kt-010", true
+	for _, dep := range []string{"kt-010", "kt-002"} {
+		createDeps = []string{dep}
+		assert.ErrorContains(t, runCreate(createCmd, []string{"Cycle"}), "dependency cycle")
+	}
+	tickets, err = Store.List()
+	require.NoError(t, err)
+	assert.Len(t, tickets, 4, "a refused ticket is not created")
+	epic, err := Store.Get("kt-010")
+	require.NoError(t, err)
+	assert.Empty(t, epic.Deps)
+}
+
+func TestRunCreateEdit(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { createEdit = false }()
+	createDesc, createDesign, createAcceptance, createTests = "", "", "", ""
+	createType, createAssignee, createExtRef, createParent, createFromFile = "", "", "", "", ""
+	createEdit = true
+	editor := filepath.Join(t.TempDir(), "editor")
+	t.Setenv("EDITOR", editor)
+
+	// The skeleton has an empty title and headings for the empty sections
+	require.NoError(t, os.WriteFile(editor, []byte(`#!/bin/sh
+grep -q '^## Acceptance Criteria$' "$1" || exit 1
+sed -i -e 's/^# $/# Written in the editor/' -e 's/^## Design$/## Design\n\nUse a queue./' "$1"
+`), 0755))
+	require.NoError(t, runCreate(createCmd, nil))
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	require.Len(t, tickets, 1)
+	assert.Equal(t, "Written in the editor", tickets[0].Title)
+	assert.Equal(t, "Use a queue.", tickets[0].Design)
+	data, err := os.ReadFile(Store.Path(tickets[0].ID))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "## Tests", "empty headings are dropped")
+
+	// Saving without a title and declining to edit again discards the ticket
+	require.NoError(t, os.WriteFile(editor, []byte("#!/bin/sh\n"), 0755))
+	mockStdin(t, "\n")
+	assert.ErrorContains(t, runCreate(createCmd, nil), "not created")
+	tickets, err = Store.List()
+	require.NoError(t, err)
+	assert.Len(t, tickets, 1)
+	assert.Empty(t, reservedIDs(t), "the discarded ticket's ID is given up")
+
+	// Links and the parent's dep are only written once the edit succeeds
+	defer func() { createLinks, createChildOf, createBlocks = nil, "", false }()
+	first := tickets[0].ID
+	epic := mkTicket(t, "kt-epic", "Epic", ticket.StatusOpen)
+	epic.Type = ticket.TypeEpic
+	require.NoError(t, Store.Save(epic))
+	createLinks, createChildOf, createBlocks = []string{first}, "kt-epic", true
+	mockStdin(t, "\n")
+	assert.ErrorContains(t, runCreate(createCmd, nil), "not created")
+	tickets, err = Store.List()
+	require.NoError(t, err)
+	assert.Len(t, tickets, 2)
+	for _, tk := range tickets {
+		assert.Empty(t, tk.Links, tk.ID)
+		assert.Empty(t, tk.Deps, tk.ID)
+	}
+
+	require.NoError(t, os.WriteFile(editor, []byte("#!/bin/sh\nsed -i 's/^# $/# Linked/' \"$1\"\n"), 0755))
+	require.NoError(t, runCreate(createCmd, nil))
+	epic, err = Store.Get("kt-epic")
+	require.NoError(t, err)
+	require.Len(t, epic.Deps, 1)
+	linked, err := Store.Get(first)
+	require.NoError(t, err)
+	assert.Equal(t, epic.Deps, linked.Links)
+}
+
 func TestSetStatusMultipleErrors(t *testing.T) {
 	defer setupTestEnv(t)()
 
@@ -1570,3 +1912,67 @@ func TestPromptChoice_OutOfRange(t *testing.T) {
 	choice := promptChoice(reader, "Pick one", []string{"A", "B", "C"})
 	assert.Equal(t, 3, choice) // Defaults to last
 }
+
+func TestFormatTemplate(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { formatFlag = "" }()
+
+	tk := mkTicket(t, "kt-fmt", "Formatted", ticket.StatusOpen)
+
+	assert.False(t, IsTemplate())
+	formatFlag = `{{.ID}}\t{{.Status}}\t{{join .Labels ","}}`
+	assert.True(t, IsTemplate())
+	require.NoError(t, runList(nil, nil))
+	require.NoError(t, runShow(nil, []string{tk.ID}))
+	require.NoError(t, runReady(nil, nil))
+
+	// --json wins over --format
+	jsonFlag = true
+	assert.False(t, IsTemplate())
+	jsonFlag = false
+
+	formatFlag = "{{.ID"
+	err := runList(nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --format template")
+}
+
+func TestRenderEnabled(t *testing.T) {
+	defer func() { showRender = "" }()
+
+	// Not a terminal under test: raw by default
+	assert.False(t, renderEnabled())
+	assert.Equal(t, "- **x**", renderBody("- **x**"))
+
+	t.Setenv("KTICKET_RENDER", "1")
+	assert.True(t, renderEnabled())
+	assert.Equal(t, "• x", renderBody("- **x**"))
+
+	// The flag wins over the environment
+	showRender = "false"
+	assert.False(t, renderEnabled())
+}
+
+func TestInstallCustomTemplates(t *testing.T) {
+	defer setupTestEnv(t)()
+	dir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", dir)
+
+	require.NoError(t, runInstallTemplates(nil, nil))
+	custom := filepath.Join(Store.Dir, TemplatesDir)
+	require.NoError(t, os.WriteFile(filepath.Join(custom, "kt-run.md"), []byte("our /kt-run\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(custom, "kt-triage.md"), []byte("triage\n"), 0644))
+	require.NoError(t, runInstallTemplates(nil, nil), "existing templates are kept")
+
+	assert.Equal(t, []string{"kt-create.md", "kt-run.md", "kt-run-all.md", "kt-triage.md"}, slashCommands())
+	require.NoError(t, installSlashCommands(true))
+	content, err := os.ReadFile(filepath.Join(dir, "commands/kt-run.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "our /kt-run\n", string(content))
+	content, err = os.ReadFile(filepath.Join(dir, "commands/kt-triage.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "triage\n", string(content))
+	content, err = os.ReadFile(filepath.Join(dir, "commands/kt-create.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "kt create", "built-in copy")
+}
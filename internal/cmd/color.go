@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/kostyay/kticket/internal/ticket"
+)
+
+// ANSI escape codes used by the text renderers.
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiDim    = "\033[2m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+)
+
+var noColorFlag bool
+
+// colorEnabled reports whether text output should be colorized: only on a
+// TTY, and never with --no-color or NO_COLOR set.
+func colorEnabled() bool {
+	if noColorFlag || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return OutputMode() == "text"
+}
+
+// paint wraps s in the given ANSI code when color is enabled.
+func paint(code, s string) string {
+	if code == "" || !colorEnabled() {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// statusColor returns the ANSI code for a status.
+func statusColor(s ticket.Status) string {
+	switch s {
+	case ticket.StatusOpen:
+		return ansiCyan
+	case ticket.StatusInProgress:
+		return ansiYellow
+	case ticket.StatusClosed:
+		return ansiDim
+	case "unknown":
+		return ansiRed
+	}
+	return ""
+}
+
+// priorityColor returns the ANSI code for a priority (0 = highest).
+func priorityColor(p int) string {
+	switch p {
+	case 0:
+		return ansiBold + ansiRed
+	case 1:
+		return ansiRed
+	case 2:
+		return ansiYellow
+	}
+	return ansiDim
+}
+
+// paintStatus colors s (typically a padded status) by status.
+func paintStatus(status ticket.Status, s string) string {
+	return paint(statusColor(status), s)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorDisabled(t *testing.T) {
+	// Tests don't run on a TTY, so color is always off here
+	assert.False(t, colorEnabled())
+	assert.Equal(t, "open", paintStatus(ticket.StatusOpen, "open"))
+
+	t.Setenv("NO_COLOR", "1")
+	assert.False(t, colorEnabled())
+
+	noColorFlag = true
+	defer func() { noColorFlag = false }()
+	assert.False(t, colorEnabled())
+}
+
+func TestStatusAndPriorityColors(t *testing.T) {
+	assert.Equal(t, ansiCyan, statusColor(ticket.StatusOpen))
+	assert.Equal(t, ansiYellow, statusColor(ticket.StatusInProgress))
+	assert.Equal(t, ansiDim, statusColor(ticket.StatusClosed))
+	assert.Equal(t, "", statusColor("custom"))
+
+	assert.Equal(t, ansiBold+ansiRed, priorityColor(0))
+	assert.Equal(t, ansiDim, priorityColor(4))
+}
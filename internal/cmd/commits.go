@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var commitLinkCmd = &cobra.Command{
+	Use:   "commit-link <id> <commit>...",
+	Short: "Record commits that implement a ticket",
+	Long: `Record commits on a ticket's commits list. Commits may be given as any
+revision git understands (abbreviated SHA, branch, HEAD~1) and are stored
+as full SHAs. Linking a commit twice is a no-op.
+
+kt scan-commits records commits automatically for tickets they close or
+reference with a "Refs: <id>" trailer.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runCommitLink,
+}
+
+func init() {
+	rootCmd.AddCommand(commitLinkCmd)
+}
+
+type commitLinkResult struct {
+	ID    string   `json:"id"`
+	Added []string `json:"added,omitempty"`
+}
+
+func runCommitLink(cmd *cobra.Command, args []string) error {
+	// Resolve commits before locking the ticket
+	shas := make([]string, 0, len(args)-1)
+	for _, rev := range args[1:] {
+		sha, err := git.ResolveCommit(rev)
+		if err != nil {
+			return err
+		}
+		shas = append(shas, sha)
+	}
+
+	lt, err := Store.ResolveForUpdate(args[0])
+	if err != nil {
+		return err
+	}
+	result := commitLinkResult{ID: lt.Ticket.ID}
+	for _, sha := range shas {
+		if lt.Ticket.AddCommit(sha) {
+			result.Added = append(result.Added, sha)
+		}
+	}
+	if len(result.Added) == 0 {
+		lt.Release()
+	} else if err := lt.SaveAndRelease(); err != nil {
+		return err
+	}
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	if len(result.Added) == 0 {
+		fmt.Printf("%s: commits already linked\n", result.ID)
+		return nil
+	}
+	short := make([]string, len(result.Added))
+	for i, sha := range result.Added {
+		short[i] = git.Commit{SHA: sha}.Short()
+	}
+	fmt.Printf("%s: linked %s\n", result.ID, strings.Join(short, ", "))
+	return nil
+}
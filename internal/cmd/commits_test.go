@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCommitLink(t *testing.T) {
+	defer setupTestEnv(t)()
+	initGitRepo(t)
+	tk := mkTicket(t, "kt-a1b2", "Login", ticket.StatusOpen)
+	first := gitCommit(t, "first")
+	second := gitCommit(t, "second")
+
+	require.NoError(t, runCommitLink(nil, []string{"a1b2", first[:7], "HEAD"}))
+	u, err := Store.Get(tk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{first, second}, u.Commits)
+
+	// Relinking is a no-op
+	require.NoError(t, runCommitLink(nil, []string{"a1b2", "HEAD"}))
+	u, _ = Store.Get(tk.ID)
+	assert.Equal(t, []string{first, second}, u.Commits)
+
+	assert.Error(t, runCommitLink(nil, []string{"a1b2", "no-such-ref"}))
+	assert.Error(t, runCommitLink(nil, []string{"kt-none", "HEAD"}))
+}
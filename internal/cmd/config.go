@@ -0,0 +1,436 @@
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/notify"
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and write settings in config.yml",
+	Long: `Read and write settings in the project's config.yml (in the tickets
+directory) or, with --user, the user's ~/.config/kt/config.yml. Keys are
+dotted paths into the file, e.g. defaults.type, integrations.auto_commit, or
+hooks.ticket_closed. get and list show the effective settings, the
+project's over the user's, unless --user or --project picks one file.
+
+  kt config list
+  kt config get defaults.priority
+  kt config set defaults.priority 1
+  kt config set --user editor "code --wait"
+  kt config set types "[spike, research]"`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a setting (or every setting under a key)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting; the value is YAML, e.g. true, 3, or [a, b]",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print every setting that is set",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigList,
+}
+
+var configTrustCmd = &cobra.Command{
+	Use:   "trust",
+	Short: "Let the hooks and agent commands in the project's config.yml run on this machine",
+	Long: `Let the hooks and agent commands (agent.command and agent.verify, for kt
+run) in the project's config.yml run on this machine. They are shell
+commands, and the file is committed with the tickets, so kt runs them only
+once you have read and trusted them; until then it warns and runs only the
+hooks in your own config file, and kt run refuses the agent commands. Trust
+covers the commands as they are now: after anyone changes them, run kt
+config trust again. Set KTICKET_TRUST_HOOKS=true to trust them without
+asking, e.g. in CI.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigTrust,
+}
+
+var (
+	configUser    bool
+	configProject bool
+)
+
+func init() {
+	for _, c := range []*cobra.Command{configGetCmd, configSetCmd, configListCmd} {
+		c.Flags().BoolVar(&configUser, "user", false, "Use the user's config file")
+		if c != configSetCmd {
+			c.Flags().BoolVar(&configProject, "project", false, "Use the project's config file only")
+			c.MarkFlagsMutuallyExclusive("user", "project")
+		}
+		configCmd.AddCommand(c)
+	}
+	configCmd.AddCommand(configTrustCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// configSetResult is the JSON output of kt config set.
+type configSetResult struct {
+	File  string `json:"file"`
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+// configFile returns the config file kt config writes: the user's with
+// --user, else the project's.
+func configFile() (string, error) {
+	if !configUser {
+		return filepath.Join(Store.Dir, config.ProjectFile), nil
+	}
+	path := config.UserFile()
+	if path == "" {
+		return "", fmt.Errorf("cannot find the user config directory (set XDG_CONFIG_HOME or HOME)")
+	}
+	return path, nil
+}
+
+// configSettings returns the settings kt config get and list show.
+func configSettings() (map[string]any, error) {
+	cfg := projectConfig()
+	switch {
+	case configUser:
+		var err error
+		if cfg, err = config.LoadFile(config.UserFile()); err != nil {
+			return nil, err
+		}
+	case configProject:
+		var err error
+		if cfg, err = config.LoadProject(Store.Dir); err != nil {
+			return nil, err
+		}
+	}
+	return cfg.Settings()
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	settings, err := configSettings()
+	if err != nil {
+		return err
+	}
+	key := args[0]
+	if v, ok := settings[key]; ok {
+		if IsJSON() {
+			return PrintJSON(v)
+		}
+		fmt.Println(formatSetting(v))
+		return nil
+	}
+	maps.DeleteFunc(settings, func(k string, _ any) bool { return !strings.HasPrefix(k, key+".") })
+	if len(settings) == 0 {
+		return fmt.Errorf("%s is not set", key)
+	}
+	return printSettings(settings)
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	if !configUser && slices.Contains(config.PersonalKeys, args[0]) {
+		return fmt.Errorf("%s is read only from the user's config file (use --user)", args[0])
+	}
+	path, err := configFile()
+	if err != nil {
+		return err
+	}
+	if err := config.Set(path, args[0], args[1]); err != nil {
+		return err
+	}
+	Config = nil // reload for hooks and auto-commit after this command
+
+	if IsJSON() {
+		cfg, err := config.LoadFile(path)
+		if err != nil {
+			return err
+		}
+		settings, err := cfg.Settings()
+		if err != nil {
+			return err
+		}
+		return PrintJSON(configSetResult{File: path, Key: args[0], Value: settings[args[0]]})
+	}
+	fmt.Printf("Set %s in %s\n", args[0], path)
+	return nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	settings, err := configSettings()
+	if err != nil {
+		return err
+	}
+	return printSettings(settings)
+}
+
+// configTrustResult is the JSON output of kt config trust.
+type configTrustResult struct {
+	File  string            `json:"file"`
+	Hooks map[string]string `json:"hooks"`
+	Agent config.Agent      `json:"agent"`
+}
+
+func runConfigTrust(cmd *cobra.Command, args []string) error {
+	project, err := config.LoadProject(Store.Dir)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(Store.Dir, config.ProjectFile)
+	commands := project.Commands()
+	if len(commands) == 0 {
+		return fmt.Errorf("%s has no hooks or agent commands", path)
+	}
+	if err := config.TrustHooks(Store.Dir, commands); err != nil {
+		return err
+	}
+
+	if IsJSON() {
+		return PrintJSON(configTrustResult{File: path, Hooks: project.Hooks, Agent: project.Agent})
+	}
+	fmt.Printf("Trusted the commands in %s:\n", path)
+	for _, key := range slices.Sorted(maps.Keys(commands)) {
+		fmt.Printf("  %s: %s\n", key, commands[key])
+	}
+	return nil
+}
+
+// printSettings prints settings as key=value lines sorted by key, or as a
+// JSON object.
+func printSettings(settings map[string]any) error {
+	if IsJSON() {
+		return PrintJSON(settings)
+	}
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		fmt.Printf("%s=%s\n", key, formatSetting(settings[key]))
+	}
+	return nil
+}
+
+// formatSetting renders a setting value on one line, lists as [a, b].
+func formatSetting(v any) string {
+	if list, ok := v.([]any); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprint(v)
+}
+
+// Config is the configuration (.ktickets/config.yml over the user's
+// ~/.config/kt/config.yml), loaded on first use by projectConfig.
+var Config *config.Project
+
+// projectConfig returns the configuration, loading it if needed. An invalid
+// config file is reported and ignored.
+func projectConfig() *config.Project {
+	if Config != nil {
+		return Config
+	}
+	p, err := config.Load(currentDir())
+	if err != nil {
+		Warnf("%v", err)
+		p = &config.Project{}
+	}
+	for _, typ := range p.Types {
+		if !slices.Contains(ticket.Types, ticket.Type(typ)) {
+			ticket.Types = append(ticket.Types, ticket.Type(typ))
+		}
+	}
+	for _, status := range append(p.Statuses, p.AdvanceFrom) {
+		if status == "" {
+			continue
+		}
+		if !slices.Contains(ticket.Statuses, ticket.Status(status)) {
+			ticket.Statuses = append(ticket.Statuses, ticket.Status(status))
+		}
+	}
+	events := slices.Sorted(maps.Keys(p.Hooks))
+	for _, event := range events {
+		if event != "*" && !notify.KnownEvent(event) {
+			Warnf("%s: unknown hook event %q (expected one of %v or *)", config.ProjectFile, event, notify.EventTypes)
+		}
+	}
+	Config = p
+	return Config
+}
+
+// currentDir returns the tickets directory: the open store's, or before it
+// is opened (during Args validation, or in tests) the default one.
+func currentDir() string {
+	if Store != nil {
+		return Store.Dir
+	}
+	dir, _ := config.FindDir() // opening the store warns, if needed
+	return dir
+}
+
+// orConfig returns the flag value, else the integrations setting key from
+// config.yml, erroring if neither is set.
+func orConfig(flagValue, flag, key, configValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if configValue != "" {
+		return configValue, nil
+	}
+	return "", fmt.Errorf("%s is required (or set integrations.%s in %s)", flag, key, config.ProjectFile)
+}
+
+// checkStatus rejects statuses outside the built-in and configured ones,
+// when config.yml lists statuses. Otherwise any status is allowed.
+func checkStatus(s ticket.Status) error {
+	if len(projectConfig().Statuses) > 0 && !slices.Contains(ticket.Statuses, s) {
+		return fmt.Errorf("invalid status %q (expected one of %v)", s, ticket.Statuses)
+	}
+	return nil
+}
+
+// checkRequired enforces the require settings on a new ticket, reporting
+// every requirement it misses.
+func checkRequired(t *ticket.Ticket) error {
+	req := projectConfig().Require
+	var errs []error
+	if n := utf8.RuneCountInString(t.Title); req.MaxTitle > 0 && n > req.MaxTitle {
+		errs = append(errs, fmt.Errorf("title is %d characters, more than require.max_title (%d)", n, req.MaxTitle))
+	}
+	for _, r := range []struct {
+		key   string // in require, and the kt create flag
+		what  string
+		types []string
+		value string
+	}{
+		{"description", "a description", req.Description, t.Description},
+		{"acceptance", "acceptance criteria", req.Acceptance, t.AcceptanceCriteria},
+		{"tests", "a tests section", req.Tests, t.Tests},
+	} {
+		if strings.TrimSpace(r.value) == "" && slices.Contains(r.types, string(t.Type)) {
+			errs = append(errs, fmt.Errorf("%s tickets need %s (--%s; see require.%s)", t.Type, r.what, r.key, r.key))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parentProblem is a ticket whose parent breaks the epic_parents rule.
+type parentProblem struct {
+	ID      string `json:"id"`
+	Parent  string `json:"parent"`
+	Problem string `json:"problem"`
+}
+
+// parentProblems returns the tickets whose parent is not an epic, and the
+// epics with a parent. Parents that do not exist are left to danglingRefs.
+func parentProblems(tickets []*ticket.Ticket) []parentProblem {
+	byID := make(map[string]*ticket.Ticket, len(tickets))
+	for _, t := range tickets {
+		byID[t.ID] = t
+	}
+	var problems []parentProblem
+	for _, t := range tickets {
+		p, ok := byID[t.Parent]
+		if t.Parent == "" || !ok {
+			continue
+		}
+		if t.Type == ticket.TypeEpic {
+			problems = append(problems, parentProblem{ID: t.ID, Parent: p.ID, Problem: fmt.Sprintf("epic has parent %s", p.ID)})
+		}
+		if p.Type != ticket.TypeEpic {
+			problems = append(problems, parentProblem{ID: t.ID, Parent: p.ID, Problem: fmt.Sprintf("parent %s is a %s, not an epic", p.ID, p.Type)})
+		}
+	}
+	return problems
+}
+
+// checkParentRule enforces epic_parents, if set, on t about to be saved to
+// s: as a child, and as the parent of other tickets. Only problems that
+// saving t would introduce are errors.
+func checkParentRule(s *store.Store, t *ticket.Ticket) error {
+	if !projectConfig().EpicParents {
+		return nil
+	}
+	tickets, err := s.List()
+	if err != nil {
+		return err
+	}
+	before := parentProblems(tickets)
+	tickets = slices.DeleteFunc(tickets, func(o *ticket.Ticket) bool { return o.ID == t.ID })
+	tickets = append(tickets, t)
+	if t.Parent != "" && !slices.ContainsFunc(tickets, func(o *ticket.Ticket) bool { return o.ID == t.Parent }) {
+		return fmt.Errorf("parent %s not found", t.Parent)
+	}
+	for _, p := range parentProblems(tickets) {
+		if (p.ID == t.ID || p.Parent == t.ID) && !slices.Contains(before, p) {
+			return fmt.Errorf("%s: %s (epic_parents is set)", cmp.Or(p.ID, t.Title), p.Problem) // t by title before it has an ID
+		}
+	}
+	return nil
+}
+
+// newTicketID returns an ID for a new ticket in s, using the configured
+// ID strategy. Hash IDs are checked against the store's tickets.
+func newTicketID(s *store.Store) (string, error) {
+	cfg := projectConfig()
+	if cfg.IDStrategy == config.IDSequential {
+		return s.NextSequentialID(cfg.IDPrefix)
+	}
+	return s.NewHashID(cfg.IDPrefix, cfg.IDLength)
+}
+
+// applyTemplates fills the sections of a new ticket that were not given
+// from the defaults in config.yml.
+func applyTemplates(t *ticket.Ticket) {
+	d := projectConfig().Defaults
+	t.Description = cmp.Or(t.Description, strings.TrimSpace(d.Description))
+	t.Design = cmp.Or(t.Design, strings.TrimSpace(d.Design))
+	t.AcceptanceCriteria = cmp.Or(t.AcceptanceCriteria, strings.TrimSpace(d.Acceptance))
+	t.Tests = cmp.Or(t.Tests, strings.TrimSpace(d.Tests))
+}
+
+// defaultType returns the type for new tickets: defaults.type, else task.
+func defaultType() ticket.Type {
+	if typ := projectConfig().Defaults.Type; typ != "" {
+		return ticket.Type(typ)
+	}
+	return ticket.TypeTask
+}
+
+// defaultPriority returns the priority for new tickets: defaults.priority,
+// else 2.
+func defaultPriority() int {
+	if p := projectConfig().Defaults.Priority; p != nil {
+		return *p
+	}
+	return 2
+}
+
+// defaultAssignee returns the assignee for new tickets: KTICKET_ASSIGNEE,
+// else defaults.assignee, else git user.name.
+func defaultAssignee() string {
+	if a := os.Getenv(config.EnvAssignee); a != "" {
+		return a
+	}
+	if a := projectConfig().Defaults.Assignee; a != "" {
+		return a
+	}
+	return getGitUser()
+}
@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/filelock"
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProjectConfig(t *testing.T, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(Store.Dir, config.ProjectFile), []byte(content), 0644))
+	Config = nil
+}
+
+func TestCreateUsesProjectConfig(t *testing.T) {
+	defer setupTestEnv(t)()
+	writeProjectConfig(t, `
+defaults:
+  type: spike
+  priority: 3
+  assignee: ann
+  acceptance: |
+    - [ ] Docs updated
+  tests: "- TestTODO"
+types: [spike]
+id_prefix: web
+`)
+	createDesc, createDesign, createAcceptance, createTests = "", "", "", ""
+	createType, createAssignee, createExtRef, createParent = "", "", "", ""
+	require.NoError(t, runCreate(createCmd, []string{"Configured"}))
+	createTests = "- TestGiven"
+	defer func() { createTests = "" }()
+	require.NoError(t, runCreate(createCmd, []string{"Flags win"}))
+
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	require.Len(t, tickets, 2)
+	byTitle := map[string]*ticket.Ticket{}
+	for _, tk := range tickets {
+		byTitle[tk.Title] = tk
+	}
+	tk := byTitle["Configured"]
+	assert.Equal(t, "- [ ] Docs updated", tk.AcceptanceCriteria)
+	assert.Equal(t, "- TestTODO", tk.Tests)
+	assert.Equal(t, "- TestGiven", byTitle["Flags win"].Tests)
+	assert.Equal(t, "- [ ] Docs updated", byTitle["Flags win"].AcceptanceCriteria)
+	assert.True(t, strings.HasPrefix(tk.ID, "web-"), tk.ID)
+	assert.Equal(t, ticket.Type("spike"), tk.Type)
+	assert.Equal(t, 3, tk.Priority)
+	assert.Equal(t, "ann", tk.Assignee)
+
+	// Custom types are accepted by kt set
+	assert.NoError(t, tk.SetField("type", "spike"))
+}
+
+func TestSequentialIDs(t *testing.T) {
+	defer setupTestEnv(t)()
+	writeProjectConfig(t, "id_strategy: sequential\nid_prefix: web\n")
+	createDesc, createDesign, createAcceptance, createTests = "", "", "", ""
+	createType, createAssignee, createExtRef, createParent = "", "", "", ""
+	for _, title := range []string{"First", "Second"} {
+		require.NoError(t, runCreate(createCmd, []string{title}))
+	}
+
+	_, err := Store.Get("web-001")
+	assert.NoError(t, err)
+	tk, err := Store.Resolve("2")
+	require.NoError(t, err)
+	assert.Equal(t, "web-002", tk.ID)
+}
+
+func TestEditorCommand(t *testing.T) {
+	defer setupTestEnv(t)()
+	t.Setenv("EDITOR", "")
+	assert.Equal(t, "vi", editorCommand())
+
+	t.Setenv("EDITOR", "nano")
+	assert.Equal(t, "nano", editorCommand())
+
+	require.NoError(t, os.MkdirAll(filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "kt"), 0755))
+	require.NoError(t, os.WriteFile(config.UserFile(), []byte("editor: code --wait\n"), 0644))
+	Config = nil
+	assert.Equal(t, "code --wait", editorCommand())
+
+	// A committed config.yml cannot pick the editor, trusted or not
+	writeProjectConfig(t, "editor: ./evil\n")
+	assert.Equal(t, "code --wait", editorCommand())
+	assert.ErrorContains(t, runConfigSet(nil, []string{"editor", "./evil"}), "use --user")
+}
+
+func TestCheckStatus(t *testing.T) {
+	defer setupTestEnv(t)()
+	assert.NoError(t, checkStatus("anything"), "any status without configured statuses")
+
+	writeProjectConfig(t, "statuses: [review]\n")
+	assert.NoError(t, checkStatus("review"))
+	assert.NoError(t, checkStatus(ticket.StatusClosed))
+	assert.ErrorContains(t, checkStatus("revew"), `invalid status "revew"`)
+}
+
+func TestHooks(t *testing.T) {
+	defer setupTestEnv(t)()
+	out := filepath.Join(t.TempDir(), "hook.log")
+	writeProjectConfig(t, `
+hooks:
+  closed: echo "$KT_EVENT $KT_ID" >> `+out+` # the old name of ticket_closed
+  tests_passed: cat >> `+out+`; echo >> `+out+`
+  "*": cat >/dev/null; echo "any $KT_EVENT" >> `+out+`
+`)
+	mkTicket(t, "kt-h", "Hooked", ticket.StatusOpen)
+
+	// The project's hooks do not run until trusted
+	s := store.New(Store.Dir) // a separate operation, like the next kt command
+	require.NoError(t, s.Update("kt-h", func(tk *ticket.Ticket) error {
+		tk.Priority = 1
+		return nil
+	}))
+	notifyChanges(s)
+	assert.NoFileExists(t, out)
+
+	require.NoError(t, runConfigTrust(nil, nil))
+	s = store.New(Store.Dir)
+	require.NoError(t, s.Update("kt-h", func(tk *ticket.Ticket) error {
+		tk.TestsPassed = true
+		tk.SetStatus(ticket.StatusClosed)
+		return nil
+	}))
+
+	notifyChanges(s)
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "ticket_closed kt-h\n")
+	assert.Contains(t, string(data), "any ticket_closed\n")
+	assert.Contains(t, string(data), `"id":"kt-h"`, "tests_passed hook gets the ticket JSON")
+	assert.Contains(t, string(data), "any tests_passed\n")
+}
+
+func TestAdvanceDependents(t *testing.T) {
+	defer setupTestEnv(t)()
+	t.Setenv(config.EnvTrustHooks, "true")
+	out := filepath.Join(t.TempDir(), "hook.log")
+	writeProjectConfig(t, `
+advance_from: waiting
+hooks:
+  status: echo "$KT_ID $KT_STATUS" >> `+out+`
+`)
+	mkTicket(t, "kt-a", "Build", ticket.StatusOpen)
+	mkTicket(t, "kt-b", "Other", ticket.StatusOpen)
+	for id, deps := range map[string][]string{"kt-next": {"kt-a"}, "kt-both": {"kt-a", "kt-b"}} {
+		tk := mkTicket(t, id, "Waiting", "waiting")
+		tk.Deps = deps
+		require.NoError(t, Store.Save(tk))
+	}
+	later := mkTicket(t, "kt-later", "Not waiting", ticket.StatusOpen)
+	later.Deps = []string{"kt-a"}
+	require.NoError(t, Store.Save(later))
+	assert.NoError(t, checkStatus("waiting"))
+
+	s := store.New(Store.Dir)
+	require.NoError(t, s.Update("kt-a", func(tk *ticket.Ticket) error {
+		tk.SetStatus(ticket.StatusClosed)
+		return nil
+	}))
+	assert.Equal(t, []string{"kt-next"}, advanceDependents(s))
+	notifyChanges(s)
+
+	for id, want := range map[string]ticket.Status{"kt-next": ticket.StatusOpen, "kt-both": "waiting", "kt-later": ticket.StatusOpen} {
+		got, err := Store.Get(id)
+		require.NoError(t, err)
+		assert.Equal(t, want, got.Status, id)
+	}
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "kt-next open\n", string(data))
+
+	writeProjectConfig(t, "")
+	s = store.New(Store.Dir)
+	require.NoError(t, s.Update("kt-b", func(tk *ticket.Ticket) error {
+		tk.SetStatus(ticket.StatusClosed)
+		return nil
+	}))
+	assert.Empty(t, advanceDependents(s), "off unless advance_from is set")
+}
+
+func TestFlagEnv(t *testing.T) {
+	defer setupTestEnv(t)()
+	t.Setenv(config.EnvDir, Store.Dir)
+	t.Setenv(config.EnvJSON, "true")
+	t.Setenv(config.EnvLockTimeout, "250ms")
+	t.Setenv(config.EnvAssignee, "bot")
+	t.Setenv(config.EnvStrictParse, "true")
+	defer func() {
+		jsonFlag, filelock.Timeout, strictParse = false, filelock.DefaultTimeout, false
+		for _, name := range []string{"json", "lock-timeout", "strict-parse"} {
+			rootCmd.PersistentFlags().Lookup(name).Changed = false
+		}
+	}()
+
+	rootCmd.SetArgs([]string{"version"})
+	require.NoError(t, rootCmd.Execute())
+	assert.True(t, jsonFlag)
+	assert.Equal(t, 250*time.Millisecond, filelock.Timeout)
+	assert.Equal(t, "bot", defaultAssignee())
+	assert.True(t, ticket.Strict)
+
+	// kt dep graph has a --format of its own, which KTICKET_FORMAT leaves alone
+	t.Setenv(config.EnvFormat, "{{.ID}}")
+	rootCmd.SetArgs([]string{"dep", "graph"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Equal(t, "dot", depGraphFormat)
+	t.Setenv(config.EnvFormat, "")
+
+	t.Setenv(config.EnvLockTimeout, "soon")
+	rootCmd.PersistentFlags().Lookup("lock-timeout").Changed = false
+	rootCmd.SetArgs([]string{"version"})
+	assert.ErrorContains(t, rootCmd.Execute(), "invalid KTICKET_LOCK_TIMEOUT")
+}
+
+func TestConfigCommand(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { configUser, configProject = false, false }()
+
+	require.NoError(t, runConfigSet(nil, []string{"defaults.priority", "1"}))
+	configUser = true
+	require.NoError(t, runConfigSet(nil, []string{"defaults.assignee", "me"}))
+	configUser = false
+
+	cfg := projectConfig()
+	assert.Equal(t, 1, *cfg.Defaults.Priority)
+	assert.Equal(t, "me", cfg.Defaults.Assignee, "user file merged beneath")
+
+	configProject = true
+	settings, err := configSettings()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"defaults.priority": uint64(1)}, settings)
+
+	assert.ErrorContains(t, runConfigGet(nil, []string{"editor"}), "editor is not set")
+	assert.Error(t, runConfigSet(nil, []string{"output", "yaml"}))
+}
+
+func TestEpicParents(t *testing.T) {
+	defer setupTestEnv(t)()
+	mkTicket(t, "kt-task", "Task", ticket.StatusOpen)
+	epic := mkTicket(t, "kt-epic", "Epic", ticket.StatusOpen)
+	epic.Type = ticket.TypeEpic
+	require.NoError(t, Store.Save(epic))
+	createDesc, createDesign, createAcceptance, createTests = "", "", "", ""
+	createType, createAssignee, createExtRef = "", "", ""
+	defer func() { createParent, createType = "", "" }()
+
+	createParent = "kt-task"
+	require.NoError(t, runCreate(createCmd, []string{"Unchecked"}), "any parent without epic_parents")
+	createParent = "kt-nope"
+	require.NoError(t, runCreate(createCmd, []string{"Missing"}), "a missing parent is left for fsck")
+	var missing *ticket.Ticket
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	for _, tk := range tickets {
+		if tk.Title == "Missing" {
+			missing = tk
+		}
+	}
+	require.NotNil(t, missing)
+	require.NoError(t, Store.Delete(missing.ID))
+
+	writeProjectConfig(t, "epic_parents: true\n")
+	assert.ErrorContains(t, runCreate(createCmd, []string{"Missing"}), "parent:")
+	createParent = "kt-task"
+	assert.ErrorContains(t, runCreate(createCmd, []string{"Under task"}), "parent kt-task is a task, not an epic")
+	createParent = "epic"
+	require.NoError(t, runCreate(createCmd, []string{"Under epic"}))
+	createType = "epic"
+	assert.ErrorContains(t, runCreate(createCmd, []string{"Sub-epic"}), "epic has parent kt-epic")
+	mockStdin(t, "---\nparent: kt-epic\n---\n# Sub-plan\n## Step\n")
+	assert.EqualError(t, runBreakdown(breakdownCmd, []string{"-"}), "Sub-plan: epic has parent kt-epic (epic_parents is set)")
+
+	// The editor can change the parent, so the rule is checked after it too
+	createParent, createType, createEdit = "", "", true
+	defer func() { createEdit = false }()
+	editor := filepath.Join(t.TempDir(), "editor")
+	t.Setenv("EDITOR", editor)
+	require.NoError(t, os.WriteFile(editor, []byte("#!/bin/sh\nsed -i -e 's/^# $/# Under task/' -e 's/^type: task$/type: task\\nparent: kt-task/' \"$1\"\n"), 0755))
+	mockStdin(t, "\n")
+	assert.ErrorContains(t, runCreate(createCmd, nil), "not created")
+	createEdit = false
+
+	assert.ErrorContains(t, runSet(nil, []string{"kt-task", "parent=kt-task"}), "not an epic")
+	assert.ErrorContains(t, runSet(nil, []string{"kt-task", "type=epic", "parent=kt-epic"}), "epic has parent")
+	assert.ErrorContains(t, runSet(nil, []string{"kt-epic", "type=task"}), "parent kt-epic is a task", "would orphan its child")
+	require.NoError(t, runSet(nil, []string{"kt-task", "parent=kt-epic"}))
+
+	// Tickets from before epic_parents was set are left for fsck to report.
+	tickets, err = Store.List()
+	require.NoError(t, err)
+	problems := parentProblems(tickets)
+	require.Len(t, problems, 1)
+	assert.Equal(t, "parent kt-task is a task, not an epic", problems[0].Problem)
+	assert.EqualError(t, runFsck(nil, nil), "1 ticket(s) break epic_parents")
+}
+
+// reservedIDs returns the reservation files of IDs handed out for new
+// tickets not yet saved.
+func reservedIDs(t *testing.T) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(Store.Dir, ".locks", "*.new"))
+	require.NoError(t, err)
+	return paths
+}
+
+func TestCreateRequirements(t *testing.T) {
+	defer setupTestEnv(t)()
+	writeProjectConfig(t, `
+require:
+  max_title: 10
+  acceptance: [feature]
+  tests: [bug]
+`)
+	createDesc, createDesign, createAcceptance, createTests = "", "", "", ""
+	createAssignee, createExtRef, createParent = "", "", ""
+	defer func() { createType, createTests = "", "" }()
+
+	createType = "bug"
+	err := runCreate(createCmd, []string{"Far too long a title"})
+	assert.EqualError(t, err, "title is 20 characters, more than require.max_title (10)\n"+
+		"bug tickets need a tests section (--tests; see require.tests)")
+	createType = "feature"
+	assert.ErrorContains(t, runCreate(createCmd, []string{"Login"}), "feature tickets need acceptance criteria")
+	assert.Empty(t, reservedIDs(t), "a refused ticket reserves no ID")
+
+	createType, createTests = "bug", "- TestCrash"
+	require.NoError(t, runCreate(createCmd, []string{"Crash"}))
+	createType = "task"
+	require.NoError(t, runCreate(createCmd, []string{"Chore"}), "no requirements for tasks")
+
+	writeProjectConfig(t, "require:\n  acceptance: [feature]\ndefaults:\n  acceptance: \"- [ ] Works\"\n")
+	createType = "feature"
+	assert.NoError(t, runCreate(createCmd, []string{"Templated"}), "defaults count")
+}
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"os/exec"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,8 +17,41 @@ import (
 var createCmd = &cobra.Command{
 	Use:   "create [title]",
 	Short: "Create a new ticket",
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runCreate,
+	Long: `Create a new ticket from flags, or with --from-file from a markdown file
+("-" reads stdin) laid out like a ticket file: a "# Title" line, the
+description, then sections such as "## Design" and "## Acceptance Criteria".
+The file may start with frontmatter setting type, priority, assignee,
+external-ref, parent, labels, due, estimate, recurrence, deps, or links.
+A title argument and flags given on the command line win over the file.
+
+--child-of makes the new ticket a child of an epic, taking the epic's
+priority and assignee unless they are given (tickets have no milestone
+field, so there is nothing else to inherit). With --blocks-parent the epic
+also gets the new ticket as a dependency, so it cannot be done first; a
+closed parent is refused, as it would be waiting on a ticket after the
+fact.
+
+--edit then opens the new ticket in the editor, as git commit does, with
+headings for the sections still empty. The title may be left out until
+then. If the saved ticket does not parse, has no title, or lacks what
+require in config.yml asks for, kt offers to edit it again; declining
+discards the ticket.
+
+  kt create --from-file - <<'EOF'
+  ---
+  type: bug
+  priority: 1
+  ---
+  # Login fails after password reset
+
+  Users get a 500 on the first login after a reset.
+
+  ## Acceptance Criteria
+
+  - [ ] Login works after a reset
+  EOF`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCreate,
 }
 
 var (
@@ -28,70 +64,321 @@ var (
 	createAssignee   string
 	createExtRef     string
 	createParent     string
+	createLabels     []string
+	createDue        string
+	createEstimate   string
+	createLinks      []string
+	createDeps       []string
+	createFromFile   string
+	createEdit       bool
+	createChildOf    string
+	createBlocks     bool
 )
 
 func init() {
-	createCmd.Flags().StringVarP(&createDesc, "description", "d", "", "Description text")
-	createCmd.Flags().StringVar(&createDesign, "design", "", "Design notes")
-	createCmd.Flags().StringVar(&createAcceptance, "acceptance", "", "Acceptance criteria")
-	createCmd.Flags().StringVar(&createTests, "tests", "", "Test requirements")
-	createCmd.Flags().StringVarP(&createType, "type", "t", "task", "Type (bug|feature|task|epic|chore)")
-	createCmd.Flags().IntVarP(&createPriority, "priority", "p", 2, "Priority 0-4, 0=highest")
-	createCmd.Flags().StringVarP(&createAssignee, "assignee", "a", "", "Assignee (default: git user.name)")
+	createCmd.Flags().StringVarP(&createDesc, "description", "d", "", "Description text (default: defaults.description in config.yml)")
+	createCmd.Flags().StringVar(&createDesign, "design", "", "Design notes (default: defaults.design in config.yml)")
+	createCmd.Flags().StringVar(&createAcceptance, "acceptance", "", "Acceptance criteria (default: defaults.acceptance in config.yml)")
+	createCmd.Flags().StringVar(&createTests, "tests", "", "Test requirements (default: defaults.tests in config.yml)")
+	createCmd.Flags().StringVarP(&createType, "type", "t", "", "Type (bug|feature|task|epic|chore) (default: defaults.type in config.yml, else task)")
+	createCmd.Flags().IntVarP(&createPriority, "priority", "p", 2, "Priority 0-4, 0=highest (default: defaults.priority in config.yml, else 2)")
+	createCmd.Flags().StringVarP(&createAssignee, "assignee", "a", "", "Assignee (default: KTICKET_ASSIGNEE, else defaults.assignee in config.yml, else git user.name)")
 	createCmd.Flags().StringVar(&createExtRef, "external-ref", "", "External reference (e.g., gh-123)")
 	createCmd.Flags().StringVar(&createParent, "parent", "", "Parent ticket ID")
+	createCmd.Flags().StringVar(&createChildOf, "child-of", "", "Parent ticket to inherit priority and assignee from, unless given")
+	createCmd.Flags().BoolVar(&createBlocks, "blocks-parent", false, "Add the new ticket to its parent's deps, so the parent waits for it")
+	createCmd.Flags().StringSliceVarP(&createLabels, "label", "l", nil, "Labels (repeatable or comma-separated)")
+	createCmd.Flags().StringVar(&createDue, "due", "", "Due date (YYYY-MM-DD)")
+	createCmd.Flags().StringVar(&createEstimate, "estimate", "", "Estimate, a non-negative number (e.g. 2.5)")
+	createCmd.Flags().StringSliceVar(&createDeps, "deps", nil, "Tickets the new one depends on (repeatable or comma-separated)")
+	createCmd.Flags().StringSliceVar(&createLinks, "link", nil, "Link to these tickets, as kt link add does (repeatable)")
+	createCmd.Flags().StringVar(&createFromFile, "from-file", "", "Read the ticket from a markdown file (\"-\" for stdin)")
+	createCmd.Flags().BoolVarP(&createEdit, "edit", "e", false, "Open the new ticket in the editor before finishing")
 
 	rootCmd.AddCommand(createCmd)
 }
 
 func runCreate(cmd *cobra.Command, args []string) error {
-	var title string
+	t := &ticket.Ticket{}
+	var fileKeys []string
+	if createFromFile != "" {
+		text, err := readTextInput(cmd, nil, createFromFile)
+		if err != nil {
+			return err
+		}
+		if t, fileKeys, err = ticket.ParseDraft([]byte(text)); err != nil {
+			return fmt.Errorf("%s: %w", createFromFile, err)
+		}
+	}
 	if len(args) > 0 {
-		title = args[0]
+		t.Title = args[0]
 	}
-	if title == "" {
+	if t.Title == "" && !createEdit {
+		if createFromFile != "" {
+			return fmt.Errorf("title is required (a \"# Title\" line in %s, or an argument)", createFromFile)
+		}
 		return fmt.Errorf("title is required")
 	}
 
-	id, err := store.GenerateID()
-	if err != nil {
-		return fmt.Errorf("generate ID: %w", err)
+	for _, f := range []struct{ name, value string }{
+		{"labels", strings.Join(createLabels, ",")}, {"due", createDue}, {"estimate", createEstimate},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if err := t.SetField(f.name, f.value); err != nil {
+			return err
+		}
+	}
+	if len(createLinks) > 0 {
+		t.Links = slices.Clone(createLinks)
+	}
+	if len(createDeps) > 0 {
+		t.Deps = slices.Clone(createDeps)
+	}
+
+	var err error
+	var epic *ticket.Ticket // the --child-of parent
+	switch {
+	case createChildOf != "" && createParent != "":
+		return fmt.Errorf("--child-of and --parent cannot be used together")
+	case createChildOf != "":
+		if epic, err = Store.Resolve(createChildOf); err != nil {
+			return fmt.Errorf("child-of: %w", err)
+		}
+		t.Parent = epic.ID
+	default:
+		if t.Parent, err = resolveParent(cmp.Or(createParent, t.Parent)); err != nil {
+			return fmt.Errorf("parent: %w", err)
+		}
+	}
+	if createBlocks && t.Parent == "" {
+		return fmt.Errorf("--blocks-parent needs a parent (--child-of or --parent)")
 	}
+	if createBlocks {
+		// Checked again when saving; this spares an --edit session
+		if parent, err := Store.Get(t.Parent); err == nil {
+			if err := checkBlockable(parent); err != nil {
+				return err
+			}
+		}
+	}
+	deps := t.Deps
+	t.Deps = nil
+	for _, dep := range deps {
+		id, err := resolveID(dep)
+		if err != nil {
+			return fmt.Errorf("deps: %w", err)
+		}
+		if !slices.Contains(t.Deps, id) {
+			t.Deps = append(t.Deps, id)
+		}
+	}
+	links := t.Links
+	t.Links = nil
+	for _, link := range links {
+		id, err := resolveID(link)
+		if err != nil {
+			return fmt.Errorf("links: %w", err)
+		}
+		t.AddLink(ticket.LinkRelatesTo, id)
+	}
+
+	t.Status = ticket.StatusOpen
+	t.Created = time.Now().UTC().Format(time.RFC3339)
 
-	assignee := createAssignee
-	if assignee == "" {
-		assignee = getGitUser()
+	t.Type = cmp.Or(ticket.Type(createType), t.Type, defaultType())
+	switch {
+	case cmd == nil || cmd.Flags().Changed("priority"):
+		t.Priority = createPriority
+	case slices.Contains(fileKeys, "priority"):
+		// as the file gives it
+	case epic != nil:
+		t.Priority = epic.Priority
+	default:
+		t.Priority = defaultPriority()
 	}
+	t.Assignee = cmp.Or(createAssignee, t.Assignee)
+	if t.Assignee == "" && epic != nil {
+		t.Assignee = epic.Assignee
+	}
+	if t.Assignee == "" {
+		t.Assignee = defaultAssignee()
+	}
+	t.ExternalRef = cmp.Or(createExtRef, t.ExternalRef)
+	t.Description = cmp.Or(createDesc, t.Description)
+	t.Design = cmp.Or(createDesign, t.Design)
+	t.AcceptanceCriteria = cmp.Or(createAcceptance, t.AcceptanceCriteria)
+	t.Tests = cmp.Or(createTests, t.Tests)
 
-	t := &ticket.Ticket{
-		ID:                 id,
-		Status:             ticket.StatusOpen,
-		Created:            time.Now().UTC().Format(time.RFC3339),
-		Type:               ticket.Type(createType),
-		Priority:           createPriority,
-		Assignee:           assignee,
-		ExternalRef:        createExtRef,
-		Parent:             createParent,
-		TestsPassed:        false,
-		Title:              title,
-		Description:        createDesc,
-		Design:             createDesign,
-		AcceptanceCriteria: createAcceptance,
-		Tests:              createTests,
+	applyTemplates(t)
+	if !createEdit { // checked once edited
+		if err := checkRequired(t); err != nil {
+			return err
+		}
+	}
+	if err := checkParentRule(Store, t); err != nil {
+		return err
 	}
 
-	if err := Store.Save(t); err != nil {
-		return fmt.Errorf("save ticket: %w", err)
+	// The ID comes last, once the checks pass: it stays reserved until the
+	// ticket is saved or Unreserve gives it up
+	if t.ID, err = newTicketID(Store); err != nil {
+		return fmt.Errorf("generate ID: %w", err)
+	}
+	id := t.ID
+	if createEdit {
+		t, err = editNewTicket(t, createBlocks)
+	} else if err = saveNew(t, createBlocks); err != nil {
+		err = fmt.Errorf("save ticket: %w", err)
+	}
+	if err != nil {
+		Store.Unreserve(id)
+		return err
 	}
 
 	if IsJSON() {
 		return PrintJSON(t)
 	}
 
-	fmt.Println(id)
+	fmt.Println(t.ID)
+	return nil
+}
+
+// saveNew saves a new ticket and, in the same transaction, the links back
+// to it from the tickets it links to and, with blockParent, its parent's
+// dependency on it.
+func saveNew(t *ticket.Ticket, blockParent bool) error {
+	if len(t.Links) == 0 && !blockParent {
+		return Store.Save(t)
+	}
+	return Store.Transaction(func(tx *store.Tx) error {
+		tx.Save(t)
+		if blockParent {
+			parent, err := tx.Get(t.Parent)
+			if err != nil {
+				return fmt.Errorf("parent: %w", err)
+			}
+			if err := checkBlockable(parent); err != nil {
+				return err
+			}
+			parent.Deps = append(parent.Deps, t.ID)
+			tx.Save(parent)
+
+			// Only the parent depends on the new ticket, so a cycle has to go through it
+			byID := make(map[string]*ticket.Ticket)
+			for _, o := range tx.List() {
+				byID[o.ID] = o
+			}
+			for _, dep := range t.Deps {
+				if dependsOn(byID, dep, t.ID) {
+					return fmt.Errorf("adding %s would create a dependency cycle", dep)
+				}
+			}
+		}
+		return linkBack(tx, t)
+	})
+}
+
+// linkBack adds a link back to t to each ticket t links to, as links are
+// symmetric (see kt link add).
+func linkBack(tx *store.Tx, t *ticket.Ticket) error {
+	for _, id := range t.Links {
+		other, err := tx.Get(id)
+		if err != nil {
+			return err
+		}
+		if other.AddLink(ticket.LinkRelatesTo, t.ID) {
+			tx.Save(other)
+		}
+	}
+	return nil
+}
+
+// checkBlockable refuses to make a closed parent depend on a new ticket.
+func checkBlockable(parent *ticket.Ticket) error {
+	if parent.Status == ticket.StatusClosed {
+		return fmt.Errorf("--blocks-parent: parent %s is closed", parent.ID)
+	}
 	return nil
 }
 
+// editNewTicket opens a new ticket in the editor and returns it as saved,
+// with the links back to it and, with blockParent, its parent's dependency
+// on it. Those are written only once the edit succeeds, so a ticket that
+// does not end up valid can be deleted again.
+func editNewTicket(t *ticket.Ticket, blockParent bool) (*ticket.Ticket, error) {
+	skeleton, err := ticket.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	for _, section := range []struct{ heading, value string }{
+		{"Design", t.Design}, {"Acceptance Criteria", t.AcceptanceCriteria}, {"Tests", t.Tests},
+	} {
+		if section.value == "" {
+			skeleton = fmt.Appendf(skeleton, "\n## %s\n\n", section.heading)
+		}
+	}
+
+	err = ticket.WriteRaw(Store.Path(t.ID), skeleton)
+	if err == nil {
+		err = editTicket(t.ID, func(edited *ticket.Ticket) error {
+			if edited.Title == "" {
+				return fmt.Errorf("title is required")
+			}
+			if blockParent && edited.Parent == "" {
+				return fmt.Errorf("--blocks-parent needs a parent")
+			}
+			// As a ticket not yet saved, since its file already holds the edit
+			unsaved := *edited
+			unsaved.ID = ""
+			if err := checkParentRule(Store, &unsaved); err != nil {
+				return err
+			}
+			return checkRequired(edited)
+		}, "discard the ticket")
+	}
+	var edited *ticket.Ticket
+	if err == nil {
+		edited, err = Store.Get(t.ID)
+	}
+	if err == nil {
+		// Saving again also drops the section headings left empty
+		err = saveNew(edited, blockParent)
+	}
+	if err != nil {
+		if derr := Store.Delete(t.ID); derr != nil {
+			return nil, errors.Join(err, derr)
+		}
+		return nil, fmt.Errorf("%s not created: %w", t.ID, err)
+	}
+	return edited, nil
+}
+
+// resolveParent resolves a new ticket's parent to a full ID. Unless
+// epic_parents is set, a parent that does not exist is kept as given, with
+// a warning, for kt fsck to report later.
+func resolveParent(partial string) (string, error) {
+	id, err := resolveID(partial)
+	if errors.Is(err, store.ErrNotFound) && !projectConfig().EpicParents {
+		Warnf("parent %s not found", partial)
+		return partial, nil
+	}
+	return id, err
+}
+
+// resolveID resolves a partial ticket ID to a full one, leaving "" as is.
+func resolveID(partial string) (string, error) {
+	if partial == "" {
+		return "", nil
+	}
+	t, err := Store.Resolve(partial)
+	if err != nil {
+		return "", err
+	}
+	return t.ID, nil
+}
+
 func getGitUser() string {
 	out, err := exec.Command("git", "config", "user.name").Output()
 	if err != nil {
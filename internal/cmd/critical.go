@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var criticalPathCmd = &cobra.Command{
+	Use:   "critical-path <epic-or-id>",
+	Short: "Show the longest dependency chain to completion",
+	Long: `Show the longest chain of open tickets that must be done before
+<epic-or-id> is complete. For an epic the scope is all its descendants
+and their deps; for any other ticket it is the ticket and its transitive
+deps.
+
+Tickets are weighted by their estimate (kt set <id> estimate=3), or count
+as 1 when no estimate is set. Also lists the open tickets that gate the
+most downstream work.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCriticalPath,
+}
+
+// maxGates is the number of gating tickets listed.
+const maxGates = 5
+
+func init() {
+	rootCmd.AddCommand(criticalPathCmd)
+}
+
+type pathStep struct {
+	ID     string        `json:"id"`
+	Status ticket.Status `json:"status"`
+	Title  string        `json:"title"`
+	Weight float64       `json:"weight"`
+}
+
+type gateInfo struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Downstream int    `json:"downstream"`
+}
+
+type criticalPathResult struct {
+	Root   string     `json:"root"`
+	Unit   string     `json:"unit"` // "estimate" or "tickets"
+	Length float64    `json:"length"`
+	Path   []pathStep `json:"path"`
+	Gates  []gateInfo `json:"gates,omitempty"`
+}
+
+func runCriticalPath(cmd *cobra.Command, args []string) error {
+	root, err := Store.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+
+	result := criticalPath(tickets, root.ID)
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+
+	if len(result.Path) == 0 {
+		fmt.Printf("Nothing left to do for %s\n", result.Root)
+		return nil
+	}
+
+	fmt.Printf("Critical path for %s (%s %s):\n", result.Root, formatWeight(result.Length), result.Unit)
+	for i, s := range result.Path {
+		fmt.Printf("  %d. %s [%s] %s (%s)\n", i+1, s.ID, s.Status, s.Title, formatWeight(s.Weight))
+	}
+	if len(result.Gates) > 0 {
+		fmt.Println("\nGating tickets:")
+		for _, g := range result.Gates {
+			fmt.Printf("  %s %s (gates %d)\n", g.ID, g.Title, g.Downstream)
+		}
+	}
+	return nil
+}
+
+// criticalPath computes the longest weighted chain of open tickets in
+// rootID's scope, in execution order, plus the tickets gating the most work.
+func criticalPath(tickets []*ticket.Ticket, rootID string) *criticalPathResult {
+	byID := make(map[string]*ticket.Ticket, len(tickets))
+	children := make(map[string][]string)
+	for _, t := range tickets {
+		byID[t.ID] = t
+		if t.Parent != "" {
+			children[t.Parent] = append(children[t.Parent], t.ID)
+		}
+	}
+
+	// Scope: root, its descendants, and everything they depend on
+	scope := make(map[string]bool)
+	stack := []string{rootID}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		t, ok := byID[id]
+		if !ok || scope[id] {
+			continue
+		}
+		scope[id] = true
+		stack = append(stack, t.Deps...)
+		stack = append(stack, children[id]...)
+	}
+
+	// Only open work counts; closed tickets drop out of the graph, as does
+	// an epic root, which is a container rather than a unit of work
+	if len(children[rootID]) > 0 {
+		delete(scope, rootID)
+	}
+	var open []string
+	useEstimates := false
+	for id := range scope {
+		if byID[id].Status == ticket.StatusClosed {
+			continue
+		}
+		open = append(open, id)
+		if byID[id].Estimate > 0 {
+			useEstimates = true
+		}
+	}
+	sort.Strings(open)
+
+	weight := func(id string) float64 {
+		if useEstimates {
+			return byID[id].Estimate
+		}
+		return 1
+	}
+	isOpen := func(id string) bool {
+		t, ok := byID[id]
+		return ok && scope[id] && t.Status != ticket.StatusClosed
+	}
+
+	// longest[id] is the heaviest chain ending at id; next points at its
+	// predecessor. visiting guards against cycles in the deps data.
+	longest := make(map[string]float64)
+	next := make(map[string]string)
+	visiting := make(map[string]bool)
+	var visit func(id string) float64
+	visit = func(id string) float64 {
+		if l, ok := longest[id]; ok {
+			return l
+		}
+		if visiting[id] {
+			return 0
+		}
+		visiting[id] = true
+		best, bestDep := 0.0, ""
+		for _, d := range byID[id].Deps {
+			if !isOpen(d) {
+				continue
+			}
+			if l := visit(d); l > best || bestDep == "" {
+				best, bestDep = l, d
+			}
+		}
+		delete(visiting, id)
+		longest[id] = weight(id) + best
+		if bestDep != "" {
+			next[id] = bestDep
+		}
+		return longest[id]
+	}
+
+	end := ""
+	for _, id := range open {
+		if l := visit(id); end == "" || l > longest[end] {
+			end = id
+		}
+	}
+
+	result := &criticalPathResult{Root: rootID, Unit: "tickets", Path: []pathStep{}}
+	if useEstimates {
+		result.Unit = "estimate"
+	}
+	if end == "" {
+		return result
+	}
+	result.Length = longest[end]
+
+	seen := make(map[string]bool)
+	for id := end; id != "" && !seen[id]; id = next[id] {
+		seen[id] = true
+		t := byID[id]
+		result.Path = append(result.Path, pathStep{ID: t.ID, Status: t.Status, Title: t.Title, Weight: weight(id)})
+	}
+	for i, j := 0, len(result.Path)-1; i < j; i, j = i+1, j-1 {
+		result.Path[i], result.Path[j] = result.Path[j], result.Path[i]
+	}
+
+	// Gates: open tickets ranked by how many open tickets transitively wait on them
+	dependents := make(map[string][]string)
+	for _, id := range open {
+		for _, d := range byID[id].Deps {
+			if isOpen(d) {
+				dependents[d] = append(dependents[d], id)
+			}
+		}
+	}
+	for _, id := range open {
+		n := countReachable(dependents, id)
+		if n > 0 {
+			result.Gates = append(result.Gates, gateInfo{ID: id, Title: byID[id].Title, Downstream: n})
+		}
+	}
+	sort.SliceStable(result.Gates, func(i, j int) bool {
+		return result.Gates[i].Downstream > result.Gates[j].Downstream
+	})
+	if len(result.Gates) > maxGates {
+		result.Gates = result.Gates[:maxGates]
+	}
+	return result
+}
+
+// countReachable counts nodes reachable from id through edges, excluding id.
+func countReachable(edges map[string][]string, id string) int {
+	seen := map[string]bool{id: true}
+	stack := append([]string(nil), edges[id]...)
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		stack = append(stack, edges[n]...)
+	}
+	return len(seen) - 1
+}
+
+func formatWeight(w float64) string {
+	return strconv.FormatFloat(w, 'g', -1, 64)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pathIDs(r *criticalPathResult) []string {
+	ids := make([]string, len(r.Path))
+	for i, s := range r.Path {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+func TestCriticalPathCounts(t *testing.T) {
+	// d ← c ← b ← a, and e ← a; x is done
+	tickets := []*ticket.Ticket{
+		{ID: "kt-a", Status: ticket.StatusOpen, Deps: []string{"kt-b", "kt-e"}},
+		{ID: "kt-b", Status: ticket.StatusOpen, Deps: []string{"kt-c"}},
+		{ID: "kt-c", Status: ticket.StatusOpen, Deps: []string{"kt-d", "kt-x"}},
+		{ID: "kt-d", Status: ticket.StatusOpen},
+		{ID: "kt-e", Status: ticket.StatusOpen},
+		{ID: "kt-x", Status: ticket.StatusClosed},
+	}
+
+	r := criticalPath(tickets, "kt-a")
+	assert.Equal(t, "tickets", r.Unit)
+	assert.Equal(t, 4.0, r.Length)
+	assert.Equal(t, []string{"kt-d", "kt-c", "kt-b", "kt-a"}, pathIDs(r))
+	require.NotEmpty(t, r.Gates)
+	assert.Equal(t, "kt-d", r.Gates[0].ID)
+	assert.Equal(t, 3, r.Gates[0].Downstream)
+}
+
+func TestCriticalPathEstimatesEpic(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "kt-epic", Status: ticket.StatusOpen, Type: ticket.TypeEpic},
+		{ID: "kt-a", Status: ticket.StatusOpen, Parent: "kt-epic", Estimate: 1, Deps: []string{"kt-b"}},
+		{ID: "kt-b", Status: ticket.StatusOpen, Parent: "kt-epic", Estimate: 1},
+		{ID: "kt-c", Status: ticket.StatusOpen, Parent: "kt-epic", Estimate: 5},
+	}
+
+	r := criticalPath(tickets, "kt-epic")
+	assert.Equal(t, "estimate", r.Unit)
+	assert.Equal(t, 5.0, r.Length)
+	assert.Equal(t, []string{"kt-c"}, pathIDs(r))
+}
+
+func TestCriticalPathCycleSafe(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "kt-a", Status: ticket.StatusOpen, Deps: []string{"kt-b"}},
+		{ID: "kt-b", Status: ticket.StatusOpen, Deps: []string{"kt-a"}},
+	}
+	r := criticalPath(tickets, "kt-a")
+	assert.NotEmpty(t, r.Path)
+}
+
+func TestRunCriticalPath(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	a.Deps = []string{b.ID}
+	require.NoError(t, Store.Save(a))
+
+	require.NoError(t, runCriticalPath(nil, []string{a.ID}))
+
+	a.Status, b.Status = ticket.StatusClosed, ticket.StatusClosed
+	require.NoError(t, Store.Save(a))
+	require.NoError(t, Store.Save(b))
+	require.NoError(t, runCriticalPath(nil, []string{a.ID}))
+
+	require.Error(t, runCriticalPath(nil, []string{"kt-missing"}))
+}
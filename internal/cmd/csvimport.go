@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var importCSVCmd = &cobra.Command{
+	Use:   "csv <file.csv>",
+	Short: "Import a spreadsheet as tickets, one per row",
+	Long: `Import a CSV file, creating one ticket per row. Columns named after a
+ticket field (Title, Priority, External-Ref, ...) are used automatically;
+--map assigns fields to other columns. A title column is required.
+
+Fields: ` + strings.Join(csvFields, ", ") + `
+
+Rows whose external-ref was already imported are skipped. Priorities may be
+written as 0-4 or P0-P4; labels are comma-separated.
+
+  kt import csv backlog.csv --map title=Summary,priority=Prio,labels=Tags`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportCSV,
+}
+
+var importMap string
+
+// csvFields are the ticket fields a CSV column can be mapped to.
+var csvFields = []string{"title", "description", "design", "acceptance", "tests", "type",
+	"priority", "status", "assignee", "labels", "external-ref", "parent", "due", "estimate", "created"}
+
+func init() {
+	importCSVCmd.Flags().StringVar(&importMap, "map", "", "Field=Column mappings, e.g. title=Summary,priority=Prio")
+	importCmd.AddCommand(importCSVCmd)
+}
+
+func runImportCSV(cmd *cobra.Command, args []string) error {
+	mapping, err := parseCSVMap(importMap)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tickets, err := readCSV(f, mapping)
+	if err != nil {
+		return err
+	}
+	result, err := importTickets(tickets)
+	if err != nil {
+		return err
+	}
+	return printImport(result)
+}
+
+// csvField canonicalizes a field or column name: lowercase, with spaces and
+// underscores as dashes.
+func csvField(name string) string {
+	name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	return strings.NewReplacer(" ", "-", "_", "-").Replace(name)
+}
+
+// parseCSVMap parses field=Column mappings into a field → column map.
+func parseCSVMap(s string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, rule := range strings.Split(s, ",") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+		field, column, ok := strings.Cut(rule, "=")
+		field, column = csvField(field), strings.TrimSpace(column)
+		if !ok || column == "" {
+			return nil, fmt.Errorf("invalid --map rule %q (expected field=Column)", rule)
+		}
+		if !slices.Contains(csvFields, field) {
+			return nil, fmt.Errorf("invalid --map field %q (expected one of %s)", field, strings.Join(csvFields, ", "))
+		}
+		mapping[field] = column
+	}
+	return mapping, nil
+}
+
+// readCSV converts CSV rows to tickets (without IDs). mapping assigns fields
+// to column names; unmapped fields use the column with the field's name.
+// Any invalid value fails the whole file, so nothing is half-imported.
+func readCSV(r io.Reader, mapping map[string]string) ([]*ticket.Ticket, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+
+	byName := make(map[string]int, len(header))
+	for i, name := range header {
+		if _, ok := byName[csvField(name)]; !ok {
+			byName[csvField(name)] = i
+		}
+	}
+	cols := make(map[string]int)
+	for _, field := range csvFields {
+		column, ok := mapping[field]
+		if !ok {
+			column = field
+		}
+		i, ok := byName[csvField(column)]
+		if !ok {
+			if mapping[field] != "" {
+				return nil, fmt.Errorf("no column %q for field %s", column, field)
+			}
+			continue
+		}
+		cols[field] = i
+	}
+	if _, ok := cols["title"]; !ok {
+		return nil, fmt.Errorf("no title column (use --map title=<column>)")
+	}
+
+	var tickets []*ticket.Ticket
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read CSV: %w", err)
+		}
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		t, err := csvTicket(row, cols)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", line, err)
+		}
+		tickets = append(tickets, t)
+	}
+	return tickets, nil
+}
+
+// csvTicket builds a ticket from one row, given each field's column.
+func csvTicket(row []string, cols map[string]int) (*ticket.Ticket, error) {
+	t := &ticket.Ticket{
+		Status:   ticket.StatusOpen,
+		Type:     ticket.TypeTask,
+		Priority: 2,
+		Created:  time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, field := range csvFields {
+		i, ok := cols[field]
+		if !ok || i >= len(row) {
+			continue
+		}
+		value := strings.TrimSpace(strings.ReplaceAll(row[i], "\r\n", "\n"))
+		if value == "" && field != "title" {
+			continue
+		}
+		switch field {
+		case "created":
+			if t.Created = jiraTime(value); t.Created == "" {
+				return nil, fmt.Errorf("invalid created date %q", value)
+			}
+			continue
+		case "priority":
+			value = strings.TrimPrefix(strings.ToUpper(value), "P")
+		case "type":
+			value = strings.ToLower(value)
+		case "status":
+			value = strings.ReplaceAll(strings.ToLower(value), " ", "_")
+		}
+		if err := t.SetField(field, value); err != nil {
+			return nil, err
+		}
+	}
+	if t.Title == "" {
+		return nil, fmt.Errorf("title cannot be empty")
+	}
+	return t, nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunImportCSV(t *testing.T) {
+	defer setupTestEnv(t)()
+	path := filepath.Join(t.TempDir(), "backlog.csv")
+	require.NoError(t, os.WriteFile(path, []byte("Summary,Prio,Type,Status,Tags,External Ref,Notes\n"+
+		"Login fails,P1,Bug,In Progress,\"auth, web\",ROW-1,ignored\n"+
+		",,,,,,\n"+
+		"Dark mode,3,,,,,\n"), 0644))
+
+	importMap = "title=Summary,priority=Prio,labels=Tags"
+	defer func() { importMap = "" }()
+	require.NoError(t, runImportCSV(nil, []string{path}))
+
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	require.Len(t, tickets, 2)
+	byTitle := map[string]*ticket.Ticket{}
+	for _, tk := range tickets {
+		byTitle[tk.Title] = tk
+	}
+
+	bug := byTitle["Login fails"]
+	require.NotNil(t, bug)
+	assert.Equal(t, 1, bug.Priority)
+	assert.Equal(t, ticket.TypeBug, bug.Type)
+	assert.Equal(t, ticket.StatusInProgress, bug.Status)
+	assert.Equal(t, []string{"auth", "web"}, bug.Labels)
+	assert.Equal(t, "ROW-1", bug.ExternalRef)
+
+	plain := byTitle["Dark mode"]
+	require.NotNil(t, plain)
+	assert.Equal(t, 3, plain.Priority)
+	assert.Equal(t, ticket.TypeTask, plain.Type)
+	assert.Equal(t, ticket.StatusOpen, plain.Status)
+
+	// Rows with a known external-ref are skipped; rows without one are not
+	require.NoError(t, runImportCSV(nil, []string{path}))
+	tickets, _ = Store.List()
+	assert.Len(t, tickets, 3)
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	read := func(csv, mapping string) error {
+		m, err := parseCSVMap(mapping)
+		if err != nil {
+			return err
+		}
+		_, err = readCSV(strings.NewReader(csv), m)
+		return err
+	}
+
+	assert.ErrorContains(t, read("Name\nx\n", ""), "no title column")
+	assert.ErrorContains(t, read("Title\nx\n", "title=Summary"), `no column "Summary" for field title`)
+	assert.ErrorContains(t, read("Title\nx\n", "color=Title"), `invalid --map field "color"`)
+	assert.ErrorContains(t, read("Title\nx\n", "title"), "invalid --map rule")
+	assert.ErrorContains(t, read("Title,Priority\nok,1\nbad,High\n", ""), `row 3: invalid priority "HIGH"`)
+	assert.ErrorContains(t, read("Title,Priority\n,1\n", ""), "row 2: title cannot be empty")
+	assert.NoError(t, read("\ufefftitle,due\nx,2026-02-01\n", ""))
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keep an in-memory index of the store for faster queries",
+	Long: `Run in the foreground, keeping every ticket parsed in memory and serving
+them over a unix socket in the store directory. While it runs, commands that
+list tickets (ls, ready, blocked, query, ...) ask the daemon instead of
+parsing every file, which matters for large stores. Writes still go to the
+files directly, and the daemon picks them up before answering the next
+query. The daemon also reopens recurring tickets when they come due (see
+kt recur).
+
+Without a daemon, or with ` + config.EnvNoDaemon + ` set, kt reads the files.
+
+  kt daemon &
+  kt daemon status
+  kt daemon stop`,
+	Args: cobra.NoArgs,
+	RunE: runDaemon,
+}
+
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether a daemon is serving this store",
+	Args:  cobra.NoArgs,
+	RunE:  runDaemonStatus,
+}
+
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the daemon serving this store",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return Store.StopDaemon()
+	},
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonStatusCmd)
+	daemonCmd.AddCommand(daemonStopCmd)
+	rootCmd.AddCommand(daemonCmd)
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "Serving %s on %s\n", Store.Dir, Store.DaemonSocket())
+	go recurLoop(ctx, Store.Dir)
+	return Store.ServeDaemon(ctx)
+}
+
+func runDaemonStatus(cmd *cobra.Command, args []string) error {
+	st, err := Store.DaemonStatus()
+	if err != nil {
+		return err
+	}
+	if IsJSON() {
+		return PrintJSON(st)
+	}
+	if !st.Running {
+		fmt.Println("not running")
+		return nil
+	}
+	fmt.Printf("running (pid %d) since %s: %d tickets, %d requests\n", st.PID, st.Started, st.Tickets, st.Requests)
+	return nil
+}
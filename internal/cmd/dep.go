@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/kostyay/kticket/internal/ticket"
 	"github.com/spf13/cobra"
@@ -13,9 +17,9 @@ var depCmd = &cobra.Command{
 }
 
 var depAddCmd = &cobra.Command{
-	Use:   "add <id> <dep-id>",
-	Short: "Add dependency (id depends on dep-id)",
-	Args:  cobra.ExactArgs(2),
+	Use:   "add <id> <dep-id>...",
+	Short: "Add dependencies (id depends on each dep-id)",
+	Args:  cobra.MinimumNArgs(2),
 	RunE:  runDepAdd,
 }
 
@@ -33,51 +37,125 @@ var depTreeCmd = &cobra.Command{
 	RunE:  runDepTree,
 }
 
-var depTreeFull bool
+var depDependentsCmd = &cobra.Command{
+	Use:   "dependents <id>",
+	Short: "List tickets that depend on id",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDepDependents,
+}
+
+var (
+	depTreeFull         bool
+	depTreeWithChildren bool
+	depTreeWithParent   bool
+)
 
 func init() {
 	depTreeCmd.Flags().BoolVar(&depTreeFull, "full", false, "Disable deduplication")
+	depTreeCmd.Flags().BoolVar(&depTreeWithChildren, "with-children", false, "Include child tickets of each node")
+	depTreeCmd.Flags().BoolVar(&depTreeWithParent, "with-parent", false, "Include the parent of each node")
 
 	depCmd.AddCommand(depAddCmd)
 	depCmd.AddCommand(depRmCmd)
 	depCmd.AddCommand(depTreeCmd)
+	depCmd.AddCommand(depDependentsCmd)
 	rootCmd.AddCommand(depCmd)
 }
 
+type depAddResult struct {
+	ID     string        `json:"id"`
+	Added  []string      `json:"added,omitempty"`
+	Errors []statusError `json:"errors,omitempty"`
+}
+
 func runDepAdd(cmd *cobra.Command, args []string) error {
-	// Resolve dep ticket first (read-only) to validate it exists
-	depTicket, err := Store.Resolve(args[1])
+	tickets, err := Store.List()
 	if err != nil {
 		return err
 	}
+	byID := make(map[string]*ticket.Ticket, len(tickets))
+	for _, t := range tickets {
+		byID[t.ID] = t
+	}
+
+	// Resolve dep tickets first (read-only) to validate they exist
+	result := depAddResult{}
+	var depIDs []string
+	for _, id := range args[1:] {
+		dep, err := Store.Resolve(id)
+		if err != nil {
+			result.Errors = append(result.Errors, statusError{ID: id, Error: err.Error()})
+			continue
+		}
+		depIDs = append(depIDs, dep.ID)
+	}
 
 	// Lock the ticket we're modifying
 	lt, err := Store.ResolveForUpdate(args[0])
 	if err != nil {
 		return err
 	}
+	result.ID = lt.Ticket.ID
+
+	for _, depID := range depIDs {
+		switch {
+		case slices.Contains(lt.Ticket.Deps, depID):
+			result.Errors = append(result.Errors, statusError{ID: depID,
+				Error: fmt.Sprintf("%s already depends on %s", lt.Ticket.ID, depID)})
+		case depID == lt.Ticket.ID || dependsOn(byID, depID, lt.Ticket.ID):
+			result.Errors = append(result.Errors, statusError{ID: depID,
+				Error: fmt.Sprintf("adding %s would create a dependency cycle", depID)})
+		default:
+			lt.Ticket.Deps = append(lt.Ticket.Deps, depID)
+			result.Added = append(result.Added, depID)
+		}
+	}
 
-	// Check if already exists
-	for _, d := range lt.Ticket.Deps {
-		if d == depTicket.ID {
-			lt.Release()
-			return fmt.Errorf("%s already depends on %s", lt.Ticket.ID, depTicket.ID)
+	if len(result.Added) == 0 {
+		lt.Release()
+		errs := make([]error, len(result.Errors))
+		for i, e := range result.Errors {
+			errs[i] = errors.New(e.Error)
 		}
+		return errors.Join(errs...)
 	}
 
-	lt.Ticket.Deps = append(lt.Ticket.Deps, depTicket.ID)
 	if err := lt.SaveAndRelease(); err != nil {
 		return err
 	}
 
 	if IsJSON() {
-		return PrintJSON(lt.Ticket)
+		return PrintJSON(result)
 	}
 
-	fmt.Printf("%s now depends on %s\n", lt.Ticket.ID, depTicket.ID)
+	fmt.Printf("%s now depends on %s\n", result.ID, strings.Join(result.Added, ", "))
+	for _, e := range result.Errors {
+		Errorf("%s: %s", e.ID, e.Error)
+	}
 	return nil
 }
 
+// dependsOn reports whether from transitively depends on target.
+func dependsOn(byID map[string]*ticket.Ticket, from, target string) bool {
+	seen := make(map[string]bool)
+	stack := []string{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == target {
+			return true
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if t, ok := byID[id]; ok {
+			stack = append(stack, t.Deps...)
+		}
+	}
+	return false
+}
+
 func runDepRm(cmd *cobra.Command, args []string) error {
 	// Resolve dep ticket first (read-only) to validate it exists
 	depTicket, err := Store.Resolve(args[1])
@@ -120,10 +198,59 @@ func runDepRm(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runDepDependents(cmd *cobra.Command, args []string) error {
+	t, err := Store.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*ticket.Ticket, len(tickets))
+	for _, t := range tickets {
+		byID[t.ID] = t
+	}
+	dependents := make([]*ticket.Ticket, 0)
+	for _, id := range dependentIndex(tickets)[t.ID] {
+		dependents = append(dependents, byID[id])
+	}
+
+	if IsJSON() {
+		return PrintJSON(dependents)
+	}
+
+	if IsPlain() {
+		for _, d := range dependents {
+			fmt.Printf("%s [%s] %s\n", d.ID, d.Status, d.Title)
+		}
+		return nil
+	}
+
+	for _, d := range dependents {
+		fmt.Printf("%-12s [%-11s] %s\n", d.ID, d.Status, truncate(d.Title, 50))
+	}
+	return nil
+}
+
+// dependentIndex maps each ticket ID to the IDs of the tickets whose deps
+// include it.
+func dependentIndex(tickets []*ticket.Ticket) map[string][]string {
+	index := make(map[string][]string)
+	for _, t := range tickets {
+		for _, dep := range t.Deps {
+			index[dep] = append(index[dep], t.ID)
+		}
+	}
+	return index
+}
+
 type depTreeNode struct {
 	ID       string         `json:"id"`
 	Status   ticket.Status  `json:"status"`
 	Title    string         `json:"title"`
+	Relation string         `json:"relation,omitempty"` // set for typed links, e.g. "blocked-by"
 	Children []*depTreeNode `json:"children,omitempty"`
 }
 
@@ -133,8 +260,28 @@ func runDepTree(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	seen := make(map[string]bool)
-	tree := buildDepTree(t, seen, depTreeFull)
+	b := &depTreeBuilder{
+		full:       depTreeFull,
+		withParent: depTreeWithParent,
+		seen:       make(map[string]bool),
+		path:       make(map[string]bool),
+	}
+	if depTreeWithChildren {
+		tickets, err := Store.List()
+		if err != nil {
+			return err
+		}
+		b.children = make(map[string][]string)
+		for _, c := range tickets {
+			if c.Parent != "" {
+				b.children[c.Parent] = append(b.children[c.Parent], c.ID)
+			}
+		}
+		for _, ids := range b.children {
+			sort.Strings(ids)
+		}
+	}
+	tree := b.build(t)
 
 	if IsJSON() {
 		return PrintJSON(tree)
@@ -144,30 +291,68 @@ func runDepTree(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// depTreeBuilder expands a ticket into its deps, blocked-by links and,
+// optionally, its parent and children.
+type depTreeBuilder struct {
+	full       bool
+	withParent bool
+	children   map[string][]string // parent ID → child IDs; nil unless --with-children
+	seen       map[string]bool     // expanded anywhere (dedup)
+	path       map[string]bool     // expanded on the current branch (cycle guard)
+}
+
 func buildDepTree(t *ticket.Ticket, seen map[string]bool, full bool) *depTreeNode {
+	b := &depTreeBuilder{full: full, seen: seen, path: make(map[string]bool)}
+	return b.build(t)
+}
+
+func (b *depTreeBuilder) build(t *ticket.Ticket) *depTreeNode {
 	node := &depTreeNode{
 		ID:     t.ID,
 		Status: t.Status,
 		Title:  t.Title,
 	}
 
-	if !full && seen[t.ID] {
+	// Cycles are cut even with --full, which parent/child edges make common
+	if b.path[t.ID] || (!b.full && b.seen[t.ID]) {
 		return node
 	}
-	seen[t.ID] = true
+	b.seen[t.ID] = true
+	b.path[t.ID] = true
+	defer delete(b.path, t.ID)
 
+	// Deps first, then tickets this one is blocked by through typed links
+	var children []ticket.TypedLink
 	for _, depID := range t.Deps {
-		dep, err := Store.Get(depID)
+		children = append(children, ticket.TypedLink{ID: depID})
+	}
+	for _, id := range t.Relations[ticket.LinkBlockedBy] {
+		if !slices.Contains(t.Deps, id) {
+			children = append(children, ticket.TypedLink{Type: ticket.LinkBlockedBy, ID: id})
+		}
+	}
+	if b.withParent && t.Parent != "" {
+		children = append(children, ticket.TypedLink{Type: "parent", ID: t.Parent})
+	}
+	for _, id := range b.children[t.ID] {
+		children = append(children, ticket.TypedLink{Type: "child", ID: id})
+	}
+
+	for _, c := range children {
+		dep, err := Store.Get(c.ID)
 		if err != nil {
 			// Dependency not found, add placeholder
 			node.Children = append(node.Children, &depTreeNode{
-				ID:     depID,
-				Status: "unknown",
-				Title:  "(not found)",
+				ID:       c.ID,
+				Status:   "unknown",
+				Title:    "(not found)",
+				Relation: string(c.Type),
 			})
 			continue
 		}
-		node.Children = append(node.Children, buildDepTree(dep, seen, full))
+		child := b.build(dep)
+		child.Relation = string(c.Type)
+		node.Children = append(node.Children, child)
 	}
 
 	return node
@@ -181,9 +366,13 @@ func printDepTree(node *depTreeNode, prefix string, isLast bool) {
 	}
 	if prefix == "" {
 		// Root node
-		fmt.Printf("%s [%s] %s\n", node.ID, node.Status, node.Title)
+		fmt.Printf("%s [%s] %s\n", node.ID, paintStatus(node.Status, string(node.Status)), node.Title)
 	} else {
-		fmt.Printf("%s%s%s [%s] %s\n", prefix, connector, node.ID, node.Status, node.Title)
+		relation := ""
+		if node.Relation != "" {
+			relation = " (" + node.Relation + ")"
+		}
+		fmt.Printf("%s%s%s [%s] %s%s\n", prefix, connector, node.ID, paintStatus(node.Status, string(node.Status)), node.Title, relation)
 	}
 
 	// Print children
@@ -216,6 +405,18 @@ func hasUnresolvedDeps(t *ticket.Ticket) bool {
 	return false
 }
 
+// unresolvedDeps returns the deps of t that are not closed (or not found).
+func unresolvedDeps(t *ticket.Ticket) []string {
+	var open []string
+	for _, depID := range t.Deps {
+		dep, err := Store.Get(depID)
+		if err != nil || dep.Status != ticket.StatusClosed {
+			open = append(open, depID)
+		}
+	}
+	return open
+}
+
 // Helper to check if any dependencies exist and are all resolved
 func allDepsResolved(t *ticket.Ticket) bool {
 	if len(t.Deps) == 0 {
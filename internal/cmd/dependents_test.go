@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependentIndex(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "kt-a", Deps: []string{"kt-c"}},
+		{ID: "kt-b", Deps: []string{"kt-c", "kt-d"}},
+		{ID: "kt-c"},
+	}
+
+	index := dependentIndex(tickets)
+	assert.Equal(t, []string{"kt-a", "kt-b"}, index["kt-c"])
+	assert.Equal(t, []string{"kt-b"}, index["kt-d"])
+	assert.Empty(t, index["kt-a"])
+}
+
+func TestRunDepDependents(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	base := mkTicket(t, "kt-base", "Base", ticket.StatusOpen)
+	user := mkTicket(t, "kt-user", "User", ticket.StatusOpen)
+	user.Deps = []string{base.ID}
+	require.NoError(t, Store.Save(user))
+
+	require.NoError(t, runDepDependents(nil, []string{"base"}))
+	dependents := storeDependents()
+	assert.Equal(t, []string{user.ID}, dependents[base.ID])
+	assert.Empty(t, dependents[user.ID])
+
+	err := runDepDependents(nil, []string{"kt-missing"})
+	require.Error(t, err)
+}
+
+func TestRunDepDependentsJSON(t *testing.T) {
+	defer setupTestEnv(t)()
+	jsonFlag = true
+	defer func() { jsonFlag = false }()
+
+	base := mkTicket(t, "kt-base", "Base", ticket.StatusOpen)
+	require.NoError(t, runDepDependents(nil, []string{base.ID}))
+}
+
+func TestRunDepAddMultiple(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	c := mkTicket(t, "kt-c", "C", ticket.StatusOpen)
+	d := mkTicket(t, "kt-d", "D", ticket.StatusOpen)
+	d.Deps = []string{a.ID}
+	require.NoError(t, Store.Save(d))
+
+	// b and c are added; d would form a cycle and the missing ID is reported
+	require.NoError(t, runDepAdd(nil, []string{a.ID, b.ID, c.ID, d.ID, "kt-missing"}))
+
+	u, err := Store.Get(a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{b.ID, c.ID}, u.Deps)
+}
+
+func TestRunDepAddCycle(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	c := mkTicket(t, "kt-c", "C", ticket.StatusOpen)
+	b.Deps = []string{c.ID}
+	c.Deps = []string{a.ID}
+	require.NoError(t, Store.Save(b))
+	require.NoError(t, Store.Save(c))
+
+	err := runDepAdd(nil, []string{a.ID, b.ID})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle")
+
+	err = runDepAdd(nil, []string{a.ID, a.ID})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle")
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetDepTreeFlags() {
+	depTreeFull = false
+	depTreeWithChildren = false
+	depTreeWithParent = false
+}
+
+func TestDepTreeWithChildren(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer resetDepTreeFlags()
+
+	epic := mkTicket(t, "kt-epic", "Epic", ticket.StatusOpen)
+	base := mkTicket(t, "kt-base", "Base", ticket.StatusOpen)
+	c1 := mkTicket(t, "kt-c1", "Child 1", ticket.StatusOpen)
+	c2 := mkTicket(t, "kt-c2", "Child 2", ticket.StatusOpen)
+	c1.Parent, c1.Deps = epic.ID, []string{base.ID}
+	c2.Parent, c2.Deps = epic.ID, []string{c1.ID}
+	require.NoError(t, Store.Save(c1))
+	require.NoError(t, Store.Save(c2))
+
+	depTreeWithChildren = true
+	require.NoError(t, runDepTree(nil, []string{epic.ID}))
+
+	b := &depTreeBuilder{
+		seen:     make(map[string]bool),
+		path:     make(map[string]bool),
+		children: map[string][]string{epic.ID: {c1.ID, c2.ID}},
+	}
+	tree := b.build(epic)
+	require.Len(t, tree.Children, 2)
+	assert.Equal(t, c1.ID, tree.Children[0].ID)
+	assert.Equal(t, "child", tree.Children[0].Relation)
+	require.Len(t, tree.Children[0].Children, 1)
+	assert.Equal(t, base.ID, tree.Children[0].Children[0].ID)
+	// c1 was already expanded under the epic, so c2's dep is a leaf
+	require.Len(t, tree.Children[1].Children, 1)
+	assert.Empty(t, tree.Children[1].Children[0].Children)
+}
+
+func TestDepTreeWithParentCycleSafe(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer resetDepTreeFlags()
+
+	epic := mkTicket(t, "kt-epic", "Epic", ticket.StatusOpen)
+	child := mkTicket(t, "kt-child", "Child", ticket.StatusOpen)
+	child.Parent = epic.ID
+	require.NoError(t, Store.Save(child))
+
+	// Parent and children together form a cycle; --full must still terminate
+	b := &depTreeBuilder{
+		full:       true,
+		withParent: true,
+		seen:       make(map[string]bool),
+		path:       make(map[string]bool),
+		children:   map[string][]string{epic.ID: {child.ID}},
+	}
+	tree := b.build(child)
+	require.Len(t, tree.Children, 1)
+	assert.Equal(t, epic.ID, tree.Children[0].ID)
+	assert.Equal(t, "parent", tree.Children[0].Relation)
+	require.Len(t, tree.Children[0].Children, 1)
+	assert.Equal(t, child.ID, tree.Children[0].Children[0].ID)
+	assert.Empty(t, tree.Children[0].Children[0].Children)
+
+	depTreeWithParent = true
+	depTreeWithChildren = true
+	depTreeFull = true
+	require.NoError(t, runDepTree(nil, []string{child.ID}))
+}
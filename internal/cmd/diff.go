@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <id>",
+	Short: "Show how a ticket changed since a git revision",
+	Long: `Compare a ticket's file in a git revision (--since, default HEAD) with the
+file on disk, field by field: status transitions, edited fields, and the
+lines added to or removed from each section.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDiff,
+}
+
+var diffSince string
+
+func init() {
+	diffCmd.Flags().StringVar(&diffSince, "since", "HEAD", "Git revision to compare with, e.g. HEAD~5 or main")
+	rootCmd.AddCommand(diffCmd)
+}
+
+type diffResult struct {
+	ID      string               `json:"id"`
+	Since   string               `json:"since"`
+	Created bool                 `json:"created,omitempty"` // the ticket did not exist in Since
+	Changes []ticket.FieldChange `json:"changes"`
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	t, err := Store.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+	before, err := ticketAt(diffSince, Store.Path(t.ID))
+	if err != nil {
+		return err
+	}
+
+	result := diffResult{ID: t.ID, Since: diffSince, Created: before == nil, Changes: ticket.Diff(before, t)}
+	if result.Changes == nil {
+		result.Changes = []ticket.FieldChange{}
+	}
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	switch {
+	case result.Created:
+		fmt.Printf("%s did not exist in %s\n", t.ID, diffSince)
+	case len(result.Changes) == 0:
+		fmt.Printf("%s is unchanged since %s\n", t.ID, diffSince)
+		return nil
+	default:
+		fmt.Printf("%s since %s:\n", t.ID, diffSince)
+	}
+	writeFieldChanges(os.Stdout, result.Changes, "  ")
+	return nil
+}
+
+// ticketAt parses the ticket file at path as of git revision rev, or
+// returns nil if it did not exist then.
+func ticketAt(rev, path string) (*ticket.Ticket, error) {
+	data, ok, err := git.FileAt(rev, path)
+	if err != nil || !ok {
+		return nil, err
+	}
+	t, err := ticket.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s in %s: %w", path, rev, err)
+	}
+	return t, nil
+}
+
+// writeFieldChanges writes one line per changed field, "field: before →
+// after", indented by indent. Changed sections list their removed and
+// added lines instead.
+func writeFieldChanges(w io.Writer, changes []ticket.FieldChange, indent string) {
+	for _, c := range changes {
+		if _, section := sectionHeadings[c.Field]; !section {
+			fmt.Fprintf(w, "%s%s: %s → %s\n", indent, c.Field, orNone(c.Before), orNone(c.After))
+			continue
+		}
+		fmt.Fprintf(w, "%s%s:\n", indent, c.Field)
+		for _, line := range lineDiff(c.Before, c.After) {
+			fmt.Fprintf(w, "%s  %s\n", indent, paintDiffLine(line))
+		}
+	}
+}
+
+// orNone shows an empty field value as (none).
+func orNone(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return v
+}
+
+// paintDiffLine colors a removed line red and an added one green.
+func paintDiffLine(line string) string {
+	if strings.HasPrefix(line, "-") {
+		return paint(ansiRed, line)
+	}
+	return paint(ansiGreen, line)
+}
+
+// lineDiff returns the lines removed from a ("- " prefix) and added in b
+// ("+ "), in order, leaving out the lines they have in common.
+func lineDiff(a, b string) []string {
+	var x, y []string
+	if a != "" {
+		x = strings.Split(a, "\n")
+	}
+	if b != "" {
+		y = strings.Split(b, "\n")
+	}
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out []string
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			i, j = i+1, j+1
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			out = append(out, "- "+x[i])
+			i++
+		default:
+			out = append(out, "+ "+y[j])
+			j++
+		}
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketAt(t *testing.T) {
+	defer setupTestEnv(t)()
+	dir := initGitRepo(t)
+	Store = store.New(filepath.Join(dir, ".ktickets"))
+	require.NoError(t, Store.EnsureDir())
+
+	tk := mkTicket(t, "kt-001", "Diff me", ticket.StatusOpen)
+	gitCommit(t, "empty")
+	_, err := git.CommitPaths(dir, "add", []string{Store.Path("kt-001")})
+	require.NoError(t, err)
+
+	tk.Status = ticket.StatusClosed
+	tk.AcceptanceCriteria = "- [x] done"
+	require.NoError(t, Store.Save(tk))
+
+	before, err := ticketAt("HEAD", Store.Path("kt-001"))
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusOpen, before.Status)
+	gone, err := ticketAt("HEAD~1", Store.Path("kt-001"))
+	require.NoError(t, err)
+	assert.Nil(t, gone)
+
+	diffSince = "HEAD"
+	require.NoError(t, runDiff(nil, []string{"kt-001"}))
+	diffSince = "nope"
+	defer func() { diffSince = "HEAD" }()
+	assert.ErrorContains(t, runDiff(nil, []string{"kt-001"}), "unknown commit")
+}
+
+func TestWriteFieldChanges(t *testing.T) {
+	var b bytes.Buffer
+	writeFieldChanges(&b, []ticket.FieldChange{
+		{Field: "status", Before: "open", After: "closed"},
+		{Field: "assignee", After: "ann"},
+		{Field: "acceptance_criteria", Before: "- [ ] a\n- [ ] b", After: "- [x] a\n- [ ] b\n- [ ] c"},
+	}, "  ")
+	assert.Equal(t, `  status: open → closed
+  assignee: (none) → ann
+  acceptance_criteria:
+    - - [ ] a
+    + - [x] a
+    + - [ ] c
+`, b.String())
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// dryRun is the --dry-run flag of the commands that delete or rewrite
+// tickets in bulk.
+var dryRun bool
+
+// addDryRunFlag gives cmd a --dry-run flag. Its RunE must call
+// startDryRun first and, when dryRun is set, end with printDryRun.
+func addDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Show the files that would change, without changing them")
+}
+
+// startDryRun puts s in dry-run mode if --dry-run was given.
+func startDryRun(s *store.Store) {
+	if dryRun {
+		s.SetDryRun(true)
+	}
+}
+
+// dryRunResult is the JSON output of a command run with --dry-run: the
+// files it would have changed and the result it would have reported.
+type dryRunResult struct {
+	DryRun  bool               `json:"dry_run"`
+	Changes []store.FileChange `json:"changes"`
+	Result  any                `json:"result,omitempty"`
+}
+
+// printDryRun reports the changes s skipped in dry-run mode, along with
+// the command's result in JSON mode.
+func printDryRun(s *store.Store, result any) error {
+	changes := s.Planned()
+	if IsJSON() {
+		return PrintJSON(dryRunResult{DryRun: true, Changes: changes, Result: result})
+	}
+	if len(changes) == 0 {
+		fmt.Println("Dry run: no files would change")
+		return nil
+	}
+	fmt.Printf("Dry run: %d file(s) would change\n", len(changes))
+	for _, c := range changes {
+		if c.To != "" {
+			fmt.Printf("  %-7s %s → %s\n", c.Action, relPath(c.Path), relPath(c.To))
+		} else {
+			fmt.Printf("  %-7s %s\n", c.Action, relPath(c.Path))
+		}
+	}
+	return nil
+}
+
+// relPath returns path relative to the working directory, if it is below
+// it.
+func relPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && filepath.IsLocal(rel) {
+		return rel
+	}
+	return path
+}
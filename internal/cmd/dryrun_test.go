@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunCommands(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer resetBulkFlags()
+	defer func() { dryRun, jsonFlag = false, false }()
+	mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	done := mkTicket(t, "kt-done", "Done", ticket.StatusClosed)
+	done.Closed = "2026-01-10T10:00:00Z"
+	require.NoError(t, Store.Save(done))
+	dryRun = true
+
+	planned := func(run func() error) []store.FileChange {
+		t.Helper()
+		Store = store.New(Store.Dir)
+		require.NoError(t, run())
+		return Store.Planned()
+	}
+
+	jsonFlag = true // no prompt, no --yes needed
+	assert.Equal(t, []store.FileChange{{Action: "delete", Path: Store.Path("kt-done")}},
+		planned(func() error { return runPurge(nil, nil) }))
+	jsonFlag = false
+
+	assert.Equal(t, []store.FileChange{
+		{Action: "update", Path: Store.Path("kt-a")},
+		{Action: "update", Path: Store.Path("kt-b")},
+	}, planned(func() error { return runMerge(nil, []string{"kt-b", "kt-a"}) }))
+
+	bulkFilter, bulkAssignee = "status=open", "alice"
+	assert.Len(t, planned(func() error { return runBulk(nil, nil) }), 2)
+
+	gcClaimTTL, gcArchiveAfter = "24h", "1d"
+	defer func() { gcArchiveAfter = "" }()
+	assert.Equal(t, []store.FileChange{{Action: "archive", Path: Store.Path("kt-done"), To: Store.ArchivePath("kt-done")}},
+		planned(func() error { return runGC(nil, nil) }))
+
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	assert.Len(t, tickets, 3, "nothing deleted or archived")
+	for _, tk := range tickets {
+		assert.Empty(t, tk.Assignee, tk.ID)
+		assert.NotEqual(t, ticket.ResolutionDuplicate, tk.Resolution, tk.ID)
+	}
+	_, err = os.Stat(Store.ArchiveDir())
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+// dumpVersion is the version of the dump document format. Load rejects
+// documents from newer versions.
+const dumpVersion = 1
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Write every ticket as one JSON document",
+	Long: `Write the whole store (all tickets, including description, design,
+acceptance criteria, tests, and notes) to stdout as a single versioned
+JSON document, for backup or for moving tickets to another repository.
+
+  kt dump > backlog.json
+  kt load backlog.json`,
+	Args: cobra.NoArgs,
+	RunE: runDump,
+}
+
+var loadCmd = &cobra.Command{
+	Use:   "load <file>",
+	Short: "Load tickets from a kt dump document",
+	Long: `Recreate tickets from a document written by kt dump ("-" reads stdin).
+
+Tickets identical to an existing one are left alone. When a ticket's ID is
+already taken by a different ticket, --on-conflict decides:
+
+  rename     give the loaded ticket a new ID and update references to it
+             among the loaded tickets (default)
+  skip       keep the existing ticket
+  overwrite  replace the existing ticket`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLoad,
+}
+
+var loadOnConflict string
+
+func init() {
+	loadCmd.Flags().StringVar(&loadOnConflict, "on-conflict", "rename", "ID collision handling: rename|skip|overwrite")
+	rootCmd.AddCommand(dumpCmd)
+	rootCmd.AddCommand(loadCmd)
+}
+
+type dumpDocument struct {
+	Version int              `json:"version"`
+	Tickets []*ticket.Ticket `json:"tickets"`
+}
+
+type loadResult struct {
+	Loaded      []string          `json:"loaded,omitempty"`
+	Renamed     map[string]string `json:"renamed,omitempty"`
+	Overwritten []string          `json:"overwritten,omitempty"`
+	Skipped     []string          `json:"skipped,omitempty"`
+	Unchanged   []string          `json:"unchanged,omitempty"`
+}
+
+func runDump(cmd *cobra.Command, args []string) error {
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+	// Oldest first, so dumps of the same store diff cleanly
+	sort.SliceStable(tickets, func(i, j int) bool {
+		if tickets[i].Created != tickets[j].Created {
+			return tickets[i].Created < tickets[j].Created
+		}
+		return tickets[i].ID < tickets[j].ID
+	})
+	if tickets == nil {
+		tickets = []*ticket.Ticket{}
+	}
+	return PrintJSON(dumpDocument{Version: dumpVersion, Tickets: tickets})
+}
+
+func runLoad(cmd *cobra.Command, args []string) error {
+	switch loadOnConflict {
+	case "rename", "skip", "overwrite":
+	default:
+		return fmt.Errorf("invalid --on-conflict %q (use rename, skip, or overwrite)", loadOnConflict)
+	}
+
+	doc, err := readDump(args[0])
+	if err != nil {
+		return err
+	}
+
+	var result loadResult
+	err = Store.Transaction(func(tx *store.Tx) error {
+		r, err := loadTickets(tx, doc.Tickets, nil, loadOnConflict)
+		result = *r
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return printLoadResult(result)
+}
+
+// printLoadResult reports the outcome of loading tickets into the store.
+func printLoadResult(result loadResult) error {
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+
+	fmt.Printf("Loaded %d tickets\n", len(result.Loaded))
+	for _, from := range slices.Sorted(maps.Keys(result.Renamed)) {
+		fmt.Printf("  %s → %s (ID already in use)\n", from, result.Renamed[from])
+	}
+	if len(result.Overwritten) > 0 {
+		fmt.Printf("Overwrote: %v\n", result.Overwritten)
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Printf("Skipped (ID already in use): %v\n", result.Skipped)
+	}
+	if len(result.Unchanged) > 0 {
+		fmt.Printf("Already present: %d\n", len(result.Unchanged))
+	}
+	return nil
+}
+
+// readDump reads and validates a dump document from path ("-" for stdin).
+func readDump(path string) (*dumpDocument, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read dump: %w", err)
+	}
+
+	var doc dumpDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse dump: %w", err)
+	}
+	if doc.Version == 0 {
+		return nil, fmt.Errorf("parse dump: missing version (not a kt dump document?)")
+	}
+	if doc.Version > dumpVersion {
+		return nil, fmt.Errorf("dump version %d is newer than supported version %d; upgrade kt", doc.Version, dumpVersion)
+	}
+
+	seen := make(map[string]bool, len(doc.Tickets))
+	for i, t := range doc.Tickets {
+		if t == nil || t.ID == "" {
+			return nil, fmt.Errorf("parse dump: ticket %d has no ID", i+1)
+		}
+		if !validID.MatchString(t.ID) {
+			return nil, fmt.Errorf("parse dump: invalid ticket ID %q", t.ID)
+		}
+		if seen[t.ID] {
+			return nil, fmt.Errorf("parse dump: duplicate ticket ID %s", t.ID)
+		}
+		seen[t.ID] = true
+	}
+	return &doc, nil
+}
+
+// loadTickets saves tickets into the transaction, resolving ID collisions
+// with existing tickets, or the archived ones given, according to
+// onConflict.
+func loadTickets(tx *store.Tx, tickets []*ticket.Ticket, archived map[string]*ticket.Ticket, onConflict string) (*loadResult, error) {
+	result := &loadResult{}
+	incoming := make(map[string]bool, len(tickets))
+	for _, t := range tickets {
+		incoming[t.ID] = true
+	}
+
+	renamed := make(map[string]string)
+	var load []*ticket.Ticket
+	for _, t := range tickets {
+		existing, err := tx.Get(t.ID)
+		if err != nil {
+			existing = archived[t.ID]
+		}
+		if existing == nil {
+			load = append(load, t)
+			continue
+		}
+		switch {
+		case sameTicket(existing, t):
+			result.Unchanged = append(result.Unchanged, t.ID)
+		case onConflict == "skip":
+			result.Skipped = append(result.Skipped, t.ID)
+		case onConflict == "overwrite":
+			result.Overwritten = append(result.Overwritten, t.ID)
+			load = append(load, t)
+		default:
+			id, err := generateLoadID(tx, incoming)
+			if err != nil {
+				return result, err
+			}
+			incoming[id] = true
+			renamed[t.ID] = id
+			load = append(load, t)
+		}
+	}
+
+	for _, t := range load {
+		if id, ok := renamed[t.ID]; ok {
+			t.ID = id
+		}
+		renameLoadedRefs(t, renamed)
+		tx.Save(t)
+		result.Loaded = append(result.Loaded, t.ID)
+	}
+	if len(renamed) > 0 {
+		result.Renamed = renamed
+	}
+	return result, nil
+}
+
+// generateLoadID returns a new ID used neither in the store nor by any
+// ticket being loaded.
+func generateLoadID(tx *store.Tx, taken map[string]bool) (string, error) {
+	for range 10 {
+		id, err := generateUniqueID(tx)
+		if err != nil {
+			return "", err
+		}
+		if !taken[id] {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("could not generate a unique ID")
+}
+
+// renameLoadedRefs points a loaded ticket's deps/links/relations/parent at
+// the new IDs of renamed tickets.
+func renameLoadedRefs(t *ticket.Ticket, renamed map[string]string) {
+	if len(renamed) == 0 {
+		return
+	}
+	rename := func(ids []string) {
+		for i, id := range ids {
+			if to, ok := renamed[id]; ok {
+				ids[i] = to
+			}
+		}
+	}
+	if to, ok := renamed[t.Parent]; ok {
+		t.Parent = to
+	}
+	rename(t.Deps)
+	rename(t.Links)
+	for _, ids := range t.Relations {
+		rename(ids)
+	}
+}
+
+// sameTicket reports whether two tickets have identical content.
+func sameTicket(a, b *ticket.Ticket) bool {
+	return reflect.DeepEqual(normalizeTicket(a), normalizeTicket(b))
+}
+
+// normalizeTicket round-trips a ticket through JSON so that nil and empty
+// slices compare equal.
+func normalizeTicket(t *ticket.Ticket) map[string]any {
+	data, _ := json.Marshal(t)
+	var m map[string]any
+	_ = json.Unmarshal(data, &m)
+	return m
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dumpToFile runs kt dump with stdout redirected to a file and returns its path.
+func dumpToFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "backlog.json")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	old := os.Stdout
+	os.Stdout = f
+	err = runDump(nil, nil)
+	os.Stdout = old
+	require.NoError(t, f.Close())
+	require.NoError(t, err)
+	return path
+}
+
+func TestDumpLoadRoundTrip(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	epic := mkTicket(t, "kt-epic", "Epic", ticket.StatusOpen)
+	child := mkTicket(t, "kt-child", "Child", ticket.StatusClosed)
+	child.Parent = epic.ID
+	child.Deps = []string{"kt-epic"}
+	child.Description = "Body text"
+	child.Design = "Design notes"
+	child.AcceptanceCriteria = "- works"
+	child.Tests = "- TestIt"
+	child.Notes = "**2026-01-09T14:00:00Z**\n\nA note"
+	child.Relations = map[ticket.LinkType][]string{ticket.LinkBlocks: {"kt-epic"}}
+	require.NoError(t, Store.Save(child))
+
+	path := dumpToFile(t)
+	want, err := Store.List()
+	require.NoError(t, err)
+
+	// Load into an empty store
+	Store = store.New(t.TempDir())
+	require.NoError(t, Store.EnsureDir())
+	require.NoError(t, runLoad(nil, []string{path}))
+
+	got, err := Store.List()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, want, got)
+
+	// Loading again is a no-op
+	doc, err := readDump(path)
+	require.NoError(t, err)
+	var result *loadResult
+	require.NoError(t, Store.Transaction(func(tx *store.Tx) error {
+		result, err = loadTickets(tx, doc.Tickets, nil, "rename")
+		return err
+	}))
+	assert.Empty(t, result.Loaded)
+	assert.ElementsMatch(t, []string{"kt-epic", "kt-child"}, result.Unchanged)
+}
+
+func TestLoadConflicts(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	mkTicket(t, "kt-a", "Local A", ticket.StatusOpen)
+	// Fresh copies each time, since loading rewrites IDs in place
+	remote := func() []*ticket.Ticket {
+		return []*ticket.Ticket{
+			{ID: "kt-a", Status: ticket.StatusOpen, Type: ticket.TypeTask, Title: "Remote A"},
+			{ID: "kt-b", Status: ticket.StatusOpen, Type: ticket.TypeTask, Title: "Remote B", Deps: []string{"kt-a"}, Parent: "kt-a"},
+		}
+	}
+	load := func(policy string) *loadResult {
+		t.Helper()
+		var result *loadResult
+		require.NoError(t, Store.Transaction(func(tx *store.Tx) error {
+			var err error
+			result, err = loadTickets(tx, remote(), nil, policy)
+			return err
+		}))
+		return result
+	}
+
+	result := load("skip")
+	assert.Equal(t, []string{"kt-a"}, result.Skipped)
+	assert.Equal(t, []string{"kt-b"}, result.Loaded)
+	a, err := Store.Get("kt-a")
+	require.NoError(t, err)
+	assert.Equal(t, "Local A", a.Title)
+
+	require.NoError(t, Store.Delete("kt-b"))
+	result = load("rename")
+	newID := result.Renamed["kt-a"]
+	require.NotEmpty(t, newID)
+	renamed, err := Store.Get(newID)
+	require.NoError(t, err)
+	assert.Equal(t, "Remote A", renamed.Title)
+	b, err := Store.Get("kt-b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{newID}, b.Deps, "references follow the renamed ticket")
+	assert.Equal(t, newID, b.Parent)
+	a, err = Store.Get("kt-a")
+	require.NoError(t, err)
+	assert.Equal(t, "Local A", a.Title)
+
+	result = load("overwrite")
+	assert.Equal(t, []string{"kt-a", "kt-b"}, result.Overwritten)
+	a, err = Store.Get("kt-a")
+	require.NoError(t, err)
+	assert.Equal(t, "Remote A", a.Title)
+}
+
+func TestReadDumpErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "dump.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	_, err := readDump(write(`{"tickets":[]}`))
+	assert.ErrorContains(t, err, "missing version")
+	_, err = readDump(write(`{"version":99,"tickets":[]}`))
+	assert.ErrorContains(t, err, "newer than supported")
+	_, err = readDump(write(`{"version":1,"tickets":[{"id":"kt-a"},{"id":"kt-a"}]}`))
+	assert.ErrorContains(t, err, "duplicate ticket ID kt-a")
+	_, err = readDump(write(`{"version":1,"tickets":[{"id":"../x"}]}`))
+	assert.ErrorContains(t, err, "invalid ticket ID")
+	_, err = readDump(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var fsckCmd = &cobra.Command{
+	Use:   "fsck",
+	Short: "Check for references to tickets that do not exist",
+	Long: `Check every ticket's parent, deps, and links for IDs that no ticket has,
+e.g. after a ticket file was deleted by hand or lost in a merge. Archived
+tickets (kt gc) count as existing. A missing dep keeps a ticket blocked
+forever. With epic_parents set in config.yml, also check that parents are
+epics and epics have no parent.
+
+Exits with status 1 if any are found. With --fix-refs, asks for each missing
+ID whether to remove the references to it or point them at another ticket.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runFsck,
+}
+
+var fsckFixRefs bool
+
+func init() {
+	fsckCmd.Flags().BoolVar(&fsckFixRefs, "fix-refs", false, "Interactively remove or remap references to missing tickets")
+	rootCmd.AddCommand(fsckCmd)
+}
+
+// danglingRef is a reference to a ticket that does not exist.
+type danglingRef struct {
+	ID     string `json:"id"`     // the ticket holding the reference
+	Field  string `json:"field"`  // parent, deps, links, or a link type
+	Target string `json:"target"` // the missing ticket
+}
+
+// refFix is how --fix-refs resolved the references to one missing ticket.
+type refFix struct {
+	Target  string   `json:"target"`
+	RemapTo string   `json:"remap_to,omitempty"` // empty if the references were removed
+	Updated []string `json:"updated"`
+}
+
+type fsckResult struct {
+	Dangling []danglingRef   `json:"dangling"`
+	Fixed    []refFix        `json:"fixed,omitempty"`
+	Parents  []parentProblem `json:"parents,omitempty"` // with epic_parents
+}
+
+func runFsck(cmd *cobra.Command, args []string) error {
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+	archived, err := Store.ListArchived()
+	if err != nil {
+		return err
+	}
+	result := fsckResult{Dangling: danglingRefs(tickets, archived)}
+
+	if fsckFixRefs && len(result.Dangling) > 0 {
+		fixes, err := askRefFixes(result.Dangling, bufio.NewReader(os.Stdin), os.Stderr)
+		if err != nil {
+			return err
+		}
+		if result.Fixed, err = applyRefFixes(Store, fixes); err != nil {
+			return err
+		}
+		if tickets, err = Store.List(); err != nil {
+			return err
+		}
+		result.Dangling = danglingRefs(tickets, archived)
+	}
+	if projectConfig().EpicParents {
+		result.Parents = parentProblems(tickets)
+	}
+
+	if IsJSON() {
+		if err := PrintJSON(result); err != nil {
+			return err
+		}
+	} else {
+		for _, f := range result.Fixed {
+			if f.RemapTo == "" {
+				fmt.Printf("Removed references to %s from %s\n", f.Target, strings.Join(f.Updated, ", "))
+			} else {
+				fmt.Printf("Remapped %s → %s in %s\n", f.Target, f.RemapTo, strings.Join(f.Updated, ", "))
+			}
+		}
+		for _, r := range result.Dangling {
+			fmt.Printf("%s: %s refers to missing %s\n", r.ID, r.Field, r.Target)
+		}
+		for _, p := range result.Parents {
+			fmt.Printf("%s: %s\n", p.ID, p.Problem)
+		}
+	}
+	if n := len(result.Dangling); n > 0 {
+		hint := ""
+		if !fsckFixRefs {
+			hint = " (kt fsck --fix-refs repairs them)"
+		}
+		return fmt.Errorf("%d reference(s) to missing tickets%s", n, hint)
+	}
+	if n := len(result.Parents); n > 0 {
+		return fmt.Errorf("%d ticket(s) break epic_parents", n)
+	}
+	if !IsJSON() && len(result.Fixed) == 0 {
+		fmt.Println("No problems found")
+	}
+	return nil
+}
+
+// danglingRefs returns the references in tickets to IDs no ticket has,
+// sorted by missing ID. References to archived tickets (kt gc) are not
+// dangling: the ticket still exists, in the archive.
+func danglingRefs(tickets, archived []*ticket.Ticket) []danglingRef {
+	exists := make(map[string]bool, len(tickets)+len(archived))
+	for _, t := range slices.Concat(tickets, archived) {
+		exists[t.ID] = true
+	}
+	var refs []danglingRef
+	check := func(t *ticket.Ticket, field, target string) {
+		if target != "" && !exists[target] {
+			refs = append(refs, danglingRef{ID: t.ID, Field: field, Target: target})
+		}
+	}
+	for _, t := range tickets {
+		check(t, "parent", t.Parent)
+		for _, dep := range t.Deps {
+			check(t, "deps", dep)
+		}
+		for _, link := range t.Links {
+			check(t, "links", link)
+		}
+		for _, lt := range slices.Sorted(maps.Keys(t.Relations)) {
+			for _, id := range t.Relations[lt] {
+				check(t, string(lt), id)
+			}
+		}
+	}
+	slices.SortStableFunc(refs, func(a, b danglingRef) int {
+		return cmp.Or(strings.Compare(a.Target, b.Target), strings.Compare(a.ID, b.ID))
+	})
+	return refs
+}
+
+// askRefFixes asks, for each missing ID in refs, whether to remove the
+// references to it, remap them to another ID, or leave them. It returns
+// the answers by missing ID, "" meaning remove.
+func askRefFixes(refs []danglingRef, in *bufio.Reader, out io.Writer) (map[string]string, error) {
+	fixes := map[string]string{}
+	for i := 0; i < len(refs); {
+		target := refs[i].Target
+		var holders []string
+		for ; i < len(refs) && refs[i].Target == target; i++ {
+			holders = append(holders, fmt.Sprintf("%s (%s)", refs[i].ID, refs[i].Field))
+		}
+		fmt.Fprintf(out, "%s does not exist; referenced by %s\n", target, strings.Join(holders, ", "))
+		fmt.Fprint(out, "  [r]emove the references, enter an ID to remap them to, or press Enter to skip: ")
+		answer, err := in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		switch answer = strings.TrimSpace(answer); answer {
+		case "":
+		case "r", "remove":
+			fixes[target] = ""
+		default:
+			fixes[target] = answer
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	return fixes, nil
+}
+
+// applyRefFixes removes or remaps the references to each missing ID in
+// fixes in one transaction. Remap targets may be partial IDs.
+func applyRefFixes(s *store.Store, fixes map[string]string) ([]refFix, error) {
+	var applied []refFix
+	err := s.Transaction(func(tx *store.Tx) error {
+		for _, target := range slices.Sorted(maps.Keys(fixes)) {
+			remap := fixes[target]
+			if remap != "" {
+				t, err := tx.Resolve(remap)
+				if err != nil {
+					return fmt.Errorf("remap %s: %w", target, err)
+				}
+				remap = t.ID
+			}
+			applied = append(applied, refFix{Target: target, RemapTo: remap, Updated: tx.RewriteRefs(target, remap)})
+		}
+		return nil
+	})
+	return applied, err
+}
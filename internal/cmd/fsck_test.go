@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDanglingRefs(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "kt-a", Parent: "kt-gone", Deps: []string{"kt-b", "kt-x"}},
+		{ID: "kt-b", Links: []string{"kt-x"}, Relations: map[ticket.LinkType][]string{ticket.LinkBlocks: {"kt-a", "kt-y"}}},
+	}
+	assert.Equal(t, []danglingRef{
+		{ID: "kt-a", Field: "parent", Target: "kt-gone"},
+		{ID: "kt-a", Field: "deps", Target: "kt-x"},
+		{ID: "kt-b", Field: "links", Target: "kt-x"},
+		{ID: "kt-b", Field: "blocks", Target: "kt-y"},
+	}, danglingRefs(tickets, nil))
+
+	// Archived tickets still exist
+	archived := []*ticket.Ticket{{ID: "kt-x", Status: ticket.StatusClosed}}
+	assert.Equal(t, []danglingRef{
+		{ID: "kt-a", Field: "parent", Target: "kt-gone"},
+		{ID: "kt-b", Field: "blocks", Target: "kt-y"},
+	}, danglingRefs(tickets, archived))
+}
+
+func TestFixRefs(t *testing.T) {
+	defer setupTestEnv(t)()
+	mkTicket(t, "kt-new", "Replacement", ticket.StatusOpen)
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	a.Deps = []string{"kt-old", "kt-x"}
+	a.Parent = "kt-epic"
+	require.NoError(t, Store.Save(a))
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	b.Links = []string{"kt-x"}
+	require.NoError(t, Store.Save(b))
+
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	refs := danglingRefs(tickets, nil)
+	require.Len(t, refs, 4)
+
+	var prompts strings.Builder
+	fixes, err := askRefFixes(refs, bufio.NewReader(strings.NewReader("\nnew\nr\n")), &prompts)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"kt-old": "new", "kt-x": ""}, fixes, "kt-epic skipped")
+	assert.Contains(t, prompts.String(), "kt-x does not exist; referenced by kt-a (deps), kt-b (links)\n")
+
+	fixed, err := applyRefFixes(Store, fixes)
+	require.NoError(t, err)
+	assert.Equal(t, []refFix{
+		{Target: "kt-old", RemapTo: "kt-new", Updated: []string{"kt-a"}},
+		{Target: "kt-x", Updated: []string{"kt-a", "kt-b"}},
+	}, fixed)
+
+	got, err := Store.Get("kt-a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-new"}, got.Deps)
+	assert.Equal(t, "kt-epic", got.Parent)
+
+	_, err = applyRefFixes(Store, map[string]string{"kt-epic": "kt-nope"})
+	assert.ErrorContains(t, err, "remap kt-epic")
+
+	fixes, err = askRefFixes(refs[:1], bufio.NewReader(strings.NewReader("")), io.Discard)
+	require.NoError(t, err)
+	assert.Empty(t, fixes, "EOF skips")
+}
+
+func TestFsckArchived(t *testing.T) {
+	defer setupTestEnv(t)()
+	mkTicket(t, "kt-old", "Archived", ticket.StatusClosed)
+	a := mkTicket(t, "kt-a", "A", ticket.StatusClosed)
+	a.Deps = []string{"kt-old"}
+	require.NoError(t, Store.Save(a))
+	require.NoError(t, Store.Archive("kt-old"))
+
+	require.NoError(t, runFsck(nil, nil))
+
+	fsckFixRefs = true
+	defer func() { fsckFixRefs = false }()
+	require.NoError(t, runFsck(nil, nil))
+	got, err := Store.Get("kt-a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-old"}, got.Deps, "--fix-refs keeps references to archived tickets")
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Clean up the store: locks, claims, recurring and old closed tickets",
+	Long: `Run routine store maintenance and report what was done:
+
+  - remove lock files (and a daemon socket) left behind by killed processes
+  - reopen recurring tickets that are due again (see kt recur)
+  - release claims on in-progress tickets whose session has been idle for
+    --claim-ttl, so another agent can pick them up
+  - with --archive-after, move tickets closed longer ago than that into
+    .ktickets/archive/, unless a ticket left behind still refers to them
+    or they recur
+  - with --purge-after, delete archived tickets closed longer ago than that
+  - have a running kt daemon rescan every ticket file
+
+retention.closed and retention.archived in config.yml set the defaults
+for --archive-after and --purge-after, so a store kept for years stays
+small without anyone running kt purge.
+
+It is safe to run at any time, e.g. from cron or a post-merge git hook.
+--dry-run lists the files it would change instead.`,
+	Args: cobra.NoArgs,
+	RunE: runGC,
+}
+
+var (
+	gcClaimTTL     string
+	gcArchiveAfter string
+	gcPurgeAfter   string
+)
+
+func init() {
+	gcCmd.Flags().StringVar(&gcClaimTTL, "claim-ttl", "24h", "Release claims idle this long (e.g. 12h, 2d; 0 to keep all)")
+	gcCmd.Flags().StringVar(&gcArchiveAfter, "archive-after", "", "Archive tickets closed this long ago (e.g. 90d; default retention.closed)")
+	gcCmd.Flags().StringVar(&gcPurgeAfter, "purge-after", "", "Delete archived tickets closed this long ago (e.g. 365d; default retention.archived)")
+	addDryRunFlag(gcCmd)
+	rootCmd.AddCommand(gcCmd)
+}
+
+type expiredClaim struct {
+	ID      string `json:"id"`
+	Session string `json:"session"`
+}
+
+type gcResult struct {
+	LocksPruned   int            `json:"locks_pruned"`
+	Reopened      []string       `json:"reopened"`
+	ClaimsExpired []expiredClaim `json:"claims_expired"`
+	Archived      []string       `json:"archived"`
+	Purged        []string       `json:"purged"`              // archived tickets deleted
+	Reindexed     int            `json:"reindexed,omitempty"` // tickets a running daemon rescanned
+}
+
+func runGC(cmd *cobra.Command, args []string) error {
+	claimTTL, err := parseSince(gcClaimTTL)
+	if err != nil {
+		return fmt.Errorf("invalid --claim-ttl %q (expected e.g. 12h, 2d, or 0)", gcClaimTTL)
+	}
+	retention := projectConfig().Retention
+	archiveAfter, err := gcAge("--archive-after", gcArchiveAfter, retention.Closed)
+	if err != nil {
+		return err
+	}
+	purgeAfter, err := gcAge("--purge-after", gcPurgeAfter, retention.Archived)
+	if err != nil {
+		return err
+	}
+
+	startDryRun(Store)
+	r, err := collectGarbage(Store, time.Now(), claimTTL, archiveAfter, purgeAfter)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return printDryRun(Store, r)
+	}
+	if IsJSON() {
+		return PrintJSON(r)
+	}
+	fmt.Print(r.Text())
+	return nil
+}
+
+// gcAge parses the age given by flag, or if that is empty, by the config
+// setting fallback. Neither being set gives zero.
+func gcAge(flag, value, fallback string) (time.Duration, error) {
+	if value == "" {
+		value = fallback
+	}
+	if value == "" {
+		return 0, nil
+	}
+	age, err := parseSince(value)
+	if err != nil || age == 0 {
+		return 0, fmt.Errorf("invalid %s %q (expected e.g. 90d)", flag, value)
+	}
+	return age, nil
+}
+
+// collectGarbage runs kt gc against s. A zero claimTTL keeps every claim,
+// a zero archiveAfter archives nothing, and a zero purgeAfter deletes
+// nothing from the archive.
+func collectGarbage(s *store.Store, now time.Time, claimTTL, archiveAfter, purgeAfter time.Duration) (*gcResult, error) {
+	r := &gcResult{Reopened: []string{}, ClaimsExpired: []expiredClaim{}, Archived: []string{}, Purged: []string{}}
+	var err error
+	if r.LocksPruned, err = s.PruneLocks(); err != nil {
+		return r, fmt.Errorf("prune locks: %w", err)
+	}
+
+	reopened, err := reopenRecurring(s, now)
+	for _, t := range reopened {
+		r.Reopened = append(r.Reopened, t.ID)
+	}
+	if err != nil {
+		return r, err
+	}
+
+	tickets, err := s.List()
+	if err != nil {
+		return r, err
+	}
+	for _, t := range tickets {
+		if t.Status != ticket.StatusInProgress || t.ClaimedBy == "" || claimTTL == 0 ||
+			now.Sub(claimActivity(t)) < claimTTL {
+			continue
+		}
+		session := t.ClaimedBy
+		err := s.Update(t.ID, func(t *ticket.Ticket) error {
+			t.ClaimedBy = ""
+			return nil
+		})
+		if err != nil {
+			return r, err
+		}
+		r.ClaimsExpired = append(r.ClaimsExpired, expiredClaim{t.ID, session})
+	}
+
+	if archiveAfter > 0 {
+		for _, t := range archivable(tickets, now.Add(-archiveAfter)) {
+			if err := s.Archive(t.ID); err != nil {
+				return r, fmt.Errorf("archive %s: %w", t.ID, err)
+			}
+			r.Archived = append(r.Archived, t.ID)
+		}
+	}
+
+	if purgeAfter > 0 {
+		archived, err := s.ListArchived()
+		if err != nil {
+			return r, err
+		}
+		live := slices.DeleteFunc(tickets, func(t *ticket.Ticket) bool { return slices.Contains(r.Archived, t.ID) })
+		for _, t := range expired(archived, live, now.Add(-purgeAfter)) {
+			if err := s.RemoveArchived(t.ID); err != nil {
+				return r, fmt.Errorf("purge archived %s: %w", t.ID, err)
+			}
+			r.Purged = append(r.Purged, t.ID)
+		}
+	}
+
+	if n, ok, err := s.Reindex(); err != nil {
+		return r, err
+	} else if ok {
+		r.Reindexed = n
+	}
+	return r, nil
+}
+
+// claimActivity returns when the session holding t's claim last touched
+// it, or when t was created if its history does not say.
+func claimActivity(t *ticket.Ticket) time.Time {
+	for _, e := range slices.Backward(t.History) {
+		if e.Session == t.ClaimedBy {
+			if at, err := time.Parse(time.RFC3339, e.At); err == nil {
+				return at
+			}
+		}
+	}
+	created, _ := time.Parse(time.RFC3339, t.Created)
+	return created
+}
+
+// archivable returns the tickets closed before cutoff that do not recur
+// and that no ticket left in the store refers to, sorted by ID.
+func archivable(tickets []*ticket.Ticket, cutoff time.Time) []*ticket.Ticket {
+	return expired(tickets, nil, cutoff)
+}
+
+// expired returns the tickets closed before cutoff that do not recur and
+// that no ticket staying behind refers to, sorted by ID. The tickets in
+// others all stay behind, and so do those in tickets that are not
+// returned, so a closed child of a closed epic only goes with its parent.
+func expired(tickets, others []*ticket.Ticket, cutoff time.Time) []*ticket.Ticket {
+	leaving := map[string]*ticket.Ticket{}
+	for _, t := range tickets {
+		closed, err := time.Parse(time.RFC3339, t.Closed)
+		if t.Status == ticket.StatusClosed && err == nil && closed.Before(cutoff) && t.Recurrence == "" {
+			leaving[t.ID] = t
+		}
+	}
+	// Keeping one ticket can keep the ones it refers to, so repeat until
+	// nothing more stays
+	for {
+		referenced := map[string]bool{}
+		for _, t := range slices.Concat(tickets, others) {
+			if leaving[t.ID] == t {
+				continue
+			}
+			referenced[t.Parent] = true
+			for _, id := range slices.Concat(t.Deps, t.LinkedIDs()) {
+				referenced[id] = true
+			}
+		}
+		kept := false
+		for id := range leaving {
+			if referenced[id] {
+				delete(leaving, id)
+				kept = true
+			}
+		}
+		if !kept {
+			break
+		}
+	}
+	old := slices.Collect(maps.Values(leaving))
+	slices.SortFunc(old, func(a, b *ticket.Ticket) int { return strings.Compare(a.ID, b.ID) })
+	return old
+}
+
+// Text reports what gc did, one line per kind of cleanup.
+func (r *gcResult) Text() string {
+	var b strings.Builder
+	if r.LocksPruned > 0 {
+		fmt.Fprintf(&b, "Removed %d stale lock files\n", r.LocksPruned)
+	}
+	if len(r.Reopened) > 0 {
+		fmt.Fprintf(&b, "Reopened recurring: %s\n", strings.Join(r.Reopened, ", "))
+	}
+	for _, c := range r.ClaimsExpired {
+		fmt.Fprintf(&b, "Released %s's claim on %s\n", c.Session, c.ID)
+	}
+	if len(r.Archived) > 0 {
+		fmt.Fprintf(&b, "Archived %d closed tickets: %s\n", len(r.Archived), strings.Join(r.Archived, ", "))
+	}
+	if len(r.Purged) > 0 {
+		fmt.Fprintf(&b, "Deleted %d archived tickets: %s\n", len(r.Purged), strings.Join(r.Purged, ", "))
+	}
+	if r.Reindexed > 0 {
+		fmt.Fprintf(&b, "Daemon reindexed %d tickets\n", r.Reindexed)
+	}
+	if b.Len() == 0 {
+		return "Nothing to clean up\n"
+	}
+	return b.String()
+}
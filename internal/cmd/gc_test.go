@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectGarbage(t *testing.T) {
+	defer setupTestEnv(t)()
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	closed := func(id, at string) *ticket.Ticket {
+		tk := mkTicket(t, id, "Closed "+id, ticket.StatusClosed)
+		tk.Closed = at
+		require.NoError(t, Store.Save(tk))
+		return tk
+	}
+	closed("kt-old", "2026-01-01T00:00:00Z")
+	closed("kt-recent", "2026-05-20T00:00:00Z")
+	closed("kt-dep", "2026-01-01T00:00:00Z")
+	weekly := closed("kt-weekly", "2026-01-01T00:00:00Z")
+	weekly.Recurrence = "every 4 weeks"
+	require.NoError(t, Store.Save(weekly))
+
+	open := mkTicket(t, "kt-open", "Needs dep", ticket.StatusOpen)
+	open.Deps = []string{"kt-dep"}
+	require.NoError(t, Store.Save(open))
+
+	idle := mkTicket(t, "kt-idle", "Abandoned", ticket.StatusInProgress)
+	idle.ClaimedBy = "agent-1"
+	idle.History = []ticket.Event{{At: "2026-05-30T10:00:00Z", Session: "agent-1", Op: "start kt-idle"}}
+	require.NoError(t, Store.Save(idle))
+	busy := mkTicket(t, "kt-busy", "Active", ticket.StatusInProgress)
+	busy.ClaimedBy = "agent-2"
+	busy.History = []ticket.Event{{At: "2026-06-01T11:00:00Z", Session: "agent-2"}}
+	require.NoError(t, Store.Save(busy))
+
+	locks := filepath.Join(Store.Dir, ".locks")
+	require.NoError(t, os.MkdirAll(locks, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(locks, "kt-x.lock"), nil, 0644))
+
+	r, err := collectGarbage(Store, now, 24*time.Hour, 90*24*time.Hour, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, r.LocksPruned)
+	assert.Equal(t, []string{"kt-weekly"}, r.Reopened)
+	assert.Equal(t, []expiredClaim{{"kt-idle", "agent-1"}}, r.ClaimsExpired)
+	assert.Equal(t, []string{"kt-old"}, r.Archived, "not recent, referenced, or recurring ones")
+
+	got, err := Store.Get("kt-idle")
+	require.NoError(t, err)
+	assert.Empty(t, got.ClaimedBy)
+	assert.Equal(t, ticket.StatusInProgress, got.Status)
+	_, err = os.Stat(Store.ArchivePath("kt-old"))
+	assert.NoError(t, err)
+
+	r, err = collectGarbage(Store, now, 0, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Nothing to clean up\n", r.Text())
+}
+
+func TestGCRetention(t *testing.T) {
+	defer setupTestEnv(t)()
+	writeProjectConfig(t, "retention:\n  closed: 30d\n  archived: 365d\n")
+	closed := func(id string, age time.Duration) {
+		tk := mkTicket(t, id, "Closed "+id, ticket.StatusClosed)
+		tk.Closed = time.Now().Add(-age).UTC().Format(time.RFC3339)
+		require.NoError(t, Store.Save(tk))
+	}
+	day := 24 * time.Hour
+	closed("kt-ancient", 400*day)
+	closed("kt-old", 60*day)
+	closed("kt-new", day)
+
+	require.NoError(t, runGC(nil, nil))
+	assert.NoFileExists(t, Store.Path("kt-ancient"))
+	assert.NoFileExists(t, Store.ArchivePath("kt-ancient"), "archived, then deleted")
+	assert.FileExists(t, Store.ArchivePath("kt-old"))
+	assert.FileExists(t, Store.Path("kt-new"))
+
+	writeProjectConfig(t, "retention:\n  closed: 30d\n")
+	gcPurgeAfter = "1w"
+	defer func() { gcPurgeAfter = "" }()
+	require.NoError(t, runGC(nil, nil))
+	assert.NoFileExists(t, Store.ArchivePath("kt-old"), "--purge-after without retention.archived")
+}
+
+func TestGCKeepsReferencedClosed(t *testing.T) {
+	defer setupTestEnv(t)()
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	closed := func(id, at string, edit func(*ticket.Ticket)) {
+		tk := mkTicket(t, id, "Closed "+id, ticket.StatusClosed)
+		tk.Closed = at
+		edit(tk)
+		require.NoError(t, Store.Save(tk))
+	}
+	old, recent := "2026-01-01T00:00:00Z", "2026-05-20T00:00:00Z"
+	// An old epic goes with its old child; one with a recent child stays,
+	// and so does the old dep of that recent child
+	closed("kt-epic1", old, func(*ticket.Ticket) {})
+	closed("kt-child1", old, func(tk *ticket.Ticket) { tk.Parent = "kt-epic1" })
+	closed("kt-epic2", old, func(*ticket.Ticket) {})
+	closed("kt-dep2", old, func(*ticket.Ticket) {})
+	closed("kt-child2", recent, func(tk *ticket.Ticket) { tk.Parent, tk.Deps = "kt-epic2", []string{"kt-dep2"} })
+	closed("kt-link", old, func(tk *ticket.Ticket) { tk.AddLink(ticket.LinkRelatesTo, "kt-child2") })
+
+	r, err := collectGarbage(Store, now, 0, 90*24*time.Hour, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-child1", "kt-epic1", "kt-link"}, r.Archived)
+
+	// Purging keeps archived tickets the store still refers to
+	r, err = collectGarbage(Store, now.AddDate(1, 0, 0), 0, 0, 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-child1", "kt-epic1", "kt-link"}, r.Purged)
+}
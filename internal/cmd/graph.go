@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var depGraphCmd = &cobra.Command{
+	Use:   "graph [id...]",
+	Short: "Export dependency graph as DOT or Mermaid",
+	Long: `Export the dependency graph for rendering with Graphviz or Mermaid.
+Without IDs the whole store is exported; with IDs only tickets reachable
+from them through deps. Edges point from a dependency to the ticket that
+waits on it (execution order).
+
+  kt dep graph | dot -Tsvg > deps.svg
+  kt dep graph --format mermaid kt-a1b2`,
+	RunE: runDepGraph,
+}
+
+var depGraphFormat string
+
+func init() {
+	depGraphCmd.Flags().StringVar(&depGraphFormat, "format", "dot", "Output format (dot|mermaid)")
+	depCmd.AddCommand(depGraphCmd)
+}
+
+type graphNode struct {
+	ID     string        `json:"id"`
+	Status ticket.Status `json:"status"`
+	Title  string        `json:"title"`
+}
+
+type graphEdge struct {
+	From string `json:"from"` // dependency
+	To   string `json:"to"`   // dependent
+}
+
+type depGraph struct {
+	Nodes []graphNode `json:"nodes"`
+	Edges []graphEdge `json:"edges"`
+}
+
+func runDepGraph(cmd *cobra.Command, args []string) error {
+	if depGraphFormat != "dot" && depGraphFormat != "mermaid" {
+		return fmt.Errorf("invalid format %q (expected dot|mermaid)", depGraphFormat)
+	}
+
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+
+	var roots []string
+	for _, id := range args {
+		t, err := Store.Resolve(id)
+		if err != nil {
+			return err
+		}
+		roots = append(roots, t.ID)
+	}
+
+	g := buildDepGraph(tickets, roots)
+
+	if IsJSON() {
+		return PrintJSON(g)
+	}
+
+	if depGraphFormat == "mermaid" {
+		fmt.Print(g.Mermaid())
+	} else {
+		fmt.Print(g.DOT())
+	}
+	return nil
+}
+
+// buildDepGraph collects nodes and edges, restricted to tickets reachable
+// from roots through deps when roots is non-empty.
+func buildDepGraph(tickets []*ticket.Ticket, roots []string) *depGraph {
+	byID := make(map[string]*ticket.Ticket, len(tickets))
+	for _, t := range tickets {
+		byID[t.ID] = t
+	}
+
+	include := make(map[string]bool)
+	if len(roots) == 0 {
+		for id := range byID {
+			include[id] = true
+		}
+	} else {
+		stack := append([]string(nil), roots...)
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if include[id] {
+				continue
+			}
+			include[id] = true
+			if t, ok := byID[id]; ok {
+				stack = append(stack, t.Deps...)
+			}
+		}
+	}
+
+	g := &depGraph{Nodes: []graphNode{}, Edges: []graphEdge{}}
+	seen := make(map[string]bool)
+	addNode := func(id string) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		if t, ok := byID[id]; ok {
+			g.Nodes = append(g.Nodes, graphNode{ID: t.ID, Status: t.Status, Title: t.Title})
+		} else {
+			g.Nodes = append(g.Nodes, graphNode{ID: id, Status: "unknown", Title: "(not found)"})
+		}
+	}
+
+	ids := make([]string, 0, len(include))
+	for id := range include {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		addNode(id)
+		t, ok := byID[id]
+		if !ok {
+			continue
+		}
+		for _, dep := range t.Deps {
+			addNode(dep)
+			g.Edges = append(g.Edges, graphEdge{From: dep, To: t.ID})
+		}
+	}
+	return g
+}
+
+// DOT renders the graph in Graphviz format.
+func (g *depGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph deps {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, n := range g.Nodes {
+		label := fmt.Sprintf("%s\\n%s", n.ID, dotEscape(n.Title))
+		attrs := fmt.Sprintf("label=\"%s\"", label)
+		switch n.Status {
+		case ticket.StatusClosed:
+			attrs += ", style=filled, fillcolor=lightgray"
+		case ticket.StatusInProgress:
+			attrs += ", style=filled, fillcolor=lightyellow"
+		case "unknown":
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  \"%s\" [%s];\n", n.ID, attrs)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  \"%s\" -> \"%s\";\n", e.From, e.To)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+var mermaidUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *depGraph) Mermaid() string {
+	nodeID := func(id string) string { return mermaidUnsafe.ReplaceAllString(id, "_") }
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, n := range g.Nodes {
+		title := strings.ReplaceAll(n.Title, `"`, "#quot;")
+		fmt.Fprintf(&b, "  %s[\"%s: %s\"]\n", nodeID(n.ID), n.ID, title)
+		switch n.Status {
+		case ticket.StatusClosed:
+			fmt.Fprintf(&b, "  class %s closed\n", nodeID(n.ID))
+		case ticket.StatusInProgress:
+			fmt.Fprintf(&b, "  class %s inprogress\n", nodeID(n.ID))
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", nodeID(e.From), nodeID(e.To))
+	}
+	b.WriteString("  classDef closed fill:#ddd,color:#666\n")
+	b.WriteString("  classDef inprogress fill:#ffc\n")
+	return b.String()
+}
+
+func dotEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
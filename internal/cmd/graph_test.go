@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func graphFixture() []*ticket.Ticket {
+	return []*ticket.Ticket{
+		{ID: "kt-a", Status: ticket.StatusOpen, Title: `Say "hi"`, Deps: []string{"kt-b", "kt-c"}},
+		{ID: "kt-b", Status: ticket.StatusInProgress, Title: "B", Deps: []string{"kt-d"}},
+		{ID: "kt-c", Status: ticket.StatusClosed, Title: "C", Deps: []string{"kt-d"}},
+		{ID: "kt-d", Status: ticket.StatusOpen, Title: "D", Deps: []string{"kt-gone"}},
+		{ID: "kt-x", Status: ticket.StatusOpen, Title: "Unrelated"},
+	}
+}
+
+func TestBuildDepGraphFull(t *testing.T) {
+	g := buildDepGraph(graphFixture(), nil)
+
+	assert.Len(t, g.Nodes, 6) // 5 tickets + missing dep
+	assert.Len(t, g.Edges, 5)
+	assert.Contains(t, g.Edges, graphEdge{From: "kt-d", To: "kt-b"})
+	assert.Contains(t, g.Edges, graphEdge{From: "kt-d", To: "kt-c"})
+}
+
+func TestBuildDepGraphRooted(t *testing.T) {
+	g := buildDepGraph(graphFixture(), []string{"kt-b"})
+
+	ids := make([]string, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+	assert.ElementsMatch(t, []string{"kt-b", "kt-d", "kt-gone"}, ids)
+	assert.Len(t, g.Edges, 2)
+}
+
+func TestDepGraphDOT(t *testing.T) {
+	out := buildDepGraph(graphFixture(), []string{"kt-a"}).DOT()
+
+	assert.Contains(t, out, "digraph deps {")
+	assert.Contains(t, out, `"kt-b" -> "kt-a";`)
+	assert.Contains(t, out, `Say \"hi\"`)
+	assert.Contains(t, out, "fillcolor=lightgray")
+	assert.Contains(t, out, `"kt-gone" [label="kt-gone\n(not found)", style=dashed];`)
+}
+
+func TestDepGraphMermaid(t *testing.T) {
+	out := buildDepGraph(graphFixture(), []string{"kt-a"}).Mermaid()
+
+	assert.Contains(t, out, "graph LR")
+	assert.Contains(t, out, "kt_b --> kt_a")
+	assert.Contains(t, out, `kt_a["kt-a: Say #quot;hi#quot;"]`)
+	assert.Contains(t, out, "class kt_c closed")
+}
+
+func TestRunDepGraph(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { depGraphFormat = "dot" }()
+
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	a.Deps = []string{b.ID}
+	require.NoError(t, Store.Save(a))
+
+	require.NoError(t, runDepGraph(nil, nil))
+
+	depGraphFormat = "mermaid"
+	require.NoError(t, runDepGraph(nil, []string{"a"}))
+
+	depGraphFormat = "svg"
+	err := runDepGraph(nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid format")
+}
@@ -0,0 +1,379 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+)
+
+// gqlRequest is a GraphQL request, as a POST body or GET parameters.
+type gqlRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+}
+
+// The relation fields (parent, children, deps, dependents, links) let a
+// query walk the graph as deep as it likes, so queries are limited in how
+// deeply they nest and in how many tickets they return in all.
+var (
+	gqlMaxDepth   = 12
+	gqlMaxTickets = 10000
+)
+
+// gqlGraph is a snapshot of the store with relation indexes, shared by all
+// resolvers of one request.
+type gqlGraph struct {
+	s          *store.Store
+	tickets    []*ticket.Ticket
+	byID       map[string]*ticket.Ticket
+	children   map[string][]*ticket.Ticket
+	dependents map[string][]*ticket.Ticket
+	returned   atomic.Int64 // tickets resolved so far
+}
+
+type gqlGraphKey struct{}
+
+func newGQLGraph(s *store.Store) (*gqlGraph, error) {
+	tickets, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	g := &gqlGraph{
+		s:          s,
+		tickets:    tickets,
+		byID:       make(map[string]*ticket.Ticket, len(tickets)),
+		children:   make(map[string][]*ticket.Ticket),
+		dependents: make(map[string][]*ticket.Ticket),
+	}
+	for _, t := range tickets {
+		g.byID[t.ID] = t
+		if t.Parent != "" {
+			g.children[t.Parent] = append(g.children[t.Parent], t)
+		}
+		for _, dep := range t.Deps {
+			g.dependents[dep] = append(g.dependents[dep], t)
+		}
+	}
+	return g, nil
+}
+
+// lookup returns the tickets with the given IDs, skipping missing ones.
+func (g *gqlGraph) lookup(ids []string) []*ticket.Ticket {
+	out := make([]*ticket.Ticket, 0, len(ids))
+	for _, id := range ids {
+		if t, ok := g.byID[id]; ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// serve counts n more tickets toward the request's gqlMaxTickets.
+func (g *gqlGraph) serve(n int) error {
+	if g.returned.Add(int64(n)) > int64(gqlMaxTickets) {
+		return fmt.Errorf("query returns more than %d tickets; select fewer relations or filter the tickets", gqlMaxTickets)
+	}
+	return nil
+}
+
+// serveList returns ts if it fits within the request's gqlMaxTickets.
+func serveList(p graphql.ResolveParams, ts []*ticket.Ticket) (any, error) {
+	if err := graphFrom(p).serve(len(ts)); err != nil {
+		return nil, err
+	}
+	return nonNil(ts), nil
+}
+
+func graphFrom(p graphql.ResolveParams) *gqlGraph {
+	return p.Context.Value(gqlGraphKey{}).(*gqlGraph)
+}
+
+// gqlLink is a typed relationship as exposed over GraphQL.
+type gqlLink struct {
+	Type   ticket.LinkType `json:"type"`
+	Ticket *ticket.Ticket  `json:"ticket"`
+}
+
+// gqlSchema is built once; its resolvers read the request's gqlGraph.
+var gqlSchema = sync.OnceValues(func() (graphql.Schema, error) {
+	nonNullString := graphql.NewNonNull(graphql.String)
+	stringList := graphql.NewNonNull(graphql.NewList(nonNullString))
+	// optional exposes an empty string as null.
+	optional := func(get func(*ticket.Ticket) string) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (any, error) {
+			if v := get(p.Source.(*ticket.Ticket)); v != "" {
+				return v, nil
+			}
+			return nil, nil
+		}
+	}
+
+	var ticketType *graphql.Object
+	linkType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Link",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"type":   &graphql.Field{Type: nonNullString},
+				"ticket": &graphql.Field{Type: graphql.NewNonNull(ticketType)},
+			}
+		}),
+	})
+	ticketType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Ticket",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			tickets := graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ticketType)))
+			return graphql.Fields{
+				"id":                  &graphql.Field{Type: nonNullString},
+				"title":               &graphql.Field{Type: nonNullString},
+				"status":              &graphql.Field{Type: nonNullString},
+				"type":                &graphql.Field{Type: nonNullString},
+				"priority":            &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"tests_passed":        &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+				"labels":              &graphql.Field{Type: stringList, Resolve: func(p graphql.ResolveParams) (any, error) { return nonNil(p.Source.(*ticket.Ticket).Labels), nil }},
+				"commits":             &graphql.Field{Type: stringList, Resolve: func(p graphql.ResolveParams) (any, error) { return nonNil(p.Source.(*ticket.Ticket).Commits), nil }},
+				"assignee":            &graphql.Field{Type: graphql.String, Resolve: optional(func(t *ticket.Ticket) string { return t.Assignee })},
+				"claimed_by":          &graphql.Field{Type: graphql.String, Resolve: optional(func(t *ticket.Ticket) string { return t.ClaimedBy })},
+				"created":             &graphql.Field{Type: graphql.String, Resolve: optional(func(t *ticket.Ticket) string { return t.Created })},
+				"closed":              &graphql.Field{Type: graphql.String, Resolve: optional(func(t *ticket.Ticket) string { return t.Closed })},
+				"due":                 &graphql.Field{Type: graphql.String, Resolve: optional(func(t *ticket.Ticket) string { return t.Due })},
+				"external_ref":        &graphql.Field{Type: graphql.String, Resolve: optional(func(t *ticket.Ticket) string { return t.ExternalRef })},
+				"resolution":          &graphql.Field{Type: graphql.String, Resolve: optional(func(t *ticket.Ticket) string { return t.Resolution })},
+				"description":         &graphql.Field{Type: graphql.String, Resolve: optional(func(t *ticket.Ticket) string { return t.Description })},
+				"design":              &graphql.Field{Type: graphql.String, Resolve: optional(func(t *ticket.Ticket) string { return t.Design })},
+				"acceptance_criteria": &graphql.Field{Type: graphql.String, Resolve: optional(func(t *ticket.Ticket) string { return t.AcceptanceCriteria })},
+				"tests":               &graphql.Field{Type: graphql.String, Resolve: optional(func(t *ticket.Ticket) string { return t.Tests })},
+				"notes":               &graphql.Field{Type: graphql.String, Resolve: optional(func(t *ticket.Ticket) string { return t.Notes })},
+				"estimate": &graphql.Field{Type: graphql.Float, Resolve: func(p graphql.ResolveParams) (any, error) {
+					if e := p.Source.(*ticket.Ticket).Estimate; e != 0 {
+						return e, nil
+					}
+					return nil, nil
+				}},
+				"blocked": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.Boolean),
+					Description: "Whether the ticket is not closed and has a dep that is not closed",
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						t := p.Source.(*ticket.Ticket)
+						if t.Status == ticket.StatusClosed {
+							return false, nil
+						}
+						for _, dep := range graphFrom(p).lookup(t.Deps) {
+							if dep.Status != ticket.StatusClosed {
+								return true, nil
+							}
+						}
+						return false, nil
+					},
+				},
+				"parent": &graphql.Field{Type: ticketType, Resolve: func(p graphql.ResolveParams) (any, error) {
+					g := graphFrom(p)
+					if parent, ok := g.byID[p.Source.(*ticket.Ticket).Parent]; ok {
+						return parent, g.serve(1)
+					}
+					return nil, nil
+				}},
+				"children": &graphql.Field{Type: tickets, Resolve: func(p graphql.ResolveParams) (any, error) {
+					return serveList(p, graphFrom(p).children[p.Source.(*ticket.Ticket).ID])
+				}},
+				"deps": &graphql.Field{Type: tickets, Resolve: func(p graphql.ResolveParams) (any, error) {
+					return serveList(p, graphFrom(p).lookup(p.Source.(*ticket.Ticket).Deps))
+				}},
+				"dependents": &graphql.Field{Type: tickets, Resolve: func(p graphql.ResolveParams) (any, error) {
+					return serveList(p, graphFrom(p).dependents[p.Source.(*ticket.Ticket).ID])
+				}},
+				"links": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(linkType))),
+					Args: graphql.FieldConfigArgument{
+						"type": &graphql.ArgumentConfig{Type: graphql.String, Description: "Only links of this type, e.g. blocks"},
+					},
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						var only ticket.LinkType
+						if s, _ := p.Args["type"].(string); s != "" {
+							lt, err := ticket.ParseLinkType(s)
+							if err != nil {
+								return nil, err
+							}
+							only = lt
+						}
+						g := graphFrom(p)
+						links := []gqlLink{}
+						for _, l := range p.Source.(*ticket.Ticket).TypedLinks() {
+							if t, ok := g.byID[l.ID]; ok && (only == "" || l.Type == only) {
+								links = append(links, gqlLink{Type: l.Type, Ticket: t})
+							}
+						}
+						return links, g.serve(len(links))
+					},
+				},
+			}
+		}),
+	})
+
+	filterArgs := graphql.FieldConfigArgument{}
+	for _, name := range []string{"status", "type", "assignee", "parent", "filter"} {
+		filterArgs[name] = &graphql.ArgumentConfig{Type: graphql.String}
+	}
+	filterArgs["filter"].Description = "Filter expression, as kt ls --filter"
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"ticket": &graphql.Field{
+				Type:        ticketType,
+				Description: "A ticket by ID, partial ID, or external ref",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					g := graphFrom(p)
+					t, err := g.s.Resolve(p.Args["id"].(string))
+					if err != nil {
+						return nil, err
+					}
+					// Return the snapshot's copy so relations are consistent
+					if snap, ok := g.byID[t.ID]; ok {
+						t = snap
+					}
+					return t, g.serve(1)
+				},
+			},
+			"tickets": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ticketType))),
+				Description: "Tickets matching all given arguments, newest first",
+				Args:        filterArgs,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					filter, err := listFilter(func(name string) string {
+						v, _ := p.Args[name].(string)
+						return v
+					})
+					if err != nil {
+						return nil, err
+					}
+					out := []*ticket.Ticket{}
+					for _, t := range graphFrom(p).tickets {
+						if filter.Match(t) {
+							out = append(out, t)
+						}
+					}
+					return serveList(p, out)
+				},
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+})
+
+// nonNil returns s, or an empty slice if s is nil, for non-null lists.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+func graphqlAPI(s *store.Store, r *http.Request) (int, any, error) {
+	var req gqlRequest
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		req.Query, req.OperationName = q.Get("query"), q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				return 0, nil, badRequest(fmt.Errorf("invalid variables: %w", err))
+			}
+		}
+	} else if err := decodeBody(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Query == "" {
+		return 0, nil, badRequest(errors.New("query is required"))
+	}
+
+	// A query that fails to parse is left for graphql.Do to report
+	if doc, err := parser.Parse(parser.ParseParams{Source: req.Query}); err == nil {
+		if depth := queryDepth(doc); depth > gqlMaxDepth {
+			return 0, nil, badRequest(fmt.Errorf("query nests %d levels deep (at most %d allowed)", depth, gqlMaxDepth))
+		}
+	}
+
+	schema, err := gqlSchema()
+	if err != nil {
+		return 0, nil, err
+	}
+	g, err := newGQLGraph(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        context.WithValue(r.Context(), gqlGraphKey{}, g),
+	})
+	// Queries that fail to parse or validate produce no data
+	if result.Data == nil && result.HasErrors() {
+		return http.StatusBadRequest, result, nil
+	}
+	return http.StatusOK, result, nil
+}
+
+// queryDepth returns how deeply the operations of doc nest field
+// selections, following fragment spreads.
+func queryDepth(doc *ast.Document) int {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[f.Name.Value] = f
+		}
+	}
+	// Fragments are measured once; a cycle (rejected by validation
+	// anyway) counts as no depth
+	fragmentDepth := map[string]int{}
+	var depth func(set *ast.SelectionSet) int
+	depth = func(set *ast.SelectionSet) int {
+		if set == nil {
+			return 0
+		}
+		deepest := 0
+		for _, sel := range set.Selections {
+			d := 0
+			switch sel := sel.(type) {
+			case *ast.Field:
+				d = 1 + depth(sel.SelectionSet)
+			case *ast.InlineFragment:
+				d = depth(sel.SelectionSet)
+			case *ast.FragmentSpread:
+				name := sel.Name.Value
+				known, ok := fragmentDepth[name]
+				if !ok {
+					if f := fragments[name]; f != nil {
+						fragmentDepth[name] = 0
+						known = depth(f.SelectionSet)
+					}
+					fragmentDepth[name] = known
+				}
+				d = known
+			}
+			deepest = max(deepest, d)
+		}
+		return deepest
+	}
+	deepest := 0
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			deepest = max(deepest, depth(op.SelectionSet))
+		}
+	}
+	return deepest
+}
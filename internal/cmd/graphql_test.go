@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gqlResponse decodes a GraphQL response with data of type T.
+type gqlResponse[T any] struct {
+	Data   T `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func TestGraphQL(t *testing.T) {
+	defer setupTestEnv(t)()
+	c := newTestAPI(t)
+	epic := mkTicket(t, "kt-epic", "Epic", ticket.StatusOpen)
+	epic.Type = ticket.TypeEpic
+	require.NoError(t, Store.Save(epic))
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	a.Parent, a.Deps = "kt-epic", []string{"kt-b"}
+	a.AddLink(ticket.LinkBlocks, "kt-c")
+	require.NoError(t, Store.Save(a))
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	b.Parent = "kt-epic"
+	require.NoError(t, Store.Save(b))
+	mkTicket(t, "kt-c", "C", ticket.StatusClosed)
+
+	body, _ := json.Marshal(gqlRequest{
+		Query: `query($id: String!) {
+			ticket(id: $id) {
+				title
+				children { id }
+			}
+			tickets(status: "open", filter: "type=task") {
+				id blocked
+				parent { id }
+				deps { id dependents { id } }
+				links(type: "blocks") { type ticket { id status } }
+			}
+		}`,
+		Variables: map[string]any{"id": "epic"},
+	})
+	var resp gqlResponse[struct {
+		Ticket struct {
+			Title    string
+			Children []struct{ ID string }
+		}
+		Tickets []struct {
+			ID      string
+			Blocked bool
+			Parent  *struct{ ID string }
+			Deps    []struct {
+				ID         string
+				Dependents []struct{ ID string }
+			}
+			Links []struct {
+				Type   string
+				Ticket struct{ ID, Status string }
+			}
+		}
+	}]
+	require.Equal(t, http.StatusOK, c.do("POST", "/graphql", string(body), &resp))
+	require.Empty(t, resp.Errors)
+
+	assert.Equal(t, "Epic", resp.Data.Ticket.Title)
+	assert.Len(t, resp.Data.Ticket.Children, 2)
+
+	require.Len(t, resp.Data.Tickets, 2)
+	byID := map[string]int{}
+	for i, tk := range resp.Data.Tickets {
+		byID[tk.ID] = i
+	}
+	ta := resp.Data.Tickets[byID["kt-a"]]
+	assert.True(t, ta.Blocked)
+	assert.Equal(t, "kt-epic", ta.Parent.ID)
+	require.Len(t, ta.Deps, 1)
+	assert.Equal(t, "kt-b", ta.Deps[0].ID)
+	assert.Equal(t, "kt-a", ta.Deps[0].Dependents[0].ID)
+	require.Len(t, ta.Links, 1)
+	assert.Equal(t, "blocks", ta.Links[0].Type)
+	assert.Equal(t, "closed", ta.Links[0].Ticket.Status)
+	assert.False(t, resp.Data.Tickets[byID["kt-b"]].Blocked)
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	defer setupTestEnv(t)()
+	c := newTestAPI(t)
+
+	var resp gqlResponse[map[string]any]
+	require.Equal(t, http.StatusBadRequest, c.do("GET", "/graphql?query="+url.QueryEscape("{ tickets { nope } }"), "", &resp))
+	require.NotEmpty(t, resp.Errors)
+	assert.Contains(t, resp.Errors[0].Message, "nope")
+
+	resp = gqlResponse[map[string]any]{}
+	require.Equal(t, http.StatusOK, c.do("GET", "/graphql?query="+url.QueryEscape(`{ ticket(id: "zzz") { id } }`), "", &resp))
+	require.NotEmpty(t, resp.Errors)
+	assert.Contains(t, resp.Errors[0].Message, "not found")
+
+	var apiErr map[string]string
+	require.Equal(t, http.StatusBadRequest, c.do("POST", "/graphql", `{}`, &apiErr))
+	assert.Equal(t, "query is required", apiErr["error"])
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	defer setupTestEnv(t)()
+	c := newTestAPI(t)
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	a.Deps = []string{"kt-b"}
+	require.NoError(t, Store.Save(a))
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	b.Deps = []string{"kt-a"}
+	require.NoError(t, Store.Save(b))
+
+	// Nesting through a fragment counts toward the depth
+	deep := "fragment F on Ticket { deps { deps { deps { deps { id } } } } } { tickets { deps { deps { deps { deps { deps { deps { deps { ...F } } } } } } } } }"
+	var apiErr map[string]string
+	require.Equal(t, http.StatusBadRequest, c.do("GET", "/graphql?query="+url.QueryEscape(deep), "", &apiErr))
+	assert.Contains(t, apiErr["error"], "13 levels deep")
+
+	gqlMaxTickets = 5
+	defer func() { gqlMaxTickets = 10000 }()
+	var resp gqlResponse[map[string]any]
+	require.Equal(t, http.StatusOK, c.do("GET", "/graphql?query="+url.QueryEscape("{ tickets { deps { id } } }"), "", &resp))
+	assert.Empty(t, resp.Errors)
+	resp = gqlResponse[map[string]any]{}
+	require.Equal(t, http.StatusBadRequest, c.do("GET", "/graphql?query="+url.QueryEscape("{ tickets { deps { deps { deps { id } } } } }"), "", &resp))
+	require.NotEmpty(t, resp.Errors)
+	assert.Contains(t, resp.Errors[0].Message, "more than 5 tickets")
+}
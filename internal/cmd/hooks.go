@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+// hookMarker identifies hooks written by kt, so they can be updated and
+// uninstalled without touching hooks the user wrote.
+const hookMarker = "# Installed by kt"
+
+// postMergeHook scans the commits brought in by a merge or pull.
+const postMergeHook = `#!/bin/sh
+` + hookMarker + `: close tickets referenced in merged commits
+kt scan-commits --since ORIG_HEAD
+`
+
+// commitMsgHook references the current ticket in each commit message.
+const commitMsgHook = `#!/bin/sh
+` + hookMarker + `: reference the current ticket in commit messages
+kt hook commit-msg "$1" || true
+`
+
+var installHooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Install git hooks that reference tickets in commits",
+	Long: `Install a commit-msg hook that appends "Refs: <id>" to commit messages.
+The ticket is taken from the branch name (e.g. kt-a1b2-fix-login), or else
+from the single in_progress ticket assigned to you.
+
+With --uninstall, removes every hook kt installed (including the
+post-merge hook from 'kt scan-commits --install-hook').`,
+	Args: cobra.NoArgs,
+	RunE: runInstallHooks,
+}
+
+var hookCmd = &cobra.Command{
+	Use:    "hook <name> [args...]",
+	Short:  "Run a git hook (called from hooks installed by kt)",
+	Hidden: true,
+}
+
+var hookCommitMsgCmd = &cobra.Command{
+	Use:   "commit-msg <file>",
+	Short: "Append the current ticket reference to a commit message file",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHookCommitMsg,
+}
+
+var hooksUninstall bool
+
+func init() {
+	installHooksCmd.Flags().BoolVar(&hooksUninstall, "uninstall", false, "Remove hooks installed by kt")
+	installCmd.AddCommand(installHooksCmd)
+
+	hookCmd.AddCommand(hookCommitMsgCmd)
+	rootCmd.AddCommand(hookCmd)
+}
+
+func runInstallHooks(cmd *cobra.Command, args []string) error {
+	if hooksUninstall {
+		return uninstallHooks()
+	}
+	return installHook("commit-msg", commitMsgHook)
+}
+
+// installHook writes a hook script, refusing to overwrite a hook kt did
+// not install.
+func installHook(name, content string) error {
+	dir, err := git.HooksDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, name)
+	if data, err := os.ReadFile(path); err == nil && !strings.Contains(string(data), hookMarker) {
+		return fmt.Errorf("%s already exists and was not installed by kt; merge it manually", path)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create hooks dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		return fmt.Errorf("write hook: %w", err)
+	}
+	fmt.Printf("Installed %s\n", path)
+	return nil
+}
+
+// uninstallHooks removes every hook carrying hookMarker.
+func uninstallHooks() error {
+	dir, err := git.HooksDir()
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read hooks dir: %w", err)
+	}
+	removed := 0
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil || !strings.Contains(string(data), hookMarker) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("remove hook: %w", err)
+		}
+		fmt.Printf("Removed %s\n", path)
+		removed++
+	}
+	if removed == 0 {
+		fmt.Println("No kt hooks installed")
+	}
+	return nil
+}
+
+func runHookCommitMsg(cmd *cobra.Command, args []string) error {
+	id := currentTicketID()
+	if id == "" {
+		return nil
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("read commit message: %w", err)
+	}
+	msg := addTicketTrailer(string(data), id)
+	if msg == string(data) {
+		return nil
+	}
+	return os.WriteFile(args[0], []byte(msg), 0644)
+}
+
+// currentTicketID returns the ticket being worked on: the longest ticket
+// ID contained in the branch name, or else the only in_progress ticket
+// assigned to the git user. Returns "" if there is none.
+func currentTicketID() string {
+	tickets, err := Store.List()
+	if err != nil {
+		return ""
+	}
+
+	if id := branchTicketID(tickets); id != "" {
+		return id
+	}
+
+	user := getGitUser()
+	var active []string
+	for _, t := range tickets {
+		if t.Status == ticket.StatusInProgress && user != "" && t.Assignee == user {
+			active = append(active, t.ID)
+		}
+	}
+	if len(active) == 1 {
+		return active[0]
+	}
+	return ""
+}
+
+// branchTicketID returns the longest ticket ID found in the current branch name.
+func branchTicketID(tickets []*ticket.Ticket) string {
+	branch, err := git.CurrentBranch()
+	if err != nil {
+		return ""
+	}
+	best := ""
+	for _, t := range tickets {
+		if strings.Contains(branch, t.ID) && len(t.ID) > len(best) {
+			best = t.ID
+		}
+	}
+	return best
+}
+
+var trailerLine = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*: `)
+
+// addTicketTrailer appends "Refs: <id>" to a commit message unless the
+// message is empty or already mentions id. Trailing comment lines (as left
+// by git's editor template) stay at the end.
+func addTicketTrailer(msg, id string) string {
+	lines := strings.Split(strings.TrimRight(msg, "\n"), "\n")
+
+	// Split off the trailing block of comments and blank lines
+	end := len(lines)
+	for end > 0 && (strings.HasPrefix(lines[end-1], "#") || strings.TrimSpace(lines[end-1]) == "") {
+		end--
+	}
+	body, tail := lines[:end], lines[end:]
+	if len(body) == 0 {
+		return msg
+	}
+	for _, l := range body {
+		if !strings.HasPrefix(l, "#") && strings.Contains(l, id) {
+			return msg
+		}
+	}
+
+	out := append([]string(nil), body...)
+	if !trailerLine.MatchString(body[len(body)-1]) || len(body) == 1 {
+		out = append(out, "")
+	}
+	out = append(out, "Refs: "+id)
+	out = append(out, tail...)
+	return strings.Join(out, "\n") + "\n"
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddTicketTrailer(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"subject only", "fix: login\n", "fix: login\n\nRefs: kt-a1\n"},
+		{"body", "Fix login\n\nDetails here\n", "Fix login\n\nDetails here\n\nRefs: kt-a1\n"},
+		{"existing trailers", "Fix\n\nSigned-off-by: me\n", "Fix\n\nSigned-off-by: me\nRefs: kt-a1\n"},
+		{"comments kept last", "Fix\n\n# Please enter\n# the message\n", "Fix\n\nRefs: kt-a1\n\n# Please enter\n# the message\n"},
+		{"already referenced", "Fix kt-a1 bug\n", "Fix kt-a1 bug\n"},
+		{"empty message", "\n# comment\n", "\n# comment\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, addTicketTrailer(tt.in, "kt-a1"))
+		})
+	}
+}
+
+func TestCurrentTicketIDFromBranch(t *testing.T) {
+	defer setupTestEnv(t)()
+	initGitRepo(t)
+	gitCommit(t, "initial")
+
+	mkTicket(t, "kt-a1b2", "Login", ticket.StatusOpen)
+	assert.Equal(t, "", currentTicketID())
+
+	_, err := git.Run("checkout", "-q", "-b", "feature/kt-a1b2-login")
+	require.NoError(t, err)
+	assert.Equal(t, "kt-a1b2", currentTicketID())
+
+	msgFile := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	require.NoError(t, os.WriteFile(msgFile, []byte("Fix login\n"), 0644))
+	require.NoError(t, runHookCommitMsg(nil, []string{msgFile}))
+	data, err := os.ReadFile(msgFile)
+	require.NoError(t, err)
+	assert.Equal(t, "Fix login\n\nRefs: kt-a1b2\n", string(data))
+}
+
+func TestInstallHooks(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { hooksUninstall = false }()
+	dir := initGitRepo(t)
+	hooks := filepath.Join(dir, ".git", "hooks")
+
+	require.NoError(t, runInstallHooks(nil, nil))
+	data, err := os.ReadFile(filepath.Join(hooks, "commit-msg"))
+	require.NoError(t, err)
+	assert.Equal(t, commitMsgHook, string(data))
+	require.NoError(t, installHook("post-merge", postMergeHook))
+
+	// A user's own hook is left alone
+	require.NoError(t, os.WriteFile(filepath.Join(hooks, "pre-push"), []byte("#!/bin/sh\n"), 0755))
+
+	hooksUninstall = true
+	require.NoError(t, runInstallHooks(nil, nil))
+	assert.NoFileExists(t, filepath.Join(hooks, "commit-msg"))
+	assert.NoFileExists(t, filepath.Join(hooks, "post-merge"))
+	assert.FileExists(t, filepath.Join(hooks, "pre-push"))
+}
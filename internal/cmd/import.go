@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/github"
+	"github.com/kostyay/kticket/internal/gitlab"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import tickets from other trackers",
+}
+
+var importGitHubCmd = &cobra.Command{
+	Use:   "github",
+	Short: "Import GitHub issues as tickets",
+	Long: `Import a repository's issues as tickets with external-ref
+gh:<owner>/<repo>#<number>, so issues of different repositories stay apart.
+Title, body, labels, assignee, and open/closed state carry over; labels
+set the ticket type (see --type-map; of several, the last wins). Issues
+already imported (a ticket with the same external-ref exists) are skipped,
+so importing again only picks up new issues.
+
+Set GITHUB_TOKEN (or GH_TOKEN) for private repositories and higher rate
+limits.
+
+  kt import github --repo acme/app --state all`,
+	Args: cobra.NoArgs,
+	RunE: runImportGitHub,
+}
+
+var importGitLabCmd = &cobra.Command{
+	Use:   "gitlab",
+	Short: "Import GitLab issues as tickets",
+	Long: `Import a project's issues as tickets with external-ref
+gl:<group>/<project>#<iid>, the same way 'kt import github' does. Set GITLAB_URL for self-hosted GitLab
+and GITLAB_TOKEN for private projects.
+
+  kt import gitlab --project group/app --type-map incident=bug`,
+	Args: cobra.NoArgs,
+	RunE: runImportGitLab,
+}
+
+var (
+	importRepo    string
+	importProject string
+	importState   string
+	importTypeMap string
+
+	// newGitHubClient and newGitLabClient are replaced in tests.
+	newGitHubClient = github.NewClient
+	newGitLabClient = gitlab.NewClient
+)
+
+// defaultLabelTypes maps issue labels (lowercase) to ticket types.
+var defaultLabelTypes = map[string]ticket.Type{
+	"bug":         ticket.TypeBug,
+	"enhancement": ticket.TypeFeature,
+	"feature":     ticket.TypeFeature,
+	"epic":        ticket.TypeEpic,
+	"chore":       ticket.TypeChore,
+}
+
+func init() {
+	importGitHubCmd.Flags().StringVar(&importRepo, "repo", "", "Repository as owner/name (default: integrations.github_repo in config.yml)")
+	importGitLabCmd.Flags().StringVar(&importProject, "project", "", "Project path as group/name (default: integrations.gitlab_project in config.yml)")
+
+	for _, c := range []*cobra.Command{importGitHubCmd, importGitLabCmd} {
+		c.Flags().StringVar(&importState, "state", "open", "Issue state to import (open|closed|all)")
+		c.Flags().StringVar(&importTypeMap, "type-map", "",
+			"Extra label=type rules, e.g. incident=bug,story=feature, over import.type_map in config.yml (bug, enhancement, feature, epic, chore are built in)")
+		importCmd.AddCommand(c)
+	}
+	rootCmd.AddCommand(importCmd)
+}
+
+type importedTicket struct {
+	ID          string `json:"id"`
+	ExternalRef string `json:"external_ref,omitempty"`
+	Title       string `json:"title"`
+}
+
+type importResult struct {
+	Imported []importedTicket `json:"imported"`
+	Skipped  []string         `json:"skipped,omitempty"` // external refs already imported
+}
+
+func runImportGitHub(cmd *cobra.Command, args []string) error {
+	rules, err := importRules()
+	if err != nil {
+		return err
+	}
+	repo, err := orConfig(importRepo, "--repo", "github_repo", projectConfig().Integrations.GitHubRepo)
+	if err != nil {
+		return err
+	}
+	issues, err := newGitHubClient().Issues(repo, importState)
+	if err != nil {
+		return err
+	}
+
+	tickets := make([]*ticket.Ticket, 0, len(issues))
+	for _, is := range issues {
+		tickets = append(tickets, issueTicket(repo, is, rules))
+	}
+	result, err := importTickets(tickets)
+	if err != nil {
+		return err
+	}
+	return printImport(result)
+}
+
+func runImportGitLab(cmd *cobra.Command, args []string) error {
+	rules, err := importRules()
+	if err != nil {
+		return err
+	}
+	state := importState
+	if state == "open" {
+		state = "opened"
+	}
+	project, err := orConfig(importProject, "--project", "gitlab_project", projectConfig().Integrations.GitLabProject)
+	if err != nil {
+		return err
+	}
+	issues, err := newGitLabClient().Issues(project, state)
+	if err != nil {
+		return err
+	}
+
+	tickets := make([]*ticket.Ticket, 0, len(issues))
+	for _, is := range issues {
+		tickets = append(tickets, gitlabIssueTicket(project, is, rules))
+	}
+	result, err := importTickets(tickets)
+	if err != nil {
+		return err
+	}
+	return printImport(result)
+}
+
+// importRules validates --state and returns the label rules: the defaults,
+// then import.type_map from config.yml, then --type-map.
+func importRules() (map[string]ticket.Type, error) {
+	if !slices.Contains([]string{"open", "closed", "all"}, importState) {
+		return nil, fmt.Errorf("invalid --state %q (expected open, closed, or all)", importState)
+	}
+	rules := maps.Clone(defaultLabelTypes)
+	typeMap := projectConfig().Import.TypeMap
+	for _, label := range slices.Sorted(maps.Keys(typeMap)) {
+		if err := addTypeRule(rules, label, typeMap[label]); err != nil {
+			return nil, fmt.Errorf("%s: import.type_map: %w", config.ProjectFile, err)
+		}
+	}
+	return parseTypeMap(importTypeMap, rules)
+}
+
+// parseTypeMap parses label=type rules on top of a copy of base.
+func parseTypeMap(s string, base map[string]ticket.Type) (map[string]ticket.Type, error) {
+	rules := maps.Clone(base)
+	for _, rule := range strings.Split(s, ",") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+		label, typ, ok := strings.Cut(rule, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --type-map rule %q (expected label=type)", rule)
+		}
+		if err := addTypeRule(rules, label, typ); err != nil {
+			return nil, fmt.Errorf("invalid --type-map rule %q: %w", rule, err)
+		}
+	}
+	return rules, nil
+}
+
+// addTypeRule adds the rule that label (in any case) makes a ticket typ.
+func addTypeRule(rules map[string]ticket.Type, label, typ string) error {
+	label, typ = strings.ToLower(strings.TrimSpace(label)), strings.TrimSpace(typ)
+	if label == "" {
+		return fmt.Errorf("empty label")
+	}
+	if !slices.Contains(ticket.Types, ticket.Type(typ)) {
+		return fmt.Errorf("invalid type %q for label %s (expected one of %v)", typ, label, ticket.Types)
+	}
+	rules[label] = ticket.Type(typ)
+	return nil
+}
+
+// labelType returns the type of the last label with a rule, or task.
+func labelType(labels []string, rules map[string]ticket.Type) ticket.Type {
+	typ := ticket.TypeTask
+	for _, l := range labels {
+		if t, ok := rules[strings.ToLower(l)]; ok {
+			typ = t
+		}
+	}
+	return typ
+}
+
+// importTickets saves tickets whose external-ref is not in the store yet,
+// ignoring case (tickets without one are always saved), assigning each a
+// new ID.
+func importTickets(tickets []*ticket.Ticket) (*importResult, error) {
+	existing, err := Store.List()
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		if t.ExternalRef != "" {
+			refs[strings.ToLower(t.ExternalRef)] = true
+		}
+	}
+
+	result := &importResult{Imported: []importedTicket{}}
+	for _, t := range tickets {
+		if t.ExternalRef != "" && refs[strings.ToLower(t.ExternalRef)] {
+			result.Skipped = append(result.Skipped, t.ExternalRef)
+			continue
+		}
+		id, err := newTicketID(Store)
+		if err != nil {
+			return nil, fmt.Errorf("generate ID: %w", err)
+		}
+		t.ID = id
+		if err := Store.Save(t); err != nil {
+			return nil, fmt.Errorf("save ticket: %w", err)
+		}
+		if t.ExternalRef != "" {
+			refs[strings.ToLower(t.ExternalRef)] = true
+		}
+		result.Imported = append(result.Imported, importedTicket{ID: t.ID, ExternalRef: t.ExternalRef, Title: t.Title})
+	}
+	return result, nil
+}
+
+func printImport(result *importResult) error {
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	for _, it := range result.Imported {
+		if it.ExternalRef == "" {
+			fmt.Printf("%s  %s\n", it.ID, it.Title)
+			continue
+		}
+		fmt.Printf("%s  %s  %s\n", it.ID, it.ExternalRef, it.Title)
+	}
+	fmt.Printf("Imported %d, skipped %d already imported\n", len(result.Imported), len(result.Skipped))
+	return nil
+}
+
+// issueTicket converts an issue of the GitHub repository repo to a ticket
+// (without an ID).
+func issueTicket(repo string, is github.Issue, rules map[string]ticket.Type) *ticket.Ticket {
+	labels := make([]string, 0, len(is.Labels))
+	for _, l := range is.Labels {
+		labels = append(labels, l.Name)
+	}
+	assignee := ""
+	if is.Assignee != nil {
+		assignee = is.Assignee.Login
+	}
+	return importedIssue(fmt.Sprintf("gh:%s#%d", repo, is.Number), is.Title, is.Body, labels, assignee,
+		is.State == "closed", is.CreatedAt, is.ClosedAt, rules)
+}
+
+// gitlabIssueTicket converts an issue of the GitLab project to a ticket
+// (without an ID).
+func gitlabIssueTicket(project string, is gitlab.Issue, rules map[string]ticket.Type) *ticket.Ticket {
+	assignee := ""
+	if is.Assignee != nil {
+		assignee = is.Assignee.Username
+	}
+	return importedIssue(fmt.Sprintf("gl:%s#%d", project, is.IID), is.Title, is.Description, is.Labels, assignee,
+		is.State == "closed", is.CreatedAt, is.ClosedAt, rules)
+}
+
+// importedIssue builds a ticket from the fields issue trackers share.
+func importedIssue(ref, title, body string, labels []string, assignee string, closed bool,
+	created, closedAt time.Time, rules map[string]ticket.Type) *ticket.Ticket {
+	t := &ticket.Ticket{
+		Status:      ticket.StatusOpen,
+		Created:     created.UTC().Format(time.RFC3339),
+		Type:        labelType(labels, rules),
+		Priority:    2,
+		Assignee:    assignee,
+		ExternalRef: ref,
+		Labels:      labels,
+		Title:       title,
+		Description: strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n")),
+	}
+	if len(t.Labels) == 0 {
+		t.Labels = nil
+	}
+	if created.IsZero() {
+		t.Created = time.Now().UTC().Format(time.RFC3339)
+	}
+	if closed {
+		t.Status = ticket.StatusClosed
+		t.Closed = closedAt.UTC().Format(time.RFC3339)
+		if closedAt.IsZero() {
+			t.Closed = t.Created
+		}
+	}
+	return t
+}
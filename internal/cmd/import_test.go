@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/github"
+	"github.com/kostyay/kticket/internal/gitlab"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHub serves handler as the GitHub API for the duration of the test.
+func fakeGitHub(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	orig := newGitHubClient
+	newGitHubClient = func() *github.Client {
+		return &github.Client{BaseURL: srv.URL, HTTP: srv.Client()}
+	}
+	t.Cleanup(func() { newGitHubClient = orig })
+}
+
+func TestRunImportGitHub(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { importRepo, importState = "", "open" }()
+	fakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "all", r.URL.Query().Get("state"))
+		fmt.Fprint(w, `[
+			{"number":7,"title":"Crash on login","body":"Steps:\r\n1. log in","state":"open",
+			 "labels":[{"name":"bug"},{"name":"auth"}],"assignee":{"login":"alice"},"created_at":"2026-01-02T03:04:05Z"},
+			{"number":8,"title":"Dark mode","state":"closed","labels":[{"name":"enhancement"}],
+			 "created_at":"2026-01-01T00:00:00Z","closed_at":"2026-01-05T00:00:00Z"},
+			{"number":9,"title":"Already here","state":"open"}
+		]`)
+	})
+	existing := mkTicket(t, "kt-old", "Already here", ticket.StatusOpen)
+	existing.ExternalRef = "gh:Acme/App#9"
+	require.NoError(t, Store.Save(existing))
+
+	importRepo, importState = "acme/app", "all"
+	require.NoError(t, runImportGitHub(nil, nil))
+
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	require.Len(t, tickets, 3)
+	byRef := make(map[string]*ticket.Ticket)
+	for _, tk := range tickets {
+		byRef[tk.ExternalRef] = tk
+	}
+
+	bug := byRef["gh:acme/app#7"]
+	require.NotNil(t, bug)
+	assert.Equal(t, "Crash on login", bug.Title)
+	assert.Equal(t, "Steps:\n1. log in", bug.Description)
+	assert.Equal(t, ticket.TypeBug, bug.Type)
+	assert.Equal(t, []string{"bug", "auth"}, bug.Labels)
+	assert.Equal(t, "alice", bug.Assignee)
+	assert.Equal(t, "2026-01-02T03:04:05Z", bug.Created)
+
+	feat := byRef["gh:acme/app#8"]
+	require.NotNil(t, feat)
+	assert.Equal(t, ticket.TypeFeature, feat.Type)
+	assert.Equal(t, ticket.StatusClosed, feat.Status)
+	assert.Equal(t, "2026-01-05T00:00:00Z", feat.Closed)
+
+	// Importing again adds nothing, but another repository's issues are new
+	require.NoError(t, runImportGitHub(nil, nil))
+	tickets, _ = Store.List()
+	assert.Len(t, tickets, 3)
+	importRepo = "acme/web"
+	require.NoError(t, runImportGitHub(nil, nil))
+	tickets, _ = Store.List()
+	assert.Len(t, tickets, 6)
+
+	importState = "bogus"
+	assert.Error(t, runImportGitHub(nil, nil))
+}
+
+func TestRunImportGitLab(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { importProject, importTypeMap = "", "" }()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "opened", r.URL.Query().Get("state"))
+		fmt.Fprint(w, `[
+			{"iid":3,"title":"Pager went off","description":"db down","state":"opened",
+			 "labels":["incident","ops"],"assignee":{"username":"bob"},"created_at":"2026-01-02T03:04:05Z"},
+			{"iid":4,"title":"Docs","state":"opened","labels":[]}
+		]`)
+	}))
+	defer srv.Close()
+	orig := newGitLabClient
+	newGitLabClient = func() *gitlab.Client { return &gitlab.Client{BaseURL: srv.URL, HTTP: srv.Client()} }
+	defer func() { newGitLabClient = orig }()
+
+	importProject, importTypeMap = "group/app", "incident=bug"
+	require.NoError(t, runImportGitLab(nil, nil))
+
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	require.Len(t, tickets, 2)
+	byRef := make(map[string]*ticket.Ticket)
+	for _, tk := range tickets {
+		byRef[tk.ExternalRef] = tk
+	}
+	inc := byRef["gl:group/app#3"]
+	require.NotNil(t, inc)
+	assert.Equal(t, ticket.TypeBug, inc.Type)
+	assert.Equal(t, "bob", inc.Assignee)
+	assert.Equal(t, []string{"incident", "ops"}, inc.Labels)
+	assert.Equal(t, ticket.TypeTask, byRef["gl:group/app#4"].Type)
+	assert.Nil(t, byRef["gl:group/app#4"].Labels)
+}
+
+func TestParseTypeMap(t *testing.T) {
+	rules, err := parseTypeMap("Incident=bug, story=feature", defaultLabelTypes)
+	require.NoError(t, err)
+	assert.Equal(t, ticket.TypeBug, rules["incident"])
+	assert.Equal(t, ticket.TypeFeature, rules["story"])
+	assert.Equal(t, ticket.TypeBug, rules["bug"], "defaults are kept")
+	assert.NotContains(t, defaultLabelTypes, "incident")
+
+	_, err = parseTypeMap("incident=outage", defaultLabelTypes)
+	assert.ErrorContains(t, err, `invalid type "outage"`)
+	_, err = parseTypeMap("incident", defaultLabelTypes)
+	assert.Error(t, err)
+	_, err = parseTypeMap("=bug", defaultLabelTypes)
+	assert.Error(t, err)
+
+	assert.Equal(t, ticket.TypeBug, labelType([]string{"EPIC", "bug"}, defaultLabelTypes), "the last label with a rule wins")
+	assert.Equal(t, ticket.TypeTask, labelType(nil, defaultLabelTypes))
+}
+
+func TestImportRulesConfig(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { importTypeMap = "" }()
+	writeProjectConfig(t, `
+import:
+  type_map:
+    incident: bug
+    story: feature
+`)
+	rules, err := importRules()
+	require.NoError(t, err)
+	assert.Equal(t, ticket.TypeBug, rules["incident"])
+	assert.Equal(t, ticket.TypeFeature, rules["story"])
+
+	importTypeMap = "story=chore"
+	rules, err = importRules()
+	require.NoError(t, err)
+	assert.Equal(t, ticket.TypeChore, rules["story"], "--type-map overrides config")
+	assert.Equal(t, ticket.TypeBug, rules["incident"])
+
+	writeProjectConfig(t, "import:\n  type_map:\n    incident: outage\n")
+	_, err = importRules()
+	assert.ErrorContains(t, err, "import.type_map")
+}
@@ -3,9 +3,11 @@ package cmd
 import (
 	"bufio"
 	"embed"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -16,72 +18,233 @@ import (
 //go:embed templates/*
 var templatesFS embed.FS
 
+// TemplatesDir is the directory in the store where a project keeps its own
+// kt.md and slash command templates, which replace or add to the built-in
+// ones.
+const TemplatesDir = "claude-templates"
+
+// builtinCommands are the slash commands kt always installs.
+var builtinCommands = []string{"kt-create.md", "kt-run.md", "kt-run-all.md"}
+
+var installTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Copy the built-in templates into the store for customizing",
+	Long: `Copy kt.md and the built-in slash command templates into
+.ktickets/` + TemplatesDir + `/, skipping files that already exist. kt install
+then uses the copies in place of the built-in ones. Any other .md file there
+is installed as an additional slash command.`,
+	Args: cobra.NoArgs,
+	RunE: runInstallTemplates,
+}
+
 var installCmd = &cobra.Command{
 	Use:   "install",
-	Short: "Install kt.md and Claude slash commands",
-	Long:  "Creates kt.md file and optionally installs Claude slash commands and permissions",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		cwd, err := os.Getwd()
-		if err != nil {
-			return fmt.Errorf("get working directory: %w", err)
+	Short: "Install kt.md and agent slash commands",
+	Long: `Creates kt.md and sets up a coding agent to use kt: Claude Code (slash
+commands, permission, and hooks, globally or for the project), Cursor (.cursor/
+rule, commands, and CLI permission), Windsurf (.windsurf/ rule and
+workflows), Gemini CLI (.gemini/ commands, context, and allowed tool), or
+OpenCode and Codex CLI (kt section in AGENTS.md and kt permission). Pick
+with --target, or at the prompt.`,
+	Args: cobra.NoArgs,
+	RunE: runInstall,
+}
+
+var installTargetFlag []string
+
+// installTarget is an agent kt install can set up.
+type installTarget struct {
+	name    string // for --target
+	label   string // for the prompt
+	install func(reader *bufio.Reader) error
+}
+
+// installTargets are the agents kt install can set up.
+var installTargets = []installTarget{
+	{"claude", "Claude Code", installClaude},
+	{"cursor", "Cursor", func(*bufio.Reader) error { return installCursor() }},
+	{"windsurf", "Windsurf", func(*bufio.Reader) error { return installWindsurf() }},
+	{"gemini", "Gemini CLI", func(*bufio.Reader) error { return installGemini() }},
+	{"opencode", "OpenCode", func(*bufio.Reader) error { return installOpenCode() }},
+	{"codex", "Codex CLI", func(*bufio.Reader) error { return installCodex() }},
+}
+
+// installTargetNames lists the --target names, e.g. for error messages.
+func installTargetNames() string {
+	names := make([]string, len(installTargets))
+	for i, t := range installTargets {
+		names[i] = t.name
+	}
+	return strings.Join(names, ", ")
+}
+
+func runInstall(cmd *cobra.Command, args []string) error {
+	var targets []int
+	for _, name := range installTargetFlag {
+		i := slices.IndexFunc(installTargets, func(t installTarget) bool { return t.name == name })
+		if i < 0 {
+			return fmt.Errorf("unknown target %q (expected one of %s)", name, installTargetNames())
 		}
+		targets = append(targets, i)
+	}
 
-		reader := bufio.NewReader(os.Stdin)
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
 
-		// Install kt.md
-		ktMdPath := filepath.Join(cwd, "kt.md")
-		if _, err := os.Stat(ktMdPath); err == nil {
-			if !promptYesNo(reader, "kt.md already exists. Regenerate?") {
-				fmt.Println("Skipped kt.md")
-			} else {
-				if err := writeKtMd(ktMdPath); err != nil {
-					return err
-				}
-			}
+	reader := bufio.NewReader(os.Stdin)
+
+	// Install kt.md
+	ktMdPath := filepath.Join(cwd, "kt.md")
+	if _, err := os.Stat(ktMdPath); err == nil {
+		if !promptYesNo(reader, "kt.md already exists. Regenerate?") {
+			fmt.Println("Skipped kt.md")
 		} else {
 			if err := writeKtMd(ktMdPath); err != nil {
 				return err
 			}
 		}
+	} else {
+		if err := writeKtMd(ktMdPath); err != nil {
+			return err
+		}
+	}
 
-		// Install slash commands
-		globalDir := getClaudeConfigDir()
-		cmdChoice := promptChoice(reader, "Install slash commands (/kt-create, /kt-run, /kt-run-all)?", []string{
-			fmt.Sprintf("Global (%s/commands/)", globalDir),
-			"Project (.claude/commands/)",
-			"Skip",
-		})
-		if cmdChoice != 3 {
-			global := cmdChoice == 1
-			if err := installSlashCommands(global); err != nil {
-				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
-			}
+	if promptYesNo(reader, fmt.Sprintf("Also add kt.md as a section of %s?", strings.Join(instructionFiles, " and "))) {
+		if err := installInstructions(instructionFiles); err != nil {
+			return err
 		}
+	}
 
-		// Install kt permission
-		permChoice := promptChoice(reader, "Add kt permission (allows Claude to run kt commands without prompting)?", []string{
-			fmt.Sprintf("Global (%s/settings.json)", globalDir),
-			"Project (.claude/settings.local.json)",
-			"Skip",
-		})
-		if permChoice != 3 {
-			global := permChoice == 1
-			if err := registerKtPermission(global); err != nil {
-				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
-			}
+	if len(targets) == 0 {
+		labels := make([]string, len(installTargets))
+		for i, t := range installTargets {
+			labels[i] = t.label
 		}
+		targets = []int{promptChoiceDefault(reader, "Set up which agent?", labels, 1) - 1}
+	}
+	for _, i := range targets {
+		if err := installTargets[i].install(reader); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		return nil
-	},
+// installClaude installs the Claude slash commands and kt permission,
+// asking whether globally or for the project.
+func installClaude(reader *bufio.Reader) error {
+	// Install slash commands
+	globalDir := getClaudeConfigDir()
+	var names []string
+	for _, name := range slashCommands() {
+		names = append(names, commandName(name))
+	}
+	cmdChoice := promptChoice(reader, fmt.Sprintf("Install slash commands (%s)?", strings.Join(names, ", ")), []string{
+		fmt.Sprintf("Global (%s/commands/)", globalDir),
+		"Project (.claude/commands/)",
+		"Skip",
+	})
+	if cmdChoice != 3 {
+		global := cmdChoice == 1
+		if err := installSlashCommands(global); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+
+	// Install kt permission
+	permChoice := promptChoice(reader, "Add kt permission (allows Claude to run kt commands without prompting)?", []string{
+		fmt.Sprintf("Global (%s/settings.json)", globalDir),
+		"Project (.claude/settings.local.json)",
+		"Skip",
+	})
+	if permChoice != 3 {
+		global := permChoice == 1
+		if err := registerKtPermission(global); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+
+	// Install hooks
+	hookChoice := promptChoice(reader, "Add Claude hooks (kt prime at session start, warn on edits with no ticket in progress)?", []string{
+		fmt.Sprintf("Global (%s/settings.json)", globalDir),
+		"Project (.claude/settings.local.json)",
+		"Skip",
+	})
+	if hookChoice != 3 {
+		if err := registerClaudeHooks(hookChoice == 1); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+	return nil
 }
 
 func init() {
+	installCmd.Flags().StringSliceVar(&installTargetFlag, "target", nil, "Agents to set up: "+installTargetNames()+" (default: ask)")
+	installCmd.AddCommand(installTemplatesCmd)
 	rootCmd.AddCommand(installCmd)
 }
 
-// writeKtMd writes kt.md from embedded template.
+// templatePath returns the path of the project's template name.
+func templatePath(name string) string {
+	return filepath.Join(currentDir(), TemplatesDir, name)
+}
+
+// readTemplate returns the project's template name if it has one, else the
+// built-in one.
+func readTemplate(name string) ([]byte, error) {
+	content, err := os.ReadFile(templatePath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return templatesFS.ReadFile("templates/" + name)
+	}
+	return content, err
+}
+
+// slashCommands returns the templates to install as slash commands: the
+// built-in ones and any other .md files in the project's templates.
+func slashCommands() []string {
+	commands := slices.Clone(builtinCommands)
+	matches, _ := filepath.Glob(templatePath("*.md"))
+	for _, path := range matches {
+		name := filepath.Base(path)
+		if name != "kt.md" && !slices.Contains(commands, name) {
+			commands = append(commands, name)
+		}
+	}
+	return commands
+}
+
+// commandName returns the slash command for a template, e.g. /kt-run.
+func commandName(template string) string {
+	return "/" + strings.TrimSuffix(template, ".md")
+}
+
+func runInstallTemplates(cmd *cobra.Command, args []string) error {
+	if err := os.MkdirAll(templatePath(""), 0755); err != nil {
+		return fmt.Errorf("create templates directory: %w", err)
+	}
+	for _, name := range append([]string{"kt.md"}, builtinCommands...) {
+		path := templatePath(name)
+		if _, err := os.Stat(path); err == nil {
+			fmt.Printf("Kept %s\n", path)
+			continue
+		}
+		content, err := templatesFS.ReadFile("templates/" + name)
+		if err != nil {
+			return fmt.Errorf("read template %s: %w", name, err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+		fmt.Printf("Created %s\n", path)
+	}
+	return nil
+}
+
+// writeKtMd writes kt.md from the project's template or the embedded one.
 func writeKtMd(path string) error {
-	content, err := templatesFS.ReadFile("templates/kt.md")
+	content, err := readTemplate("kt.md")
 	if err != nil {
 		return fmt.Errorf("read template: %w", err)
 	}
@@ -102,6 +265,12 @@ func promptYesNo(reader *bufio.Reader, prompt string) bool {
 
 // promptChoice presents numbered options and returns 1-indexed selection.
 func promptChoice(reader *bufio.Reader, prompt string, options []string) int {
+	return promptChoiceDefault(reader, prompt, options, len(options)) // Default to last option (Skip)
+}
+
+// promptChoiceDefault is promptChoice with the selection def (1-indexed)
+// when the answer is empty or invalid.
+func promptChoiceDefault(reader *bufio.Reader, prompt string, options []string, def int) int {
 	fmt.Println(prompt)
 	for i, opt := range options {
 		fmt.Printf("  %d. %s\n", i+1, opt)
@@ -111,7 +280,7 @@ func promptChoice(reader *bufio.Reader, prompt string, options []string) int {
 	answer = strings.TrimSpace(answer)
 	choice, err := strconv.Atoi(answer)
 	if err != nil || choice < 1 || choice > len(options) {
-		return len(options) // Default to last option (Skip)
+		return def
 	}
 	return choice
 }
@@ -125,7 +294,7 @@ func getClaudeConfigDir() string {
 	return filepath.Join(home, ".claude")
 }
 
-// installSlashCommands installs kt-create.md and kt-run.md commands.
+// installSlashCommands installs the slash commands from slashCommands.
 func installSlashCommands(global bool) error {
 	var commandsDir string
 	if global {
@@ -138,9 +307,11 @@ func installSlashCommands(global bool) error {
 		return fmt.Errorf("create commands directory: %w", err)
 	}
 
-	commands := []string{"kt-create.md", "kt-run.md", "kt-run-all.md"}
-	for _, cmd := range commands {
-		content, err := templatesFS.ReadFile("templates/" + cmd)
+	commands := slashCommands()
+	names := make([]string, len(commands))
+	for i, cmd := range commands {
+		names[i] = commandName(cmd)
+		content, err := readTemplate(cmd)
 		if err != nil {
 			return fmt.Errorf("read template %s: %w", cmd, err)
 		}
@@ -154,82 +325,84 @@ func installSlashCommands(global bool) error {
 	if global {
 		scope = "global"
 	}
-	fmt.Printf("Installed /kt-create, /kt-run, /kt-run-all (%s)\n", scope)
+	fmt.Printf("Installed %s (%s)\n", strings.Join(names, ", "), scope)
 	return nil
 }
 
 // registerKtPermission adds "Bash(kt:*)" to Claude settings.
 func registerKtPermission(global bool) error {
-	var settingsPath string
-	if global {
-		settingsPath = filepath.Join(getClaudeConfigDir(), "settings.json")
-	} else {
-		settingsPath = ".claude/settings.local.json"
-	}
-	return registerKtPermissionAt(settingsPath, global)
+	return registerKtPermissionAt(claudeSettingsPath(global), global)
 }
 
 // registerKtPermissionAt adds "Bash(kt:*)" to the specified settings file if not present.
 func registerKtPermissionAt(settingsPath string, global bool) error {
-	const permission = "Bash(kt:*)"
+	added, err := permissionProfileFor("claude").add(settingsPath)
+	if err != nil {
+		return err
+	}
+
+	scope := scopeName(global)
+	if !added {
+		fmt.Printf("kt permission already registered (%s)\n", scope)
+		return nil
+	}
+	fmt.Printf("Registered kt permission (%s)\n", scope)
+	return nil
+}
 
-	var settings *gabs.Container
-	data, err := os.ReadFile(settingsPath)
+// ktPermission is the Claude permission that lets it run kt.
+const ktPermission = "Bash(kt:*)"
+
+// addJSONListItem adds item to the list at the dotted path in the JSON
+// file, creating the file and list as needed. It reports false if the item
+// was already there.
+func addJSONListItem(path, list, item string) (bool, error) {
+	settings, err := readSettings(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			// Create new settings with permission
-			settings = gabs.New()
-			if _, err := settings.SetP([]string{permission}, "permissions.allow"); err != nil {
-				return fmt.Errorf("set permission: %w", err)
+		return false, err
+	}
+
+	if existing := settings.Path(list); existing != nil {
+		for _, p := range existing.Children() {
+			if s, ok := p.Data().(string); ok && s == item {
+				return false, nil
 			}
-		} else {
-			return fmt.Errorf("read settings: %w", err)
 		}
-	} else {
-		settings, err = gabs.ParseJSON(data)
-		if err != nil {
-			return fmt.Errorf("parse settings: %w", err)
-		}
-
-		// Check if already registered
-		if allow := settings.Path("permissions.allow"); allow != nil {
-			for _, p := range allow.Children() {
-				if p.Data().(string) == permission {
-					scope := "project"
-					if global {
-						scope = "global"
-					}
-					fmt.Printf("kt permission already registered (%s)\n", scope)
-					return nil
-				}
-			}
-			// Append to existing array
-			if err := settings.ArrayAppendP(permission, "permissions.allow"); err != nil {
-				return fmt.Errorf("append permission: %w", err)
-			}
-		} else {
-			// Create permissions.allow with our permission
-			if _, err := settings.SetP([]string{permission}, "permissions.allow"); err != nil {
-				return fmt.Errorf("set permission: %w", err)
-			}
+		if err := settings.ArrayAppendP(item, list); err != nil {
+			return false, fmt.Errorf("append %s: %w", list, err)
 		}
+	} else if _, err := settings.SetP([]string{item}, list); err != nil {
+		return false, fmt.Errorf("set %s: %w", list, err)
 	}
+	return true, writeSettings(path, settings)
+}
 
-	// Ensure directory exists
-	if dir := filepath.Dir(settingsPath); dir != "." {
+// readSettings parses a JSON settings file, or returns empty settings if
+// it does not exist.
+func readSettings(path string) (*gabs.Container, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return gabs.New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read settings: %w", err)
+	}
+	settings, err := gabs.ParseJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse settings: %w", err)
+	}
+	return settings, nil
+}
+
+// writeSettings writes a JSON settings file, creating its directory.
+func writeSettings(path string, settings *gabs.Container) error {
+	if dir := filepath.Dir(path); dir != "." {
 		if err := os.MkdirAll(dir, 0755); err != nil {
 			return fmt.Errorf("create directory: %w", err)
 		}
 	}
-
-	if err := os.WriteFile(settingsPath, settings.BytesIndent("", "  "), 0644); err != nil {
+	if err := os.WriteFile(path, settings.BytesIndent("", "  "), 0644); err != nil {
 		return fmt.Errorf("write settings: %w", err)
 	}
-
-	scope := "project"
-	if global {
-		scope = "global"
-	}
-	fmt.Printf("Registered kt permission (%s)\n", scope)
 	return nil
 }
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Markers around the kt section kt install writes into agent instruction
+// files, so it can be updated and removed without touching the rest.
+const (
+	sectionStart = "<!-- kt:start (managed by kt install; edits here are replaced) -->"
+	sectionEnd   = "<!-- kt:end -->"
+)
+
+// instructionFiles are the agent instruction files that get a kt section
+// by default: Claude Code reads CLAUDE.md, most other agents AGENTS.md.
+var instructionFiles = []string{"CLAUDE.md", "AGENTS.md"}
+
+var installInstructionsCmd = &cobra.Command{
+	Use:   "instructions [file...]",
+	Short: "Add the kt reference to CLAUDE.md and AGENTS.md",
+	Long: `Add the contents of kt.md as a section of each file (default: CLAUDE.md
+and AGENTS.md), for agents that only read those. The section sits between
+marker comments: running this again replaces it, and kt uninstall removes
+it. Files that don't exist are created.`,
+	RunE: runInstallInstructions,
+}
+
+func init() {
+	installCmd.AddCommand(installInstructionsCmd)
+}
+
+func runInstallInstructions(cmd *cobra.Command, args []string) error {
+	files := args
+	if len(files) == 0 {
+		files = instructionFiles
+	}
+	return installInstructions(files)
+}
+
+// installInstructions writes the kt section into each file.
+func installInstructions(files []string) error {
+	ktMd, err := readTemplate("kt.md")
+	if err != nil {
+		return fmt.Errorf("read template: %w", err)
+	}
+	for _, path := range files {
+		changed, err := writeSection(path, string(ktMd))
+		if err != nil {
+			return err
+		}
+		if changed {
+			fmt.Printf("Updated kt section in %s\n", path)
+		} else {
+			fmt.Printf("kt section in %s is up to date\n", path)
+		}
+	}
+	return nil
+}
+
+// writeSection puts content between the markers in the file at path,
+// replacing the previous section or else appending one. It reports
+// whether the file changed.
+func writeSection(path, content string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("read %s: %w", path, err)
+	}
+	text := string(data)
+	section := sectionStart + "\n" + strings.TrimSpace(content) + "\n" + sectionEnd
+
+	var updated string
+	if before, after, ok := cutSection(text); ok {
+		updated = before + section + after
+	} else if strings.TrimSpace(text) == "" {
+		updated = section + "\n"
+	} else {
+		updated = strings.TrimRight(text, "\n") + "\n\n" + section + "\n"
+	}
+	if updated == text {
+		return false, nil
+	}
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return false, fmt.Errorf("write %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// removeSection removes the kt section from the file at path, and the
+// file if nothing else is left. It reports whether the file changed.
+func removeSection(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read %s: %w", path, err)
+	}
+	before, after, ok := cutSection(string(data))
+	if !ok {
+		return false, nil
+	}
+	rest := strings.TrimRight(before, "\n")
+	if after = strings.TrimLeft(after, "\n"); after != "" {
+		rest += "\n\n" + after
+	}
+	if strings.TrimSpace(rest) == "" {
+		return true, os.Remove(path)
+	}
+	if err := os.WriteFile(path, []byte(strings.TrimRight(rest, "\n")+"\n"), 0644); err != nil {
+		return false, fmt.Errorf("write %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// cutSection splits text around the kt section, markers included. The
+// start marker is matched by its prefix so older wording still matches.
+func cutSection(text string) (before, after string, ok bool) {
+	start := strings.Index(text, "<!-- kt:start")
+	if start < 0 {
+		return text, "", false
+	}
+	end := strings.Index(text[start:], sectionEnd)
+	if end < 0 {
+		return text, "", false
+	}
+	return text[:start], text[start+end+len(sectionEnd):], true
+}
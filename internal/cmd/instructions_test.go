@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CLAUDE.md")
+	require.NoError(t, os.WriteFile(path, []byte("# Project\n\nUse tabs.\n"), 0644))
+
+	changed, err := writeSection(path, "## kt\nv1\n")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Project\n\nUse tabs.\n\n"+sectionStart+"\n## kt\nv1\n"+sectionEnd+"\n", string(data))
+
+	changed, err = writeSection(path, "## kt\nv1\n")
+	require.NoError(t, err)
+	assert.False(t, changed, "idempotent")
+
+	// Text added after the section is kept when it is replaced
+	require.NoError(t, os.WriteFile(path, append(data, "\nMore rules.\n"...), 0644))
+	_, err = writeSection(path, "## kt\nv2")
+	require.NoError(t, err)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Project\n\nUse tabs.\n\n"+sectionStart+"\n## kt\nv2\n"+sectionEnd+"\n\nMore rules.\n", string(data))
+
+	changed, err = removeSection(path)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Project\n\nUse tabs.\n\nMore rules.\n", string(data))
+}
+
+func TestInstallInstructions(t *testing.T) {
+	defer setupTestEnv(t)()
+	t.Chdir(t.TempDir())
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	require.NoError(t, os.WriteFile("AGENTS.md", []byte("# Agents\n"), 0644))
+
+	require.NoError(t, installInstructions(instructionFiles))
+	for _, path := range instructionFiles {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "## kt - ticket tracker", path)
+	}
+
+	require.NoError(t, runUninstall(nil, nil))
+	_, err := os.Stat("CLAUDE.md")
+	assert.True(t, os.IsNotExist(err), "file kt created is removed")
+	data, err := os.ReadFile("AGENTS.md")
+	require.NoError(t, err)
+	assert.Equal(t, "# Agents\n", string(data))
+}
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var importJiraCmd = &cobra.Command{
+	Use:   "jira <file.csv>",
+	Short: "Import a Jira CSV export as tickets",
+	Long: `Import issues from a Jira CSV export (Filters > Export > CSV). The Jira
+key is stored in external-ref, and issues whose key was already imported
+are skipped. Recognized columns: Issue key, Summary, Description, Issue
+Type, Status, Priority, Assignee, Labels (repeatable), Created, Resolved.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportJira,
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export tickets for other trackers",
+}
+
+var exportJiraCmd = &cobra.Command{
+	Use:   "jira",
+	Short: "Export tickets as a Jira-importable CSV",
+	Long: `Write tickets as CSV for Jira's CSV importer, using the same columns
+'kt import jira' reads. Tickets with a Jira key in external-ref keep it in
+the Issue key column.`,
+	Args: cobra.NoArgs,
+	RunE: runExportJira,
+}
+
+var exportOutput string
+
+func init() {
+	importCmd.AddCommand(importJiraCmd)
+
+	exportJiraCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to file instead of stdout")
+	exportCmd.AddCommand(exportJiraCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+// jiraColumns are the CSV columns written by export, in order. Labels is
+// repeated once per label, as Jira does.
+var jiraColumns = []string{"Issue key", "Summary", "Issue Type", "Status", "Priority",
+	"Assignee", "Created", "Resolved", "Description"}
+
+// jiraPriorities maps Jira priority names to kt priorities, highest first.
+var jiraPriorities = []string{"Highest", "High", "Medium", "Low", "Lowest"}
+
+// jiraTimeLayouts are the date formats Jira uses in CSV exports.
+var jiraTimeLayouts = []string{time.RFC3339, "02/Jan/06 3:04 PM", "2006-01-02 15:04", "2006-01-02"}
+
+func runImportJira(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tickets, err := readJiraCSV(f)
+	if err != nil {
+		return err
+	}
+	result, err := importTickets(tickets)
+	if err != nil {
+		return err
+	}
+	return printImport(result)
+}
+
+// readJiraCSV converts the rows of a Jira CSV export to tickets (without IDs).
+func readJiraCSV(r io.Reader) ([]*ticket.Ticket, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	col := make(map[string]int)
+	var labelCols []int
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if name == "Labels" {
+			labelCols = append(labelCols, i)
+		} else if _, ok := col[name]; !ok {
+			col[name] = i
+		}
+	}
+	if _, ok := col["Summary"]; !ok {
+		return nil, fmt.Errorf("not a Jira CSV export: no Summary column")
+	}
+
+	var tickets []*ticket.Ticket
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read CSV: %w", err)
+		}
+		get := func(name string) string {
+			if i, ok := col[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		if get("Summary") == "" {
+			continue
+		}
+
+		t := &ticket.Ticket{
+			Status:      jiraStatus(get("Status")),
+			Type:        jiraType(get("Issue Type")),
+			Priority:    jiraPriority(get("Priority")),
+			Assignee:    get("Assignee"),
+			ExternalRef: get("Issue key"),
+			Title:       get("Summary"),
+			Description: strings.ReplaceAll(get("Description"), "\r\n", "\n"),
+			Created:     jiraTime(get("Created")),
+		}
+		if t.Created == "" {
+			t.Created = time.Now().UTC().Format(time.RFC3339)
+		}
+		if t.Status == ticket.StatusClosed {
+			if t.Closed = jiraTime(get("Resolved")); t.Closed == "" {
+				t.Closed = t.Created
+			}
+		}
+		for _, i := range labelCols {
+			if i < len(row) && strings.TrimSpace(row[i]) != "" {
+				t.Labels = append(t.Labels, strings.TrimSpace(row[i]))
+			}
+		}
+		tickets = append(tickets, t)
+	}
+	return tickets, nil
+}
+
+func runExportJira(cmd *cobra.Command, args []string) error {
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+
+	w := io.Writer(os.Stdout)
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return writeJiraCSV(w, tickets)
+}
+
+// writeJiraCSV writes tickets in the format readJiraCSV reads.
+func writeJiraCSV(w io.Writer, tickets []*ticket.Ticket) error {
+	labels := 0
+	for _, t := range tickets {
+		labels = max(labels, len(t.Labels))
+	}
+	header := append([]string(nil), jiraColumns...)
+	for range labels {
+		header = append(header, "Labels")
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, t := range tickets {
+		key := t.ExternalRef
+		if !isJiraKey(key) {
+			key = ""
+		}
+		priority := ""
+		if t.Priority >= 0 && t.Priority < len(jiraPriorities) {
+			priority = jiraPriorities[t.Priority]
+		}
+		row := []string{key, t.Title, jiraTypeName(t.Type), jiraStatusName(t.Status), priority,
+			t.Assignee, t.Created, t.Closed, t.Description}
+		for i := range labels {
+			label := ""
+			if i < len(t.Labels) {
+				label = t.Labels[i]
+			}
+			row = append(row, label)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// isJiraKey reports whether ref looks like a Jira key (PROJ-123).
+func isJiraKey(ref string) bool {
+	project, num, ok := strings.Cut(ref, "-")
+	if !ok || project == "" || strings.ToUpper(project) != project {
+		return false
+	}
+	_, err := strconv.Atoi(num)
+	return err == nil
+}
+
+func jiraStatus(s string) ticket.Status {
+	switch strings.ToLower(s) {
+	case "done", "closed", "resolved", "won't do", "cancelled":
+		return ticket.StatusClosed
+	case "in progress", "in review", "in development":
+		return ticket.StatusInProgress
+	}
+	return ticket.StatusOpen
+}
+
+func jiraStatusName(s ticket.Status) string {
+	switch s {
+	case ticket.StatusClosed:
+		return "Done"
+	case ticket.StatusInProgress:
+		return "In Progress"
+	}
+	return "To Do"
+}
+
+func jiraType(s string) ticket.Type {
+	switch strings.ToLower(s) {
+	case "bug":
+		return ticket.TypeBug
+	case "story", "new feature", "improvement":
+		return ticket.TypeFeature
+	case "epic":
+		return ticket.TypeEpic
+	}
+	return ticket.TypeTask
+}
+
+func jiraTypeName(t ticket.Type) string {
+	switch t {
+	case ticket.TypeBug:
+		return "Bug"
+	case ticket.TypeFeature:
+		return "Story"
+	case ticket.TypeEpic:
+		return "Epic"
+	}
+	return "Task"
+}
+
+func jiraPriority(s string) int {
+	for i, name := range jiraPriorities {
+		if strings.EqualFold(s, name) {
+			return i
+		}
+	}
+	return 2
+}
+
+// jiraTime converts a Jira timestamp to RFC3339, or "" if unparseable.
+func jiraTime(s string) string {
+	for _, layout := range jiraTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jiraExport = "\ufeffSummary,Issue key,Issue Type,Status,Priority,Assignee,Created,Resolved,Description,Labels,Labels\n" +
+	"Login fails,APP-12,Bug,In Progress,High,alice,02/Jan/26 3:04 PM,,\"Steps:\r\n1. log in\",auth,\n" +
+	"Dark mode,APP-13,Story,Done,Lowest,,01/Jan/26 9:00 AM,05/Jan/26 10:00 AM,,ui,theme\n" +
+	",APP-14,Task,To Do,,,,,,,\n"
+
+func TestRunImportJira(t *testing.T) {
+	defer setupTestEnv(t)()
+	path := filepath.Join(t.TempDir(), "jira.csv")
+	require.NoError(t, os.WriteFile(path, []byte(jiraExport), 0644))
+
+	require.NoError(t, runImportJira(nil, []string{path}))
+	tickets, err := Store.List()
+	require.NoError(t, err)
+	require.Len(t, tickets, 2)
+	byRef := map[string]*ticket.Ticket{}
+	for _, tk := range tickets {
+		byRef[tk.ExternalRef] = tk
+	}
+
+	bug := byRef["APP-12"]
+	require.NotNil(t, bug)
+	assert.Equal(t, "Login fails", bug.Title)
+	assert.Equal(t, ticket.TypeBug, bug.Type)
+	assert.Equal(t, ticket.StatusInProgress, bug.Status)
+	assert.Equal(t, 1, bug.Priority)
+	assert.Equal(t, "alice", bug.Assignee)
+	assert.Equal(t, "2026-01-02T15:04:00Z", bug.Created)
+	assert.Equal(t, "Steps:\n1. log in", bug.Description)
+	assert.Equal(t, []string{"auth"}, bug.Labels)
+
+	story := byRef["APP-13"]
+	require.NotNil(t, story)
+	assert.Equal(t, ticket.TypeFeature, story.Type)
+	assert.Equal(t, ticket.StatusClosed, story.Status)
+	assert.Equal(t, "2026-01-05T10:00:00Z", story.Closed)
+	assert.Equal(t, 4, story.Priority)
+	assert.Equal(t, []string{"ui", "theme"}, story.Labels)
+
+	// Re-importing skips known keys
+	require.NoError(t, runImportJira(nil, []string{path}))
+	tickets, _ = Store.List()
+	assert.Len(t, tickets, 2)
+}
+
+func TestJiraCSVRoundTrip(t *testing.T) {
+	in := []*ticket.Ticket{
+		{ID: "kt-a", Title: "Login, again", Type: ticket.TypeBug, Status: ticket.StatusOpen, Priority: 0,
+			ExternalRef: "APP-1", Created: "2026-01-02T03:04:05Z", Description: "multi\nline", Labels: []string{"auth"}},
+		{ID: "kt-b", Title: "Plain", Type: ticket.TypeChore, Status: ticket.StatusClosed, Priority: 2,
+			ExternalRef: "gh-7", Created: "2026-01-01T00:00:00Z", Closed: "2026-01-03T00:00:00Z"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, writeJiraCSV(&buf, in))
+	assert.True(t, strings.HasPrefix(buf.String(),
+		"Issue key,Summary,Issue Type,Status,Priority,Assignee,Created,Resolved,Description,Labels\n"))
+
+	out, err := readJiraCSV(&buf)
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	assert.Equal(t, "APP-1", out[0].ExternalRef)
+	assert.Equal(t, "Login, again", out[0].Title)
+	assert.Equal(t, ticket.TypeBug, out[0].Type)
+	assert.Equal(t, 0, out[0].Priority)
+	assert.Equal(t, "multi\nline", out[0].Description)
+	assert.Equal(t, []string{"auth"}, out[0].Labels)
+	assert.Equal(t, "2026-01-02T03:04:05Z", out[0].Created)
+
+	assert.Empty(t, out[1].ExternalRef, "non-Jira refs are not exported as keys")
+	assert.Equal(t, ticket.StatusClosed, out[1].Status)
+	assert.Equal(t, "2026-01-03T00:00:00Z", out[1].Closed)
+}
+
+func TestReadJiraCSVRejectsOtherCSV(t *testing.T) {
+	_, err := readJiraCSV(strings.NewReader("a,b\n1,2\n"))
+	assert.ErrorContains(t, err, "no Summary column")
+}
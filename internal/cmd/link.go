@@ -17,25 +17,34 @@ var linkCmd = &cobra.Command{
 
 var linkAddCmd = &cobra.Command{
 	Use:   "add <id> <id> [id...]",
-	Short: "Link tickets together (symmetric)",
+	Short: "Link tickets together (symmetric, or typed with --type)",
 	Args:  cobra.MinimumNArgs(2),
 	RunE:  runLinkAdd,
 }
 
 var linkRmCmd = &cobra.Command{
 	Use:   "rm <id> <target-id>",
-	Short: "Remove link between tickets",
+	Short: "Remove all links between tickets",
 	Args:  cobra.ExactArgs(2),
 	RunE:  runLinkRm,
 }
 
+var linkAddType string
+
 func init() {
+	linkAddCmd.Flags().StringVar(&linkAddType, "type", string(ticket.LinkRelatesTo),
+		"Link type (relates-to|blocks|blocked-by|duplicates|duplicated-by|caused-by|causes)")
 	linkCmd.AddCommand(linkAddCmd)
 	linkCmd.AddCommand(linkRmCmd)
 	rootCmd.AddCommand(linkCmd)
 }
 
 func runLinkAdd(cmd *cobra.Command, args []string) error {
+	linkType, err := ticket.ParseLinkType(linkAddType)
+	if err != nil {
+		return err
+	}
+
 	// Resolve all ticket IDs first (read-only) to get canonical IDs
 	ids := make([]string, 0, len(args))
 	for _, id := range args {
@@ -45,6 +54,7 @@ func runLinkAdd(cmd *cobra.Command, args []string) error {
 		}
 		ids = append(ids, t.ID)
 	}
+	sourceID := ids[0]
 
 	// Sort IDs to prevent deadlocks when locking multiple tickets
 	sort.Strings(ids)
@@ -65,16 +75,30 @@ func runLinkAdd(cmd *cobra.Command, args []string) error {
 		locked = append(locked, lt)
 	}
 
-	// Add symmetric links between all pairs
-	for i, lt1 := range locked {
-		for j, lt2 := range locked {
-			if i == j {
-				continue
+	if linkType == ticket.LinkRelatesTo {
+		// Add symmetric links between all pairs
+		for i, lt1 := range locked {
+			for j, lt2 := range locked {
+				if i == j {
+					continue
+				}
+				lt1.Ticket.AddLink(ticket.LinkRelatesTo, lt2.Ticket.ID)
 			}
-			// Add lt2 to lt1's links if not already there
-			if !slices.Contains(lt1.Ticket.Links, lt2.Ticket.ID) {
-				lt1.Ticket.Links = append(lt1.Ticket.Links, lt2.Ticket.ID)
+		}
+	} else {
+		// Directed: first ticket gets the type, targets get the inverse
+		var source *store.LockedTicket
+		for _, lt := range locked {
+			if lt.Ticket.ID == sourceID {
+				source = lt
+			}
+		}
+		for _, lt := range locked {
+			if lt == source {
+				continue
 			}
+			source.Ticket.AddLink(linkType, lt.Ticket.ID)
+			lt.Ticket.AddLink(linkType.Inverse(), source.Ticket.ID)
 		}
 	}
 
@@ -96,7 +120,11 @@ func runLinkAdd(cmd *cobra.Command, args []string) error {
 	for i, t := range tickets {
 		resultIDs[i] = t.ID
 	}
-	fmt.Printf("Linked: %v\n", resultIDs)
+	if linkType == ticket.LinkRelatesTo {
+		fmt.Printf("Linked: %v\n", resultIDs)
+	} else {
+		fmt.Printf("Linked (%s): %v\n", linkType, resultIDs)
+	}
 	return nil
 }
 
@@ -126,9 +154,9 @@ func runLinkRm(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Remove from both directions
-	lt1.Ticket.Links = slices.DeleteFunc(lt1.Ticket.Links, func(s string) bool { return s == lt2.Ticket.ID })
-	lt2.Ticket.Links = slices.DeleteFunc(lt2.Ticket.Links, func(s string) bool { return s == lt1.Ticket.ID })
+	// Remove all link types from both directions
+	lt1.Ticket.RemoveLinks(lt2.Ticket.ID)
+	lt2.Ticket.RemoveLinks(lt1.Ticket.ID)
 
 	if err := lt1.SaveAndRelease(); err != nil {
 		lt2.Release()
@@ -152,14 +180,14 @@ func runLinkRm(cmd *cobra.Command, args []string) error {
 var readyCmd = &cobra.Command{
 	Use:   "ready",
 	Short: "List open/in_progress with deps resolved",
-	RunE:  runReady,
+	RunE:  paged(runReady),
 }
 
 // Blocked command - list tickets with unresolved deps
 var blockedCmd = &cobra.Command{
 	Use:   "blocked",
 	Short: "List open/in_progress with unresolved deps",
-	RunE:  runBlocked,
+	RunE:  paged(runBlocked),
 }
 
 func init() {
@@ -187,6 +215,10 @@ func runReady(cmd *cobra.Command, args []string) error {
 		return PrintJSON(ready)
 	}
 
+	if IsTemplate() {
+		return PrintTemplate(ready)
+	}
+
 	if IsPlain() {
 		for _, t := range ready {
 			fmt.Printf("%s [%s] %s\n", t.ID, t.Status, t.Title)
@@ -221,6 +253,20 @@ func runBlocked(cmd *cobra.Command, args []string) error {
 		return PrintJSON(blocked)
 	}
 
+	archived, err := Store.ListArchived()
+	if err != nil {
+		return err
+	}
+	for _, r := range danglingRefs(tickets, archived) {
+		if r.Field == "deps" && slices.ContainsFunc(blocked, func(t *ticket.Ticket) bool { return t.ID == r.ID }) {
+			Warnf("%s depends on missing %s (kt fsck --fix-refs removes or remaps it)", r.ID, r.Target)
+		}
+	}
+
+	if IsTemplate() {
+		return PrintTemplate(blocked)
+	}
+
 	if IsPlain() {
 		for _, t := range blocked {
 			fmt.Printf("%s [%s] %s\n", t.ID, t.Status, t.Title)
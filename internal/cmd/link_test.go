@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunLinkAddTyped(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { linkAddType = string(ticket.LinkRelatesTo) }()
+
+	a := mkTicket(t, "kt-aaa", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-bbb", "B", ticket.StatusOpen)
+
+	// Source is the first argument even though it sorts last
+	linkAddType = "blocks"
+	require.NoError(t, runLinkAdd(nil, []string{b.ID, a.ID}))
+
+	ua, _ := Store.Get(a.ID)
+	ub, _ := Store.Get(b.ID)
+	assert.Equal(t, []string{a.ID}, ub.Relations[ticket.LinkBlocks])
+	assert.Equal(t, []string{b.ID}, ua.Relations[ticket.LinkBlockedBy])
+	assert.Empty(t, ua.Links)
+
+	// Blocked-by relations show up in the dep tree
+	tree := buildDepTree(ua, make(map[string]bool), false)
+	require.Len(t, tree.Children, 1)
+	assert.Equal(t, b.ID, tree.Children[0].ID)
+	assert.Equal(t, "blocked-by", tree.Children[0].Relation)
+
+	// link rm clears every type in both directions
+	require.NoError(t, runLinkRm(nil, []string{a.ID, b.ID}))
+	ua, _ = Store.Get(a.ID)
+	ub, _ = Store.Get(b.ID)
+	assert.Nil(t, ua.Relations)
+	assert.Nil(t, ub.Relations)
+}
+
+func TestRunLinkAddInvalidType(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { linkAddType = string(ticket.LinkRelatesTo) }()
+
+	a := mkTicket(t, "kt-aaa", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-bbb", "B", ticket.StatusOpen)
+
+	linkAddType = "depends"
+	err := runLinkAdd(nil, []string{a.ID, b.ID})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid link type")
+}
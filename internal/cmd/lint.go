@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [id...]",
+	Short: "Check ticket files for structural problems",
+	Long: `Check ticket files (all of them, or the given ones) for problems: files that
+do not parse, unknown frontmatter keys, a missing id or title, an ID that
+does not match the file name, unknown statuses or types, a priority outside
+0-4, and timestamps or due dates in the wrong format.
+
+Exits with status 1 if any are found. With --json, lists each ticket with
+problems, so an agent can check the tickets it wrote.`,
+	SilenceUsage: true,
+	RunE:         runLint,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
+
+// lintReport is the problems found in one ticket file.
+type lintReport struct {
+	ID       string   `json:"id"`
+	File     string   `json:"file"`
+	Problems []string `json:"problems"`
+}
+
+type lintResult struct {
+	Checked int          `json:"checked"`
+	Tickets []lintReport `json:"tickets"`
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	paths, err := lintPaths(args)
+	if err != nil {
+		return err
+	}
+	result := lintResult{Checked: len(paths), Tickets: []lintReport{}}
+	problems := 0
+	for _, path := range paths {
+		id := strings.TrimSuffix(filepath.Base(path), ".md")
+		if p := lintFile(path); len(p) > 0 {
+			result.Tickets = append(result.Tickets, lintReport{ID: id, File: path, Problems: p})
+			problems += len(p)
+		}
+	}
+
+	if IsJSON() {
+		if err := PrintJSON(result); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range result.Tickets {
+			for _, p := range r.Problems {
+				fmt.Printf("%s: %s\n", r.ID, p)
+			}
+		}
+	}
+	if problems > 0 {
+		return fmt.Errorf("%d problem(s) in %d of %d ticket(s)", problems, len(result.Tickets), len(paths))
+	}
+	if !IsJSON() {
+		fmt.Printf("%d ticket(s) OK\n", len(paths))
+	}
+	return nil
+}
+
+// lintPaths returns the files of the tickets named in args, or of every
+// ticket. An argument naming a file that does not parse is taken as is,
+// since it cannot be resolved.
+func lintPaths(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return filepath.Glob(filepath.Join(Store.Dir, "*.md"))
+	}
+	paths := make([]string, 0, len(args))
+	for _, arg := range args {
+		if path := Store.Path(arg); isFile(path) {
+			paths = append(paths, path)
+			continue
+		}
+		t, err := Store.Resolve(arg)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, Store.Path(t.ID))
+	}
+	return paths, nil
+}
+
+func isFile(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// lintFile returns the problems in the ticket file at path.
+func lintFile(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	t, errs := ticket.Lint(data)
+	problems := make([]string, 0, len(errs))
+	for _, err := range errs {
+		problems = append(problems, err.Error())
+	}
+	if id := strings.TrimSuffix(filepath.Base(path), ".md"); t != nil && t.ID != "" && t.ID != id {
+		problems = append(problems, fmt.Sprintf("id %q does not match the file name", t.ID))
+	}
+	return problems
+}
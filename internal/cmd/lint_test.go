@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintCommand(t *testing.T) {
+	defer setupTestEnv(t)()
+	mkTicket(t, "kt-good", "Fine", ticket.StatusOpen)
+	require.NoError(t, os.WriteFile(Store.Path("kt-bad"),
+		[]byte("---\nid: kt-other\nstatus: open\ncreated: 2026-01-09T10:00:00Z\ntype: story\npriority: 2\n---\n"), 0o644))
+	require.NoError(t, os.WriteFile(Store.Path("kt-junk"), []byte("not a ticket\n"), 0o644))
+
+	assert.Equal(t, []string{
+		"missing title (a # heading after the frontmatter)",
+		`invalid type "story" (expected one of [bug feature task epic chore])`,
+		`id "kt-other" does not match the file name`,
+	}, lintFile(Store.Path("kt-bad")))
+	assert.Equal(t, []string{"missing frontmatter delimiter"}, lintFile(Store.Path("kt-junk")))
+	assert.Empty(t, lintFile(Store.Path("kt-good")))
+
+	err := runLint(nil, nil)
+	assert.EqualError(t, err, "4 problem(s) in 2 of 3 ticket(s)")
+	assert.NoError(t, runLint(nil, []string{"good"}), "resolves partial IDs")
+	assert.Error(t, runLint(nil, []string{"kt-junk"}), "takes unparsable files by name")
+}
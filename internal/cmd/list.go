@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 
 	"github.com/kostyay/kticket/internal/ticket"
@@ -12,17 +13,19 @@ var listCmd = &cobra.Command{
 	Use:     "ls",
 	Aliases: []string{"list"},
 	Short:   "List tickets",
-	RunE:    runList,
+	RunE:    paged(runList),
 }
 
 var (
-	listStatus string
-	listParent string
+	listStatus     string
+	listParent     string
+	listFilterExpr string
 )
 
 func init() {
 	listCmd.Flags().StringVar(&listStatus, "status", "", "Filter by status (open|in_progress|closed)")
 	listCmd.Flags().StringVar(&listParent, "parent", "", "Filter by parent ticket ID")
+	listCmd.Flags().StringVar(&listFilterExpr, "filter", "", "Filter expression, as kt bulk (e.g. 'type=bug and priority=0')")
 	rootCmd.AddCommand(listCmd)
 }
 
@@ -58,10 +61,22 @@ func runList(cmd *cobra.Command, args []string) error {
 		tickets = filtered
 	}
 
+	if listFilterExpr != "" {
+		filter, err := ticket.ParseFilter(listFilterExpr)
+		if err != nil {
+			return err
+		}
+		tickets = slices.DeleteFunc(tickets, func(t *ticket.Ticket) bool { return !filter.Match(t) })
+	}
+
 	if IsJSON() {
 		return PrintJSON(tickets)
 	}
 
+	if IsTemplate() {
+		return PrintTemplate(tickets)
+	}
+
 	if IsPlain() {
 		for _, t := range tickets {
 			fmt.Printf("%s [%s] %s\n", t.ID, t.Status, t.Title)
@@ -70,7 +85,8 @@ func runList(cmd *cobra.Command, args []string) error {
 	}
 
 	for _, t := range tickets {
-		fmt.Printf("%-12s [%-11s] %s\n", t.ID, t.Status, truncate(t.Title, 50))
+		fmt.Printf("%-12s [%s] %s %s\n", t.ID, paintStatus(t.Status, fmt.Sprintf("%-11s", t.Status)),
+			paint(priorityColor(t.Priority), fmt.Sprintf("P%d", t.Priority)), truncate(t.Title, 50))
 	}
 
 	return nil
@@ -135,7 +151,7 @@ func runStats(cmd *cobra.Command, args []string) error {
 var closedCmd = &cobra.Command{
 	Use:   "closed",
 	Short: "List recently closed tickets",
-	RunE:  runClosed,
+	RunE:  paged(runClosed),
 }
 
 var closedLimit int
@@ -173,6 +189,10 @@ func runClosed(cmd *cobra.Command, args []string) error {
 		return PrintJSON(closed)
 	}
 
+	if IsTemplate() {
+		return PrintTemplate(closed)
+	}
+
 	if IsPlain() {
 		for _, t := range closed {
 			fmt.Printf("%s [%s] %s\n", t.ID, t.Status, t.Title)
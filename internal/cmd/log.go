@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log <id>",
+	Short: "Show a ticket's change history from git",
+	Long: `List the commits that changed a ticket's file, newest first, with who made
+each change, when, and which fields it changed (see kt diff). The history
+comes from git alone, so it covers only committed changes. A deleted
+ticket's history is shown when given its full ID.`,
+	Args: cobra.ExactArgs(1),
+	RunE: paged(runLog),
+}
+
+func init() {
+	rootCmd.AddCommand(logCmd)
+}
+
+// logEntry is one commit in a ticket's history.
+type logEntry struct {
+	git.FileCommit
+	Created bool                 `json:"created,omitempty"`
+	Deleted bool                 `json:"deleted,omitempty"`
+	Changes []ticket.FieldChange `json:"changes"`
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	t, resolveErr := Store.Resolve(id)
+	if resolveErr == nil {
+		id = t.ID
+	} else if !validID.MatchString(id) {
+		return resolveErr
+	}
+
+	entries, err := ticketLog(Store.Path(id))
+	if err != nil {
+		return err
+	}
+	if resolveErr != nil && len(entries) == 0 {
+		return resolveErr
+	}
+
+	if IsJSON() {
+		return PrintJSON(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Printf("%s has no committed changes\n", id)
+		return nil
+	}
+	for i, e := range entries {
+		if i > 0 {
+			fmt.Println()
+		}
+		commit := git.Commit{SHA: e.SHA}
+		fmt.Printf("%s  %s  %s  %s\n", paint(ansiYellow, commit.Short()), e.Date, e.Author, e.Subject)
+		switch {
+		case e.Created:
+			fmt.Println("  created")
+		case e.Deleted:
+			fmt.Println("  deleted")
+			continue
+		}
+		writeFieldChanges(os.Stdout, e.Changes, "  ")
+	}
+	return nil
+}
+
+// ticketLog returns the commits that changed the ticket file at path,
+// newest first, each with the fields it changed.
+func ticketLog(path string) ([]logEntry, error) {
+	commits, err := git.FileLog(path)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]*ticket.Ticket, len(commits)) // nil where deleted or unreadable
+	deleted := make([]bool, len(commits))
+	for i, c := range commits {
+		data, ok, err := c.Contents(filepath.Dir(path))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			deleted[i] = true
+			continue
+		}
+		if versions[i], err = ticket.Parse(data); err != nil {
+			Warnf("%s in %s: %s", c.Path, git.Commit{SHA: c.SHA}.Short(), err)
+		}
+	}
+
+	entries := make([]logEntry, len(commits))
+	for i, c := range commits {
+		var before *ticket.Ticket
+		if i+1 < len(versions) {
+			before = versions[i+1]
+		}
+		after := versions[i]
+		entries[i] = logEntry{
+			FileCommit: c,
+			Created:    i == len(commits)-1,
+			Deleted:    deleted[i],
+			Changes:    ticket.Diff(before, after),
+		}
+		if entries[i].Deleted || entries[i].Changes == nil {
+			entries[i].Changes = []ticket.FieldChange{}
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketLog(t *testing.T) {
+	defer setupTestEnv(t)()
+	dir := initGitRepo(t)
+	Store = store.New(filepath.Join(dir, ".ktickets"))
+	require.NoError(t, Store.EnsureDir())
+	path := Store.Path("kt-001")
+
+	tk := mkTicket(t, "kt-001", "Log me", ticket.StatusOpen)
+	_, err := git.CommitPaths(dir, "add kt-001", []string{path})
+	require.NoError(t, err)
+	tk.Status = ticket.StatusInProgress
+	tk.Assignee = "ann"
+	require.NoError(t, Store.Save(tk))
+	_, err = git.CommitPaths(dir, "start kt-001", []string{path})
+	require.NoError(t, err)
+
+	entries, err := ticketLog(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "start kt-001", entries[0].Subject)
+	assert.Equal(t, "test", entries[0].Author)
+	assert.False(t, entries[0].Created)
+	assert.ElementsMatch(t, []ticket.FieldChange{
+		{Field: "status", Before: "open", After: "in_progress"},
+		{Field: "assignee", After: "ann"},
+	}, entries[0].Changes)
+	assert.True(t, entries[1].Created)
+
+	// A deleted ticket's history is still reachable by its full ID
+	require.NoError(t, Store.Delete("kt-001"))
+	_, err = git.CommitPaths(dir, "drop kt-001", []string{path})
+	require.NoError(t, err)
+	entries, err = ticketLog(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.True(t, entries[0].Deleted)
+	assert.Empty(t, entries[0].Changes)
+	require.NoError(t, runLog(nil, []string{"kt-001"}))
+	assert.Error(t, runLog(nil, []string{"kt-999"}))
+}
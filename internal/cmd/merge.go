@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <src> <dst>",
+	Short: "Merge src into dst and close src as duplicate",
+	Long: `Merge src into dst: appends src's description, tests, and notes to dst,
+carries over src's deps and links, rewrites every deps/links/parent reference
+to src across the store to point at dst, and closes src as a duplicate.
+With --dry-run, lists the ticket files that would change instead.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMerge,
+}
+
+func init() {
+	addDryRunFlag(mergeCmd)
+	rootCmd.AddCommand(mergeCmd)
+}
+
+type mergeResult struct {
+	Merged  string   `json:"merged"`
+	Into    string   `json:"into"`
+	Updated []string `json:"updated,omitempty"`
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	var result mergeResult
+
+	startDryRun(Store)
+	err := Store.Transaction(func(tx *store.Tx) error {
+		src, err := tx.Resolve(args[0])
+		if err != nil {
+			return err
+		}
+		dst, err := tx.Resolve(args[1])
+		if err != nil {
+			return err
+		}
+		if src.ID == dst.ID {
+			return fmt.Errorf("cannot merge %s into itself", src.ID)
+		}
+		if src.Status == ticket.StatusClosed && src.Resolution == ticket.ResolutionDuplicate {
+			return fmt.Errorf("%s is already closed as a duplicate", src.ID)
+		}
+
+		mergeInto(dst, src)
+		tx.Save(dst)
+
+		result = mergeResult{Merged: src.ID, Into: dst.ID}
+		result.Updated = tx.RewriteRefs(src.ID, dst.ID)
+		if err := checkMergeCycle(tx, dst); err != nil {
+			return err
+		}
+
+		src.SetStatus(ticket.StatusClosed)
+		src.Resolution = ticket.ResolutionDuplicate
+		appendNote(src, fmt.Sprintf("Merged into %s", dst.ID))
+		tx.Save(src)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		return printDryRun(Store, result)
+	}
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+
+	fmt.Printf("Merged %s into %s\n", result.Merged, result.Into)
+	if len(result.Updated) > 0 {
+		fmt.Printf("Updated references in: %v\n", result.Updated)
+	}
+	return nil
+}
+
+// mergeInto appends src's content and relationships to dst.
+func mergeInto(dst, src *ticket.Ticket) {
+	dst.Description = joinSection(dst.Description, src.Description)
+	if src.Tests != "" {
+		dst.Tests = joinSection(dst.Tests, src.Tests)
+		dst.TestsPassed = dst.TestsPassed && src.TestsPassed
+	}
+	dst.Notes = joinSection(dst.Notes, src.Notes)
+
+	for _, d := range src.Deps {
+		if d != dst.ID && !slices.Contains(dst.Deps, d) {
+			dst.Deps = append(dst.Deps, d)
+		}
+	}
+	for _, l := range src.TypedLinks() {
+		if l.ID != dst.ID {
+			dst.AddLink(l.Type, l.ID)
+		}
+	}
+	for _, sha := range src.Commits {
+		dst.AddCommit(sha)
+	}
+
+	appendNote(dst, fmt.Sprintf("Merged %s (%s)", src.ID, src.Title))
+}
+
+// checkMergeCycle refuses a merge that leaves dst depending on itself
+// through the deps it took over from src, as kt dep add would refuse it.
+func checkMergeCycle(tx *store.Tx, dst *ticket.Ticket) error {
+	byID := make(map[string]*ticket.Ticket)
+	for _, t := range tx.List() {
+		byID[t.ID] = t
+	}
+	for _, dep := range dst.Deps {
+		if dependsOn(byID, dep, dst.ID) {
+			return fmt.Errorf("merging into %s would create a dependency cycle: %s depends on it", dst.ID, dep)
+		}
+	}
+	return nil
+}
+
+// joinSection appends extra to a markdown section, separated by a blank line.
+func joinSection(base, extra string) string {
+	switch {
+	case extra == "":
+		return base
+	case base == "":
+		return extra
+	default:
+		return base + "\n\n" + extra
+	}
+}
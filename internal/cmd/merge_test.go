@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMerge(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	src := mkTicket(t, "kt-src", "Source", ticket.StatusOpen)
+	dst := mkTicket(t, "kt-dst", "Target", ticket.StatusOpen)
+	other := mkTicket(t, "kt-other", "Other", ticket.StatusOpen)
+	dep := mkTicket(t, "kt-dep", "Dep", ticket.StatusOpen)
+
+	src.Description = "src description"
+	src.Tests = "- TestSrc"
+	src.Deps = []string{dep.ID}
+	require.NoError(t, Store.Save(src))
+
+	dst.Description = "dst description"
+	dst.TestsPassed = true
+	require.NoError(t, Store.Save(dst))
+
+	other.Deps = []string{src.ID}
+	other.Links = []string{src.ID, dst.ID}
+	other.Parent = src.ID
+	require.NoError(t, Store.Save(other))
+
+	require.NoError(t, runMerge(nil, []string{"src", "dst"}))
+
+	us, _ := Store.Get(src.ID)
+	assert.Equal(t, ticket.StatusClosed, us.Status)
+	assert.Equal(t, ticket.ResolutionDuplicate, us.Resolution)
+	assert.Contains(t, us.Notes, "Merged into kt-dst")
+
+	ud, _ := Store.Get(dst.ID)
+	assert.Contains(t, ud.Description, "dst description")
+	assert.Contains(t, ud.Description, "src description")
+	assert.Contains(t, ud.Tests, "TestSrc")
+	assert.False(t, ud.TestsPassed, "new tests require re-pass")
+	assert.Equal(t, []string{dep.ID}, ud.Deps)
+	assert.Contains(t, ud.Notes, "Merged kt-src")
+
+	uo, _ := Store.Get(other.ID)
+	assert.Equal(t, []string{dst.ID}, uo.Deps)
+	assert.Equal(t, []string{dst.ID}, uo.Links)
+	assert.Equal(t, dst.ID, uo.Parent)
+}
+
+func TestRunMergeErrors(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+
+	err := runMerge(nil, []string{a.ID, a.ID})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "into itself")
+
+	err = runMerge(nil, []string{a.ID, "kt-missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestRunMergeCycle(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	// src's dep waits on dst, so dst would end up waiting on itself
+	src := mkTicket(t, "kt-src", "Source", ticket.StatusOpen)
+	dst := mkTicket(t, "kt-dst", "Target", ticket.StatusOpen)
+	mid := mkTicket(t, "kt-mid", "Middle", ticket.StatusOpen)
+	src.Deps = []string{mid.ID}
+	require.NoError(t, Store.Save(src))
+	mid.Deps = []string{dst.ID}
+	require.NoError(t, Store.Save(mid))
+
+	err := runMerge(nil, []string{src.ID, dst.ID})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle")
+	ud, _ := Store.Get(dst.ID)
+	assert.Empty(t, ud.Deps)
+	us, _ := Store.Get(src.ID)
+	assert.Equal(t, ticket.StatusOpen, us.Status, "nothing written")
+
+	// dst waiting on src loses the dep rather than depending on itself
+	other := mkTicket(t, "kt-other", "Other", ticket.StatusOpen)
+	dst.Deps = []string{other.ID}
+	require.NoError(t, Store.Save(dst))
+	require.NoError(t, runMerge(nil, []string{dst.ID, other.ID}))
+	uo, _ := Store.Get(other.ID)
+	assert.Empty(t, uo.Deps)
+}
+
+func TestRunMergeJSON(t *testing.T) {
+	defer setupTestEnv(t)()
+	jsonFlag = true
+	defer func() { jsonFlag = false }()
+
+	mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+
+	require.NoError(t, runMerge(nil, []string{"kt-a", "kt-b"}))
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade ticket files to the current file format",
+	Long: fmt.Sprintf(`Rewrite every ticket file written in an older file format (schema in the
+frontmatter; none before version 1) in the current one, schema %d. Files
+from a newer kt are refused. kt undo reverts the migration.`, ticket.SchemaVersion),
+	Args: cobra.NoArgs,
+	RunE: runMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+}
+
+type migrateResult struct {
+	Schema   int      `json:"schema"`
+	Migrated []string `json:"migrated"`
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	ticket.SchemaMismatch = nil // the warnings are about what this does
+	result, err := migrateStore(Store)
+	if err != nil {
+		return err
+	}
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	fmt.Printf("Migrated %d ticket(s) to schema %d\n", len(result.Migrated), result.Schema)
+	return nil
+}
+
+// migrateStore rewrites the tickets in s with an older schema.
+func migrateStore(s *store.Store) (*migrateResult, error) {
+	result := &migrateResult{Schema: ticket.SchemaVersion, Migrated: []string{}}
+	err := s.Transaction(func(tx *store.Tx) error {
+		for _, t := range tx.List() {
+			if t.Schema > ticket.SchemaVersion {
+				return fmt.Errorf("%s has schema %d, newer than this kt supports (%d); upgrade kt", t.ID, t.Schema, ticket.SchemaVersion)
+			}
+		}
+		for _, t := range tx.List() {
+			if t.Schema < ticket.SchemaVersion {
+				t.Schema = ticket.SchemaVersion
+				tx.Save(t)
+				result.Migrated = append(result.Migrated, t.ID)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+var schemaWarned sync.Map // "older" or "newer" → true
+
+// warnSchema warns, once per direction, that a ticket file is in another
+// version of the file format than this kt writes.
+func warnSchema(t *ticket.Ticket) {
+	if t.Schema > ticket.SchemaVersion {
+		if _, warned := schemaWarned.LoadOrStore("newer", true); !warned {
+			Warnf("%s was written by a newer kt (schema %d, this kt has %d); upgrade kt before changing tickets", t.ID, t.Schema, ticket.SchemaVersion)
+		}
+		return
+	}
+	if _, warned := schemaWarned.LoadOrStore("older", true); !warned {
+		Warnf("%s is in an older ticket file format (schema %d, this kt has %d); run kt migrate", t.ID, t.Schema, ticket.SchemaVersion)
+	}
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	defer setupTestEnv(t)()
+	mkTicket(t, "kt-new", "Current", ticket.StatusOpen)
+	legacy := "---\nid: kt-old\nstatus: open\ncreated: 2026-01-09T10:00:00Z\ntype: task\npriority: 2\ntests_passed: false\n---\n# Legacy\n"
+	require.NoError(t, os.WriteFile(Store.Path("kt-old"), []byte(legacy), 0o644))
+
+	result, err := migrateStore(Store)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-old"}, result.Migrated)
+	data, err := os.ReadFile(Store.Path("kt-old"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "---\nschema: 1\n"), string(data))
+
+	result, err = migrateStore(Store)
+	require.NoError(t, err)
+	assert.Empty(t, result.Migrated, "nothing left to migrate")
+
+	newer := strings.Replace(legacy, "id: kt-old", "schema: 99\nid: kt-future", 1)
+	require.NoError(t, os.WriteFile(Store.Path("kt-future"), []byte(newer), 0o644))
+	_, err = migrateStore(Store)
+	assert.ErrorContains(t, err, "kt-future has schema 99")
+}
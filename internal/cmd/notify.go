@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/notify"
+	"github.com/kostyay/kticket/internal/store"
+)
+
+// notifyChanges posts events for the tickets s changed to the webhooks
+// configured in KTICKET_NOTIFY (or integrations.notify in config.yml), and
+// runs the matching trusted hooks from config.yml. It runs after
+// autoCommit, so hooks see the change committed. Failures are warnings:
+// the ticket change itself already succeeded.
+func notifyChanges(s *store.Store) {
+	if s == nil {
+		return
+	}
+	spec, hooks := projectConfig().Notify(), projectConfig().Hooks
+	if spec == "" && len(hooks) == 0 {
+		return
+	}
+	changes := s.Changes()
+	if len(changes) == 0 {
+		return
+	}
+	var rules []notify.Rule
+	if spec != "" {
+		var err error
+		if rules, err = notify.ParseRules(spec); err != nil {
+			Warnf("%s: %v", config.EnvNotify, err)
+		}
+	}
+	tickets, err := s.List()
+	if err != nil {
+		Warnf("notify: %v", err)
+		return
+	}
+	events := notify.Events(changes, tickets)
+	if len(rules) > 0 {
+		if err := notify.New(rules).Send(events); err != nil {
+			Warnf("%v", err)
+		}
+	}
+	runHooks(trustedHooks(s.Dir, events), events)
+}
+
+// trustedHooks returns the hooks that may run for events: the project's
+// once trusted (kt config trust or KTICKET_TRUST_HOOKS), else only the
+// user's, since anyone who can commit to the repository can change the
+// project's. It warns when events would have run untrusted hooks.
+func trustedHooks(dir string, events []notify.Event) map[string]string {
+	hooks := projectConfig().Hooks
+	project, err := config.LoadProject(dir)
+	if err != nil || len(project.Hooks) == 0 || config.HooksTrusted(dir, project.Commands()) {
+		return hooks
+	}
+	for _, ev := range events {
+		if slices.ContainsFunc(slices.Collect(maps.Keys(project.Hooks)), ev.Type.Matches) {
+			Warnf("not running the hooks in %s: run 'kt config trust' to allow them", filepath.Join(dir, config.ProjectFile))
+			break
+		}
+	}
+	user, err := config.LoadFile(config.UserFile())
+	if err != nil {
+		return nil
+	}
+	return user.Hooks
+}
+
+// runHooks runs the shell command configured for each event (and for "*")
+// with the ticket as JSON on stdin and KT_EVENT, KT_ID, KT_TITLE, and
+// KT_STATUS set. Hook output goes to stderr so it never mixes with kt's.
+func runHooks(hooks map[string]string, events []notify.Event) {
+	for _, ev := range events {
+		for _, key := range append(ev.Type.Names(), "*") {
+			command := hooks[key]
+			if command == "" {
+				continue
+			}
+			data, _ := json.Marshal(ev.Ticket)
+			c := exec.Command("sh", "-c", command)
+			c.Env = append(os.Environ(),
+				"KT_EVENT="+string(ev.Type),
+				"KT_ID="+ev.Ticket.ID,
+				"KT_TITLE="+ev.Ticket.Title,
+				"KT_STATUS="+string(ev.Ticket.Status))
+			c.Stdin = bytes.NewReader(data)
+			c.Stdout, c.Stderr = os.Stderr, os.Stderr
+			if err := c.Run(); err != nil {
+				Warnf("hook %s for %s: %v", key, ev.Ticket.ID, err)
+			}
+		}
+	}
+}
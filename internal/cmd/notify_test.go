@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyChanges(t *testing.T) {
+	defer setupTestEnv(t)()
+	var posts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		posts = append(posts, string(body))
+	}))
+	defer srv.Close()
+
+	p0 := mkTicket(t, "kt-p0", "Outage", ticket.StatusOpen)
+	p0.Priority = 0
+	require.NoError(t, Store.Save(p0))
+	mkTicket(t, "kt-p2", "Docs", ticket.StatusOpen)
+
+	// Not configured: nothing is sent
+	notifyChanges(Store)
+	assert.Empty(t, posts)
+
+	t.Setenv(config.EnvNotify, "p0_opened=slack:"+srv.URL)
+	notifyChanges(Store)
+	assert.Equal(t, []string{`{"text":":rotating_light: P0 kt-p0 opened: Outage"}`}, posts)
+}
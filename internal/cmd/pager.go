@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	noPagerFlag bool
+	// paging is set while output is captured for the pager, so OutputMode
+	// still reports a terminal even though os.Stdout is a pipe.
+	paging bool
+)
+
+// paged wraps a RunE so its output goes through the pager when it does not
+// fit on the terminal.
+func paged(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if !pagerEnabled() {
+			return run(cmd, args)
+		}
+		return withPager(func() error { return run(cmd, args) })
+	}
+}
+
+// pagerEnabled reports whether text output to a terminal may be paged.
+func pagerEnabled() bool {
+	if noPagerFlag || os.Getenv(config.EnvNoPager) != "" || paging {
+		return false
+	}
+	return OutputMode() == "text"
+}
+
+// pagerCommand returns the pager to run: $PAGER, or less -R.
+func pagerCommand() []string {
+	if p := strings.Fields(os.Getenv("PAGER")); len(p) > 0 {
+		return p
+	}
+	return []string{"less", "-R"}
+}
+
+// withPager runs fn with stdout captured, then writes the output directly
+// if it fits the terminal height or pipes it through the pager otherwise.
+func withPager(fn func() error) error {
+	stdout := os.Stdout
+	_, height, err := term.GetSize(int(stdout.Fd()))
+	if err != nil || height <= 0 {
+		return fn()
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return fn()
+	}
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&buf, r)
+		close(done)
+	}()
+
+	os.Stdout = w
+	paging = true
+	runErr := fn()
+	paging = false
+	os.Stdout = stdout
+	_ = w.Close()
+	<-done
+	_ = r.Close()
+
+	if bytes.Count(buf.Bytes(), []byte("\n")) < height {
+		_, _ = stdout.Write(buf.Bytes())
+		return runErr
+	}
+
+	args := pagerCommand()
+	pager := exec.Command(args[0], args[1:]...)
+	pager.Stdin = &buf
+	pager.Stdout = stdout
+	pager.Stderr = os.Stderr
+	if err := pager.Run(); err != nil {
+		// Pager missing or failed before reading: fall back to plain output
+		if buf.Len() > 0 {
+			_, _ = stdout.Write(buf.Bytes())
+		}
+	}
+	return runErr
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagerCommand(t *testing.T) {
+	t.Setenv("PAGER", "")
+	assert.Equal(t, []string{"less", "-R"}, pagerCommand())
+
+	t.Setenv("PAGER", "most -s")
+	assert.Equal(t, []string{"most", "-s"}, pagerCommand())
+}
+
+func TestPagerDisabled(t *testing.T) {
+	// Not a TTY under test
+	assert.False(t, pagerEnabled())
+
+	t.Setenv(config.EnvNoPager, "1")
+	assert.False(t, pagerEnabled())
+}
+
+func TestWithPagerNoTerminal(t *testing.T) {
+	ran := false
+	require.NoError(t, withPager(func() error {
+		ran = true
+		return nil
+	}))
+	assert.True(t, ran)
+	assert.False(t, paging)
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show open tickets in dependency order",
+	Long: `Show all open tickets as layers of work: every ticket in a layer
+depends only on closed tickets or tickets in earlier layers, so tickets
+within a layer can be worked on in parallel.
+
+With --parent, only descendants of that ticket are planned; open deps
+outside it are reported as external and do not delay the ticket.`,
+	RunE: runPlan,
+}
+
+var planParent string
+
+func init() {
+	planCmd.Flags().StringVar(&planParent, "parent", "", "Plan only descendants of this ticket")
+	rootCmd.AddCommand(planCmd)
+}
+
+type planStep struct {
+	ID       string        `json:"id"`
+	Status   ticket.Status `json:"status"`
+	Priority int           `json:"priority"`
+	Title    string        `json:"title"`
+	External []string      `json:"external,omitempty"` // open deps outside the plan
+}
+
+type workPlan struct {
+	Layers      [][]planStep `json:"layers"`
+	Unplannable []string     `json:"unplannable,omitempty"` // in a cycle or waiting on a missing ticket
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+
+	root := ""
+	if planParent != "" {
+		parent, err := Store.Resolve(planParent)
+		if err != nil {
+			return err
+		}
+		root = parent.ID
+	}
+
+	plan := buildPlan(tickets, root)
+
+	if IsJSON() {
+		return PrintJSON(plan)
+	}
+
+	if len(plan.Layers) == 0 && len(plan.Unplannable) == 0 {
+		fmt.Println("No open tickets")
+		return nil
+	}
+
+	for i, layer := range plan.Layers {
+		fmt.Printf("Layer %d:\n", i+1)
+		for _, s := range layer {
+			line := fmt.Sprintf("  %-12s [%-11s] P%d %s", s.ID, s.Status, s.Priority, s.Title)
+			if len(s.External) > 0 {
+				line += fmt.Sprintf(" (after %s)", strings.Join(s.External, ", "))
+			}
+			fmt.Println(line)
+		}
+	}
+	if len(plan.Unplannable) > 0 {
+		fmt.Printf("Unplannable (cycle or missing dep): %s\n", strings.Join(plan.Unplannable, ", "))
+	}
+	return nil
+}
+
+// buildPlan layers open tickets (descendants of root, or all when root is
+// empty) so each depends only on closed tickets or earlier layers.
+func buildPlan(tickets []*ticket.Ticket, root string) *workPlan {
+	byID := make(map[string]*ticket.Ticket, len(tickets))
+	children := make(map[string][]string)
+	for _, t := range tickets {
+		byID[t.ID] = t
+		if t.Parent != "" {
+			children[t.Parent] = append(children[t.Parent], t.ID)
+		}
+	}
+
+	inScope := make(map[string]bool)
+	if root == "" {
+		for _, t := range tickets {
+			inScope[t.ID] = true
+		}
+	} else {
+		stack := append([]string(nil), children[root]...)
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if inScope[id] {
+				continue
+			}
+			inScope[id] = true
+			stack = append(stack, children[id]...)
+		}
+	}
+
+	// pending[id] holds the in-plan deps id still waits on
+	pending := make(map[string]map[string]bool)
+	steps := make(map[string]*planStep)
+	var unplannable []string
+	for id := range inScope {
+		t := byID[id]
+		if t.Status == ticket.StatusClosed {
+			continue
+		}
+		step := &planStep{ID: t.ID, Status: t.Status, Priority: t.Priority, Title: t.Title}
+		waits := make(map[string]bool)
+		missing := false
+		for _, d := range t.Deps {
+			dep, ok := byID[d]
+			switch {
+			case !ok:
+				missing = true
+			case dep.Status == ticket.StatusClosed:
+			case inScope[d]:
+				waits[d] = true
+			default:
+				step.External = append(step.External, d)
+			}
+		}
+		if missing {
+			unplannable = append(unplannable, id)
+			continue
+		}
+		steps[id] = step
+		pending[id] = waits
+	}
+
+	// Tickets waiting on an unplannable one can't be planned either
+	for changed := true; changed; {
+		changed = false
+		for id, waits := range pending {
+			for d := range waits {
+				if _, ok := steps[d]; !ok {
+					unplannable = append(unplannable, id)
+					delete(pending, id)
+					delete(steps, id)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	plan := &workPlan{Layers: [][]planStep{}}
+	for len(pending) > 0 {
+		var layer []planStep
+		for id, waits := range pending {
+			if len(waits) == 0 {
+				layer = append(layer, *steps[id])
+			}
+		}
+		if len(layer) == 0 {
+			// Everything left is in or behind a dependency cycle
+			for id := range pending {
+				unplannable = append(unplannable, id)
+			}
+			break
+		}
+		sort.Slice(layer, func(i, j int) bool {
+			if layer[i].Priority != layer[j].Priority {
+				return layer[i].Priority < layer[j].Priority
+			}
+			return layer[i].ID < layer[j].ID
+		})
+		for _, s := range layer {
+			delete(pending, s.ID)
+		}
+		for _, waits := range pending {
+			for _, s := range layer {
+				delete(waits, s.ID)
+			}
+		}
+		plan.Layers = append(plan.Layers, layer)
+	}
+
+	sort.Strings(unplannable)
+	plan.Unplannable = unplannable
+	return plan
+}
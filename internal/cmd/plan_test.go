@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func layerIDs(p *workPlan) [][]string {
+	out := make([][]string, len(p.Layers))
+	for i, layer := range p.Layers {
+		for _, s := range layer {
+			out[i] = append(out[i], s.ID)
+		}
+	}
+	return out
+}
+
+func TestBuildPlan(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "kt-a", Status: ticket.StatusOpen, Priority: 2},
+		{ID: "kt-b", Status: ticket.StatusOpen, Priority: 1},
+		{ID: "kt-c", Status: ticket.StatusOpen, Deps: []string{"kt-a", "kt-b"}},
+		{ID: "kt-d", Status: ticket.StatusInProgress, Deps: []string{"kt-c", "kt-done"}},
+		{ID: "kt-done", Status: ticket.StatusClosed},
+		{ID: "kt-x", Status: ticket.StatusOpen, Deps: []string{"kt-y"}},
+		{ID: "kt-y", Status: ticket.StatusOpen, Deps: []string{"kt-x"}},
+		{ID: "kt-m", Status: ticket.StatusOpen, Deps: []string{"kt-gone"}},
+		{ID: "kt-n", Status: ticket.StatusOpen, Deps: []string{"kt-m"}},
+	}
+
+	p := buildPlan(tickets, "")
+	assert.Equal(t, [][]string{{"kt-b", "kt-a"}, {"kt-c"}, {"kt-d"}}, layerIDs(p))
+	assert.Equal(t, []string{"kt-m", "kt-n", "kt-x", "kt-y"}, p.Unplannable)
+}
+
+func TestBuildPlanParent(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "kt-epic", Status: ticket.StatusOpen},
+		{ID: "kt-a", Status: ticket.StatusOpen, Parent: "kt-epic"},
+		{ID: "kt-sub", Status: ticket.StatusOpen, Parent: "kt-a", Deps: []string{"kt-a"}},
+		{ID: "kt-b", Status: ticket.StatusOpen, Parent: "kt-epic", Deps: []string{"kt-other"}},
+		{ID: "kt-other", Status: ticket.StatusOpen},
+	}
+
+	p := buildPlan(tickets, "kt-epic")
+	assert.Equal(t, [][]string{{"kt-a", "kt-b"}, {"kt-sub"}}, layerIDs(p))
+	assert.Equal(t, []string{"kt-other"}, p.Layers[0][1].External)
+	assert.Empty(t, p.Unplannable)
+}
+
+func TestRunPlan(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { planParent = "" }()
+
+	epic := mkTicket(t, "kt-epic", "Epic", ticket.StatusOpen)
+	child := mkTicket(t, "kt-child", "Child", ticket.StatusOpen)
+	child.Parent = epic.ID
+	require.NoError(t, Store.Save(child))
+
+	require.NoError(t, runPlan(nil, nil))
+
+	planParent = "epic"
+	require.NoError(t, runPlan(nil, nil))
+
+	planParent = "kt-missing"
+	require.Error(t, runPlan(nil, nil))
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var prBodyCmd = &cobra.Command{
+	Use:   "pr-body <id>",
+	Short: "Render a ticket as a pull request description",
+	Long: `Render the ticket's title, description, acceptance criteria, and tests
+as a markdown pull request body, ending with a "Refs: <id>" line so
+kt scan-commits links the merge commit to the ticket.
+
+  kt pr-body kt-a1b2 | gh pr create --title "Fix login" --body-file -
+  kt pr-body kt-a1b2 --create   # same, via gh with the ticket title`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPRBody,
+}
+
+var prBodyCreate bool
+
+func init() {
+	prBodyCmd.Flags().BoolVar(&prBodyCreate, "create", false, "Open the pull request with 'gh pr create'")
+	rootCmd.AddCommand(prBodyCmd)
+}
+
+type prBodyResult struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+func runPRBody(cmd *cobra.Command, args []string) error {
+	t, err := Store.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+	result := prBodyResult{ID: t.ID, Title: t.Title, Body: prBody(t)}
+
+	if prBodyCreate {
+		gh := exec.Command("gh", "pr", "create", "--title", result.Title, "--body-file", "-")
+		gh.Stdin = strings.NewReader(result.Body)
+		gh.Stdout = os.Stdout
+		gh.Stderr = os.Stderr
+		if err := gh.Run(); err != nil {
+			return fmt.Errorf("gh pr create: %w", err)
+		}
+		return nil
+	}
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	fmt.Print(result.Body)
+	return nil
+}
+
+// prBody renders a ticket as a pull request description.
+func prBody(t *ticket.Ticket) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n", t.Title)
+	if t.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", t.Description)
+	}
+	if t.AcceptanceCriteria != "" {
+		fmt.Fprintf(&b, "\n## Acceptance Criteria\n\n%s\n", t.AcceptanceCriteria)
+	}
+	if t.Tests != "" {
+		fmt.Fprintf(&b, "\n## Tests\n\n%s\n", t.Tests)
+	}
+	fmt.Fprintf(&b, "\nRefs: %s\n", t.ID)
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPRBody(t *testing.T) {
+	tk := &ticket.Ticket{
+		ID:                 "kt-a1b2",
+		Title:              "Fix login",
+		Description:        "Sessions expire too early.",
+		AcceptanceCriteria: "- [ ] Sessions last 24h",
+		Tests:              "- TestSessionTTL",
+		Notes:              "internal chatter",
+	}
+	assert.Equal(t, `## Fix login
+
+Sessions expire too early.
+
+## Acceptance Criteria
+
+- [ ] Sessions last 24h
+
+## Tests
+
+- TestSessionTTL
+
+Refs: kt-a1b2
+`, prBody(tk))
+
+	assert.Equal(t, "## Bare\n\nRefs: kt-c3d4\n", prBody(&ticket.Ticket{ID: "kt-c3d4", Title: "Bare"}))
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var primeCmd = &cobra.Command{
+	Use:   "prime",
+	Short: "Print a compact summary of the store for an agent's context",
+	Long: `Print a compact summary of the tracker, meant to be injected into a
+coding agent's context at the start of a session: work in progress, ready
+tickets (highest priority first) with their acceptance criteria, and the
+tickets blocking the most others.
+
+The text is cut to about --budget tokens (estimated at 4 characters each):
+once the budget runs out, tickets are listed without their acceptance
+criteria and then summarized as a count.`,
+	Args: cobra.NoArgs,
+	RunE: runPrime,
+}
+
+var primeBudget int
+
+func init() {
+	primeCmd.Flags().IntVar(&primeBudget, "budget", 1000, "Approximate token budget for the text output")
+	rootCmd.AddCommand(primeCmd)
+}
+
+// charsPerToken estimates the token count of English and code text.
+const charsPerToken = 4
+
+// primeBlocker is an open ticket that open tickets depend on.
+type primeBlocker struct {
+	ID     string   `json:"id"`
+	Title  string   `json:"title"`
+	Status string   `json:"status"`
+	Blocks []string `json:"blocks"`
+}
+
+type primeResult struct {
+	InProgress []*ticket.Ticket `json:"in_progress"`
+	Ready      []*ticket.Ticket `json:"ready"`
+	Blocked    int              `json:"blocked"` // open tickets waiting on deps
+	Blockers   []primeBlocker   `json:"blockers"`
+}
+
+func runPrime(cmd *cobra.Command, args []string) error {
+	if primeBudget <= 0 {
+		return fmt.Errorf("invalid --budget %d (expected a positive number of tokens)", primeBudget)
+	}
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+	r := buildPrime(tickets)
+	if IsJSON() {
+		return PrintJSON(r)
+	}
+	fmt.Print(r.Text(primeBudget * charsPerToken))
+	return nil
+}
+
+// buildPrime sorts the open tickets into work in progress, ready tickets,
+// and blockers, each most important first.
+func buildPrime(tickets []*ticket.Ticket) *primeResult {
+	byID := make(map[string]*ticket.Ticket, len(tickets))
+	for _, t := range tickets {
+		byID[t.ID] = t
+	}
+	r := &primeResult{InProgress: []*ticket.Ticket{}, Ready: []*ticket.Ticket{}, Blockers: []primeBlocker{}}
+	blocks := map[string][]string{}
+	for _, t := range tickets {
+		if t.Status == ticket.StatusClosed {
+			continue
+		}
+		waiting := false
+		for _, d := range t.Deps {
+			if dep, ok := byID[d]; !ok || dep.Status != ticket.StatusClosed {
+				waiting = true
+				if ok {
+					blocks[d] = append(blocks[d], t.ID)
+				}
+			}
+		}
+		switch {
+		case t.Status == ticket.StatusInProgress:
+			r.InProgress = append(r.InProgress, t)
+		case waiting:
+			r.Blocked++
+		default:
+			r.Ready = append(r.Ready, t)
+		}
+	}
+
+	byPriority := func(a, b *ticket.Ticket) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.Created, b.Created), cmp.Compare(a.ID, b.ID))
+	}
+	slices.SortFunc(r.InProgress, byPriority)
+	slices.SortFunc(r.Ready, byPriority)
+
+	for id, blocked := range blocks {
+		slices.Sort(blocked)
+		t := byID[id]
+		r.Blockers = append(r.Blockers, primeBlocker{ID: id, Title: t.Title, Status: string(t.Status), Blocks: blocked})
+	}
+	slices.SortFunc(r.Blockers, func(a, b primeBlocker) int {
+		return cmp.Or(cmp.Compare(len(b.Blocks), len(a.Blocks)), byPriority(byID[a.ID], byID[b.ID]))
+	})
+	return r
+}
+
+// Text renders the summary in at most about budget characters.
+func (r *primeResult) Text(budget int) string {
+	w := &primeWriter{budget: budget}
+	w.force(fmt.Sprintf("# kt: %d in progress, %d ready, %d blocked\n", len(r.InProgress), len(r.Ready), r.Blocked))
+
+	if len(r.InProgress) > 0 {
+		w.section("\n## In progress\n", r.InProgress, "kt ls --status in_progress", func(t *ticket.Ticket) string {
+			line := fmt.Sprintf("- %s P%d %s", t.ID, t.Priority, t.Title)
+			if t.Assignee != "" {
+				line += " (" + t.Assignee + ")"
+			}
+			return line + "\n"
+		})
+	}
+	if len(r.Ready) > 0 {
+		w.section("\n## Ready\n", r.Ready, "kt ready", func(t *ticket.Ticket) string {
+			return fmt.Sprintf("- %s P%d %s: %s\n", t.ID, t.Priority, t.Type, t.Title)
+		})
+	}
+	if len(r.Blockers) > 0 {
+		lines := make([]string, len(r.Blockers))
+		for i, b := range r.Blockers {
+			lines[i] = fmt.Sprintf("- %s [%s] %s: blocks %s\n", b.ID, b.Status, b.Title, strings.Join(b.Blocks, ", "))
+		}
+		w.lines("\n## Top blockers\n", lines, "kt blocked")
+	}
+	w.force("\n`kt show <id>` for details.\n")
+	return w.b.String()
+}
+
+// primeWriter builds text up to a character budget.
+type primeWriter struct {
+	b      strings.Builder
+	budget int
+}
+
+// add writes s if it fits in the budget.
+func (w *primeWriter) add(s string) bool {
+	if w.b.Len()+len(s) > w.budget {
+		return false
+	}
+	w.b.WriteString(s)
+	return true
+}
+
+// force writes s even if it exceeds the budget.
+func (w *primeWriter) force(s string) {
+	w.b.WriteString(s)
+}
+
+// section writes a heading and a line per ticket, each followed by its
+// acceptance criteria while they fit.
+func (w *primeWriter) section(heading string, tickets []*ticket.Ticket, more string, line func(*ticket.Ticket) string) {
+	if !w.add(heading) {
+		return
+	}
+	for i, t := range tickets {
+		entry := line(t)
+		if criteria := strings.TrimSpace(t.AcceptanceCriteria); criteria != "" {
+			full := entry + "  " + strings.ReplaceAll(criteria, "\n", "\n  ") + "\n"
+			if w.add(full) {
+				continue
+			}
+		}
+		if !w.add(entry) {
+			w.force(fmt.Sprintf("- … %d more (%s)\n", len(tickets)-i, more))
+			return
+		}
+	}
+}
+
+// lines writes a heading and lines while they fit.
+func (w *primeWriter) lines(heading string, lines []string, more string) {
+	if !w.add(heading) {
+		return
+	}
+	for i, l := range lines {
+		if !w.add(l) {
+			w.force(fmt.Sprintf("- … %d more (%s)\n", len(lines)-i, more))
+			return
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPrime(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "kt-1", Title: "Design", Status: ticket.StatusOpen, Type: ticket.TypeTask, Priority: 2, AcceptanceCriteria: "- [ ] Reviewed"},
+		{ID: "kt-2", Title: "Build", Status: ticket.StatusOpen, Priority: 1, Deps: []string{"kt-1"}},
+		{ID: "kt-3", Title: "Docs", Status: ticket.StatusOpen, Priority: 3, Deps: []string{"kt-1", "kt-4"}},
+		{ID: "kt-4", Title: "Done", Status: ticket.StatusClosed},
+		{ID: "kt-5", Title: "Urgent", Status: ticket.StatusOpen, Priority: 0},
+		{ID: "kt-6", Title: "Doing", Status: ticket.StatusInProgress, Assignee: "ana"},
+	}
+	r := buildPrime(tickets)
+
+	ids := func(ts []*ticket.Ticket) (out []string) {
+		for _, t := range ts {
+			out = append(out, t.ID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"kt-6"}, ids(r.InProgress))
+	assert.Equal(t, []string{"kt-5", "kt-1"}, ids(r.Ready), "priority order")
+	assert.Equal(t, 2, r.Blocked)
+	require.Len(t, r.Blockers, 1)
+	assert.Equal(t, primeBlocker{ID: "kt-1", Title: "Design", Status: "open", Blocks: []string{"kt-2", "kt-3"}}, r.Blockers[0])
+
+	text := r.Text(10000)
+	assert.Contains(t, text, "# kt: 1 in progress, 2 ready, 2 blocked")
+	assert.Contains(t, text, "- kt-6 P0 Doing (ana)")
+	assert.Contains(t, text, "- kt-1 P2 task: Design\n  - [ ] Reviewed\n")
+	assert.Contains(t, text, "- kt-1 [open] Design: blocks kt-2, kt-3")
+
+	short := r.Text(120)
+	assert.Contains(t, short, "- kt-6 P0 Doing (ana)")
+	assert.Contains(t, short, "more (kt ready)")
+	assert.NotContains(t, short, "Reviewed")
+	assert.True(t, strings.HasSuffix(short, "`kt show <id>` for details.\n"))
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// permissionProfile is how a coding agent is told it may run kt without
+// asking: the file it reads, and how to add and remove kt's entry there.
+// Supporting another agent's permission format means adding a profile.
+type permissionProfile struct {
+	agent string // the install target
+	// path returns the file for the global or project scope, or "" if the
+	// agent has none there.
+	path   func(global bool) string
+	add    func(path string) (bool, error) // reports whether the file changed
+	remove func(path string) (bool, error) // reports whether the file changed
+}
+
+// permissionProfiles are the agents whose permissions kt knows how to set.
+var permissionProfiles = []permissionProfile{
+	jsonListProfile("claude", claudeSettingsPath, "permissions.allow", ktPermission),
+	jsonListProfile("cursor", projectOnly(filepath.Join(".cursor", "cli.json")), "permissions.allow", "Shell(kt)"),
+	jsonListProfile("gemini", projectOnly(filepath.Join(".gemini", "settings.json")), "tools.allowed", "run_shell_command(kt)"),
+	{agent: "opencode", path: openCodeConfigPath, add: allowOpenCode, remove: disallowOpenCode},
+	{agent: "codex", path: codexRulesPath, add: writeCodexRule, remove: removeCodexRule},
+}
+
+// permissionProfileFor returns the profile for an install target, or nil.
+func permissionProfileFor(agent string) *permissionProfile {
+	i := slices.IndexFunc(permissionProfiles, func(p permissionProfile) bool { return p.agent == agent })
+	if i < 0 {
+		return nil
+	}
+	return &permissionProfiles[i]
+}
+
+// allowKt adds kt's permission for agent at the given scope, returning the
+// file it checked and whether it changed.
+func allowKt(agent string, global bool) (string, bool, error) {
+	p := permissionProfileFor(agent)
+	if p == nil {
+		return "", false, fmt.Errorf("no permission profile for %s", agent)
+	}
+	path := p.path(global)
+	if path == "" {
+		return "", false, fmt.Errorf("%s has no %s permission settings", agent, scopeName(global))
+	}
+	changed, err := p.add(path)
+	return path, changed, err
+}
+
+// scopeName names a settings scope in messages.
+func scopeName(global bool) string {
+	if global {
+		return "global"
+	}
+	return "project"
+}
+
+// jsonListProfile is a profile that lists item in the list at the dotted
+// path in a JSON settings file.
+func jsonListProfile(agent string, path func(bool) string, list, item string) permissionProfile {
+	return permissionProfile{
+		agent:  agent,
+		path:   path,
+		add:    func(path string) (bool, error) { return addJSONListItem(path, list, item) },
+		remove: func(path string) (bool, error) { return removeJSONListItem(path, list, item) },
+	}
+}
+
+// projectOnly is a profile path for agents kt sets up per project only.
+func projectOnly(path string) func(bool) string {
+	return func(global bool) string {
+		if global {
+			return ""
+		}
+		return path
+	}
+}
+
+// openCodeBashPattern is the OpenCode bash permission pattern matching kt.
+const openCodeBashPattern = "kt *"
+
+// openCodeConfigPath returns the project's opencode.json, or the user's
+// ~/.config/opencode/opencode.json.
+func openCodeConfigPath(global bool) string {
+	if !global {
+		return "opencode.json"
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "opencode", "opencode.json")
+}
+
+// allowOpenCode sets permission.bash."kt *" to allow in an OpenCode config.
+// A bash permission given as a single action becomes the "*" pattern, and
+// one that already allows everything is left alone.
+func allowOpenCode(path string) (bool, error) {
+	settings, err := readSettings(path)
+	if err != nil {
+		return false, err
+	}
+	switch bash := settings.Search("permission", "bash").Data().(type) {
+	case string:
+		if bash == "allow" {
+			return false, nil
+		}
+		_, err = settings.Set(map[string]any{"*": bash, openCodeBashPattern: "allow"}, "permission", "bash")
+	default:
+		if settings.Search("permission", "bash", openCodeBashPattern).Data() == "allow" {
+			return false, nil
+		}
+		_, err = settings.Set("allow", "permission", "bash", openCodeBashPattern)
+	}
+	if err != nil {
+		return false, fmt.Errorf("set permission.bash: %w", err)
+	}
+	return true, writeSettings(path, settings)
+}
+
+// disallowOpenCode removes the kt pattern from an OpenCode config.
+func disallowOpenCode(path string) (bool, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	settings, err := readSettings(path)
+	if err != nil {
+		return false, err
+	}
+	if !settings.Exists("permission", "bash", openCodeBashPattern) {
+		return false, nil
+	}
+	if err := settings.Delete("permission", "bash", openCodeBashPattern); err != nil {
+		return false, fmt.Errorf("remove permission.bash: %w", err)
+	}
+	if len(settings.Search("permission", "bash").ChildrenMap()) == 0 {
+		_ = settings.Delete("permission", "bash")
+	}
+	return true, writeSettings(path, settings)
+}
+
+// codexRule is the Codex execution policy rule that allows kt.
+const codexRule = `# Installed by kt: run kt without asking
+prefix_rule(pattern = ["kt"], decision = "allow")
+`
+
+// codexRulesPath returns $CODEX_HOME/rules/kt.rules (default ~/.codex).
+// Codex reads rules for the user only.
+func codexRulesPath(global bool) string {
+	if !global {
+		return ""
+	}
+	dir := os.Getenv("CODEX_HOME")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".codex")
+	}
+	return filepath.Join(dir, "rules", "kt.rules")
+}
+
+// writeCodexRule writes kt's Codex rules file.
+func writeCodexRule(path string) (bool, error) {
+	if data, err := os.ReadFile(path); err == nil && string(data) == codexRule {
+		return false, nil
+	}
+	return true, writeAgentFile(path, codexRule)
+}
+
+// removeCodexRule removes kt's Codex rules file.
+func removeCodexRule(path string) (bool, error) {
+	err := os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
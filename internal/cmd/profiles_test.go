@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenCodePermission(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "opencode.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"permission":{"bash":"ask","edit":"allow"}}`), 0644))
+
+	changed, err := allowOpenCode(path)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = allowOpenCode(path)
+	require.NoError(t, err)
+	assert.False(t, changed, "already allowed")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"permission":{"bash":{"*":"ask","kt *":"allow"},"edit":"allow"}}`, string(data))
+
+	changed, err = disallowOpenCode(path)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"permission":{"bash":{"*":"ask"},"edit":"allow"}}`, string(data))
+
+	allowAll := filepath.Join(t.TempDir(), "opencode.json")
+	require.NoError(t, os.WriteFile(allowAll, []byte(`{"permission":{"bash":"allow"}}`), 0644))
+	changed, err = allowOpenCode(allowAll)
+	require.NoError(t, err)
+	assert.False(t, changed, "bash already allowed")
+}
+
+func TestInstallOpenCodeAndCodex(t *testing.T) {
+	defer setupTestEnv(t)()
+	t.Chdir(t.TempDir())
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+
+	require.NoError(t, installOpenCode())
+	require.NoError(t, installCodex())
+	data, err := os.ReadFile("opencode.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"permission":{"bash":{"kt *":"allow"}}}`, string(data))
+	rule, err := os.ReadFile(filepath.Join(os.Getenv("CODEX_HOME"), "rules", "kt.rules"))
+	require.NoError(t, err)
+	assert.Contains(t, string(rule), `prefix_rule(pattern = ["kt"], decision = "allow")`)
+	agents, err := os.ReadFile("AGENTS.md")
+	require.NoError(t, err)
+	assert.Contains(t, string(agents), sectionStart)
+
+	_, _, err = allowKt("codex", false)
+	assert.ErrorContains(t, err, "codex has no project permission settings")
+
+	require.NoError(t, runUninstall(nil, nil))
+	for _, path := range []string{"AGENTS.md", filepath.Join(os.Getenv("CODEX_HOME"), "rules", "kt.rules")} {
+		_, err := os.Stat(path)
+		assert.True(t, os.IsNotExist(err), path)
+	}
+	data, err = os.ReadFile("opencode.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"permission":{}}`, string(data))
+}
@@ -4,38 +4,77 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/kostyay/kticket/internal/store"
 	"github.com/kostyay/kticket/internal/ticket"
 	"github.com/spf13/cobra"
 )
 
 var purgeCmd = &cobra.Command{
-	Use:   "purge",
-	Short: "Delete all closed tickets",
-	Long:  "Permanently delete all closed ticket files. Validates that no open tickets reference them.",
-	RunE:  runPurge,
+	Use:   "purge [id...]",
+	Short: "Delete closed tickets",
+	Long: `Permanently delete all closed ticket files, or only the given ones, which
+must be closed. Refuses if an open ticket has one as parent, depends on it,
+or links to it, unless --prune-refs is given, in which case those references
+are removed too. References from the closed tickets that remain are always
+removed, so nothing is left pointing at a deleted ticket.
+With --older-than, only tickets closed (or, lacking a close time, created)
+longer ago than that are deleted.
+
+Asks for confirmation first, unless --yes or --dry-run is given. In JSON
+mode --yes is required, and the result lists the deleted IDs.`,
+	RunE: runPurge,
 }
 
+var (
+	purgeYes       bool
+	purgeOlderThan string
+	purgePruneRefs bool
+)
+
 func init() {
+	purgeCmd.Flags().BoolVarP(&purgeYes, "yes", "y", false, "Do not ask for confirmation")
+	purgeCmd.Flags().StringVar(&purgeOlderThan, "older-than", "", "Only purge tickets closed longer ago than this, e.g. 90d")
+	purgeCmd.Flags().BoolVar(&purgePruneRefs, "prune-refs", false, "Also remove references to the purged tickets from open tickets")
+	addDryRunFlag(purgeCmd)
 	rootCmd.AddCommand(purgeCmd)
 }
 
 type purgeResult struct {
 	Deleted int      `json:"deleted"`
+	IDs     []string `json:"ids,omitempty"`
+	Cleaned []string `json:"cleaned,omitempty"` // tickets whose references were removed
 	Errors  []string `json:"errors,omitempty"`
 }
 
 func runPurge(cmd *cobra.Command, args []string) error {
+	startDryRun(Store)
+	var cutoff time.Time
+	if purgeOlderThan != "" {
+		age, err := parseSince(purgeOlderThan)
+		if err != nil || age == 0 {
+			return fmt.Errorf("invalid --older-than %q (expected e.g. 90d)", purgeOlderThan)
+		}
+		cutoff = time.Now().Add(-age)
+	}
+
 	allTickets, err := Store.List()
 	if err != nil {
 		return fmt.Errorf("list tickets: %w", err)
 	}
 
+	candidates := allTickets
+	if len(args) > 0 {
+		if candidates, err = purgeArgs(args); err != nil {
+			return err
+		}
+	}
 	var closedTickets []*ticket.Ticket
-	for _, t := range allTickets {
-		if t.Status == ticket.StatusClosed {
+	for _, t := range candidates {
+		if t.Status == ticket.StatusClosed && (cutoff.IsZero() || closedAt(t).Before(cutoff)) {
 			closedTickets = append(closedTickets, t)
 		}
 	}
@@ -48,71 +87,123 @@ func runPurge(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if err := validatePurge(allTickets, closedTickets); err != nil {
-		return err
+	ids := ticketIDs(closedTickets)
+	var referrers []*ticket.Ticket
+	if purgePruneRefs {
+		referrers = store.Referrers(allTickets, ids)
+	} else if err := validatePurge(allTickets, closedTickets); err != nil {
+		return fmt.Errorf("%w (use --prune-refs to remove the references)", err)
 	}
 
-	if IsJSON() {
-		return fmt.Errorf("refusing to purge in JSON mode (interactive confirmation required)")
+	if !purgeYes && !dryRun {
+		if IsJSON() {
+			return fmt.Errorf("refusing to purge in JSON mode without --yes (interactive confirmation required)")
+		}
+		confirmed, err := promptConfirmation(closedTickets, referrers)
+		if err != nil {
+			return fmt.Errorf("prompt: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Purge cancelled")
+			return nil
+		}
 	}
 
-	confirmed, err := promptConfirmation(closedTickets)
+	cleaned, err := purgeTickets(Store, ids, purgePruneRefs)
 	if err != nil {
-		return fmt.Errorf("prompt: %w", err)
+		return err
 	}
 
-	if !confirmed {
-		fmt.Println("Purge cancelled")
-		return nil
+	result := purgeResult{Deleted: len(ids), IDs: ids, Cleaned: cleaned}
+	if dryRun {
+		return printDryRun(Store, result)
 	}
-
-	for _, t := range closedTickets {
-		path := filepath.Join(Store.Dir, t.ID+".md")
-		if err := os.Remove(path); err != nil {
-			return fmt.Errorf("delete %s: %w", t.ID, err)
-		}
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	fmt.Printf("Purged %d tickets\n", len(ids))
+	if len(cleaned) > 0 {
+		fmt.Printf("Removed references from: %s\n", strings.Join(cleaned, ", "))
 	}
-
-	fmt.Printf("Purged %d tickets\n", len(closedTickets))
 	return nil
 }
 
-func validatePurge(allTickets, closedTickets []*ticket.Ticket) error {
-	closedSet := make(map[string]bool)
-	for _, t := range closedTickets {
-		closedSet[t.ID] = true
-	}
-
-	for _, t := range allTickets {
-		if t.Status == ticket.StatusClosed {
-			continue
+// purgeTickets deletes the tickets and removes every reference to them
+// from the tickets that remain, returning the IDs of those it changed.
+// Unless pruneOpen is set, it refuses if an open ticket refers to one.
+func purgeTickets(s *store.Store, ids []string, pruneOpen bool) ([]string, error) {
+	var cleaned []string
+	err := s.Transaction(func(tx *store.Tx) error {
+		if !pruneOpen {
+			if err := store.CheckRefs("purge", tx.List(), ids); err != nil {
+				return err
+			}
 		}
-
-		if t.Parent != "" && closedSet[t.Parent] {
-			return fmt.Errorf("cannot purge %s: ticket %s has it as parent", t.Parent, t.ID)
+		for _, id := range ids {
+			tx.Delete(id)
 		}
-
-		for _, dep := range t.Deps {
-			if closedSet[dep] {
-				return fmt.Errorf("cannot purge %s: ticket %s depends on it", dep, t.ID)
-			}
+		for _, id := range ids {
+			cleaned = append(cleaned, tx.RewriteRefs(id, "")...)
 		}
+		return nil
+	})
+	slices.Sort(cleaned)
+	return slices.Compact(cleaned), err
+}
 
-		for _, link := range t.Links {
-			if closedSet[link] {
-				return fmt.Errorf("cannot purge %s: ticket %s links to it", link, t.ID)
-			}
+// purgeArgs resolves the tickets named on the command line, refusing any
+// that are not closed.
+func purgeArgs(args []string) ([]*ticket.Ticket, error) {
+	tickets := make([]*ticket.Ticket, 0, len(args))
+	for _, arg := range args {
+		t, err := Store.Resolve(arg)
+		if err != nil {
+			return nil, err
+		}
+		if t.Status != ticket.StatusClosed {
+			return nil, fmt.Errorf("cannot purge %s: it is %s, not closed", t.ID, t.Status)
+		}
+		if !slices.ContainsFunc(tickets, func(o *ticket.Ticket) bool { return o.ID == t.ID }) {
+			tickets = append(tickets, t)
 		}
 	}
+	return tickets, nil
+}
 
-	return nil
+// closedAt returns when t was closed, or if that is not recorded, when it
+// was created.
+func closedAt(t *ticket.Ticket) time.Time {
+	if closed, err := time.Parse(time.RFC3339, t.Closed); err == nil {
+		return closed
+	}
+	created, _ := time.Parse(time.RFC3339, t.Created)
+	return created
+}
+
+func validatePurge(allTickets, closedTickets []*ticket.Ticket) error {
+	return store.CheckRefs("purge", allTickets, ticketIDs(closedTickets))
 }
 
-func promptConfirmation(tickets []*ticket.Ticket) (bool, error) {
+// ticketIDs returns the IDs of tickets, in order.
+func ticketIDs(tickets []*ticket.Ticket) []string {
+	ids := make([]string, len(tickets))
+	for i, t := range tickets {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+func promptConfirmation(tickets, referrers []*ticket.Ticket) (bool, error) {
 	fmt.Printf("Found %d closed tickets:\n", len(tickets))
 	for _, t := range tickets {
 		fmt.Printf("  %s: %s\n", t.ID, t.Title)
 	}
+	if len(referrers) > 0 {
+		fmt.Printf("\nReferences to them will be removed from %d open tickets:\n", len(referrers))
+		for _, t := range referrers {
+			fmt.Printf("  %s: %s\n", t.ID, t.Title)
+		}
+	}
 	fmt.Printf("\nPurge %d tickets? [y/N] ", len(tickets))
 
 	reader := bufio.NewReader(os.Stdin)
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/kostyay/kticket/internal/ticket"
 	"github.com/stretchr/testify/assert"
@@ -137,6 +138,116 @@ func TestPurgeJSONMode(t *testing.T) {
 	assert.Len(t, files, 1)
 }
 
+func TestPurgeYes(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { jsonFlag, purgeYes = false, false }()
+	mkTicket(t, "kt-001", "Closed Task", ticket.StatusClosed)
+	mkTicket(t, "kt-002", "Open Task", ticket.StatusOpen)
+
+	jsonFlag, purgeYes = true, true
+	require.NoError(t, runPurge(nil, nil), "no prompt, even in JSON mode")
+
+	files, _ := filepath.Glob(filepath.Join(Store.Dir, "*.md"))
+	assert.Len(t, files, 1)
+}
+
+func TestPurgeOlderThan(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { purgeYes, purgeOlderThan = false, "" }()
+	old := mkTicket(t, "kt-old", "Closed long ago", ticket.StatusClosed)
+	old.Closed = time.Now().AddDate(0, 0, -100).UTC().Format(time.RFC3339)
+	require.NoError(t, Store.Save(old))
+	recent := mkTicket(t, "kt-recent", "Closed yesterday", ticket.StatusClosed)
+	recent.Closed = time.Now().AddDate(0, 0, -1).UTC().Format(time.RFC3339)
+	require.NoError(t, Store.Save(recent))
+	undated := mkTicket(t, "kt-undated", "Closed by hand", ticket.StatusClosed)
+	undated.Closed = ""
+	undated.Created = "2020-01-01T00:00:00Z"
+	require.NoError(t, Store.Save(undated))
+
+	purgeYes, purgeOlderThan = true, "90d"
+	require.NoError(t, runPurge(nil, nil))
+
+	files, _ := filepath.Glob(filepath.Join(Store.Dir, "*.md"))
+	assert.Equal(t, []string{Store.Path("kt-recent")}, files)
+
+	purgeOlderThan = "soon"
+	assert.ErrorContains(t, runPurge(nil, nil), "invalid --older-than")
+}
+
+func TestPurgeIDs(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { purgeYes = false }()
+	mkTicket(t, "kt-gone", "Closed", ticket.StatusClosed)
+	mkTicket(t, "kt-kept", "Also closed", ticket.StatusClosed)
+	dep := mkTicket(t, "kt-dep", "Closed dep", ticket.StatusClosed)
+	task := mkTicket(t, "kt-task", "Open", ticket.StatusOpen)
+	task.Deps = []string{dep.ID}
+	require.NoError(t, Store.Save(task))
+	purgeYes = true
+
+	assert.ErrorContains(t, runPurge(nil, []string{"kt-gone", "kt-task"}), "cannot purge kt-task: it is open")
+	assert.ErrorContains(t, runPurge(nil, []string{"kt-dep"}), "cannot purge")
+	require.NoError(t, runPurge(nil, []string{"gone", "kt-gone"}))
+
+	files, _ := filepath.Glob(filepath.Join(Store.Dir, "*.md"))
+	assert.Len(t, files, 3)
+	_, err := Store.Get("kt-gone")
+	assert.Error(t, err)
+}
+
+func TestPurgeStripsRefs(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { purgeYes, purgePruneRefs = false, false }()
+	mkTicket(t, "kt-gone", "Closed", ticket.StatusClosed)
+	kept := mkTicket(t, "kt-kept", "Also closed", ticket.StatusClosed)
+	kept.Deps = []string{"kt-gone"}
+	kept.Links = []string{"kt-gone"}
+	require.NoError(t, Store.Save(kept))
+	task := mkTicket(t, "kt-task", "Open", ticket.StatusOpen)
+	task.Parent = "kt-kept"
+	require.NoError(t, Store.Save(task))
+	purgeYes = true
+
+	require.NoError(t, runPurge(nil, []string{"kt-gone"}))
+	got, err := Store.Get("kt-kept")
+	require.NoError(t, err)
+	assert.Empty(t, got.Deps, "closed tickets lose references to purged ones")
+	assert.Empty(t, got.Links)
+
+	err = runPurge(nil, nil)
+	assert.ErrorContains(t, err, "use --prune-refs")
+	purgePruneRefs = true
+	require.NoError(t, runPurge(nil, nil))
+	got, err = Store.Get("kt-task")
+	require.NoError(t, err)
+	assert.Empty(t, got.Parent)
+	files, _ := filepath.Glob(filepath.Join(Store.Dir, "*.md"))
+	assert.Len(t, files, 1)
+}
+
+func TestPurgePruneRefsConfirmation(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { purgePruneRefs = false }()
+	mkTicket(t, "kt-dep", "Closed", ticket.StatusClosed)
+	task := mkTicket(t, "kt-task", "Open", ticket.StatusOpen)
+	task.Deps = []string{"kt-dep"}
+	require.NoError(t, Store.Save(task))
+	purgePruneRefs = true
+
+	mockStdin(t, "n\n")
+	require.NoError(t, runPurge(nil, nil))
+	got, err := Store.Get("kt-task")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-dep"}, got.Deps, "nothing changes when cancelled")
+
+	mockStdin(t, "y\n")
+	require.NoError(t, runPurge(nil, nil))
+	got, err = Store.Get("kt-task")
+	require.NoError(t, err)
+	assert.Empty(t, got.Deps)
+}
+
 func TestPurgeJSONModeNoClosed(t *testing.T) {
 	defer setupTestEnv(t)()
 	jsonFlag = true
@@ -202,7 +313,7 @@ func TestPromptConfirmation(t *testing.T) {
 
 	for _, tc := range tests {
 		mockStdin(t, tc.input)
-		confirmed, err := promptConfirmation(tickets)
+		confirmed, err := promptConfirmation(tickets, nil)
 		require.NoError(t, err)
 		assert.Equal(t, tc.want, confirmed)
 	}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+// recurInterval is how often kt daemon reopens recurring tickets.
+const recurInterval = time.Hour
+
+var recurCmd = &cobra.Command{
+	Use:   "recur",
+	Short: "Reopen recurring tickets that are due again",
+	Long: `Reopen each closed ticket with a recurrence whose next occurrence has
+come, setting its due date to that occurrence and clearing tests_passed.
+
+Set a recurrence with kt set <id> recurrence=weekly (also daily, monthly,
+yearly, "every 2 weeks", or FREQ=MONTHLY;INTERVAL=3). The next occurrence
+counts from the ticket's due date, or from the day it was closed.
+
+kt daemon runs this every hour; without a daemon, run it from cron.`,
+	Args: cobra.NoArgs,
+	RunE: runRecur,
+}
+
+func init() {
+	rootCmd.AddCommand(recurCmd)
+}
+
+func runRecur(cmd *cobra.Command, args []string) error {
+	reopened, err := reopenRecurring(Store, time.Now())
+	if err != nil {
+		return err
+	}
+	if IsJSON() {
+		return PrintJSON(reopened)
+	}
+	for _, t := range reopened {
+		fmt.Printf("%s reopened (due %s, recurs %s)\n", t.ID, t.Due, t.Recurrence)
+	}
+	return nil
+}
+
+// reopenRecurring reopens the closed recurring tickets in s whose next
+// occurrence is on or before now.
+func reopenRecurring(s *store.Store, now time.Time) ([]*ticket.Ticket, error) {
+	tickets, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	reopened := []*ticket.Ticket{}
+	for _, t := range tickets {
+		next, ok := t.NextOccurrence()
+		if !ok || next.After(now) {
+			continue
+		}
+		err := s.Update(t.ID, func(t *ticket.Ticket) error {
+			t.SetStatus(ticket.StatusOpen)
+			t.TestsPassed = false
+			t.Resolution = ""
+			t.Due = next.Format(ticket.DueLayout)
+			appendNote(t, fmt.Sprintf("Reopened for %s (recurs %s)", t.Due, t.Recurrence))
+			reopened = append(reopened, t)
+			return nil
+		})
+		if err != nil {
+			return reopened, err
+		}
+	}
+	return reopened, nil
+}
+
+// recurLoop reopens due recurring tickets in dir now and every
+// recurInterval until ctx is done, as its own operation each time.
+func recurLoop(ctx context.Context, dir string) {
+	tick := time.NewTicker(recurInterval)
+	defer tick.Stop()
+	for {
+		s := store.New(dir)
+		s.SetOperation("recur")
+		if reopened, err := reopenRecurring(s, time.Now()); err != nil {
+			Warnf("recur: %v", err)
+		} else {
+			for _, t := range reopened {
+				fmt.Fprintf(os.Stderr, "Reopened %s (due %s)\n", t.ID, t.Due)
+			}
+		}
+		advanceDependents(s)
+		autoCommit(s)
+		notifyChanges(s)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReopenRecurring(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	due := mkTicket(t, "kt-due", "Rotate keys", ticket.StatusClosed)
+	due.Recurrence = "weekly"
+	due.Due = "2026-03-02"
+	due.Closed = "2026-03-03T09:00:00Z"
+	due.TestsPassed = true
+	require.NoError(t, Store.Save(due))
+
+	later := mkTicket(t, "kt-later", "Renew cert", ticket.StatusClosed)
+	later.Recurrence = "yearly"
+	later.Closed = "2026-03-01T09:00:00Z"
+	require.NoError(t, Store.Save(later))
+
+	mkTicket(t, "kt-once", "One-off", ticket.StatusClosed)
+
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	reopened, err := reopenRecurring(Store, now)
+	require.NoError(t, err)
+	require.Len(t, reopened, 1)
+	assert.Equal(t, "kt-due", reopened[0].ID)
+
+	got, err := Store.Get("kt-due")
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusOpen, got.Status)
+	assert.Equal(t, "2026-03-09", got.Due)
+	assert.False(t, got.TestsPassed)
+	assert.Empty(t, got.Closed)
+	assert.Contains(t, got.Notes, "Reopened for 2026-03-09 (recurs weekly)")
+
+	// Nothing more is due until it is closed again.
+	reopened, err = reopenRecurring(Store, now)
+	require.NoError(t, err)
+	assert.Empty(t, reopened)
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/notify"
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var remindCmd = &cobra.Command{
+	Use:   "remind",
+	Short: "Digest of overdue, due-soon, and stale tickets by assignee",
+	Long: `Print a digest of open tickets that are overdue or due within --days, and
+in-progress tickets with no activity (session history, notes, linked or
+committed changes) for --stale, grouped by assignee. With --notify, also post it to a Slack or Discord webhook.
+
+Prints nothing and posts nothing when there is nothing to remind about, so
+it suits cron:
+
+  0 9 * * 1-5  cd ~/src/web && kt remind --notify slack:https://hooks.slack.com/...`,
+	Args: cobra.NoArgs,
+	RunE: runRemind,
+}
+
+var (
+	remindDays   int
+	remindStale  string
+	remindNotify []string
+)
+
+func init() {
+	remindCmd.Flags().IntVar(&remindDays, "days", 3, "Include tickets due within this many days")
+	remindCmd.Flags().StringVar(&remindStale, "stale", "7d", "Include in-progress tickets idle this long (e.g. 3d, 2w; 0 to skip)")
+	remindCmd.Flags().StringArrayVar(&remindNotify, "notify", nil, "Also post the digest to a webhook, slack:URL or discord:URL (repeatable)")
+	rootCmd.AddCommand(remindCmd)
+}
+
+// assigneeReminders are one assignee's tickets in a reminder digest.
+type assigneeReminders struct {
+	Assignee string           `json:"assignee,omitempty"`
+	Overdue  []*ticket.Ticket `json:"overdue"`
+	DueSoon  []*ticket.Ticket `json:"due_soon"`
+	Stale    []staleTicket    `json:"stale"`
+}
+
+// staleTicket is an in-progress ticket idle for IdleDays.
+type staleTicket struct {
+	*ticket.Ticket
+	IdleDays int `json:"idle_days"`
+}
+
+type remindDigest struct {
+	Date      string               `json:"date"`
+	Assignees []*assigneeReminders `json:"assignees"`
+}
+
+func runRemind(cmd *cobra.Command, args []string) error {
+	if remindDays < 0 {
+		return fmt.Errorf("invalid --days %d", remindDays)
+	}
+	stale, err := parseSince(remindStale)
+	if err != nil {
+		return fmt.Errorf("invalid --stale %q (expected e.g. 3d, 2w, or 0)", remindStale)
+	}
+	var rules []notify.Rule
+	for _, target := range remindNotify {
+		r, err := notify.ParseTarget(target)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, r)
+	}
+
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+	d := buildRemind(tickets, time.Now(), remindDays, stale, func(t *ticket.Ticket) time.Time {
+		return lastActivity(Store, t)
+	})
+
+	if len(d.Assignees) > 0 && len(rules) > 0 {
+		if err := notify.New(rules).Post(d.Text()); err != nil {
+			return err
+		}
+	}
+	if IsJSON() {
+		return PrintJSON(d)
+	}
+	fmt.Print(d.Text())
+	return nil
+}
+
+// buildRemind collects the tickets overdue or due within days of now, and
+// the in-progress ones whose last activity is older than stale (if stale is
+// not zero), grouped by assignee with the unassigned last.
+func buildRemind(tickets []*ticket.Ticket, now time.Time, days int, stale time.Duration, active func(*ticket.Ticket) time.Time) *remindDigest {
+	today, _ := time.Parse(ticket.DueLayout, now.Format(ticket.DueLayout))
+	byAssignee := map[string]*assigneeReminders{}
+	group := func(t *ticket.Ticket) *assigneeReminders {
+		a := byAssignee[t.Assignee]
+		if a == nil {
+			a = &assigneeReminders{Assignee: t.Assignee, Overdue: []*ticket.Ticket{}, DueSoon: []*ticket.Ticket{},
+				Stale: []staleTicket{}}
+			byAssignee[t.Assignee] = a
+		}
+		return a
+	}
+
+	for _, t := range tickets {
+		if t.Status == ticket.StatusClosed {
+			continue
+		}
+		if due, err := time.Parse(ticket.DueLayout, t.Due); err == nil {
+			if due.Before(today) {
+				group(t).Overdue = append(group(t).Overdue, t)
+			} else if due.Sub(today) <= time.Duration(days)*24*time.Hour {
+				group(t).DueSoon = append(group(t).DueSoon, t)
+			}
+		}
+		if t.Status == ticket.StatusInProgress && stale > 0 {
+			if idle := now.Sub(active(t)); idle >= stale {
+				group(t).Stale = append(group(t).Stale, staleTicket{t, int(idle.Hours() / 24)})
+			}
+		}
+	}
+
+	d := &remindDigest{Date: now.Format(ticket.DueLayout), Assignees: []*assigneeReminders{}}
+	for _, a := range byAssignee {
+		byDue := func(x, y *ticket.Ticket) int { return cmp.Or(cmp.Compare(x.Due, y.Due), cmp.Compare(x.ID, y.ID)) }
+		slices.SortFunc(a.Overdue, byDue)
+		slices.SortFunc(a.DueSoon, byDue)
+		slices.SortFunc(a.Stale, func(x, y staleTicket) int {
+			return cmp.Or(cmp.Compare(y.IdleDays, x.IdleDays), cmp.Compare(x.ID, y.ID))
+		})
+		d.Assignees = append(d.Assignees, a)
+	}
+	slices.SortFunc(d.Assignees, func(x, y *assigneeReminders) int {
+		if (x.Assignee == "") != (y.Assignee == "") {
+			return cmp.Compare(y.Assignee, x.Assignee) // unassigned last
+		}
+		return cmp.Compare(x.Assignee, y.Assignee)
+	})
+	return d
+}
+
+// Text renders the digest for the terminal and chat, or "" if it is empty.
+func (d *remindDigest) Text() string {
+	if len(d.Assignees) == 0 {
+		return ""
+	}
+	today, _ := time.Parse(ticket.DueLayout, d.Date)
+	days := func(t *ticket.Ticket) int {
+		due, _ := time.Parse(ticket.DueLayout, t.Due)
+		return int(due.Sub(today).Hours() / 24)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Reminders for %s\n", d.Date)
+	for _, a := range d.Assignees {
+		fmt.Fprintf(&b, "\n%s\n", cmp.Or(a.Assignee, "(unassigned)"))
+		for _, t := range a.Overdue {
+			fmt.Fprintf(&b, "  overdue   %s %s (due %s, %dd ago)\n", t.ID, t.Title, t.Due, -days(t))
+		}
+		for _, t := range a.DueSoon {
+			when := "today"
+			if n := days(t); n > 0 {
+				when = fmt.Sprintf("in %dd", n)
+			}
+			fmt.Fprintf(&b, "  due soon  %s %s (due %s, %s)\n", t.ID, t.Title, t.Due, when)
+		}
+		for _, t := range a.Stale {
+			fmt.Fprintf(&b, "  stale     %s %s (in progress, idle %dd)\n", t.ID, t.Title, t.IdleDays)
+		}
+	}
+	return b.String()
+}
+
+// noteStamp matches the timestamp heading of a note added by kt add-note.
+var noteStamp = regexp.MustCompile(`(?m)^\*\*(\d{4}-\d\d-\d\dT[^*]+)\*\*$`)
+
+// lastActivity returns when t last saw work: the latest of its creation,
+// history events, notes, linked commits, and the last commit to its file.
+// The file's modification time does not count, as a checkout or merge
+// changes it without anyone working on the ticket.
+func lastActivity(s *store.Store, t *ticket.Ticket) time.Time {
+	stamps := []string{t.Created}
+	for _, e := range t.History {
+		stamps = append(stamps, e.At)
+	}
+	for _, m := range noteStamp.FindAllStringSubmatch(t.Notes, -1) {
+		stamps = append(stamps, m[1])
+	}
+	for _, sha := range t.Commits {
+		if info, err := git.Describe(sha); err == nil {
+			stamps = append(stamps, info.Date)
+		}
+	}
+	if info, ok := git.LastChange(s.Path(t.ID)); ok {
+		stamps = append(stamps, info.Date)
+	}
+	var last time.Time
+	for _, stamp := range stamps {
+		if at, err := time.Parse(time.RFC3339, stamp); err == nil && at.After(last) {
+			last = at
+		}
+	}
+	return last
+}
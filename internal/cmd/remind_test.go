@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRemind(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	tickets := []*ticket.Ticket{
+		{ID: "kt-late", Title: "Late", Status: ticket.StatusOpen, Assignee: "ann", Due: "2026-03-07"},
+		{ID: "kt-today", Title: "Today", Status: ticket.StatusOpen, Assignee: "ann", Due: "2026-03-10"},
+		{ID: "kt-soon", Title: "Soon", Status: ticket.StatusOpen, Due: "2026-03-13"},
+		{ID: "kt-later", Title: "Later", Status: ticket.StatusOpen, Assignee: "ann", Due: "2026-03-14"},
+		{ID: "kt-done", Title: "Done", Status: ticket.StatusClosed, Assignee: "ann", Due: "2026-03-01"},
+		{ID: "kt-idle", Title: "Idle", Status: ticket.StatusInProgress, Assignee: "bob", Created: "2026-02-28T09:00:00Z"},
+		{ID: "kt-busy", Title: "Busy", Status: ticket.StatusInProgress, Assignee: "bob", Created: "2026-03-09T09:00:00Z"},
+	}
+	created := func(t *ticket.Ticket) time.Time {
+		at, _ := time.Parse(time.RFC3339, t.Created)
+		return at
+	}
+	d := buildRemind(tickets, now, 3, 7*24*time.Hour, created)
+
+	require.Len(t, d.Assignees, 3)
+	ann, bob, none := d.Assignees[0], d.Assignees[1], d.Assignees[2]
+	assert.Equal(t, "ann", ann.Assignee)
+	assert.Equal(t, "kt-late", ann.Overdue[0].ID)
+	require.Len(t, ann.DueSoon, 1)
+	assert.Equal(t, "kt-today", ann.DueSoon[0].ID)
+	require.Len(t, bob.Stale, 1)
+	assert.Equal(t, staleTicket{tickets[5], 10}, bob.Stale[0])
+	assert.Equal(t, "", none.Assignee, "unassigned last")
+	assert.Equal(t, "kt-soon", none.DueSoon[0].ID)
+
+	assert.Equal(t, `Reminders for 2026-03-10
+
+ann
+  overdue   kt-late Late (due 2026-03-07, 3d ago)
+  due soon  kt-today Today (due 2026-03-10, today)
+
+bob
+  stale     kt-idle Idle (in progress, idle 10d)
+
+(unassigned)
+  due soon  kt-soon Soon (due 2026-03-13, in 3d)
+`, d.Text())
+
+	assert.Empty(t, buildRemind(tickets, now, 3, 0, created).Assignees[1].Stale, "--stale 0 skips stale work")
+	assert.Empty(t, buildRemind(tickets[4:5], now, 3, 0, created).Text())
+}
+
+func TestLastActivity(t *testing.T) {
+	defer setupTestEnv(t)()
+	dir := initGitRepo(t)
+	Store = store.New(filepath.Join(dir, ".ktickets"))
+	require.NoError(t, Store.EnsureDir())
+	tk := mkTicket(t, "kt-a", "Work", ticket.StatusInProgress)
+	assert.Equal(t, "2026-01-09T10:00:00Z", lastActivity(Store, tk).Format(time.RFC3339), "created, not the file's mtime")
+
+	tk.History = []ticket.Event{{At: "2026-01-12T08:00:00Z", Session: "s1"}}
+	tk.Notes = "**2026-01-15T10:30:00Z**\n\nStill going\n\n**2026-01-14T10:30:00Z**\n\nEarlier"
+	assert.Equal(t, "2026-01-15T10:30:00Z", lastActivity(Store, tk).Format(time.RFC3339), "latest note")
+
+	t.Setenv("GIT_AUTHOR_DATE", "2026-01-20T09:00:00Z")
+	tk.Commits = []string{gitCommit(t, "Work on it")}
+	assert.Equal(t, "2026-01-20T09:00:00Z", lastActivity(Store, tk).Format(time.RFC3339), "linked commit")
+
+	t.Setenv("GIT_AUTHOR_DATE", "2026-01-22T09:00:00Z")
+	_, err := git.CommitPaths(dir, "edit kt-a", []string{Store.Path(tk.ID)})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01-22T09:00:00Z", lastActivity(Store, tk).Format(time.RFC3339), "committed edit")
+}
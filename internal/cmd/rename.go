@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <old-id> <new-id>",
+	Short: "Change a ticket's ID and update all references",
+	Long: `Change a ticket's ID. The file is moved and every other ticket's
+deps/links/parent references are rewritten under a store-wide lock.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRename,
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
+
+// validID restricts IDs to characters that are safe in file names.
+var validID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// reservedID reports whether id would collide with kt's own files: the
+// store-wide lock, or a ticket lock or ID reservation (<id>.lock, <id>.new).
+func reservedID(id string) bool {
+	id = strings.ToLower(id) // lock files may be on a case-insensitive filesystem
+	return id == "store" || strings.HasSuffix(id, ".lock") || strings.HasSuffix(id, ".new")
+}
+
+type renameResult struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Updated []string `json:"updated,omitempty"`
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	newID := args[1]
+	if !validID.MatchString(newID) {
+		return fmt.Errorf("invalid ID %q (use letters, digits, '.', '_', '-')", newID)
+	}
+	if reservedID(newID) {
+		return fmt.Errorf("invalid ID %q (reserved for kt's lock files)", newID)
+	}
+
+	var result renameResult
+	err := Store.Transaction(func(tx *store.Tx) error {
+		t, err := tx.Resolve(args[0])
+		if err != nil {
+			return err
+		}
+		if t.ID == newID {
+			return fmt.Errorf("%s already has that ID", t.ID)
+		}
+		if _, err := tx.Get(newID); err == nil {
+			return fmt.Errorf("ticket %s already exists", newID)
+		}
+		if isFile(Store.ArchivePath(newID)) {
+			return fmt.Errorf("ticket %s already exists in the archive", newID)
+		}
+
+		oldID := t.ID
+		tx.Delete(oldID)
+		t.ID = newID
+		tx.Save(t)
+
+		result = renameResult{From: oldID, To: newID}
+		result.Updated = tx.RewriteRefs(oldID, newID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+
+	fmt.Printf("%s → %s\n", result.From, result.To)
+	if len(result.Updated) > 0 {
+		fmt.Printf("Updated references in: %v\n", result.Updated)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunRename(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	old := mkTicket(t, "kt-old", "Old", ticket.StatusOpen)
+	child := mkTicket(t, "kt-child", "Child", ticket.StatusOpen)
+	child.Parent = old.ID
+	child.Deps = []string{old.ID}
+	child.Links = []string{old.ID}
+	require.NoError(t, Store.Save(child))
+
+	require.NoError(t, runRename(nil, []string{"old", "kt-new"}))
+
+	_, err := Store.Get(old.ID)
+	assert.Error(t, err)
+
+	renamed, err := Store.Get("kt-new")
+	require.NoError(t, err)
+	assert.Equal(t, "Old", renamed.Title)
+
+	uc, _ := Store.Get(child.ID)
+	assert.Equal(t, "kt-new", uc.Parent)
+	assert.Equal(t, []string{"kt-new"}, uc.Deps)
+	assert.Equal(t, []string{"kt-new"}, uc.Links)
+}
+
+func TestRunRenameErrors(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+
+	err := runRename(nil, []string{a.ID, b.ID})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	err = runRename(nil, []string{a.ID, "../evil"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid ID")
+
+	for _, id := range []string{"store", "Store", "kt-x.lock", "kt-x.new"} {
+		err = runRename(nil, []string{a.ID, id})
+		require.Error(t, err, id)
+		assert.Contains(t, err.Error(), "reserved", id)
+	}
+
+	old := mkTicket(t, "kt-old", "Old", ticket.StatusClosed)
+	require.NoError(t, Store.Archive(old.ID))
+	err = runRename(nil, []string{a.ID, old.ID})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists in the archive")
+
+	err = runRename(nil, []string{"kt-missing", "kt-x"})
+	require.Error(t, err)
+
+	_, err = Store.Get(a.ID)
+	assert.NoError(t, err)
+}
+
+func TestRunRenameJSON(t *testing.T) {
+	defer setupTestEnv(t)()
+	jsonFlag = true
+	defer func() { jsonFlag = false }()
+
+	mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	require.NoError(t, runRename(nil, []string{"kt-a", "kt-z"}))
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Render a markdown status report",
+	Long: `Render a markdown summary of the tracker: open tickets by priority,
+in-progress work with assignees, tickets closed within --since, and
+blocked tickets with their blockers. Suitable for pasting into a PR or
+status update.`,
+	RunE: runReport,
+}
+
+var reportSince string
+
+func init() {
+	reportCmd.Flags().StringVar(&reportSince, "since", "7d", "Window for recently closed tickets (e.g. 24h, 7d, 2w)")
+	rootCmd.AddCommand(reportCmd)
+}
+
+type blockedItem struct {
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Blockers []string `json:"blockers"`
+}
+
+type statusReport struct {
+	Date       string                   `json:"date"`
+	Since      string                   `json:"since"`
+	Open       map[int][]*ticket.Ticket `json:"open"` // by priority
+	InProgress []*ticket.Ticket         `json:"in_progress"`
+	Closed     []*ticket.Ticket         `json:"closed"`
+	Blocked    []blockedItem            `json:"blocked"`
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	window, err := parseSince(reportSince)
+	if err != nil {
+		return err
+	}
+
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+
+	r := buildReport(tickets, time.Now().UTC(), window)
+	r.Since = reportSince
+
+	if IsJSON() {
+		return PrintJSON(r)
+	}
+
+	fmt.Print(r.Markdown())
+	return nil
+}
+
+// parseSince parses a duration, additionally accepting d (days) and w (weeks).
+func parseSince(s string) (time.Duration, error) {
+	d, err := config.ParseAge(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --since %q (expected e.g. 24h, 7d, 2w)", s)
+	}
+	return d, nil
+}
+
+// buildReport groups tickets for the report. Closed tickets count as recent
+// if their closed timestamp falls within window before now.
+func buildReport(tickets []*ticket.Ticket, now time.Time, window time.Duration) *statusReport {
+	byID := make(map[string]*ticket.Ticket, len(tickets))
+	for _, t := range tickets {
+		byID[t.ID] = t
+	}
+
+	r := &statusReport{
+		Date:       now.Format(ticket.DueLayout),
+		Open:       make(map[int][]*ticket.Ticket),
+		InProgress: []*ticket.Ticket{},
+		Closed:     []*ticket.Ticket{},
+		Blocked:    []blockedItem{},
+	}
+	cutoff := now.Add(-window)
+
+	for _, t := range tickets {
+		switch t.Status {
+		case ticket.StatusClosed:
+			if closed, err := time.Parse(time.RFC3339, t.Closed); err == nil && !closed.Before(cutoff) {
+				r.Closed = append(r.Closed, t)
+			}
+			continue
+		case ticket.StatusInProgress:
+			r.InProgress = append(r.InProgress, t)
+		default:
+			r.Open[t.Priority] = append(r.Open[t.Priority], t)
+		}
+
+		var blockers []string
+		for _, d := range t.Deps {
+			if dep, ok := byID[d]; !ok || dep.Status != ticket.StatusClosed {
+				blockers = append(blockers, d)
+			}
+		}
+		if len(blockers) > 0 {
+			r.Blocked = append(r.Blocked, blockedItem{ID: t.ID, Title: t.Title, Blockers: blockers})
+		}
+	}
+
+	sort.Slice(r.Closed, func(i, j int) bool { return r.Closed[i].Closed > r.Closed[j].Closed })
+	return r
+}
+
+// Markdown renders the report.
+func (r *statusReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Status report (%s)\n", r.Date)
+
+	b.WriteString("\n## Open by priority\n")
+	priorities := make([]int, 0, len(r.Open))
+	for p := range r.Open {
+		priorities = append(priorities, p)
+	}
+	sort.Ints(priorities)
+	if len(priorities) == 0 {
+		b.WriteString("\n_None_\n")
+	}
+	for _, p := range priorities {
+		fmt.Fprintf(&b, "\n### P%d\n\n", p)
+		for _, t := range r.Open[p] {
+			fmt.Fprintf(&b, "- `%s` %s\n", t.ID, t.Title)
+		}
+	}
+
+	b.WriteString("\n## In progress\n\n")
+	if len(r.InProgress) == 0 {
+		b.WriteString("_None_\n")
+	}
+	for _, t := range r.InProgress {
+		assignee := t.Assignee
+		if assignee == "" {
+			assignee = "unassigned"
+		}
+		fmt.Fprintf(&b, "- `%s` %s (%s)\n", t.ID, t.Title, assignee)
+	}
+
+	since := ""
+	if r.Since != "" {
+		since = " (last " + r.Since + ")"
+	}
+	fmt.Fprintf(&b, "\n## Recently closed%s\n\n", since)
+	if len(r.Closed) == 0 {
+		b.WriteString("_None_\n")
+	}
+	for _, t := range r.Closed {
+		fmt.Fprintf(&b, "- `%s` %s\n", t.ID, t.Title)
+	}
+
+	b.WriteString("\n## Blocked\n\n")
+	if len(r.Blocked) == 0 {
+		b.WriteString("_None_\n")
+	}
+	for _, item := range r.Blocked {
+		fmt.Fprintf(&b, "- `%s` %s — blocked by %s\n", item.ID, item.Title, strings.Join(item.Blockers, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSince(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"24h", 24 * time.Hour},
+		{"7d", 7 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.in)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := parseSince("soon")
+	assert.Error(t, err)
+	_, err = parseSince("-3d")
+	assert.Error(t, err)
+}
+
+func TestBuildReport(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tickets := []*ticket.Ticket{
+		{ID: "kt-p0", Title: "Urgent", Status: ticket.StatusOpen, Priority: 0},
+		{ID: "kt-p2", Title: "Later", Status: ticket.StatusOpen, Priority: 2, Deps: []string{"kt-wip"}},
+		{ID: "kt-wip", Title: "Doing", Status: ticket.StatusInProgress, Assignee: "alice"},
+		{ID: "kt-new", Title: "Just done", Status: ticket.StatusClosed, Closed: "2026-03-09T10:00:00Z"},
+		{ID: "kt-old", Title: "Long ago", Status: ticket.StatusClosed, Closed: "2026-01-01T10:00:00Z"},
+		{ID: "kt-legacy", Title: "No timestamp", Status: ticket.StatusClosed},
+	}
+
+	r := buildReport(tickets, now, 7*24*time.Hour)
+	assert.Equal(t, "2026-03-10", r.Date)
+	assert.Len(t, r.Open[0], 1)
+	assert.Len(t, r.Open[2], 1)
+	require.Len(t, r.InProgress, 1)
+	require.Len(t, r.Closed, 1)
+	assert.Equal(t, "kt-new", r.Closed[0].ID)
+	require.Len(t, r.Blocked, 1)
+	assert.Equal(t, []string{"kt-wip"}, r.Blocked[0].Blockers)
+
+	md := r.Markdown()
+	assert.Contains(t, md, "### P0\n\n- `kt-p0` Urgent")
+	assert.Contains(t, md, "- `kt-wip` Doing (alice)")
+	assert.Contains(t, md, "- `kt-p2` Later — blocked by kt-wip")
+	assert.NotContains(t, md, "kt-old")
+}
+
+func TestRunReport(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { reportSince = "7d" }()
+
+	tk := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	require.NoError(t, runClose(nil, []string{tk.ID}))
+	closed, err := Store.Get(tk.ID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, closed.Closed)
+
+	require.NoError(t, runReport(nil, nil))
+
+	reportSince = "bogus"
+	require.Error(t, runReport(nil, nil))
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var rmCmd = &cobra.Command{
+	Use:   "rm <id>...",
+	Short: "Delete tickets",
+	Long: `Delete tickets. Refuses if an open ticket has one as parent, depends on
+it, or links to it, unless --cascade removes those references too; closed
+tickets' references are always removed, as kt purge does. kt undo brings
+the tickets (and references) back.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRm,
+}
+
+var rmCascade bool
+
+func init() {
+	rmCmd.Flags().BoolVar(&rmCascade, "cascade", false, "Also remove references to the tickets from other tickets")
+	rootCmd.AddCommand(rmCmd)
+}
+
+type rmResult struct {
+	Deleted []string `json:"deleted"`
+	Cleaned []string `json:"cleaned,omitempty"` // tickets whose references were removed
+}
+
+func runRm(cmd *cobra.Command, args []string) error {
+	ids := make([]string, len(args))
+	for i, arg := range args {
+		t, err := Store.Resolve(arg)
+		if err != nil {
+			return err
+		}
+		ids[i] = t.ID
+	}
+
+	result := rmResult{Deleted: ids}
+	var err error
+	if rmCascade {
+		result.Cleaned, err = Store.DeleteCascade(ids...)
+	} else {
+		err = Store.Delete(ids...)
+	}
+	if errors.Is(err, store.ErrReferenced) {
+		return fmt.Errorf("%w (use --cascade to remove the references)", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	for _, id := range result.Deleted {
+		fmt.Printf("Deleted %s\n", id)
+	}
+	if len(result.Cleaned) > 0 {
+		fmt.Printf("Removed references from: %s\n", strings.Join(result.Cleaned, ", "))
+	}
+	return nil
+}
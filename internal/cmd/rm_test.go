@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunRm(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { rmCascade = false }()
+	mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	b.Links = []string{"kt-a"}
+	require.NoError(t, Store.Save(b))
+
+	err := runRm(rmCmd, []string{"kt-a"})
+	assert.EqualError(t, err, "cannot delete kt-a: ticket kt-b links to it (use --cascade to remove the references)")
+
+	rmCascade = true
+	require.NoError(t, runRm(rmCmd, []string{"kt-a"}))
+	_, err = Store.Get("kt-a")
+	assert.Error(t, err)
+	got, err := Store.Get("kt-b")
+	require.NoError(t, err)
+	assert.Empty(t, got.Links)
+}
@@ -2,17 +2,28 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strings"
+	"text/template"
 
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/filelock"
 	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
 
 var (
-	jsonFlag bool
-	Store    *store.Store
+	jsonFlag    bool
+	formatFlag  string
+	sessionFlag string
+	strictParse bool
+	Store       *store.Store
 )
 
 // OutputMode returns "json", "plain", or "text" based on flags and TTY detection.
@@ -20,7 +31,7 @@ func OutputMode() string {
 	if jsonFlag {
 		return "json"
 	}
-	if !term.IsTerminal(int(os.Stdout.Fd())) {
+	if !paging && !term.IsTerminal(int(os.Stdout.Fd())) {
 		return "plain" // Piped → plain text
 	}
 	return "text"
@@ -36,6 +47,31 @@ func IsPlain() bool {
 	return OutputMode() == "plain"
 }
 
+// IsTemplate returns true if tickets should be rendered with the --format
+// template. --json takes precedence.
+func IsTemplate() bool {
+	return formatFlag != "" && !IsJSON()
+}
+
+// PrintTemplate renders each ticket with the --format Go template, one per
+// line. Literal \t and \n in the template are unescaped for shell use.
+func PrintTemplate(tickets []*ticket.Ticket) error {
+	format := strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(formatFlag)
+	if !strings.HasSuffix(format, "\n") {
+		format += "\n"
+	}
+	tmpl, err := template.New("format").Funcs(template.FuncMap{"join": strings.Join}).Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid --format template: %w", err)
+	}
+	for _, t := range tickets {
+		if err := tmpl.Execute(os.Stdout, t); err != nil {
+			return fmt.Errorf("render --format template: %w", err)
+		}
+	}
+	return nil
+}
+
 // PrintJSON marshals v to JSON and prints it.
 func PrintJSON(v any) error {
 	enc := json.NewEncoder(os.Stdout)
@@ -48,22 +84,119 @@ func Errorf(format string, args ...any) {
 	fmt.Fprintf(os.Stderr, "error: "+format+"\n", args...)
 }
 
+// Warnf prints a warning to stderr.
+func Warnf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "warning: "+format+"\n", args...)
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "kt",
 	Short: "Git-backed issue tracker",
 	Long:  `kt stores tickets as markdown files with YAML frontmatter in .ktickets/`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		Store = store.New("")
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyFlagEnv(cmd); err != nil {
+			return err
+		}
+		dir, err := storeDir()
+		if err != nil {
+			return err
+		}
+		if storeFlag != "" || globalFlag {
+			Config = nil // load the chosen store's config instead
+		}
+		Store = store.New(dir)
+		Store.SetOperation(strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), "kt ") + " " + strings.Join(args, " ")))
+		Store.SetSession(sessionFlag)
+		cfg := projectConfig()
+		if globalFlag && cfg.IDPrefix == "" {
+			cfg.IDPrefix = "g" // not the prefix of whatever directory kt runs in
+		}
+		if cfg.Output == "json" && !cmd.Flags().Changed("json") {
+			jsonFlag = true
+		}
+		if cfg.NoColor && !cmd.Flags().Changed("no-color") {
+			noColorFlag = true
+		}
+		ticket.Strict = strictParse || (cfg.StrictParse && !cmd.Flags().Changed("strict-parse"))
+		ticket.SchemaMismatch = warnSchema
+		return nil
 	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		for _, id := range advanceDependents(Store) {
+			if !IsJSON() {
+				fmt.Fprintf(os.Stderr, "%s → %s (deps closed)\n", id, ticket.StatusOpen)
+			}
+		}
+		autoCommit(Store)
+		notifyChanges(Store)
+	},
+}
+
+// flagEnv maps global flags to the environment variables that set them.
+var flagEnv = map[string]string{
+	"json":         config.EnvJSON,
+	"no-color":     config.EnvNoColor,
+	"format":       config.EnvFormat,
+	"store":        config.EnvStore,
+	"global":       config.EnvGlobal,
+	"lock-timeout": config.EnvLockTimeout,
+	"session":      config.EnvSession,
+	"strict-parse": config.EnvStrictParse,
 }
 
+// applyFlagEnv sets each global flag not given on the command line from
+// its environment variable, if that is set. A command's own flag of the
+// same name hides the global one from the variable too.
+func applyFlagEnv(cmd *cobra.Command) error {
+	for _, name := range slices.Sorted(maps.Keys(flagEnv)) {
+		env := flagEnv[name]
+		v := os.Getenv(env)
+		if v == "" || cmd.Flags().Changed(name) {
+			continue
+		}
+		if cmd.LocalNonPersistentFlags().Lookup(name) != nil {
+			continue // a flag of the command's own, like kt dep graph --format
+		}
+		if err := cmd.Flags().Set(name, v); err != nil {
+			return fmt.Errorf("invalid %s: %w", env, err)
+		}
+	}
+	return nil
+}
+
+// exitError is an error that makes kt exit with a status other than 1, so
+// scripts can tell it apart from other failures.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
 // Execute runs the root command.
 func Execute() {
+	registerAliases(projectConfig().Aliases)
 	if err := rootCmd.Execute(); err != nil {
+		var exit *exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
 		os.Exit(1)
 	}
 }
 
 func init() {
-	rootCmd.PersistentFlags().BoolVar(&jsonFlag, "json", false, "Output JSON format")
+	rootCmd.PersistentFlags().BoolVar(&jsonFlag, "json", false, "Output JSON format (also KTICKET_JSON)")
+	rootCmd.PersistentFlags().BoolVar(&noPagerFlag, "no-pager", false, "Do not page long output (also KTICKET_NO_PAGER)")
+	rootCmd.PersistentFlags().BoolVar(&noColorFlag, "no-color", false, "Disable colored output (also NO_COLOR, KTICKET_NO_COLOR)")
+	rootCmd.PersistentFlags().BoolVar(&autoCommitFlag, "commit", false, "Commit changed ticket files to git (also KTICKET_AUTO_COMMIT)")
+	rootCmd.PersistentFlags().StringVar(&storeFlag, "store", "", "Use this store: a name from stores in config.yml, a repo, or a tickets directory (also KTICKET_STORE)")
+	rootCmd.PersistentFlags().DurationVar(&filelock.Timeout, "lock-timeout", filelock.DefaultTimeout, "How long to wait for a locked ticket or store (also KTICKET_LOCK_TIMEOUT)")
+	rootCmd.PersistentFlags().BoolVarP(&globalFlag, "global", "g", false, "Use the personal store in ~/.ktickets (also kt g ..., KTICKET_GLOBAL)")
+	rootCmd.PersistentFlags().StringVar(&sessionFlag, "session", "", "Agent session making changes, recorded in ticket history (also KTICKET_SESSION)")
+	rootCmd.PersistentFlags().BoolVar(&strictParse, "strict-parse", false,
+		"Refuse ticket files with unknown keys, statuses, or types, or bad timestamps (also KTICKET_STRICT_PARSE)")
+	rootCmd.PersistentFlags().StringVar(&formatFlag, "format", "",
+		"Go template for ls/show/ready/blocked/closed, e.g. '{{.ID}}\\t{{.Title}}' (also KTICKET_FORMAT)")
 }
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run <id>",
+	Short: "Hand a ticket to a coding agent, verify the result, and close it",
+	Long: `Claim the ticket and mark it in_progress, then run the agent command with
+a prompt made from the ticket on stdin (also in the file named by
+$KT_PROMPT_FILE, with KT_ID and KT_TITLE set). If the agent succeeds and
+the verify command passes, the ticket's tests are marked passed and it is
+closed. On failure the ticket stays in progress with a note saying what
+failed.
+
+The commands come from --agent and --verify, else agent.command and
+agent.verify in config.yml, which run only once trusted with kt config
+trust. The agent defaults to "` + config.DefaultAgentCommand + `"; without
+a verify command, the agent's success is enough.
+
+The agent runs with KTICKET_SESSION set to --session (default run-<id>), so
+the changes it makes with kt are recorded against the same session.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRun,
+	// A failing agent is not a usage error
+	SilenceUsage: true,
+}
+
+var (
+	runAgent  string
+	runVerify string
+)
+
+func init() {
+	runCmd.Flags().StringVar(&runAgent, "agent", "", "Agent shell command, given the prompt on stdin (default: agent.command in config.yml, else "+config.DefaultAgentCommand+")")
+	runCmd.Flags().StringVar(&runVerify, "verify", "", "Shell command that must pass before closing (default: agent.verify in config.yml)")
+	rootCmd.AddCommand(runCmd)
+}
+
+// runResult is the outcome of kt run.
+type runResult struct {
+	ID       string `json:"id"`
+	Session  string `json:"session"`
+	Verified bool   `json:"verified"` // a verify command ran and passed
+	Closed   bool   `json:"closed"`
+	Error    string `json:"error,omitempty"`
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	t, err := Store.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+	session := cmp.Or(sessionFlag, "run-"+t.ID)
+	switch {
+	case t.Status == ticket.StatusClosed:
+		return fmt.Errorf("%s is already closed", t.ID)
+	case t.ClaimedBy != "" && t.ClaimedBy != session:
+		return fmt.Errorf("%s is claimed by %s", t.ID, t.ClaimedBy)
+	}
+	if open := unresolvedDeps(t); len(open) > 0 {
+		return fmt.Errorf("%s has open deps: %s", t.ID, strings.Join(open, ", "))
+	}
+
+	if err := checkAgentTrusted(); err != nil {
+		return err
+	}
+
+	Store.SetSession(session)
+	if err := Store.Update(t.ID, func(t *ticket.Ticket) error {
+		if t.Status != ticket.StatusInProgress {
+			t.SetStatus(ticket.StatusInProgress)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if t, err = Store.Get(t.ID); err != nil {
+		return err
+	}
+
+	cfg := projectConfig().Agent
+	agent := cmp.Or(runAgent, cfg.Command, config.DefaultAgentCommand)
+	verify := cmp.Or(runVerify, cfg.Verify)
+	result := runResult{ID: t.ID, Session: session}
+
+	// Agent output goes to stderr in JSON mode, to keep stdout parseable
+	var out io.Writer = os.Stdout
+	if IsJSON() {
+		out = os.Stderr
+	}
+	if !IsJSON() {
+		fmt.Printf("%s → in_progress (session %s), running: %s\n", t.ID, session, agent)
+	}
+	prompt := runPrompt(t)
+	if err := runShell(agent, prompt, t, session, out); err != nil {
+		return runFailed(result, fmt.Sprintf("agent %q failed: %v", agent, err))
+	}
+	if verify != "" {
+		if !IsJSON() {
+			fmt.Printf("Verifying: %s\n", verify)
+		}
+		if err := runShell(verify, "", t, session, out); err != nil {
+			return runFailed(result, fmt.Sprintf("verify %q failed: %v", verify, err))
+		}
+		result.Verified = true
+	}
+
+	err = Store.Update(t.ID, func(t *ticket.Ticket) error {
+		if result.Verified {
+			t.TestsPassed = true
+		}
+		if err := t.CanClose(); err != nil {
+			return err
+		}
+		t.SetStatus(ticket.StatusClosed)
+		return nil
+	})
+	if err != nil {
+		return runFailed(result, err.Error())
+	}
+	result.Closed = true
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	fmt.Printf("%s → closed\n", t.ID)
+	return nil
+}
+
+// checkAgentTrusted refuses to run agent commands from the project's
+// config.yml, which anyone who can commit can change, until kt config
+// trust. The --agent and --verify flags replace them.
+func checkAgentTrusted() error {
+	project, err := config.LoadProject(Store.Dir)
+	if err != nil {
+		return err
+	}
+	if (runAgent != "" || project.Agent.Command == "") && (runVerify != "" || project.Agent.Verify == "") {
+		return nil
+	}
+	if config.HooksTrusted(Store.Dir, project.Commands()) {
+		return nil
+	}
+	return fmt.Errorf("not running the agent commands in %s: run 'kt config trust' to allow them", filepath.Join(Store.Dir, config.ProjectFile))
+}
+
+// runFailed notes why kt run stopped on the ticket, which stays in
+// progress, and returns the reason as an error.
+func runFailed(result runResult, reason string) error {
+	if err := Store.Update(result.ID, func(t *ticket.Ticket) error {
+		appendNote(t, "kt run: "+reason)
+		return nil
+	}); err != nil {
+		Warnf("%v", err)
+	}
+	if IsJSON() {
+		result.Error = reason
+		_ = PrintJSON(result)
+	}
+	return fmt.Errorf("%s", reason)
+}
+
+// runPrompt is the prompt kt run gives the agent: what to do, and the
+// ticket itself.
+func runPrompt(t *ticket.Ticket) string {
+	body, _ := ticket.Marshal(t)
+	return fmt.Sprintf(`Implement kt ticket %[1]s. Record progress and decisions with `+"`kt add-note %[1]s \"...\"`"+`.
+Do not close the ticket: kt run verifies the work and closes it when you exit successfully.
+
+%[2]s`, t.ID, body)
+}
+
+// runShell runs command with sh, input on stdin, and the ticket and
+// session in the environment. A non-empty input is also written to a
+// temporary file named by KT_PROMPT_FILE, since a whole ticket can be
+// too long for one environment variable.
+func runShell(command, input string, t *ticket.Ticket, session string, out io.Writer) error {
+	c := exec.Command("sh", "-c", command)
+	c.Env = append(os.Environ(),
+		"KT_ID="+t.ID,
+		"KT_TITLE="+t.Title,
+		config.EnvSession+"="+session)
+	if input != "" {
+		f, err := os.CreateTemp("", "kt-prompt-*.md")
+		if err != nil {
+			return fmt.Errorf("write prompt: %w", err)
+		}
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(input)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("write prompt: %w", err)
+		}
+		c.Env = append(c.Env, "KT_PROMPT_FILE="+f.Name())
+	}
+	c.Stdin = bytes.NewReader([]byte(input))
+	c.Stdout, c.Stderr = out, os.Stderr
+	return c.Run()
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { runAgent, runVerify = "", "" }()
+	t.Chdir(t.TempDir())
+	tk := mkTicket(t, "kt-1", "Build it", ticket.StatusOpen)
+	tk.Tests = "go test"
+	require.NoError(t, Store.Save(tk))
+
+	runAgent = `cat > prompt.txt; cp "$KT_PROMPT_FILE" prompt-file.txt; echo "$KT_ID $KTICKET_SESSION" > env.txt`
+	runVerify = "false"
+	assert.ErrorContains(t, runRun(nil, []string{"kt-1"}), `verify "false" failed`)
+	got, err := Store.Get("kt-1")
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusInProgress, got.Status)
+	assert.Equal(t, "run-kt-1", got.ClaimedBy)
+	assert.Contains(t, got.Notes, `kt run: verify "false" failed`)
+	prompt, err := os.ReadFile("prompt.txt")
+	require.NoError(t, err)
+	assert.Contains(t, string(prompt), "Implement kt ticket kt-1")
+	assert.Contains(t, string(prompt), "# Build it")
+	promptFile, err := os.ReadFile("prompt-file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, string(prompt), string(promptFile))
+	env, err := os.ReadFile("env.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "kt-1 run-kt-1\n", string(env))
+
+	runVerify = "true"
+	require.NoError(t, runRun(nil, []string{"kt-1"}))
+	got, err = Store.Get("kt-1")
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusClosed, got.Status)
+	assert.True(t, got.TestsPassed)
+	assert.ErrorContains(t, runRun(nil, []string{"kt-1"}), "already closed")
+}
+
+func TestRunRefuses(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { runAgent = "" }()
+	runAgent = "false"
+
+	dep := mkTicket(t, "kt-dep", "Dep", ticket.StatusOpen)
+	tk := mkTicket(t, "kt-1", "Blocked", ticket.StatusOpen)
+	tk.Deps = []string{dep.ID}
+	require.NoError(t, Store.Save(tk))
+	assert.ErrorContains(t, runRun(nil, []string{"kt-1"}), "open deps: kt-dep")
+
+	dep.SetStatus(ticket.StatusInProgress)
+	dep.ClaimedBy = "agent-1"
+	require.NoError(t, Store.Save(dep))
+	assert.ErrorContains(t, runRun(nil, []string{"kt-dep"}), "claimed by agent-1")
+	dep.ClaimedBy = ""
+	require.NoError(t, Store.Save(dep))
+	assert.ErrorContains(t, runRun(nil, []string{"kt-dep"}), `agent "false" failed`)
+}
+
+func TestRunUntrustedAgent(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { runAgent, runVerify = "", "" }()
+	t.Setenv(config.EnvTrustHooks, "")
+	t.Chdir(t.TempDir())
+	writeProjectConfig(t, "agent:\n  command: touch agent-ran\n  verify: touch verify-ran\n")
+	mkTicket(t, "kt-1", "Build it", ticket.StatusOpen)
+
+	assert.ErrorContains(t, runRun(nil, []string{"kt-1"}), "run 'kt config trust'")
+	got, err := Store.Get("kt-1")
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusOpen, got.Status, "not claimed")
+	assert.NoFileExists(t, "agent-ran")
+
+	// The flags replace the project's commands, which then need no trust
+	runAgent = "true"
+	assert.ErrorContains(t, runRun(nil, []string{"kt-1"}), "run 'kt config trust'")
+	runVerify = "true"
+	require.NoError(t, runRun(nil, []string{"kt-1"}))
+
+	mkTicket(t, "kt-2", "Build more", ticket.StatusOpen)
+	runAgent, runVerify = "", ""
+	require.NoError(t, runConfigTrust(nil, nil))
+	require.NoError(t, runRun(nil, []string{"kt-2"}))
+	assert.FileExists(t, "agent-ran")
+	assert.FileExists(t, "verify-ran")
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var scanCommitsCmd = &cobra.Command{
+	Use:   "scan-commits",
+	Short: "Close tickets referenced by closes/fixes in commit messages",
+	Long: `Scan git history for "closes kt-a1b2", "fixes kt-a1b2", or
+"resolves kt-a1b2" (also as "Closes: kt-a1b2" trailers) and close the
+referenced tickets, noting the commit SHA on each. Commits that close or
+reference a ticket ("Refs: kt-a1b2") are added to its commits list.
+Already closed tickets and unknown IDs are skipped, so scanning is safe to
+repeat.
+
+  kt scan-commits --since origin/main
+  kt scan-commits --install-hook   # run after every git pull/merge`,
+	RunE: runScanCommits,
+}
+
+var (
+	scanSince       string
+	scanInstallHook bool
+)
+
+func init() {
+	scanCommitsCmd.Flags().StringVar(&scanSince, "since", "", "Only scan commits after this ref")
+	scanCommitsCmd.Flags().BoolVar(&scanInstallHook, "install-hook", false, "Install a post-merge hook that runs scan-commits")
+	rootCmd.AddCommand(scanCommitsCmd)
+}
+
+var (
+	closingRef = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+([a-z0-9]+-[a-z0-9]+)\b`)
+	refsRef    = regexp.MustCompile(`(?i)\brefs?:\s*([a-z0-9]+-[a-z0-9]+)\b`)
+)
+
+type commitClose struct {
+	ID     string `json:"id"`
+	Commit string `json:"commit"`
+}
+
+type scanResult struct {
+	Scanned int           `json:"scanned"`
+	Closed  []commitClose `json:"closed,omitempty"`
+	Linked  []commitClose `json:"linked,omitempty"`
+	Errors  []statusError `json:"errors,omitempty"`
+}
+
+func runScanCommits(cmd *cobra.Command, args []string) error {
+	if scanInstallHook {
+		return installHook("post-merge", postMergeHook)
+	}
+
+	commits, err := git.Log(scanSince)
+	if err != nil {
+		return err
+	}
+
+	result := scanResult{Scanned: len(commits)}
+	// Oldest first, so notes land in history order
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		for _, id := range closingRefs(c.Message) {
+			if _, err := Store.Get(id); err != nil {
+				continue // not one of ours
+			}
+			closed, err := closeByCommit(id, c)
+			if err != nil {
+				result.Errors = append(result.Errors, statusError{ID: id, Error: err.Error()})
+				continue
+			}
+			if closed {
+				result.Closed = append(result.Closed, commitClose{ID: id, Commit: c.SHA})
+			}
+		}
+		for _, id := range matchRefs(refsRef, c.Message) {
+			if _, err := Store.Get(id); err != nil {
+				continue
+			}
+			linked, err := linkCommit(id, c)
+			if err != nil {
+				result.Errors = append(result.Errors, statusError{ID: id, Error: err.Error()})
+				continue
+			}
+			if linked {
+				result.Linked = append(result.Linked, commitClose{ID: id, Commit: c.SHA})
+			}
+		}
+	}
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+
+	for _, c := range result.Closed {
+		fmt.Printf("%s closed by %s\n", c.ID, c.Commit[:min(7, len(c.Commit))])
+	}
+	for _, c := range result.Linked {
+		fmt.Printf("%s linked to %s\n", c.ID, c.Commit[:min(7, len(c.Commit))])
+	}
+	for _, e := range result.Errors {
+		Errorf("%s: %s", e.ID, e.Error)
+	}
+	if len(result.Closed) == 0 && len(result.Linked) == 0 && len(result.Errors) == 0 {
+		fmt.Printf("Scanned %d commits, nothing to close\n", result.Scanned)
+	}
+	return nil
+}
+
+// closingRefs returns the ticket IDs a commit message closes, lowercased
+// and deduplicated.
+func closingRefs(msg string) []string {
+	return matchRefs(closingRef, msg)
+}
+
+// matchRefs returns the ticket IDs captured by re in msg, lowercased and
+// deduplicated.
+func matchRefs(re *regexp.Regexp, msg string) []string {
+	var ids []string
+	for _, m := range re.FindAllStringSubmatch(msg, -1) {
+		id := strings.ToLower(m[1])
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// closeByCommit closes a ticket, notes the commit, and records it in the
+// ticket's commits. Returns false if the ticket was already closed.
+func closeByCommit(id string, c git.Commit) (bool, error) {
+	lt, err := Store.GetForUpdate(id)
+	if err != nil {
+		return false, err
+	}
+	added := lt.Ticket.AddCommit(c.SHA)
+	if lt.Ticket.Status == ticket.StatusClosed {
+		if added {
+			return false, lt.SaveAndRelease()
+		}
+		lt.Release()
+		return false, nil
+	}
+	if err := lt.Ticket.CanClose(); err != nil {
+		if added {
+			if serr := lt.SaveAndRelease(); serr != nil {
+				return false, serr
+			}
+		} else {
+			lt.Release()
+		}
+		return false, err
+	}
+
+	lt.Ticket.SetStatus(ticket.StatusClosed)
+	appendNote(lt.Ticket, fmt.Sprintf("Closed by commit %s: %s", c.Short(), c.Subject()))
+	if err := lt.SaveAndRelease(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// linkCommit records a commit in a ticket's commits. Returns false if it
+// was already recorded.
+func linkCommit(id string, c git.Commit) (bool, error) {
+	lt, err := Store.GetForUpdate(id)
+	if err != nil {
+		return false, err
+	}
+	if !lt.Ticket.AddCommit(c.SHA) {
+		lt.Release()
+		return false, nil
+	}
+	if err := lt.SaveAndRelease(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
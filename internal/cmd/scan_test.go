@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initGitRepo creates a git repository in a temp dir and changes into it.
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	_, err := git.Run("init", "-q", "-b", "main")
+	require.NoError(t, err)
+	return dir
+}
+
+func gitCommit(t *testing.T, msg string) string {
+	t.Helper()
+	_, err := git.Run("commit", "-q", "--allow-empty", "-m", msg)
+	require.NoError(t, err)
+	sha, err := git.Run("rev-parse", "HEAD")
+	require.NoError(t, err)
+	return sha
+}
+
+func TestClosingRefs(t *testing.T) {
+	msg := "Fix login\n\nCloses kt-a1b2, fixes KT-C3D4\nresolved: kt-e5f6\nCloses: kt-a1b2\nmentions kt-zzzz"
+	assert.Equal(t, []string{"kt-a1b2", "kt-c3d4", "kt-e5f6"}, closingRefs(msg))
+	assert.Empty(t, closingRefs("refactor closer logic"))
+	assert.Equal(t, []string{"kt-a1b2"}, matchRefs(refsRef, "Fix\n\nRefs: KT-A1B2\nsee kt-c3d4"))
+}
+
+func TestRunScanCommits(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { scanSince = "" }()
+	initGitRepo(t)
+
+	open := mkTicket(t, "kt-open", "Open", ticket.StatusOpen)
+	gated := mkTicket(t, "kt-gated", "Gated", ticket.StatusOpen)
+	gated.Tests = "- must pass"
+	require.NoError(t, Store.Save(gated))
+
+	refs := mkTicket(t, "kt-refs", "Refs", ticket.StatusOpen)
+
+	base := gitCommit(t, "initial")
+	sha := gitCommit(t, "Add feature\n\nfixes kt-open\ncloses kt-gated\ncloses kt-unknown\n\nRefs: kt-refs")
+
+	scanSince = base
+	require.NoError(t, runScanCommits(nil, nil))
+
+	u, err := Store.Get(open.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusClosed, u.Status)
+	assert.Contains(t, u.Notes, "Closed by commit")
+	assert.Contains(t, u.Notes, "Add feature")
+	assert.Equal(t, []string{sha}, u.Commits)
+
+	// Tests gate still applies, but the commit is recorded
+	g, err := Store.Get(gated.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusOpen, g.Status)
+	assert.Equal(t, []string{sha}, g.Commits)
+
+	// Refs trailers link without closing
+	r, err := Store.Get(refs.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusOpen, r.Status)
+	assert.Equal(t, []string{sha}, r.Commits)
+
+	// Rescanning is a no-op
+	notes := u.Notes
+	require.NoError(t, runScanCommits(nil, nil))
+	u, _ = Store.Get(open.ID)
+	assert.Equal(t, notes, u.Notes)
+}
+
+func TestInstallPostMergeHook(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { scanInstallHook = false }()
+	dir := initGitRepo(t)
+
+	scanInstallHook = true
+	require.NoError(t, runScanCommits(nil, nil))
+	data, err := os.ReadFile(filepath.Join(dir, ".git", "hooks", "post-merge"))
+	require.NoError(t, err)
+	assert.Equal(t, postMergeHook, string(data))
+
+	// Reinstalling is fine; a foreign hook is not overwritten
+	require.NoError(t, runScanCommits(nil, nil))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "hooks", "post-merge"), []byte("#!/bin/sh\n"), 0755))
+	require.Error(t, runScanCommits(nil, nil))
+}
+
+func TestAutoCommit(t *testing.T) {
+	dir := initGitRepo(t)
+	Store = store.New(filepath.Join(dir, ".ktickets"))
+	defer func() { Store = nil }()
+	require.NoError(t, Store.EnsureDir())
+	gitCommit(t, "initial")
+
+	// Disabled by default
+	mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	autoCommit(Store)
+	subject, err := git.Run("log", "-1", "--format=%s")
+	require.NoError(t, err)
+	assert.Equal(t, "initial", subject)
+
+	t.Setenv(config.EnvAutoCommit, "true")
+	Store.SetOperation("create A")
+	autoCommit(Store)
+	files, err := git.Run("show", "--name-only", "--format=%s", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "kt: create A\n\n.ktickets/kt-a.md", files)
+
+	// Nothing changed since: no empty commit
+	autoCommit(Store)
+	subject, _ = git.Run("log", "-1", "--format=%s")
+	assert.Equal(t, "kt: create A", subject)
+}
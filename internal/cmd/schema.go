@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [name]",
+	Short: "Print the JSON Schema for tickets or command results",
+	Long: `Print a JSON Schema (draft 2020-12) generated from the Go types behind
+kt's JSON output. Without a name, prints the ticket schema.
+
+Names: ` + strings.Join(schemaNames(), ", "),
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSchema,
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+// schemaTypes maps schema names to the types they describe.
+var schemaTypes = map[string]reflect.Type{
+	"ticket":         reflect.TypeOf(ticket.Ticket{}),
+	"status-result":  reflect.TypeOf(statusResult{}),
+	"dep-add":        reflect.TypeOf(depAddResult{}),
+	"commit-link":    reflect.TypeOf(commitLinkResult{}),
+	"dep-tree":       reflect.TypeOf(depTreeNode{}),
+	"dep-graph":      reflect.TypeOf(depGraph{}),
+	"merge":          reflect.TypeOf(mergeResult{}),
+	"split":          reflect.TypeOf(splitResult{}),
+	"rename":         reflect.TypeOf(renameResult{}),
+	"rm":             reflect.TypeOf(rmResult{}),
+	"purge":          reflect.TypeOf(purgeResult{}),
+	"undo":           reflect.TypeOf(store.UndoResult{}),
+	"plan":           reflect.TypeOf(workPlan{}),
+	"critical-path":  reflect.TypeOf(criticalPathResult{}),
+	"report":         reflect.TypeOf(statusReport{}),
+	"pr-body":        reflect.TypeOf(prBodyResult{}),
+	"suggest":        reflect.TypeOf(suggestResult{}),
+	"import":         reflect.TypeOf(importResult{}),
+	"sync":           reflect.TypeOf(syncResult{}),
+	"worktree":       reflect.TypeOf(worktreeResult{}),
+	"run":            reflect.TypeOf(runResult{}),
+	"dump":           reflect.TypeOf(dumpDocument{}),
+	"load":           reflect.TypeOf(loadResult{}),
+	"watch-event":    reflect.TypeOf(watchEvent{}),
+	"daemon-status":  reflect.TypeOf(store.DaemonStatus{}),
+	"config-set":     reflect.TypeOf(configSetResult{}),
+	"prime":          reflect.TypeOf(primeResult{}),
+	"session":        reflect.TypeOf(sessionClaims{}),
+	"remind":         reflect.TypeOf(remindDigest{}),
+	"gc":             reflect.TypeOf(gcResult{}),
+	"fsck":           reflect.TypeOf(fsckResult{}),
+	"lint":           reflect.TypeOf(lintResult{}),
+	"migrate":        reflect.TypeOf(migrateResult{}),
+	"dry-run":        reflect.TypeOf(dryRunResult{}),
+	"archive-export": reflect.TypeOf(archiveExportResult{}),
+	"cat":            reflect.TypeOf(catFile{}),
+	"diff":           reflect.TypeOf(diffResult{}),
+	"log":            reflect.TypeOf(logEntry{}),
+	"timeline":       reflect.TypeOf(timelineTicket{}),
+	"breakdown":      reflect.TypeOf(breakdownResult{}),
+}
+
+// schemaEnums lists the allowed values of enum-like string types.
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(ticket.Type("")):     stringValues(ticket.Types),
+	reflect.TypeOf(ticket.LinkType("")): stringValues(ticket.LinkTypes),
+}
+
+// schemaExamples lists typical values of open-ended string types. Status is
+// not an enum since kt status accepts any value.
+var schemaExamples = map[reflect.Type][]string{
+	reflect.TypeOf(ticket.Status("")): stringValues(ticket.Statuses),
+}
+
+func stringValues[T ~string](vals []T) []string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = string(v)
+	}
+	return out
+}
+
+func schemaNames() []string {
+	names := make([]string, 0, len(schemaTypes))
+	for name := range schemaTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	name := "ticket"
+	if len(args) > 0 {
+		name = args[0]
+	}
+	typ, ok := schemaTypes[name]
+	if !ok {
+		return fmt.Errorf("unknown schema %q (expected one of %s)", name, strings.Join(schemaNames(), ", "))
+	}
+	return PrintJSON(jsonSchema(typ))
+}
+
+// jsonSchema builds a JSON Schema for typ. Named struct types other than
+// the root are emitted under $defs so recursive types terminate.
+func jsonSchema(typ reflect.Type) map[string]any {
+	g := &schemaGen{defs: make(map[string]any)}
+	root := g.structSchema(typ)
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = typ.Name()
+	if len(g.defs) > 0 {
+		root["$defs"] = g.defs
+	}
+	return root
+}
+
+type schemaGen struct {
+	defs map[string]any
+}
+
+func (g *schemaGen) schema(t reflect.Type) map[string]any {
+	if values, ok := schemaEnums[t]; ok {
+		return map[string]any{"type": "string", "enum": values}
+	}
+	if values, ok := schemaExamples[t]; ok {
+		return map[string]any{"type": "string", "examples": values}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = nil // reserve before recursing
+			g.defs[name] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	}
+	return map[string]any{}
+}
+
+func (g *schemaGen) structSchema(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if ft := f.Type; f.Anonymous && name == "" {
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct { // promoted fields, as encoding/json does
+				embedded := g.structSchema(ft)
+				maps.Copy(props, embedded["properties"].(map[string]any))
+				if req, ok := embedded["required"].([]string); ok {
+					required = append(required, req...)
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = g.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	s := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
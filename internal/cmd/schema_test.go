@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchemaTicket(t *testing.T) {
+	s := jsonSchema(reflect.TypeOf(ticket.Ticket{}))
+	assert.Equal(t, "Ticket", s["title"])
+
+	props := s["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string"}, props["id"])
+	assert.Equal(t, map[string]any{"type": "integer"}, props["priority"])
+	assert.Equal(t, map[string]any{"type": "number"}, props["estimate"])
+	assert.Equal(t, "array", props["deps"].(map[string]any)["type"])
+	assert.Contains(t, props["type"].(map[string]any)["enum"], "epic")
+	assert.Contains(t, props["status"].(map[string]any)["examples"], "open")
+
+	required := s["required"].([]string)
+	assert.Contains(t, required, "id")
+	assert.NotContains(t, required, "deps")
+
+	// Must be valid JSON
+	_, err := json.Marshal(s)
+	require.NoError(t, err)
+}
+
+func TestJSONSchemaRecursive(t *testing.T) {
+	s := jsonSchema(reflect.TypeOf(depTreeNode{}))
+	defs := s["$defs"].(map[string]any)
+	require.Contains(t, defs, "depTreeNode")
+
+	children := s["properties"].(map[string]any)["children"].(map[string]any)
+	assert.Equal(t, "#/$defs/depTreeNode", children["items"].(map[string]any)["$ref"])
+}
+
+func TestRunSchema(t *testing.T) {
+	for _, name := range schemaNames() {
+		require.NoError(t, runSchema(nil, []string{name}), name)
+	}
+	require.NoError(t, runSchema(nil, nil))
+
+	err := runSchema(nil, []string{"bogus"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown schema")
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// appendSection describes a ticket body section that can be appended to.
+type appendSection struct {
+	name string
+	sep  string // separator between existing content and appended text
+	get  func(t *ticket.Ticket) *string
+}
+
+var (
+	designSection = appendSection{
+		name: "design",
+		sep:  "\n\n",
+		get:  func(t *ticket.Ticket) *string { return &t.Design },
+	}
+	acceptanceSection = appendSection{
+		name: "acceptance criteria",
+		sep:  "\n",
+		get:  func(t *ticket.Ticket) *string { return &t.AcceptanceCriteria },
+	}
+	testsSection = appendSection{
+		name: "tests",
+		sep:  "\n",
+		get:  func(t *ticket.Ticket) *string { return &t.Tests },
+	}
+)
+
+var addDesignCmd = &cobra.Command{
+	Use:   "add-design <id> [text]",
+	Short: "Append to the Design section (or pipe stdin)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAppendSection(cmd, args, designSection)
+	},
+}
+
+var addAcceptanceCmd = &cobra.Command{
+	Use:   "add-acceptance <id> [text]",
+	Short: "Append to the Acceptance Criteria section (or pipe stdin)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAppendSection(cmd, args, acceptanceSection)
+	},
+}
+
+var addTestCmd = &cobra.Command{
+	Use:   "add-test <id> [text]",
+	Short: "Append to the Tests section and reset tests_passed (or pipe stdin)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAppendSection(cmd, args, testsSection)
+	},
+}
+
+// appendFile is the --file flag of add-note and the add-<section> commands.
+var appendFile string
+
+func init() {
+	for _, c := range []*cobra.Command{addDesignCmd, addAcceptanceCmd, addTestCmd} {
+		rootCmd.AddCommand(c)
+	}
+	for _, c := range []*cobra.Command{addNoteCmd, addDesignCmd, addAcceptanceCmd, addTestCmd} {
+		c.Flags().StringVar(&appendFile, "file", "", `Read text from file ("-" for stdin)`)
+	}
+}
+
+// readTextInput returns text from args, the --file path ("-" for stdin),
+// or stdin, in that order. Giving both text and --file is an error.
+func readTextInput(cmd *cobra.Command, args []string, file string) (string, error) {
+	if len(args) > 0 && file != "" {
+		return "", fmt.Errorf("give the text as an argument or with --file, not both")
+	}
+	if len(args) > 0 {
+		return strings.TrimSpace(args[0]), nil
+	}
+
+	if file != "" && file != "-" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("read file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	var r io.Reader = os.Stdin
+	if cmd != nil {
+		r = cmd.InOrStdin()
+	}
+	if f, ok := r.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fmt.Fprintln(os.Stderr, "Reading text from the terminal; end it with Ctrl-D")
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("read stdin: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func runAppendSection(cmd *cobra.Command, args []string, section appendSection) error {
+	t, err := Store.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+
+	text, err := readTextInput(cmd, args[1:], appendFile)
+	if err != nil {
+		return err
+	}
+	if text == "" {
+		return fmt.Errorf("%s text required", section.name)
+	}
+
+	var updated *ticket.Ticket
+	err = Store.Update(t.ID, func(t *ticket.Ticket) error {
+		field := section.get(t)
+		if *field != "" {
+			*field += section.sep
+		}
+		*field += text
+		if section.name == testsSection.name {
+			t.TestsPassed = false
+		}
+		updated = t
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if IsJSON() {
+		return PrintJSON(updated)
+	}
+
+	fmt.Printf("Added %s to %s\n", section.name, updated.ID)
+	return nil
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAppendSection(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	tk := mkTicket(t, "kt-001", "Task", ticket.StatusOpen)
+	tk.Tests = "- TestOne"
+	tk.TestsPassed = true
+	require.NoError(t, Store.Save(tk))
+
+	require.NoError(t, runAppendSection(mockCmd(), []string{tk.ID, "Use a queue"}, designSection))
+	require.NoError(t, runAppendSection(mockCmd(), []string{tk.ID, "Second paragraph"}, designSection))
+	require.NoError(t, runAppendSection(mockCmd(), []string{tk.ID, "- Works offline"}, acceptanceSection))
+	require.NoError(t, runAppendSection(mockCmd(), []string{tk.ID, "- TestTwo"}, testsSection))
+
+	updated, _ := Store.Get(tk.ID)
+	assert.Equal(t, "Use a queue\n\nSecond paragraph", updated.Design)
+	assert.Equal(t, "- Works offline", updated.AcceptanceCriteria)
+	assert.Equal(t, "- TestOne\n- TestTwo", updated.Tests)
+	assert.False(t, updated.TestsPassed, "adding a test resets tests_passed")
+}
+
+func TestRunAppendSectionFile(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { appendFile = "" }()
+
+	tk := mkTicket(t, "kt-001", "Task", ticket.StatusOpen)
+
+	path := filepath.Join(t.TempDir(), "design.md")
+	require.NoError(t, os.WriteFile(path, []byte("From file\n"), 0644))
+	appendFile = path
+
+	require.NoError(t, runAppendSection(mockCmd(), []string{tk.ID}, designSection))
+
+	updated, _ := Store.Get(tk.ID)
+	assert.Equal(t, "From file", updated.Design)
+}
+
+func TestRunAppendSectionStdin(t *testing.T) {
+	defer setupTestEnv(t)()
+	jsonFlag = true
+	defer func() { jsonFlag = false }()
+
+	tk := mkTicket(t, "kt-001", "Task", ticket.StatusOpen)
+
+	c := mockCmd()
+	c.SetIn(strings.NewReader("- From stdin\n"))
+	require.NoError(t, runAppendSection(c, []string{tk.ID}, acceptanceSection))
+
+	updated, _ := Store.Get(tk.ID)
+	assert.Equal(t, "- From stdin", updated.AcceptanceCriteria)
+}
+
+func TestRunAppendSectionEmpty(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	tk := mkTicket(t, "kt-001", "Task", ticket.StatusOpen)
+
+	err := runAppendSection(mockCmd(), []string{tk.ID, "  "}, testsSection)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tests text required")
+}
+
+func TestRunAddNoteInput(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { appendFile = "" }()
+	tk := mkTicket(t, "kt-001", "Task", ticket.StatusOpen)
+
+	c := mockCmd()
+	c.SetIn(strings.NewReader("Piped note\n"))
+	require.NoError(t, runAddNote(c, []string{tk.ID}))
+
+	path := filepath.Join(t.TempDir(), "note.md")
+	require.NoError(t, os.WriteFile(path, []byte("File note\n"), 0644))
+	appendFile = path
+	require.NoError(t, runAddNote(mockCmd(), []string{tk.ID}))
+	assert.ErrorContains(t, runAddNote(mockCmd(), []string{tk.ID, "Text"}), "not both")
+
+	appendFile = "-"
+	c = mockCmd()
+	c.SetIn(strings.NewReader("Dash note"))
+	require.NoError(t, runAddNote(c, []string{tk.ID}))
+
+	got, err := Store.Get(tk.ID)
+	require.NoError(t, err)
+	assert.Contains(t, got.Notes, "Piped note")
+	assert.Contains(t, got.Notes, "File note")
+	assert.Contains(t, got.Notes, "Dash note")
+}
@@ -0,0 +1,750 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve tickets over a local HTTP JSON API",
+	Long: `Serve the ticket store over HTTP so editors, dashboards, and other
+processes can read and change tickets without shelling out. Requests go
+through the same locking as the CLI, and each change is its own kt undo
+step (auto-commit and notifications apply per request).
+
+The root URL serves a web UI: a kanban board (drag cards to change
+status), ticket details, and a dependency graph.
+
+Every request needs "Authorization: Bearer <token>". Tokens come from
+--token (repeatable), else $KTICKET_SERVE_TOKEN (comma-separated), else a
+random one printed at startup. A token may carry a scope as token:scope:
+read (GET only), write (create and change tickets), or admin (also delete
+and purge; the default). --read-only refuses every change regardless of
+scope. Errors are returned as {"error": "..."}.
+
+  GET    /api/tickets                  list (?status= &type= &assignee= &parent= &filter=)
+  POST   /api/tickets                  create {"title": ..., "type": ..., "priority": ...}
+  GET    /api/tickets/{id}             get (partial IDs and external refs work)
+  PATCH  /api/tickets/{id}             set fields {"priority": "1", ...} (as kt set)
+  POST   /api/tickets/{id}/status      {"status": "closed"} (closing checks tests)
+  POST   /api/tickets/{id}/deps        {"id": "<dep-id>"}
+  DELETE /api/tickets/{id}/deps/{dep}
+  POST   /api/tickets/{id}/links       {"id": "<id>", "type": "blocks"}
+  DELETE /api/tickets/{id}/links/{target}
+  DELETE /api/tickets/{id}             delete (admin; refused if open tickets reference it,
+                                       unless ?cascade=true removes the references)
+  POST   /api/purge                    delete all closed tickets (admin)
+  GET    /api/session                  the token's effective scope
+  POST   /graphql                      read-only GraphQL: tickets with parent, children,
+                                       deps, dependents, and links ({"query": ...})`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+var (
+	serveAddr     string
+	serveTokens   []string
+	serveReadOnly bool
+)
+
+// maxRequestBody caps request bodies; tickets are small.
+const maxRequestBody = 1 << 20
+
+// webAssets is the web UI served at /.
+//
+//go:embed web
+var webAssets embed.FS
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8377", "Address to listen on")
+	serveCmd.Flags().StringSliceVar(&serveTokens, "token", nil, "Bearer token[:read|write|admin] clients may send (default $"+config.EnvServeToken+", else random)")
+	serveCmd.Flags().BoolVar(&serveReadOnly, "read-only", false, "Refuse all changes")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	specs := serveTokens
+	if len(specs) == 0 && os.Getenv(config.EnvServeToken) != "" {
+		specs = strings.Split(os.Getenv(config.EnvServeToken), ",")
+	}
+	tokens, err := parseAPITokens(specs)
+	if err != nil {
+		return err
+	}
+	generated := len(tokens) == 0
+	if generated {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return fmt.Errorf("generate token: %w", err)
+		}
+		tokens = []apiToken{{hex.EncodeToString(b), scopeAdmin}}
+	}
+	if err := Store.EnsureDir(); err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           newAPI(Store.Dir, tokens, serveReadOnly).handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	mode := ""
+	if serveReadOnly {
+		mode = " (read-only)"
+	}
+	fmt.Fprintf(os.Stderr, "Serving %s on http://%s%s\n", Store.Dir, ln.Addr(), mode)
+	if generated {
+		token := tokens[0].token
+		fmt.Fprintf(os.Stderr, "token: %s\nweb UI: http://%s/#token=%s\n", token, ln.Addr(), token)
+	}
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// api serves the ticket store in dir over HTTP.
+type api struct {
+	dir      string
+	tokens   []apiToken
+	readOnly bool
+}
+
+func newAPI(dir string, tokens []apiToken, readOnly bool) *api {
+	return &api{dir: dir, tokens: tokens, readOnly: readOnly}
+}
+
+// apiScope is what a token may do; each scope includes those before it.
+type apiScope int
+
+const (
+	scopeRead  apiScope = iota // GET requests
+	scopeWrite                 // create and change tickets
+	scopeAdmin                 // delete and purge tickets
+)
+
+var apiScopeNames = []string{"read", "write", "admin"}
+
+func (sc apiScope) String() string { return apiScopeNames[sc] }
+
+func (sc apiScope) MarshalText() ([]byte, error) { return []byte(sc.String()), nil }
+
+// apiToken is a bearer token and the scope it grants.
+type apiToken struct {
+	token string
+	scope apiScope
+}
+
+// parseAPITokens parses token[:scope] specs. The scope defaults to admin.
+func parseAPITokens(specs []string) ([]apiToken, error) {
+	var tokens []apiToken
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		token, name, hasScope := strings.Cut(spec, ":")
+		scope := scopeAdmin
+		if hasScope {
+			i := slices.Index(apiScopeNames, name)
+			if i < 0 {
+				return nil, fmt.Errorf("invalid token scope %q (expected one of %s)", name, strings.Join(apiScopeNames, ", "))
+			}
+			scope = apiScope(i)
+		}
+		if token == "" {
+			return nil, fmt.Errorf("invalid token %q: empty token", spec)
+		}
+		tokens = append(tokens, apiToken{token, scope})
+	}
+	return tokens, nil
+}
+
+// apiHandler handles one request against a fresh Store and returns the
+// HTTP status and value to encode as JSON.
+type apiHandler func(s *store.Store, r *http.Request) (int, any, error)
+
+// apiError is an error with the HTTP status to report it with.
+type apiError struct {
+	status int
+	err    error
+}
+
+func (e *apiError) Error() string { return e.err.Error() }
+func (e *apiError) Unwrap() error { return e.err }
+
+func badRequest(err error) error { return &apiError{http.StatusBadRequest, err} }
+func conflict(err error) error   { return &apiError{http.StatusConflict, err} }
+func forbidden(err error) error  { return &apiError{http.StatusForbidden, err} }
+
+func (a *api) handler() http.Handler {
+	mux := http.NewServeMux()
+	a.route(mux, "GET /api/session", scopeRead, a.sessionAPI)
+	a.route(mux, "GET /api/tickets", scopeRead, listTicketsAPI)
+	a.route(mux, "POST /api/tickets", scopeWrite, createTicketAPI)
+	a.route(mux, "GET /api/tickets/{id}", scopeRead, getTicketAPI)
+	a.route(mux, "PATCH /api/tickets/{id}", scopeWrite, updateTicketAPI)
+	a.route(mux, "DELETE /api/tickets/{id}", scopeAdmin, deleteTicketAPI)
+	a.route(mux, "POST /api/tickets/{id}/status", scopeWrite, setStatusAPI)
+	a.route(mux, "POST /api/tickets/{id}/deps", scopeWrite, addDepAPI)
+	a.route(mux, "DELETE /api/tickets/{id}/deps/{dep}", scopeWrite, removeDepAPI)
+	a.route(mux, "POST /api/tickets/{id}/links", scopeWrite, addLinkAPI)
+	a.route(mux, "DELETE /api/tickets/{id}/links/{target}", scopeWrite, removeLinkAPI)
+	a.route(mux, "POST /api/purge", scopeAdmin, purgeAPI)
+	a.route(mux, "GET /graphql", scopeRead, graphqlAPI)
+	a.route(mux, "POST /graphql", scopeRead, graphqlAPI)
+	a.route(mux, "/api/", scopeRead, func(s *store.Store, r *http.Request) (int, any, error) {
+		return 0, nil, &apiError{http.StatusNotFound, fmt.Errorf("no such endpoint: %s %s", r.Method, r.URL.Path)}
+	})
+
+	// The web UI is static and public; its API calls carry the token
+	web, _ := fs.Sub(webAssets, "web")
+	files := http.FileServerFS(web)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writeAPIError(w, &apiError{http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method)})
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+	return mux
+}
+
+// route registers h behind token auth, requiring scope need. Each request
+// gets its own Store, so every change is a separate undo step, commit, and
+// notification.
+func (a *api) route(mux *http.ServeMux, pattern string, need apiScope, h apiHandler) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		scope, ok := a.authenticate(r)
+		if !ok {
+			writeAPIError(w, &apiError{http.StatusUnauthorized, errors.New("missing or invalid token")})
+			return
+		}
+		if need > scope {
+			if a.readOnly {
+				writeAPIError(w, forbidden(errors.New("server is read-only")))
+			} else {
+				writeAPIError(w, forbidden(fmt.Errorf("token lacks %s scope", need)))
+			}
+			return
+		}
+		s := store.New(a.dir)
+		status, v, err := h(s, r)
+		if err != nil {
+			writeAPIError(w, err)
+		} else {
+			writeAPIJSON(w, status, v)
+		}
+		advanceDependents(s)
+		autoCommit(s)
+		notifyChanges(s)
+	})
+}
+
+// authenticate returns the effective scope of r's bearer token, and false
+// if it carries none of the server's tokens.
+func (a *api) authenticate(r *http.Request) (apiScope, bool) {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return 0, false
+	}
+	scope, found := scopeRead, false
+	// Compare against every token so timing doesn't reveal which matched
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(got), []byte(t.token)) == 1 {
+			scope, found = max(scope, t.scope), true
+		}
+	}
+	if a.readOnly {
+		scope = scopeRead
+	}
+	return scope, found
+}
+
+// sessionAPI reports the caller's effective scope, so clients such as the
+// web UI can hide what they may not do.
+func (a *api) sessionAPI(s *store.Store, r *http.Request) (int, any, error) {
+	scope, _ := a.authenticate(r)
+	return http.StatusOK, map[string]any{"scope": scope, "read_only": a.readOnly}, nil
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeAPIError reports err as {"error": "..."} with a status derived from it.
+func writeAPIError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var ae *apiError
+	switch {
+	case errors.As(err, &ae):
+		status = ae.status
+	case errors.Is(err, store.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, store.ErrAmbiguous):
+		status = http.StatusBadRequest
+	}
+	writeAPIJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// decodeBody decodes a JSON request body into v, rejecting unknown fields.
+func decodeBody(r *http.Request, v any) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxRequestBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return badRequest(fmt.Errorf("invalid request body: %w", err))
+	}
+	return nil
+}
+
+func listTicketsAPI(s *store.Store, r *http.Request) (int, any, error) {
+	filter, err := listFilter(r.URL.Query().Get)
+	if err != nil {
+		return 0, nil, badRequest(err)
+	}
+
+	tickets, err := s.List()
+	if err != nil {
+		return 0, nil, err
+	}
+	out := make([]*ticket.Ticket, 0, len(tickets))
+	for _, t := range tickets {
+		if filter.Match(t) {
+			out = append(out, t)
+		}
+	}
+	return http.StatusOK, out, nil
+}
+
+// listFilter combines the status, type, assignee, and parent parameters
+// and a filter expression, as given by get, into one filter.
+func listFilter(get func(string) string) (ticket.Filter, error) {
+	var clauses []string
+	for _, field := range []string{"status", "type", "assignee", "parent"} {
+		if v := get(field); v != "" {
+			clauses = append(clauses, field+"="+v)
+		}
+	}
+	if expr := get("filter"); expr != "" {
+		clauses = append(clauses, expr)
+	}
+	return ticket.ParseFilter(strings.Join(clauses, " and "))
+}
+
+func getTicketAPI(s *store.Store, r *http.Request) (int, any, error) {
+	t, err := s.Resolve(r.PathValue("id"))
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, t, nil
+}
+
+// ticketInput is the body of POST /api/tickets.
+type ticketInput struct {
+	Title              string      `json:"title"`
+	Description        string      `json:"description,omitempty"`
+	Design             string      `json:"design,omitempty"`
+	AcceptanceCriteria string      `json:"acceptance_criteria,omitempty"`
+	Tests              string      `json:"tests,omitempty"`
+	Type               ticket.Type `json:"type,omitempty"`
+	Priority           *int        `json:"priority,omitempty"`
+	Assignee           *string     `json:"assignee,omitempty"` // default: defaults.assignee, else git user.name
+	ExternalRef        string      `json:"external_ref,omitempty"`
+	Parent             string      `json:"parent,omitempty"`
+	Labels             []string    `json:"labels,omitempty"`
+}
+
+func createTicketAPI(s *store.Store, r *http.Request) (int, any, error) {
+	var in ticketInput
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+	if strings.TrimSpace(in.Title) == "" {
+		return 0, nil, badRequest(errors.New("title is required"))
+	}
+	if in.Type == "" {
+		in.Type = defaultType()
+	}
+	if !slices.Contains(ticket.Types, in.Type) {
+		return 0, nil, badRequest(fmt.Errorf("invalid type %q (expected one of %v)", in.Type, ticket.Types))
+	}
+	priority := defaultPriority()
+	if in.Priority != nil {
+		priority = *in.Priority
+	}
+	if priority < 0 || priority > 4 {
+		return 0, nil, badRequest(fmt.Errorf("invalid priority %d (expected 0-4)", priority))
+	}
+	assignee := defaultAssignee()
+	if in.Assignee != nil {
+		assignee = *in.Assignee
+	}
+	if in.Parent != "" {
+		parent, err := s.Resolve(in.Parent)
+		if err != nil {
+			return 0, nil, badRequest(fmt.Errorf("parent: %w", err))
+		}
+		in.Parent = parent.ID
+	}
+
+	id, err := newTicketID(s)
+	if err != nil {
+		return 0, nil, fmt.Errorf("generate ID: %w", err)
+	}
+	t := &ticket.Ticket{
+		ID:                 id,
+		Status:             ticket.StatusOpen,
+		Created:            time.Now().UTC().Format(time.RFC3339),
+		Type:               in.Type,
+		Priority:           priority,
+		Assignee:           assignee,
+		ExternalRef:        in.ExternalRef,
+		Parent:             in.Parent,
+		Labels:             in.Labels,
+		Title:              in.Title,
+		Description:        in.Description,
+		Design:             in.Design,
+		AcceptanceCriteria: in.AcceptanceCriteria,
+		Tests:              in.Tests,
+	}
+	applyTemplates(t)
+	if err := checkRequired(t); err != nil {
+		return 0, nil, badRequest(err)
+	}
+	if err := checkParentRule(s, t); err != nil {
+		return 0, nil, badRequest(err)
+	}
+	s.SetOperation("create " + t.Title)
+	if err := s.Save(t); err != nil {
+		return 0, nil, fmt.Errorf("save ticket: %w", err)
+	}
+	return http.StatusCreated, t, nil
+}
+
+func updateTicketAPI(s *store.Store, r *http.Request) (int, any, error) {
+	var fields map[string]string
+	if err := decodeBody(r, &fields); err != nil {
+		return 0, nil, err
+	}
+	if len(fields) == 0 {
+		return 0, nil, badRequest(errors.New("no fields to set"))
+	}
+	if parent := strings.TrimSpace(fields["parent"]); parent != "" {
+		p, err := s.Resolve(parent)
+		if err != nil {
+			return 0, nil, badRequest(fmt.Errorf("parent: %w", err))
+		}
+		fields["parent"] = p.ID
+	}
+
+	lt, err := s.ResolveForUpdate(r.PathValue("id"))
+	if err != nil {
+		return 0, nil, err
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "status" {
+			continue // last, so tests_passed in the same request counts
+		}
+		if err := lt.Ticket.SetField(name, fields[name]); err != nil {
+			lt.Release()
+			return 0, nil, badRequest(err)
+		}
+	}
+	if status, ok := fields["status"]; ok {
+		if err := changeStatus(lt.Ticket, ticket.Status(strings.TrimSpace(status))); err != nil {
+			lt.Release()
+			return 0, nil, err
+		}
+	}
+	if err := checkParentRule(s, lt.Ticket); err != nil {
+		lt.Release()
+		return 0, nil, badRequest(err)
+	}
+	s.SetOperation("set " + lt.Ticket.ID + " " + strings.Join(names, " "))
+	if err := lt.SaveAndRelease(); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, lt.Ticket, nil
+}
+
+func setStatusAPI(s *store.Store, r *http.Request) (int, any, error) {
+	var in struct {
+		Status ticket.Status `json:"status"`
+	}
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+	if in.Status == "" {
+		return 0, nil, badRequest(errors.New("status is required"))
+	}
+
+	lt, err := s.ResolveForUpdate(r.PathValue("id"))
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := changeStatus(lt.Ticket, in.Status); err != nil {
+		lt.Release()
+		return 0, nil, err
+	}
+	s.SetOperation("status " + lt.Ticket.ID + " " + string(in.Status))
+	if err := lt.SaveAndRelease(); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, lt.Ticket, nil
+}
+
+// changeStatus sets a ticket's status as kt status and kt close do: the
+// status must be a known one, and closing needs passed tests.
+func changeStatus(t *ticket.Ticket, status ticket.Status) error {
+	if err := checkStatus(status); err != nil {
+		return badRequest(err)
+	}
+	if status == ticket.StatusClosed {
+		if err := t.CanClose(); err != nil {
+			return conflict(err)
+		}
+	}
+	t.SetStatus(status)
+	return nil
+}
+
+func deleteTicketAPI(s *store.Store, r *http.Request) (int, any, error) {
+	t, err := s.Resolve(r.PathValue("id"))
+	if err != nil {
+		return 0, nil, err
+	}
+	s.SetOperation("delete " + t.ID)
+	if r.URL.Query().Get("cascade") == "true" {
+		_, err = s.DeleteCascade(t.ID)
+	} else {
+		err = s.Delete(t.ID)
+	}
+	if errors.Is(err, store.ErrReferenced) {
+		return 0, nil, conflict(err)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, t, nil
+}
+
+func purgeAPI(s *store.Store, r *http.Request) (int, any, error) {
+	all, err := s.List()
+	if err != nil {
+		return 0, nil, err
+	}
+	var closed []*ticket.Ticket
+	for _, t := range all {
+		if t.Status == ticket.StatusClosed {
+			closed = append(closed, t)
+		}
+	}
+	if err := validatePurge(all, closed); err != nil {
+		return 0, nil, conflict(err)
+	}
+	s.SetOperation("purge")
+	ids := ticketIDs(closed)
+	cleaned, err := purgeTickets(s, ids, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, purgeResult{Deleted: len(ids), IDs: ids, Cleaned: cleaned}, nil
+}
+
+// refInput is the body of the deps and links endpoints.
+type refInput struct {
+	ID   string `json:"id"`
+	Type string `json:"type,omitempty"`
+}
+
+func addDepAPI(s *store.Store, r *http.Request) (int, any, error) {
+	var in refInput
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+	if in.ID == "" || in.Type != "" {
+		return 0, nil, badRequest(errors.New(`expected {"id": "<dep-id>"}`))
+	}
+	tickets, err := s.List()
+	if err != nil {
+		return 0, nil, err
+	}
+	byID := make(map[string]*ticket.Ticket, len(tickets))
+	for _, t := range tickets {
+		byID[t.ID] = t
+	}
+	dep, err := s.Resolve(in.ID)
+	if err != nil {
+		return 0, nil, badRequest(fmt.Errorf("dep: %w", err))
+	}
+
+	lt, err := s.ResolveForUpdate(r.PathValue("id"))
+	if err != nil {
+		return 0, nil, err
+	}
+	switch {
+	case slices.Contains(lt.Ticket.Deps, dep.ID):
+		lt.Release()
+		return 0, nil, conflict(fmt.Errorf("%s already depends on %s", lt.Ticket.ID, dep.ID))
+	case dep.ID == lt.Ticket.ID || dependsOn(byID, dep.ID, lt.Ticket.ID):
+		lt.Release()
+		return 0, nil, conflict(fmt.Errorf("adding %s would create a dependency cycle", dep.ID))
+	}
+	lt.Ticket.Deps = append(lt.Ticket.Deps, dep.ID)
+	s.SetOperation("dep add " + lt.Ticket.ID + " " + dep.ID)
+	if err := lt.SaveAndRelease(); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, lt.Ticket, nil
+}
+
+func removeDepAPI(s *store.Store, r *http.Request) (int, any, error) {
+	dep, err := s.Resolve(r.PathValue("dep"))
+	if err != nil {
+		return 0, nil, err
+	}
+	lt, err := s.ResolveForUpdate(r.PathValue("id"))
+	if err != nil {
+		return 0, nil, err
+	}
+	if !slices.Contains(lt.Ticket.Deps, dep.ID) {
+		lt.Release()
+		return 0, nil, &apiError{http.StatusNotFound, fmt.Errorf("%s does not depend on %s", lt.Ticket.ID, dep.ID)}
+	}
+	lt.Ticket.Deps = slices.DeleteFunc(lt.Ticket.Deps, func(id string) bool { return id == dep.ID })
+	s.SetOperation("dep rm " + lt.Ticket.ID + " " + dep.ID)
+	if err := lt.SaveAndRelease(); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, lt.Ticket, nil
+}
+
+func addLinkAPI(s *store.Store, r *http.Request) (int, any, error) {
+	var in refInput
+	if err := decodeBody(r, &in); err != nil {
+		return 0, nil, err
+	}
+	if in.ID == "" {
+		return 0, nil, badRequest(errors.New(`expected {"id": "<id>", "type": "<link-type>"}`))
+	}
+	linkType := ticket.LinkRelatesTo
+	if in.Type != "" {
+		lt, err := ticket.ParseLinkType(in.Type)
+		if err != nil {
+			return 0, nil, badRequest(err)
+		}
+		linkType = lt
+	}
+
+	source, target, err := lockPair(s, r.PathValue("id"), in.ID)
+	if err != nil {
+		return 0, nil, err
+	}
+	source.Ticket.AddLink(linkType, target.Ticket.ID)
+	target.Ticket.AddLink(linkType.Inverse(), source.Ticket.ID)
+	s.SetOperation("link add " + source.Ticket.ID + " " + target.Ticket.ID + " --type " + string(linkType))
+	if err := savePair(source, target); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, source.Ticket, nil
+}
+
+func removeLinkAPI(s *store.Store, r *http.Request) (int, any, error) {
+	source, target, err := lockPair(s, r.PathValue("id"), r.PathValue("target"))
+	if err != nil {
+		return 0, nil, err
+	}
+	source.Ticket.RemoveLinks(target.Ticket.ID)
+	target.Ticket.RemoveLinks(source.Ticket.ID)
+	s.SetOperation("link rm " + source.Ticket.ID + " " + target.Ticket.ID)
+	if err := savePair(source, target); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, source.Ticket, nil
+}
+
+// lockPair resolves and locks two distinct tickets, in ID order to avoid
+// deadlocks, and returns them in argument order.
+func lockPair(s *store.Store, a, b string) (*store.LockedTicket, *store.LockedTicket, error) {
+	ta, err := s.Resolve(a)
+	if err != nil {
+		return nil, nil, err
+	}
+	tb, err := s.Resolve(b)
+	if err != nil {
+		return nil, nil, badRequest(err)
+	}
+	if ta.ID == tb.ID {
+		return nil, nil, badRequest(fmt.Errorf("cannot link %s to itself", ta.ID))
+	}
+
+	first, second := ta.ID, tb.ID
+	if second < first {
+		first, second = second, first
+	}
+	l1, err := s.GetForUpdate(first)
+	if err != nil {
+		return nil, nil, err
+	}
+	l2, err := s.GetForUpdate(second)
+	if err != nil {
+		l1.Release()
+		return nil, nil, err
+	}
+	if l1.Ticket.ID == ta.ID {
+		return l1, l2, nil
+	}
+	return l2, l1, nil
+}
+
+// savePair saves both tickets, releasing both locks either way.
+func savePair(a, b *store.LockedTicket) error {
+	if err := a.SaveAndRelease(); err != nil {
+		b.Release()
+		return err
+	}
+	return b.SaveAndRelease()
+}
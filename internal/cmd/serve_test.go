@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// apiClient sends authorized JSON requests to a test server.
+type apiClient struct {
+	t     *testing.T
+	url   string
+	token string
+}
+
+func newTestAPI(t *testing.T) *apiClient {
+	t.Helper()
+	srv := httptest.NewServer(newAPI(Store.Dir, []apiToken{{"secret", scopeAdmin}}, false).handler())
+	t.Cleanup(srv.Close)
+	return &apiClient{t: t, url: srv.URL, token: "secret"}
+}
+
+// do sends a request and decodes the JSON response into out (if non-nil).
+func (c *apiClient) do(method, path, body string, out any) int {
+	c.t.Helper()
+	req, err := http.NewRequest(method, c.url+path, strings.NewReader(body))
+	require.NoError(c.t, err)
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(c.t, err)
+	defer resp.Body.Close()
+	assert.Equal(c.t, "application/json", resp.Header.Get("Content-Type"))
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(c.t, err)
+	if out != nil {
+		require.NoError(c.t, json.Unmarshal(data, out), string(data))
+	}
+	return resp.StatusCode
+}
+
+func TestServeAuth(t *testing.T) {
+	defer setupTestEnv(t)()
+	c := newTestAPI(t)
+
+	for _, auth := range []string{"", "Bearer wrong", "secret"} {
+		req, _ := http.NewRequest("GET", c.url+"/api/tickets", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, "missing or invalid token", body["error"])
+	}
+}
+
+func TestServeTickets(t *testing.T) {
+	defer setupTestEnv(t)()
+	c := newTestAPI(t)
+
+	var created ticket.Ticket
+	status := c.do("POST", "/api/tickets", `{"title":"API ticket","type":"bug","priority":0,"assignee":"ann","tests":"- TestIt"}`, &created)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, ticket.TypeBug, created.Type)
+	assert.Equal(t, 0, created.Priority)
+	assert.Equal(t, ticket.StatusOpen, created.Status)
+
+	mkTicket(t, "kt-other", "Other", ticket.StatusOpen)
+	var list []ticket.Ticket
+	require.Equal(t, http.StatusOK, c.do("GET", "/api/tickets?type=bug", "", &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, created.ID, list[0].ID)
+	require.Equal(t, http.StatusOK, c.do("GET", "/api/tickets?filter=priority=2", "", &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "kt-other", list[0].ID)
+
+	var got ticket.Ticket
+	require.Equal(t, http.StatusOK, c.do("GET", "/api/tickets/other", "", &got))
+	assert.Equal(t, "Other", got.Title)
+
+	require.Equal(t, http.StatusOK, c.do("PATCH", "/api/tickets/kt-other", `{"priority":"1","labels":"a,b"}`, &got))
+	assert.Equal(t, 1, got.Priority)
+	assert.Equal(t, []string{"a", "b"}, got.Labels)
+
+	// Closing checks tests, like kt close
+	var apiErr map[string]string
+	require.Equal(t, http.StatusConflict, c.do("POST", "/api/tickets/"+created.ID+"/status", `{"status":"closed"}`, &apiErr))
+	assert.Contains(t, apiErr["error"], "tests")
+	require.Equal(t, http.StatusConflict, c.do("PATCH", "/api/tickets/"+created.ID, `{"status":"closed"}`, &apiErr))
+	assert.Contains(t, apiErr["error"], "tests")
+	require.Equal(t, http.StatusOK, c.do("PATCH", "/api/tickets/"+created.ID, `{"tests_passed":"true"}`, nil))
+	require.Equal(t, http.StatusOK, c.do("POST", "/api/tickets/"+created.ID+"/status", `{"status":"closed"}`, &got))
+	assert.Equal(t, ticket.StatusClosed, got.Status)
+	assert.NotEmpty(t, got.Closed)
+
+	// Each request is its own undo step
+	_, err := Store.Undo()
+	require.NoError(t, err)
+	reverted, err := Store.Get(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusOpen, reverted.Status)
+	assert.True(t, reverted.TestsPassed)
+}
+
+func TestServeDepsAndLinks(t *testing.T) {
+	defer setupTestEnv(t)()
+	c := newTestAPI(t)
+	mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+
+	var got ticket.Ticket
+	require.Equal(t, http.StatusOK, c.do("POST", "/api/tickets/kt-a/deps", `{"id":"kt-b"}`, &got))
+	assert.Equal(t, []string{"kt-b"}, got.Deps)
+
+	var apiErr map[string]string
+	require.Equal(t, http.StatusConflict, c.do("POST", "/api/tickets/kt-b/deps", `{"id":"kt-a"}`, &apiErr))
+	assert.Contains(t, apiErr["error"], "cycle")
+	var removed ticket.Ticket
+	require.Equal(t, http.StatusOK, c.do("DELETE", "/api/tickets/kt-a/deps/kt-b", "", &removed))
+	assert.Empty(t, removed.Deps)
+	require.Equal(t, http.StatusNotFound, c.do("DELETE", "/api/tickets/kt-a/deps/kt-b", "", nil))
+
+	require.Equal(t, http.StatusOK, c.do("POST", "/api/tickets/kt-a/links", `{"id":"kt-b","type":"blocks"}`, &got))
+	assert.Equal(t, []string{"kt-b"}, got.Relations[ticket.LinkBlocks])
+	b, err := Store.Get("kt-b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-a"}, b.Relations[ticket.LinkBlockedBy])
+
+	var unlinked ticket.Ticket
+	require.Equal(t, http.StatusOK, c.do("DELETE", "/api/tickets/kt-b/links/kt-a", "", &unlinked))
+	assert.Empty(t, unlinked.Relations)
+}
+
+func TestServeErrors(t *testing.T) {
+	defer setupTestEnv(t)()
+	c := newTestAPI(t)
+	mkTicket(t, "kt-ab1", "One", ticket.StatusOpen)
+	mkTicket(t, "kt-ab2", "Two", ticket.StatusOpen)
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		contains           string
+	}{
+		{"GET", "/api/tickets/zzz", "", http.StatusNotFound, "not found"},
+		{"GET", "/api/tickets/ab", "", http.StatusBadRequest, "ambiguous"},
+		{"GET", "/api/nope", "", http.StatusNotFound, "no such endpoint"},
+		{"POST", "/api/tickets", `{"title":""}`, http.StatusBadRequest, "title is required"},
+		{"POST", "/api/tickets", `{"title":"x","priority":9}`, http.StatusBadRequest, "invalid priority"},
+		{"POST", "/api/tickets", `{"title":"x","color":"red"}`, http.StatusBadRequest, "unknown field"},
+		{"PATCH", "/api/tickets/kt-ab1", `{"priority":"high"}`, http.StatusBadRequest, "invalid priority"},
+		{"POST", "/api/tickets/kt-ab1/links", `{"id":"kt-ab1"}`, http.StatusBadRequest, "itself"},
+	}
+	for _, tt := range tests {
+		var apiErr map[string]string
+		assert.Equal(t, tt.status, c.do(tt.method, tt.path, tt.body, &apiErr), tt.method+" "+tt.path)
+		assert.Contains(t, apiErr["error"], tt.contains)
+	}
+}
+
+func TestServeWebUI(t *testing.T) {
+	defer setupTestEnv(t)()
+	c := newTestAPI(t)
+
+	// Static assets need no token
+	for path, want := range map[string]string{"/": "<title>kt</title>", "/app.js": "/api/tickets", "/style.css": ".card"} {
+		resp, err := http.Get(c.url + path)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+		assert.Contains(t, string(body), want, path)
+	}
+
+	resp, err := http.Post(c.url+"/", "text/plain", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestParseAPITokens(t *testing.T) {
+	tokens, err := parseAPITokens([]string{"a", " b:read ", "c:write", ""})
+	require.NoError(t, err)
+	assert.Equal(t, []apiToken{{"a", scopeAdmin}, {"b", scopeRead}, {"c", scopeWrite}}, tokens)
+
+	_, err = parseAPITokens([]string{"a:root"})
+	assert.ErrorContains(t, err, "invalid token scope")
+	_, err = parseAPITokens([]string{":read"})
+	assert.ErrorContains(t, err, "empty token")
+}
+
+func TestServeScopes(t *testing.T) {
+	defer setupTestEnv(t)()
+	mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	mkTicket(t, "kt-b", "B", ticket.StatusClosed)
+	tokens := []apiToken{{"r", scopeRead}, {"w", scopeWrite}, {"adm", scopeAdmin}}
+	srv := httptest.NewServer(newAPI(Store.Dir, tokens, false).handler())
+	t.Cleanup(srv.Close)
+	as := func(token string) *apiClient { return &apiClient{t: t, url: srv.URL, token: token} }
+
+	var session map[string]any
+	require.Equal(t, http.StatusOK, as("w").do("GET", "/api/session", "", &session))
+	assert.Equal(t, "write", session["scope"])
+
+	var apiErr map[string]string
+	require.Equal(t, http.StatusOK, as("r").do("GET", "/api/tickets", "", nil))
+	require.Equal(t, http.StatusForbidden, as("r").do("POST", "/api/tickets/kt-a/status", `{"status":"in_progress"}`, &apiErr))
+	assert.Equal(t, "token lacks write scope", apiErr["error"])
+	require.Equal(t, http.StatusOK, as("w").do("POST", "/api/tickets/kt-a/status", `{"status":"in_progress"}`, nil))
+
+	require.Equal(t, http.StatusForbidden, as("w").do("DELETE", "/api/tickets/kt-a", "", &apiErr))
+	assert.Equal(t, "token lacks admin scope", apiErr["error"])
+	require.Equal(t, http.StatusForbidden, as("w").do("POST", "/api/purge", "", nil))
+
+	var purged purgeResult
+	require.Equal(t, http.StatusOK, as("adm").do("POST", "/api/purge", "", &purged))
+	assert.Equal(t, 1, purged.Deleted)
+	assert.Len(t, purged.IDs, 1)
+	require.Equal(t, http.StatusOK, as("adm").do("DELETE", "/api/tickets/kt-a", "", nil))
+	_, err := Store.Get("kt-a")
+	assert.Error(t, err)
+}
+
+func TestServeReadOnly(t *testing.T) {
+	defer setupTestEnv(t)()
+	mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	srv := httptest.NewServer(newAPI(Store.Dir, []apiToken{{"adm", scopeAdmin}}, true).handler())
+	t.Cleanup(srv.Close)
+	c := &apiClient{t: t, url: srv.URL, token: "adm"}
+
+	var session map[string]any
+	require.Equal(t, http.StatusOK, c.do("GET", "/api/session", "", &session))
+	assert.Equal(t, "read", session["scope"])
+	assert.Equal(t, true, session["read_only"])
+
+	var apiErr map[string]string
+	require.Equal(t, http.StatusForbidden, c.do("PATCH", "/api/tickets/kt-a", `{"priority":"1"}`, &apiErr))
+	assert.Equal(t, "server is read-only", apiErr["error"])
+	require.Equal(t, http.StatusOK, c.do("GET", "/api/tickets/kt-a", "", nil))
+}
+
+func TestServeDeleteReferenced(t *testing.T) {
+	defer setupTestEnv(t)()
+	c := newTestAPI(t)
+	mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	b.Deps = []string{"kt-a"}
+	require.NoError(t, Store.Save(b))
+
+	var apiErr map[string]string
+	require.Equal(t, http.StatusConflict, c.do("DELETE", "/api/tickets/kt-a", "", &apiErr))
+	assert.Equal(t, "cannot delete kt-a: ticket kt-b depends on it", apiErr["error"])
+	require.Equal(t, http.StatusOK, c.do("DELETE", "/api/tickets/kt-a?cascade=true", "", nil))
+	got, err := Store.Get("kt-b")
+	require.NoError(t, err)
+	assert.Empty(t, got.Deps)
+	require.Equal(t, http.StatusOK, c.do("DELETE", "/api/tickets/kt-b", "", nil))
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "List in-progress tickets claimed by each agent session",
+	Long: `List the in-progress tickets claimed by each agent session.
+
+Agents name their session with --session or KTICKET_SESSION. Every ticket
+a session changes records it in the ticket's history (see kt show), and a
+ticket that is in progress after the change is claimed by that session
+until it leaves in_progress.`,
+	Args: cobra.NoArgs,
+	RunE: runSessions,
+}
+
+func init() {
+	rootCmd.AddCommand(sessionsCmd)
+}
+
+// sessionClaims is one session and the tickets it has claimed.
+type sessionClaims struct {
+	Session    string         `json:"session"`
+	LastActive string         `json:"last_active,omitempty"`
+	Tickets    []*ticketBrief `json:"tickets"`
+}
+
+// ticketBrief identifies a claimed ticket.
+type ticketBrief struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Priority int    `json:"priority"`
+}
+
+func runSessions(cmd *cobra.Command, args []string) error {
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+	sessions := claimsBySession(tickets)
+
+	if IsJSON() {
+		return PrintJSON(sessions)
+	}
+	if len(sessions) == 0 {
+		fmt.Println("No claimed tickets")
+		return nil
+	}
+	for _, s := range sessions {
+		if s.LastActive != "" {
+			fmt.Printf("%s (last active %s)\n", paint(ansiBold, s.Session), s.LastActive)
+		} else {
+			fmt.Println(paint(ansiBold, s.Session))
+		}
+		for _, t := range s.Tickets {
+			fmt.Printf("  %s [%s] %s\n", t.ID, paint(priorityColor(t.Priority), fmt.Sprintf("P%d", t.Priority)), t.Title)
+		}
+	}
+	return nil
+}
+
+// claimsBySession groups claimed in-progress tickets by session, sorted by
+// session name, each session's tickets by priority. LastActive is the
+// latest time the session changed any ticket.
+func claimsBySession(tickets []*ticket.Ticket) []sessionClaims {
+	bySession := map[string]*sessionClaims{}
+	for _, t := range tickets {
+		if t.Status != ticket.StatusInProgress || t.ClaimedBy == "" {
+			continue
+		}
+		s := bySession[t.ClaimedBy]
+		if s == nil {
+			s = &sessionClaims{Session: t.ClaimedBy}
+			bySession[t.ClaimedBy] = s
+		}
+		s.Tickets = append(s.Tickets, &ticketBrief{ID: t.ID, Title: t.Title, Priority: t.Priority})
+	}
+	for _, t := range tickets {
+		for _, e := range t.History {
+			if s := bySession[e.Session]; s != nil && e.At > s.LastActive {
+				s.LastActive = e.At
+			}
+		}
+	}
+
+	out := make([]sessionClaims, 0, len(bySession))
+	for _, s := range bySession {
+		slices.SortFunc(s.Tickets, func(a, b *ticketBrief) int {
+			return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.ID, b.ID))
+		})
+		out = append(out, *s)
+	}
+	slices.SortFunc(out, func(a, b sessionClaims) int { return cmp.Compare(a.Session, b.Session) })
+	return out
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimsBySession(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "kt-1", Title: "One", Status: ticket.StatusInProgress, Priority: 2, ClaimedBy: "b",
+			History: []ticket.Event{{At: "2026-01-02T00:00:00Z", Session: "b"}}},
+		{ID: "kt-2", Title: "Two", Status: ticket.StatusInProgress, Priority: 0, ClaimedBy: "b"},
+		{ID: "kt-3", Title: "Three", Status: ticket.StatusInProgress, ClaimedBy: "a"},
+		{ID: "kt-4", Title: "Unclaimed", Status: ticket.StatusInProgress},
+		{ID: "kt-5", Title: "Closed", Status: ticket.StatusClosed,
+			History: []ticket.Event{{At: "2026-01-03T00:00:00Z", Session: "b"}}},
+	}
+
+	assert.Equal(t, []sessionClaims{
+		{Session: "a", Tickets: []*ticketBrief{{ID: "kt-3", Title: "Three"}}},
+		{Session: "b", LastActive: "2026-01-03T00:00:00Z", Tickets: []*ticketBrief{
+			{ID: "kt-2", Title: "Two", Priority: 0},
+			{ID: "kt-1", Title: "One", Priority: 2},
+		}},
+	}, claimsBySession(tickets))
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var setCmd = &cobra.Command{
+	Use:   "set <id> field=value...",
+	Short: "Update ticket fields",
+	Long: `Update one or more ticket fields without opening $EDITOR.
+
+Fields: title, status, type, priority, assignee, external-ref, parent, due,
+labels (comma-separated), recurrence (weekly, every 2 months, ...),
+tests_passed, description, design, acceptance, tests.
+An empty value clears the field, e.g. kt set abc1 assignee=`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runSet,
+}
+
+func init() {
+	rootCmd.AddCommand(setCmd)
+}
+
+// fieldAssignment is a parsed field=value argument.
+type fieldAssignment struct {
+	Field string
+	Value string
+}
+
+// parseAssignments parses field=value arguments.
+func parseAssignments(args []string) ([]fieldAssignment, error) {
+	assignments := make([]fieldAssignment, 0, len(args))
+	for _, arg := range args {
+		field, value, ok := strings.Cut(arg, "=")
+		if !ok || strings.TrimSpace(field) == "" {
+			return nil, fmt.Errorf("invalid assignment %q (expected field=value)", arg)
+		}
+		assignments = append(assignments, fieldAssignment{Field: field, Value: value})
+	}
+	return assignments, nil
+}
+
+// resolveAssignments canonicalizes ticket references (e.g. parent) to full IDs.
+func resolveAssignments(assignments []fieldAssignment) error {
+	for i, a := range assignments {
+		if a.Field != "parent" || strings.TrimSpace(a.Value) == "" {
+			continue
+		}
+		parent, err := Store.Resolve(strings.TrimSpace(a.Value))
+		if err != nil {
+			return fmt.Errorf("parent: %w", err)
+		}
+		assignments[i].Value = parent.ID
+	}
+	return nil
+}
+
+// applyAssignments sets each field on the ticket, stopping at the first
+// error. Status goes last and is checked as kt status checks it, so
+// tests_passed=true counts toward closing wherever it is given.
+func applyAssignments(t *ticket.Ticket, assignments []fieldAssignment) error {
+	var status []fieldAssignment
+	for _, a := range assignments {
+		if key, _ := ticket.FieldKey(a.Field); key == "status" {
+			status = append(status, a)
+			continue
+		}
+		if err := t.SetField(a.Field, a.Value); err != nil {
+			return err
+		}
+	}
+	for _, a := range status {
+		if err := changeStatus(t, ticket.Status(strings.TrimSpace(a.Value))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runSet(cmd *cobra.Command, args []string) error {
+	assignments, err := parseAssignments(args[1:])
+	if err != nil {
+		return err
+	}
+	if err := resolveAssignments(assignments); err != nil {
+		return err
+	}
+
+	t, err := Store.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+
+	var updated *ticket.Ticket
+	err = Store.Update(t.ID, func(t *ticket.Ticket) error {
+		if err := applyAssignments(t, assignments); err != nil {
+			return err
+		}
+		if err := checkParentRule(Store, t); err != nil {
+			return err
+		}
+		updated = t
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if IsJSON() {
+		return PrintJSON(updated)
+	}
+
+	printTicket(updated, storeDependents()[updated.ID])
+	return nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSet(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	tk := mkTicket(t, "kt-001", "Task", ticket.StatusOpen)
+	epic := mkTicket(t, "kt-epic", "Epic", ticket.StatusOpen)
+
+	err := runSet(nil, []string{tk.ID, "priority=0", "assignee=bob", "labels=ui,api", "parent=epic", "due=2026-04-01"})
+	require.NoError(t, err)
+
+	updated, _ := Store.Get(tk.ID)
+	assert.Equal(t, 0, updated.Priority)
+	assert.Equal(t, "bob", updated.Assignee)
+	assert.Equal(t, []string{"ui", "api"}, updated.Labels)
+	assert.Equal(t, epic.ID, updated.Parent)
+	assert.Equal(t, "2026-04-01", updated.Due)
+}
+
+func TestRunSetJSON(t *testing.T) {
+	defer setupTestEnv(t)()
+	jsonFlag = true
+	defer func() { jsonFlag = false }()
+
+	tk := mkTicket(t, "kt-001", "Task", ticket.StatusOpen)
+
+	err := runSet(nil, []string{tk.ID, "title=Renamed"})
+	require.NoError(t, err)
+
+	updated, _ := Store.Get(tk.ID)
+	assert.Equal(t, "Renamed", updated.Title)
+}
+
+func TestRunSetInvalid(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	tk := mkTicket(t, "kt-001", "Task", ticket.StatusOpen)
+
+	err := runSet(nil, []string{tk.ID, "priority"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected field=value")
+
+	// Invalid value leaves ticket untouched, even if earlier fields were valid
+	err = runSet(nil, []string{tk.ID, "assignee=bob", "priority=9"})
+	require.Error(t, err)
+
+	updated, _ := Store.Get(tk.ID)
+	assert.Equal(t, 2, updated.Priority)
+	assert.Empty(t, updated.Assignee)
+}
+
+func TestRunSetParentNotFound(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	tk := mkTicket(t, "kt-001", "Task", ticket.StatusOpen)
+
+	err := runSet(nil, []string{tk.ID, "parent=kt-missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestRunSetStatus(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	tk := mkTicket(t, "kt-001", "Task", ticket.StatusOpen)
+	tk.Tests = "- [ ] passes"
+	require.NoError(t, Store.Save(tk))
+
+	// Any status goes until config.yml lists some, as with kt status
+	require.NoError(t, runSet(nil, []string{tk.ID, "status=review"}))
+	writeProjectConfig(t, "statuses: [review]\n")
+	err := runSet(nil, []string{tk.ID, "status=bogus"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid status")
+
+	err = runSet(nil, []string{tk.ID, "status=closed"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tests not passed")
+
+	updated, _ := Store.Get(tk.ID)
+	assert.Equal(t, ticket.Status("review"), updated.Status)
+
+	// tests_passed counts toward closing in either order
+	for _, args := range [][]string{
+		{"status=closed", "tests_passed=true"},
+		{"tests_passed=true", "status=closed"},
+	} {
+		require.NoError(t, runSet(nil, []string{tk.ID, "tests_passed=false", "status=open"}))
+		require.NoError(t, runSet(nil, append([]string{tk.ID}, args...)), args)
+		updated, _ = Store.Get(tk.ID)
+		assert.Equal(t, ticket.StatusClosed, updated.Status, args)
+	}
+}
@@ -1,12 +1,20 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/markdown"
 	"github.com/kostyay/kticket/internal/ticket"
 	"github.com/spf13/cobra"
 )
@@ -14,31 +22,69 @@ import (
 var showCmd = &cobra.Command{
 	Use:   "show <id>...",
 	Short: "Display ticket(s)",
-	Args:  cobra.MinimumNArgs(1),
-	RunE:  runShow,
+	Long: `Display tickets in full.
+
+--fields prints only the named fields, e.g. --fields title,status,acceptance.
+Alone, a field prints as its bare value; sections print under their
+heading, and other fields as "name: value". With --json, each ticket is an
+object holding just those keys.
+
+--timeline replaces the notes, history, and commits with one Activity
+section, oldest first: status changes (from the ticket's git history when
+committed), notes, agent session events, and linked commits. With --json,
+each ticket gains an "activity" array.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: paged(runShow),
 }
 
 var editCmd = &cobra.Command{
 	Use:   "edit <id>",
 	Short: "Open ticket in $EDITOR",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runEdit,
+	Long: `Open the ticket file in the editor (the editor setting, else $EDITOR).
+
+If the saved file no longer parses as a ticket, or its id was changed, kt
+asks whether to edit it again or restore the version from before the edit,
+so a typo in the frontmatter cannot make the ticket vanish from kt ls.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEdit,
 }
 
 var addNoteCmd = &cobra.Command{
 	Use:   "add-note <id> [text]",
-	Short: "Append timestamped note (or pipe stdin)",
+	Short: "Append timestamped note (text, --file, or stdin)",
 	Args:  cobra.RangeArgs(1, 2),
 	RunE:  runAddNote,
 }
 
+var (
+	// showRender is "" (auto), or a bool string from --render[=false].
+	showRender   string
+	showFields   []string
+	showTimeline bool
+)
+
 func init() {
+	showCmd.Flags().StringVar(&showRender, "render", "", "Render markdown (default on a terminal; KTICKET_RENDER=0 to disable)")
+	showCmd.Flags().Lookup("render").NoOptDefVal = "true"
+	showCmd.Flags().StringSliceVar(&showFields, "fields", nil, "Show only these fields, e.g. title,status,acceptance_criteria")
+	showCmd.Flags().BoolVar(&showTimeline, "timeline", false, "Merge status changes, notes, history, and commits into one Activity section")
 	rootCmd.AddCommand(showCmd)
 	rootCmd.AddCommand(editCmd)
 	rootCmd.AddCommand(addNoteCmd)
 }
 
 func runShow(cmd *cobra.Command, args []string) error {
+	keys, err := fieldKeys(showFields)
+	if err != nil {
+		return err
+	}
+	if keys != nil && IsTemplate() {
+		return fmt.Errorf("--fields and --format cannot be used together")
+	}
+	if showTimeline && (keys != nil || IsTemplate()) {
+		return fmt.Errorf("--timeline cannot be used with --fields or --format")
+	}
+
 	tickets := make([]*ticket.Ticket, 0, len(args))
 
 	for _, id := range args {
@@ -47,64 +93,263 @@ func runShow(cmd *cobra.Command, args []string) error {
 			Errorf("%s", err)
 			continue
 		}
+		for _, p := range lintFile(Store.Path(t.ID)) {
+			Warnf("%s: %s (see kt lint)", t.ID, p)
+		}
 		tickets = append(tickets, t)
 	}
 
+	if keys != nil {
+		return showSelected(tickets, keys)
+	}
+	if showTimeline {
+		return showWithTimeline(tickets)
+	}
+
+	if IsTemplate() {
+		return PrintTemplate(tickets)
+	}
+
+	dependents := storeDependents()
 	if IsJSON() {
-		if len(tickets) == 1 {
-			return PrintJSON(tickets[0])
+		shown := make([]shownTicket, len(tickets))
+		for i, t := range tickets {
+			shown[i] = shownTicket{Ticket: t, Dependents: dependents[t.ID]}
 		}
-		return PrintJSON(tickets)
+		if len(shown) == 1 {
+			return PrintJSON(shown[0])
+		}
+		return PrintJSON(shown)
 	}
 
 	for i, t := range tickets {
 		if i > 0 {
 			fmt.Println()
 		}
-		printTicket(t)
+		printTicket(t, dependents[t.ID])
 	}
 
 	return nil
 }
 
-func printTicket(t *ticket.Ticket) {
-	fmt.Printf("%s [%s] %s\n", t.ID, t.Status, t.Title)
-	fmt.Printf("Type: %s  Priority: %d  Assignee: %s\n", t.Type, t.Priority, t.Assignee)
-	fmt.Printf("Created: %s\n", t.Created)
+// fieldKeys returns the JSON keys of the --fields names, in order and
+// without repeats, or nil if none were given.
+func fieldKeys(names []string) ([]string, error) {
+	var keys []string
+	for _, name := range names {
+		key, ok := ticket.FieldKey(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// sectionHeadings are the headings of the body sections of a ticket, by
+// JSON key.
+var sectionHeadings = map[string]string{
+	"description":         "Description",
+	"design":              "Design",
+	"acceptance_criteria": "Acceptance Criteria",
+	"tests":               "Tests",
+	"notes":               "Notes",
+}
+
+// showSelected prints only the given fields (JSON keys) of each ticket.
+func showSelected(tickets []*ticket.Ticket, keys []string) error {
+	if IsJSON() {
+		objects := make([]map[string]any, len(tickets))
+		for i, t := range tickets {
+			all := normalizeTicket(t)
+			objects[i] = make(map[string]any, len(keys))
+			for _, key := range keys {
+				objects[i][key] = all[key]
+			}
+		}
+		if len(objects) == 1 {
+			return PrintJSON(objects[0])
+		}
+		return PrintJSON(objects)
+	}
+
+	for i, t := range tickets {
+		if i > 0 {
+			fmt.Println()
+		}
+		for _, key := range keys {
+			value, _ := t.Field(key)
+			switch heading, section := sectionHeadings[key]; {
+			case len(keys) == 1:
+				fmt.Println(value)
+			case section:
+				fmt.Printf("## %s\n%s\n", heading, renderBody(value))
+			default:
+				fmt.Printf("%s: %s\n", key, value)
+			}
+		}
+	}
+	return nil
+}
+
+// shownTicket is a ticket as kt show --json prints it, with the IDs of the
+// tickets that depend on it.
+type shownTicket struct {
+	*ticket.Ticket
+	Dependents []string `json:"dependents,omitempty"`
+}
+
+// showWithTimeline prints each ticket with its Activity section.
+func showWithTimeline(tickets []*ticket.Ticket) error {
+	dependents := storeDependents()
+	shown := make([]timelineTicket, len(tickets))
+	for i, t := range tickets {
+		shown[i] = timelineTicket{Ticket: t, Dependents: dependents[t.ID], Activity: timeline(t)}
+	}
+	if IsJSON() {
+		if len(shown) == 1 {
+			return PrintJSON(shown[0])
+		}
+		return PrintJSON(shown)
+	}
+	for i, s := range shown {
+		if i > 0 {
+			fmt.Println()
+		}
+		writeTimeline(os.Stdout, s.Ticket, s.Dependents, s.Activity)
+	}
+	return nil
+}
+
+func printTicket(t *ticket.Ticket, dependents []string) {
+	writeTicket(os.Stdout, t, dependents)
+}
+
+// writeTicket writes the text view of a ticket shown by kt show, with the
+// IDs of the tickets that depend on it.
+func writeTicket(w io.Writer, t *ticket.Ticket, dependents []string) {
+	if t.Resolution != "" {
+		fmt.Fprintf(w, "%s [%s] %s\n", paint(ansiBold, t.ID), paintStatus(t.Status, string(t.Status)+": "+t.Resolution), t.Title)
+	} else {
+		fmt.Fprintf(w, "%s [%s] %s\n", paint(ansiBold, t.ID), paintStatus(t.Status, string(t.Status)), t.Title)
+	}
+	fmt.Fprintf(w, "Type: %s  Priority: %s  Assignee: %s\n", t.Type, paint(priorityColor(t.Priority), strconv.Itoa(t.Priority)), t.Assignee)
+	fmt.Fprintf(w, "Created: %s\n", t.Created)
+	if t.Closed != "" {
+		fmt.Fprintf(w, "Closed: %s\n", t.Closed)
+	}
+	if t.ClaimedBy != "" {
+		fmt.Fprintf(w, "Claimed by: %s\n", t.ClaimedBy)
+	}
 
 	if len(t.Deps) > 0 {
-		fmt.Printf("Deps: %s\n", strings.Join(t.Deps, ", "))
+		fmt.Fprintf(w, "Deps: %s\n", strings.Join(t.Deps, ", "))
+	}
+	if len(dependents) > 0 {
+		fmt.Fprintf(w, "Dependents: %s\n", strings.Join(dependents, ", "))
 	}
 	if len(t.Links) > 0 {
-		fmt.Printf("Links: %s\n", strings.Join(t.Links, ", "))
+		fmt.Fprintf(w, "Links: %s\n", strings.Join(t.Links, ", "))
+	}
+	for _, lt := range ticket.LinkTypes[1:] {
+		if ids := t.Relations[lt]; len(ids) > 0 {
+			fmt.Fprintf(w, "%s: %s\n", linkLabel(lt), strings.Join(ids, ", "))
+		}
+	}
+	if len(t.Commits) > 0 {
+		short := make([]string, len(t.Commits))
+		for i, sha := range t.Commits {
+			short[i] = git.Commit{SHA: sha}.Short()
+		}
+		fmt.Fprintf(w, "Commits: %s\n", strings.Join(short, ", "))
 	}
 	if t.ExternalRef != "" {
-		fmt.Printf("External: %s\n", t.ExternalRef)
+		fmt.Fprintf(w, "External: %s\n", t.ExternalRef)
 	}
 	if t.Parent != "" {
-		fmt.Printf("Parent: %s\n", t.Parent)
+		fmt.Fprintf(w, "Parent: %s\n", t.Parent)
+	}
+	if len(t.Labels) > 0 {
+		fmt.Fprintf(w, "Labels: %s\n", strings.Join(t.Labels, ", "))
+	}
+	if t.Due != "" {
+		fmt.Fprintf(w, "Due: %s\n", t.Due)
+	}
+	if t.Estimate > 0 {
+		est, _ := t.Field("estimate")
+		fmt.Fprintf(w, "Estimate: %s\n", est)
+	}
+	if t.Recurrence != "" {
+		fmt.Fprintf(w, "Recurs: %s\n", t.Recurrence)
 	}
 
 	if t.Description != "" {
-		fmt.Printf("\n%s\n", t.Description)
+		fmt.Fprintf(w, "\n%s\n", renderBody(t.Description))
 	}
 	if t.Design != "" {
-		fmt.Printf("\n## Design\n%s\n", t.Design)
+		fmt.Fprintf(w, "\n## Design\n%s\n", renderBody(t.Design))
 	}
 	if t.AcceptanceCriteria != "" {
-		fmt.Printf("\n## Acceptance Criteria\n%s\n", t.AcceptanceCriteria)
+		fmt.Fprintf(w, "\n## Acceptance Criteria\n%s\n", renderBody(t.AcceptanceCriteria))
 	}
 	if t.Tests != "" {
-		fmt.Printf("\n## Tests\n%s\n", t.Tests)
+		fmt.Fprintf(w, "\n## Tests\n%s\n", renderBody(t.Tests))
 		if t.TestsPassed {
-			fmt.Println("✓ Tests passed")
+			fmt.Fprintln(w, paint(ansiGreen, "✓ Tests passed"))
 		} else {
-			fmt.Println("✗ Tests not passed")
+			fmt.Fprintln(w, paint(ansiRed, "✗ Tests not passed"))
 		}
 	}
 	if t.Notes != "" {
-		fmt.Printf("\n## Notes\n%s\n", t.Notes)
+		fmt.Fprintf(w, "\n## Notes\n%s\n", renderBody(t.Notes))
+	}
+	if len(t.History) > 0 {
+		fmt.Fprintf(w, "\n## History\n")
+		for _, e := range t.History {
+			fmt.Fprintf(w, "%s  %s  %s\n", e.At, e.Session, e.Op)
+		}
+	}
+}
+
+// renderEnabled reports whether ticket bodies are rendered as markdown:
+// --render if given, else KTICKET_RENDER, else only on a terminal.
+func renderEnabled() bool {
+	for _, v := range []string{showRender, os.Getenv(config.EnvRender)} {
+		if v != "" {
+			on, err := strconv.ParseBool(v)
+			return err == nil && on
+		}
+	}
+	return OutputMode() == "text"
+}
+
+// renderBody renders markdown for display when enabled, else returns s as is.
+func renderBody(s string) string {
+	if !renderEnabled() {
+		return s
+	}
+	return markdown.Renderer{Color: colorEnabled()}.Render(s)
+}
+
+// linkLabel renders a link type as a field label, e.g. "blocked-by" → "Blocked by".
+func linkLabel(lt ticket.LinkType) string {
+	label := strings.ReplaceAll(string(lt), "-", " ")
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// storeDependents lists the store once and maps each ticket ID to the IDs
+// of the tickets that depend on it (blocked by it).
+func storeDependents() map[string][]string {
+	if Store == nil {
+		return nil
+	}
+	tickets, err := Store.List()
+	if err != nil {
+		return nil
 	}
+	return dependentIndex(tickets)
 }
 
 func runEdit(cmd *cobra.Command, args []string) error {
@@ -112,19 +357,94 @@ func runEdit(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	err = editTicket(t.ID, nil, "restore the previous version")
+	if errors.Is(err, errEditDiscarded) {
+		return fmt.Errorf("%s: edit discarded, previous version restored", t.ID)
+	}
+	return err
+}
+
+// errEditDiscarded is returned by editTicket when the user gives up on an
+// edit that does not check out.
+var errEditDiscarded = errors.New("edit discarded")
+
+// editTicket opens ticket id's file in the editor until the saved file
+// parses as that ticket and passes check, if given. After each failure it
+// asks whether to edit again or take the alternative; taking it restores
+// the file as it was and returns errEditDiscarded.
+func editTicket(id string, check func(*ticket.Ticket) error, alternative string) error {
+	path := Store.Path(id)
+	backup, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for {
+		editor := strings.Fields(editorCommand())
+		c := exec.Command(editor[0], append(editor[1:], path)...)
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			return err
+		}
+
+		err := checkEdited(path, id, check)
+		if err == nil {
+			for _, p := range lintFile(path) {
+				Warnf("%s: %s (see kt lint)", id, p)
+			}
+			return nil
+		}
+		Errorf("%s: %s", id, err)
+		if reopen, perr := promptReopen(alternative); perr == nil && reopen {
+			continue
+		}
+		if werr := ticket.WriteRaw(path, backup); werr != nil {
+			return fmt.Errorf("restore %s: %w", path, werr)
+		}
+		return errEditDiscarded
+	}
+}
 
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vi"
+// checkEdited reports why the ticket file at path, edited by hand, is not
+// one kt can read back as ticket id, or fails check.
+func checkEdited(path, id string, check func(*ticket.Ticket) error) error {
+	t, err := ticket.ParseFile(path)
+	if err != nil {
+		return err
 	}
+	if t.ID != id {
+		return fmt.Errorf("id %q does not match the file name", t.ID)
+	}
+	if check != nil {
+		return check(t)
+	}
+	return nil
+}
 
-	path := Store.Path(t.ID)
-	c := exec.Command(editor, path)
-	c.Stdin = os.Stdin
-	c.Stdout = os.Stdout
-	c.Stderr = os.Stderr
+// promptReopen asks whether to edit a broken ticket file again or take the
+// alternative. Anything but yes, including no answer at all, means the
+// alternative.
+func promptReopen(alternative string) (bool, error) {
+	fmt.Fprintf(os.Stderr, "Edit again (e) or %s? [e/N] ", alternative)
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && response == "" {
+		return false, err
+	}
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "e" || response == "edit", nil
+}
 
-	return c.Run()
+// editorCommand returns the editor to run: the editor setting, else
+// $EDITOR, else vi.
+func editorCommand() string {
+	if editor := projectConfig().Editor; strings.TrimSpace(editor) != "" {
+		return editor
+	}
+	if editor := strings.TrimSpace(os.Getenv("EDITOR")); editor != "" {
+		return editor
+	}
+	return "vi"
 }
 
 func runAddNote(cmd *cobra.Command, args []string) error {
@@ -133,30 +453,20 @@ func runAddNote(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	var note string
-	if len(args) > 1 {
-		note = args[1]
-	} else {
-		// Read from stdin
-		data, err := os.ReadFile(os.Stdin.Name())
-		if err != nil {
-			return fmt.Errorf("read stdin: %w", err)
-		}
-		note = strings.TrimSpace(string(data))
+	note, err := readTextInput(cmd, args[1:], appendFile)
+	if err != nil {
+		return err
 	}
-
 	if note == "" {
 		return fmt.Errorf("note text required")
 	}
 
-	// Add timestamp and append
-	timestamp := time.Now().UTC().Format(time.RFC3339)
-	if t.Notes != "" {
-		t.Notes += "\n\n"
-	}
-	t.Notes += fmt.Sprintf("**%s**\n\n%s", timestamp, note)
-
-	if err := Store.Save(t); err != nil {
+	err = Store.Update(t.ID, func(u *ticket.Ticket) error {
+		appendNote(u, note)
+		t = u
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
@@ -167,3 +477,12 @@ func runAddNote(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Note added to %s\n", t.ID)
 	return nil
 }
+
+// appendNote adds a timestamped note to the ticket's Notes section.
+func appendNote(t *ticket.Ticket, note string) {
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	if t.Notes != "" {
+		t.Notes += "\n\n"
+	}
+	t.Notes += fmt.Sprintf("**%s**\n\n%s", timestamp, note)
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split <id>",
+	Short: "Split a ticket into child tickets",
+	Long: `Split a ticket into child tickets, one per acceptance criteria item
+(or per --titles entry). Children get the original as parent and inherit its
+deps, priority, and assignee; the original becomes an epic.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSplit,
+}
+
+var splitTitles string
+
+func init() {
+	splitCmd.Flags().StringVar(&splitTitles, "titles", "", "Comma-separated child titles (default: acceptance criteria items)")
+	rootCmd.AddCommand(splitCmd)
+}
+
+type splitResult struct {
+	Epic    string           `json:"epic"`
+	Created []*ticket.Ticket `json:"created"`
+}
+
+// checklistItem matches markdown list items, with or without a checkbox.
+var checklistItem = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?(.+)$`)
+
+// checklistTitles extracts list item text from a markdown section.
+func checklistTitles(section string) []string {
+	var titles []string
+	for _, line := range strings.Split(section, "\n") {
+		if m := checklistItem.FindStringSubmatch(line); m != nil {
+			titles = append(titles, strings.TrimSpace(m[1]))
+		}
+	}
+	return titles
+}
+
+func runSplit(cmd *cobra.Command, args []string) error {
+	result := splitResult{}
+
+	err := Store.Transaction(func(tx *store.Tx) error {
+		orig, err := tx.Resolve(args[0])
+		if err != nil {
+			return err
+		}
+
+		var titles []string
+		if splitTitles != "" {
+			for _, title := range strings.Split(splitTitles, ",") {
+				if title = strings.TrimSpace(title); title != "" {
+					titles = append(titles, title)
+				}
+			}
+		} else {
+			titles = checklistTitles(orig.AcceptanceCriteria)
+		}
+		if len(titles) == 0 {
+			return fmt.Errorf("nothing to split: %s has no acceptance criteria items (use --titles)", orig.ID)
+		}
+
+		created := time.Now().UTC().Format(time.RFC3339)
+		for _, title := range titles {
+			id, err := generateUniqueID(tx)
+			if err != nil {
+				return fmt.Errorf("generate ID: %w", err)
+			}
+			child := &ticket.Ticket{
+				ID:       id,
+				Status:   ticket.StatusOpen,
+				Deps:     slices.Clone(orig.Deps),
+				Created:  created,
+				Type:     ticket.TypeTask,
+				Priority: orig.Priority,
+				Assignee: orig.Assignee,
+				Parent:   orig.ID,
+				Title:    title,
+			}
+			tx.Save(child)
+			result.Created = append(result.Created, child)
+		}
+
+		orig.Type = ticket.TypeEpic
+		tx.Save(orig)
+		result.Epic = orig.ID
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+
+	for _, t := range result.Created {
+		fmt.Printf("%s %s\n", t.ID, t.Title)
+	}
+	fmt.Printf("%s is now an epic with %d children\n", result.Epic, len(result.Created))
+	return nil
+}
+
+// generateUniqueID returns a new ID not already present in the transaction.
+func generateUniqueID(tx *store.Tx) (string, error) {
+	cfg := projectConfig()
+	if cfg.IDStrategy == config.IDSequential {
+		return tx.NextSequentialID(cfg.IDPrefix)
+	}
+	return tx.NewHashID(cfg.IDPrefix, cfg.IDLength)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecklistTitles(t *testing.T) {
+	section := "Intro text\n- [ ] First item\n- [x] Done item\n* Star item\n1. Numbered\n\nNot an item"
+	assert.Equal(t, []string{"First item", "Done item", "Star item", "Numbered"}, checklistTitles(section))
+	assert.Empty(t, checklistTitles(""))
+}
+
+func TestRunSplitFromAcceptance(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { splitTitles = "" }()
+
+	dep := mkTicket(t, "kt-dep", "Dep", ticket.StatusOpen)
+	big := mkTicket(t, "kt-big", "Big", ticket.StatusOpen)
+	big.AcceptanceCriteria = "- [ ] Login works\n- [ ] Logout works"
+	big.Deps = []string{dep.ID}
+	big.Priority = 1
+	big.Assignee = "alice"
+	require.NoError(t, Store.Save(big))
+
+	require.NoError(t, runSplit(nil, []string{big.ID}))
+
+	updated, _ := Store.Get(big.ID)
+	assert.Equal(t, ticket.TypeEpic, updated.Type)
+
+	tickets, _ := Store.List()
+	var children []*ticket.Ticket
+	for _, tk := range tickets {
+		if tk.Parent == big.ID {
+			children = append(children, tk)
+		}
+	}
+	require.Len(t, children, 2)
+	titles := []string{children[0].Title, children[1].Title}
+	assert.ElementsMatch(t, []string{"Login works", "Logout works"}, titles)
+	for _, c := range children {
+		assert.Equal(t, []string{dep.ID}, c.Deps)
+		assert.Equal(t, 1, c.Priority)
+		assert.Equal(t, "alice", c.Assignee)
+	}
+}
+
+func TestRunSplitTitles(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { splitTitles = "" }()
+	jsonFlag = true
+	defer func() { jsonFlag = false }()
+
+	big := mkTicket(t, "kt-big", "Big", ticket.StatusOpen)
+
+	splitTitles = "a, b ,c"
+	require.NoError(t, runSplit(nil, []string{big.ID}))
+
+	tickets, _ := Store.List()
+	assert.Len(t, tickets, 4)
+}
+
+func TestRunSplitNothing(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { splitTitles = "" }()
+
+	big := mkTicket(t, "kt-big", "Big", ticket.StatusOpen)
+
+	err := runSplit(nil, []string{big.ID})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nothing to split")
+}
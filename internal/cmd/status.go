@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/kostyay/kticket/internal/ticket"
 	"github.com/spf13/cobra"
@@ -16,7 +18,7 @@ var startCmd = &cobra.Command{
 
 var closeCmd = &cobra.Command{
 	Use:   "close <id>...",
-	Short: "Set status to closed (validates tests_passed)",
+	Short: "Set status to closed (validates tests_passed and deps)",
 	Args:  cobra.MinimumNArgs(1),
 	RunE:  runClose,
 }
@@ -42,7 +44,15 @@ var passCmd = &cobra.Command{
 	RunE:  runPass,
 }
 
+var (
+	closeStrict     bool
+	closeIgnoreDeps bool
+)
+
 func init() {
+	closeCmd.Flags().BoolVar(&closeStrict, "strict", false, "Refuse to close tickets with open deps")
+	closeCmd.Flags().BoolVar(&closeIgnoreDeps, "ignore-deps", false, "Skip the open deps check")
+
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(closeCmd)
 	rootCmd.AddCommand(reopenCmd)
@@ -51,8 +61,10 @@ func init() {
 }
 
 type statusResult struct {
-	Updated []string      `json:"updated,omitempty"`
-	Errors  []statusError `json:"errors,omitempty"`
+	Updated   []string      `json:"updated,omitempty"`
+	Unblocked []string      `json:"unblocked,omitempty"`
+	Warnings  []statusError `json:"warnings,omitempty"`
+	Errors    []statusError `json:"errors,omitempty"`
 }
 
 type statusError struct {
@@ -79,7 +91,11 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	newStatus := ticket.Status(args[1])
-	lt.Ticket.Status = newStatus
+	if err := checkStatus(newStatus); err != nil {
+		lt.Release()
+		return err
+	}
+	lt.Ticket.SetStatus(newStatus)
 
 	if err := lt.SaveAndRelease(); err != nil {
 		return err
@@ -142,9 +158,19 @@ func setStatusMultiple(ids []string, status ticket.Status, validateClose bool) e
 				result.Errors = append(result.Errors, statusError{ID: lt.Ticket.ID, Error: err.Error()})
 				continue
 			}
+			if open := unresolvedDeps(lt.Ticket); len(open) > 0 && !closeIgnoreDeps {
+				msg := fmt.Sprintf("%s has open deps: %s", lt.Ticket.ID, strings.Join(open, ", "))
+				if closeStrict {
+					lt.Release()
+					result.Errors = append(result.Errors, statusError{ID: lt.Ticket.ID,
+						Error: "cannot close " + msg + " (use --ignore-deps to override)"})
+					continue
+				}
+				result.Warnings = append(result.Warnings, statusError{ID: lt.Ticket.ID, Error: msg})
+			}
 		}
 
-		lt.Ticket.Status = status
+		lt.Ticket.SetStatus(status)
 		if err := lt.SaveAndRelease(); err != nil {
 			result.Errors = append(result.Errors, statusError{ID: lt.Ticket.ID, Error: err.Error()})
 			continue
@@ -153,16 +179,59 @@ func setStatusMultiple(ids []string, status ticket.Status, validateClose bool) e
 		result.Updated = append(result.Updated, lt.Ticket.ID)
 	}
 
+	if status == ticket.StatusClosed && len(result.Updated) > 0 {
+		if tickets, err := Store.List(); err == nil {
+			result.Unblocked = newlyUnblocked(tickets, result.Updated)
+		}
+	}
+
 	if IsJSON() {
 		return PrintJSON(result)
 	}
 
+	for _, w := range result.Warnings {
+		Warnf("%s", w.Error)
+	}
 	for _, id := range result.Updated {
 		fmt.Printf("%s → %s\n", id, status)
 	}
+	if len(result.Unblocked) > 0 {
+		fmt.Printf("unblocked: %s\n", strings.Join(result.Unblocked, ", "))
+	}
 	for _, e := range result.Errors {
 		Errorf("%s: %s", e.ID, e.Error)
 	}
 
 	return nil
 }
+
+// newlyUnblocked returns open/in_progress tickets that depend on one of the
+// just-closed IDs and now have every dependency closed.
+func newlyUnblocked(tickets []*ticket.Ticket, closed []string) []string {
+	byID := make(map[string]*ticket.Ticket, len(tickets))
+	for _, t := range tickets {
+		byID[t.ID] = t
+	}
+
+	var ids []string
+	for _, t := range tickets {
+		if t.Status == ticket.StatusClosed {
+			continue
+		}
+		if !slices.ContainsFunc(t.Deps, func(d string) bool { return slices.Contains(closed, d) }) {
+			continue
+		}
+		resolved := true
+		for _, d := range t.Deps {
+			if dep, ok := byID[d]; !ok || dep.Status != ticket.StatusClosed {
+				resolved = false
+				break
+			}
+		}
+		if resolved {
+			ids = append(ids, t.ID)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
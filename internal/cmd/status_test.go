@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewlyUnblocked(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "kt-a", Status: ticket.StatusClosed},
+		{ID: "kt-b", Status: ticket.StatusOpen},
+		{ID: "kt-c", Status: ticket.StatusOpen, Deps: []string{"kt-a"}},
+		{ID: "kt-d", Status: ticket.StatusOpen, Deps: []string{"kt-a", "kt-b"}},
+		{ID: "kt-e", Status: ticket.StatusClosed, Deps: []string{"kt-a"}},
+		{ID: "kt-f", Status: ticket.StatusInProgress, Deps: []string{"kt-a", "kt-gone"}},
+	}
+
+	assert.Equal(t, []string{"kt-c"}, newlyUnblocked(tickets, []string{"kt-a"}))
+	assert.Empty(t, newlyUnblocked(tickets, []string{"kt-b"}))
+}
+
+func TestRunCloseReportsUnblocked(t *testing.T) {
+	defer setupTestEnv(t)()
+	jsonFlag = true
+	defer func() { jsonFlag = false }()
+
+	dep := mkTicket(t, "kt-dep", "Dep", ticket.StatusOpen)
+	user := mkTicket(t, "kt-user", "User", ticket.StatusOpen)
+	user.Deps = []string{dep.ID}
+	require.NoError(t, Store.Save(user))
+
+	require.NoError(t, runClose(nil, []string{dep.ID}))
+
+	u, err := Store.Get(dep.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusClosed, u.Status)
+	assert.True(t, allDepsResolved(user))
+}
+
+func TestCloseWithOpenDeps(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { closeStrict, closeIgnoreDeps = false, false }()
+
+	dep := mkTicket(t, "kt-dep", "Dep", ticket.StatusOpen)
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+	c := mkTicket(t, "kt-c", "C", ticket.StatusOpen)
+	for _, tk := range []*ticket.Ticket{a, b, c} {
+		tk.Deps = []string{dep.ID}
+		require.NoError(t, Store.Save(tk))
+	}
+	assert.Equal(t, []string{dep.ID}, unresolvedDeps(a))
+
+	// Default: warn but close
+	require.NoError(t, runClose(nil, []string{a.ID}))
+	u, _ := Store.Get(a.ID)
+	assert.Equal(t, ticket.StatusClosed, u.Status)
+
+	// --strict refuses
+	closeStrict = true
+	require.NoError(t, runClose(nil, []string{b.ID}))
+	u, _ = Store.Get(b.ID)
+	assert.Equal(t, ticket.StatusOpen, u.Status)
+
+	// --ignore-deps overrides --strict
+	closeIgnoreDeps = true
+	require.NoError(t, runClose(nil, []string{c.ID}))
+	u, _ = Store.Get(c.ID)
+	assert.Equal(t, ticket.StatusClosed, u.Status)
+}
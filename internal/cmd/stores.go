@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/config"
+)
+
+var (
+	storeFlag  string
+	globalFlag bool
+)
+
+func init() {
+	// kt g ... is short for kt --global ...
+	g := aliasCommand("g", "--global", []string{"--global"})
+	g.Short = "Run a command on the global store (kt --global)"
+	rootCmd.AddCommand(g)
+}
+
+// storeDir returns the tickets directory to use: the one chosen with
+// --store or --global, else the default (KTICKET_DIR, else .ktickets at the
+// git root).
+func storeDir() (string, error) {
+	switch {
+	case globalFlag && storeFlag != "":
+		return "", fmt.Errorf("--global and --store cannot be used together")
+	case globalFlag:
+		return config.GlobalDir()
+	case storeFlag == "":
+		return config.Dir(), nil
+	}
+	stores := projectConfig().Stores
+	if path, ok := stores[storeFlag]; ok {
+		return ticketsDir(expandHome(path)), nil
+	}
+	path := expandHome(storeFlag)
+	if _, err := os.Stat(path); err != nil && !strings.ContainsRune(storeFlag, filepath.Separator) {
+		return "", fmt.Errorf("unknown store %q (expected a path or one of %v from stores in %s)",
+			storeFlag, slices.Sorted(maps.Keys(stores)), config.ProjectFile)
+	}
+	return ticketsDir(path), nil
+}
+
+// ticketsDir returns the tickets directory for path: its .ktickets
+// subdirectory if it is a repository that has one, else path itself.
+func ticketsDir(path string) string {
+	if filepath.Base(path) != config.DefaultDir {
+		sub := filepath.Join(path, config.DefaultDir)
+		if fi, err := os.Stat(sub); err == nil && fi.IsDir() {
+			return sub
+		}
+	}
+	return path
+}
+
+// expandHome replaces a leading ~ in path with the home directory.
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
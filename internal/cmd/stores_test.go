@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreDir(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { storeFlag = "" }()
+	repo := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(repo, config.DefaultDir), 0755))
+	writeProjectConfig(t, "stores:\n  team: "+repo+"\n  plain: /srv/tickets\n")
+	t.Setenv(config.EnvDir, "/from/env")
+
+	dir, err := storeDir()
+	require.NoError(t, err)
+	assert.Equal(t, "/from/env", dir, "default without --store")
+
+	for flag, want := range map[string]string{
+		"team":                   filepath.Join(repo, config.DefaultDir), // named repo
+		"plain":                  "/srv/tickets",                         // named tickets dir
+		repo:                     filepath.Join(repo, config.DefaultDir), // repo path
+		"./relative/.ktickets":   "./relative/.ktickets",
+		filepath.Join(repo, "x"): filepath.Join(repo, "x"),
+	} {
+		storeFlag = flag
+		dir, err := storeDir()
+		require.NoError(t, err, flag)
+		assert.Equal(t, want, dir, flag)
+	}
+
+	storeFlag = "nope"
+	_, err = storeDir()
+	assert.ErrorContains(t, err, `unknown store "nope" (expected a path or one of [plain team]`)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	globalFlag = true
+	defer func() { globalFlag = false }()
+	_, err = storeDir()
+	assert.ErrorContains(t, err, "--global and --store cannot be used together")
+	storeFlag = ""
+	dir, err = storeDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, config.DefaultDir), dir)
+}
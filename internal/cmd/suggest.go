@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var suggestAssigneeCmd = &cobra.Command{
+	Use:   "suggest-assignee <id> [path...]",
+	Short: "Suggest an assignee from the git history of related files",
+	Long: `Rank authors by how many commits they made to the given paths, or to
+the file paths mentioned in the ticket's description, design, and notes
+when none are given. The most active author is the suggestion.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSuggestAssignee,
+}
+
+func init() {
+	rootCmd.AddCommand(suggestAssigneeCmd)
+}
+
+type suggestResult struct {
+	ID         string       `json:"id"`
+	Paths      []string     `json:"paths"`
+	Suggested  string       `json:"suggested,omitempty"`
+	Candidates []git.Author `json:"candidates"`
+}
+
+func runSuggestAssignee(cmd *cobra.Command, args []string) error {
+	t, err := Store.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+	root, err := config.FindGitRoot()
+	if err != nil {
+		return err
+	}
+
+	result, err := suggestAssignee(t, root, args[1:])
+	if err != nil {
+		return err
+	}
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	if result.Suggested == "" {
+		fmt.Println("No commits touch those paths")
+		return nil
+	}
+	fmt.Printf("Suggested: %s\n", result.Suggested)
+	for _, a := range result.Candidates {
+		fmt.Printf("  %4d  %s\n", a.Commits, a.Name)
+	}
+	return nil
+}
+
+// suggestAssignee ranks the authors of paths (relative to the working
+// directory), or of the paths mentioned in t if none are given.
+func suggestAssignee(t *ticket.Ticket, root string, paths []string) (*suggestResult, error) {
+	if len(paths) == 0 {
+		paths = mentionedPaths(t, root)
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no file paths mentioned in %s; pass paths explicitly", t.ID)
+		}
+	} else {
+		// Make paths relative to the repository root, where git runs
+		paths = slices.Clone(paths)
+		for i, p := range paths {
+			abs, err := filepath.Abs(p)
+			if err != nil {
+				continue
+			}
+			if rel, err := filepath.Rel(root, abs); err == nil {
+				paths[i] = rel
+			}
+		}
+	}
+
+	authors, err := git.Authors(root, paths)
+	if err != nil {
+		return nil, err
+	}
+	result := &suggestResult{ID: t.ID, Paths: paths, Candidates: authors}
+	if result.Candidates == nil {
+		result.Candidates = []git.Author{}
+	}
+	if len(authors) > 0 {
+		result.Suggested = authors[0].Name
+	}
+	return result, nil
+}
+
+// pathLike matches words that could be file paths: they contain a slash or
+// a file extension.
+var pathLike = regexp.MustCompile("[\\w.-]*(?:/[\\w.-]+)+|[\\w-]+\\.[A-Za-z0-9]+")
+
+// mentionedPaths returns the paths mentioned in a ticket's text that exist
+// under root, relative to root.
+func mentionedPaths(t *ticket.Ticket, root string) []string {
+	text := strings.Join([]string{t.Title, t.Description, t.Design, t.Notes}, "\n")
+	var paths []string
+	for _, m := range pathLike.FindAllString(text, -1) {
+		m = strings.TrimRight(strings.TrimPrefix(m, "./"), ".")
+		if m == "" || slices.Contains(paths, m) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, m)); err == nil {
+			paths = append(paths, m)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentionedPaths(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "internal", "auth"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "internal", "auth", "session.go"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "Makefile.mk"), nil, 0644))
+
+	tk := &ticket.Ticket{
+		Title:       "Fix expiry in internal/auth/session.go",
+		Description: "See ./internal/auth/session.go and Makefile.mk. Not docs/missing.md or e.g. this.",
+	}
+	assert.Equal(t, []string{"internal/auth/session.go", "Makefile.mk"}, mentionedPaths(tk, root))
+}
+
+func TestSuggestAssignee(t *testing.T) {
+	defer setupTestEnv(t)()
+	dir := initGitRepo(t)
+	n := 0
+	write := func(name, author string) {
+		t.Helper()
+		n++
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(author+strconv.Itoa(n)), 0644))
+		_, err := git.Run("add", name)
+		require.NoError(t, err)
+		_, err = git.Run("commit", "-q", "--author", author+" <"+author+"@example.com>", "-m", name)
+		require.NoError(t, err)
+	}
+	write("login.go", "alice")
+	write("login.go", "bob")
+	write("login.go", "bob")
+	write("other.go", "alice")
+
+	tk := mkTicket(t, "kt-a1b2", "Login", ticket.StatusOpen)
+	tk.Description = "Broken in login.go"
+	require.NoError(t, Store.Save(tk))
+	result, err := suggestAssignee(tk, dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"login.go"}, result.Paths)
+	assert.Equal(t, "bob", result.Suggested)
+	assert.Equal(t, []git.Author{{Name: "bob", Commits: 2}, {Name: "alice", Commits: 1}}, result.Candidates)
+
+	result, err = suggestAssignee(tk, dir, []string{"other.go"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", result.Suggested)
+
+	require.NoError(t, runSuggestAssignee(nil, []string{"a1b2"}))
+
+	bare := mkTicket(t, "kt-c3d4", "Vague", ticket.StatusOpen)
+	assert.ErrorContains(t, runSuggestAssignee(nil, []string{bare.ID}), "no file paths")
+}
@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kostyay/kticket/internal/github"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync tickets with other trackers",
+}
+
+var syncGitHubCmd = &cobra.Command{
+	Use:   "github",
+	Short: "Two-way sync of status and notes with linked GitHub issues",
+	Long: `Sync every ticket with an external-ref of gh:<owner>/<repo>#<number>
+(as kt import github sets) for --repo, or of gh-<number>, against that
+issue in --repo:
+
+  - a ticket closed or reopened since the last sync closes or reopens
+    the issue, and notes added since the last sync are posted as comments
+  - an issue closed or reopened on GitHub since the last sync updates the
+    ticket's status
+  - if both sides changed status and disagree, nothing is changed and the
+    ticket is reported as a conflict; resolve it on one side and sync again
+
+The last synced state is kept in .ktickets/.sync/github.json; commit it
+with the tickets so every clone agrees on what was already pushed.
+Requires GITHUB_TOKEN (or GH_TOKEN) unless --dry-run is given.`,
+	Args: cobra.NoArgs,
+	RunE: runSyncGitHub,
+}
+
+var (
+	syncRepo   string
+	syncDryRun bool
+)
+
+func init() {
+	syncGitHubCmd.Flags().StringVar(&syncRepo, "repo", "", "Repository as owner/name (default: integrations.github_repo in config.yml)")
+	syncGitHubCmd.Flags().BoolVarP(&syncDryRun, "dry-run", "n", false, "Show what would change without changing anything")
+	syncCmd.AddCommand(syncGitHubCmd)
+	rootCmd.AddCommand(syncCmd)
+}
+
+// syncRecord is a ticket's state as of its last sync.
+type syncRecord struct {
+	Issue    int       `json:"issue"`
+	State    string    `json:"state"` // open or closed
+	Notes    int       `json:"notes"` // note entries already pushed
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+type syncAction struct {
+	ID     string `json:"id"`
+	Issue  int    `json:"issue"`
+	Action string `json:"action"` // close, reopen, comment
+}
+
+type syncResult struct {
+	DryRun    bool          `json:"dry_run,omitempty"`
+	Pushed    []syncAction  `json:"pushed"`
+	Pulled    []syncAction  `json:"pulled"`
+	Conflicts []statusError `json:"conflicts,omitempty"`
+	Errors    []statusError `json:"errors,omitempty"`
+}
+
+func runSyncGitHub(cmd *cobra.Command, args []string) error {
+	client := newGitHubClient()
+	if client.Token == "" && !syncDryRun {
+		return errors.New("sync needs a token: set GITHUB_TOKEN or GH_TOKEN")
+	}
+	repo, err := orConfig(syncRepo, "--repo", "github_repo", projectConfig().Integrations.GitHubRepo)
+	if err != nil {
+		return err
+	}
+	syncRepo = repo
+	if err := github.ValidateRepo(syncRepo); err != nil {
+		return err
+	}
+
+	records, err := loadSyncState()
+	if err != nil {
+		return err
+	}
+	tickets, err := Store.List()
+	if err != nil {
+		return err
+	}
+
+	result := syncResult{DryRun: syncDryRun, Pushed: []syncAction{}, Pulled: []syncAction{}}
+	for _, t := range tickets {
+		n, ok := githubIssueNumber(t.ExternalRef, syncRepo)
+		if !ok {
+			continue
+		}
+		if err := syncTicket(client, t, n, records, &result); err != nil {
+			result.Errors = append(result.Errors, statusError{ID: t.ID, Error: err.Error()})
+		}
+	}
+
+	if !syncDryRun {
+		if err := saveSyncState(records); err != nil {
+			return err
+		}
+	}
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	prefix := ""
+	if syncDryRun {
+		prefix = "would "
+	}
+	for _, a := range result.Pushed {
+		fmt.Printf("%s%s gh-%d (from %s)\n", prefix, a.Action, a.Issue, a.ID)
+	}
+	for _, a := range result.Pulled {
+		fmt.Printf("%s%s %s (from gh-%d)\n", prefix, a.Action, a.ID, a.Issue)
+	}
+	for _, c := range result.Conflicts {
+		Warnf("conflict: %s: %s", c.ID, c.Error)
+	}
+	for _, e := range result.Errors {
+		Errorf("%s: %s", e.ID, e.Error)
+	}
+	if len(result.Pushed)+len(result.Pulled)+len(result.Conflicts)+len(result.Errors) == 0 {
+		fmt.Println("Everything in sync")
+	}
+	return nil
+}
+
+// syncTicket reconciles one ticket with its issue, recording what was done
+// in result and the new sync state in records.
+func syncTicket(client *github.Client, t *ticket.Ticket, n int, records map[string]syncRecord, result *syncResult) error {
+	issue, err := client.Issue(syncRepo, n)
+	if err != nil {
+		return err
+	}
+
+	local := githubState(t.Status)
+	rec, seen := records[t.ID]
+	if !seen || rec.Issue != n {
+		// Never synced: with no common baseline, a disagreement is a conflict
+		if local != issue.State {
+			result.Conflicts = append(result.Conflicts, statusError{ID: t.ID,
+				Error: fmt.Sprintf("%s locally but %s on gh-%d (never synced)", local, issue.State, n)})
+			return nil
+		}
+		rec = syncRecord{Issue: n, State: issue.State}
+	}
+	localChanged := local != rec.State
+	remoteChanged := issue.State != rec.State || issue.ClosedAt.After(rec.SyncedAt)
+
+	var state string
+	switch {
+	case localChanged && remoteChanged && local != issue.State:
+		result.Conflicts = append(result.Conflicts, statusError{ID: t.ID,
+			Error: fmt.Sprintf("%s locally but %s on gh-%d", local, issue.State, n)})
+		return nil
+	case localChanged && local != issue.State:
+		if !syncDryRun {
+			if err := client.SetState(syncRepo, n, local); err != nil {
+				return err
+			}
+		}
+		result.Pushed = append(result.Pushed, syncAction{ID: t.ID, Issue: n, Action: stateAction(local)})
+		state = local
+	case remoteChanged && local != issue.State:
+		if !syncDryRun {
+			if err := setTicketState(t.ID, issue.State); err != nil {
+				return err
+			}
+		}
+		result.Pulled = append(result.Pulled, syncAction{ID: t.ID, Issue: n, Action: stateAction(issue.State)})
+		state = issue.State
+	default:
+		state = issue.State
+	}
+
+	notes := noteEntries(t.Notes)
+	pushed := min(rec.Notes, len(notes))
+	var commentErr error
+	for _, note := range notes[pushed:] {
+		if !syncDryRun {
+			if commentErr = client.Comment(syncRepo, n, note); commentErr != nil {
+				break
+			}
+		}
+		result.Pushed = append(result.Pushed, syncAction{ID: t.ID, Issue: n, Action: "comment"})
+		pushed++
+	}
+
+	// Record partial progress too, so posted comments are not repeated
+	records[t.ID] = syncRecord{Issue: n, State: state, Notes: pushed, SyncedAt: time.Now().UTC()}
+	return commentErr
+}
+
+// setTicketState opens or closes a ticket to match its issue. An
+// in_progress ticket counts as open and is left as is.
+func setTicketState(id, state string) error {
+	return Store.Update(id, func(t *ticket.Ticket) error {
+		if state == "closed" {
+			t.SetStatus(ticket.StatusClosed)
+		} else if t.Status == ticket.StatusClosed {
+			t.SetStatus(ticket.StatusOpen)
+		}
+		return nil
+	})
+}
+
+// githubState maps a ticket status to an issue state.
+func githubState(s ticket.Status) string {
+	if s == ticket.StatusClosed {
+		return "closed"
+	}
+	return "open"
+}
+
+func stateAction(state string) string {
+	if state == "closed" {
+		return "close"
+	}
+	return "reopen"
+}
+
+// githubIssueNumber parses an external-ref of the form gh:<repo>#<number>
+// for the given repo, or gh-<number>, which is taken to be in repo.
+func githubIssueNumber(ref, repo string) (int, bool) {
+	num, ok := strings.CutPrefix(ref, "gh-")
+	if rest, qualified := strings.CutPrefix(ref, "gh:"); qualified {
+		var in string
+		in, num, ok = strings.Cut(rest, "#")
+		ok = ok && strings.EqualFold(in, repo)
+	}
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(num)
+	return n, err == nil && n > 0
+}
+
+// noteHeader matches the timestamp line appendNote starts each note with.
+var noteHeader = regexp.MustCompile(`(?m)^\*\*\d{4}-\d{2}-\d{2}T[^*\n]+\*\*$`)
+
+// noteEntries splits a Notes section into its timestamped entries, oldest
+// first. Text before the first timestamp is its own entry.
+func noteEntries(notes string) []string {
+	var entries []string
+	add := func(s string) {
+		if s = strings.TrimSpace(s); s != "" {
+			entries = append(entries, s)
+		}
+	}
+	start := 0
+	for _, loc := range noteHeader.FindAllStringIndex(notes, -1) {
+		add(notes[start:loc[0]])
+		start = loc[0]
+	}
+	add(notes[start:])
+	return entries
+}
+
+func syncStatePath() string {
+	return filepath.Join(Store.Dir, ".sync", "github.json")
+}
+
+func loadSyncState() (map[string]syncRecord, error) {
+	records := make(map[string]syncRecord)
+	data, err := os.ReadFile(syncStatePath())
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parse sync state: %w", err)
+	}
+	return records, nil
+}
+
+func saveSyncState(records map[string]syncRecord) error {
+	path := syncStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create sync dir: %w", err)
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
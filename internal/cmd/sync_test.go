@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kostyay/kticket/internal/github"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIssues is an in-memory GitHub issues API.
+type fakeIssues struct {
+	mu       sync.Mutex
+	states   map[int]string
+	closedAt map[int]time.Time
+	comments map[int][]string
+}
+
+func (f *fakeIssues) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var n int
+	comments := strings.HasSuffix(r.URL.Path, "/comments")
+	fmt.Sscanf(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/repos/acme/app/issues/"), "/comments"), "%d", &n)
+	var body map[string]string
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	switch {
+	case r.Method == http.MethodPatch:
+		f.states[n] = body["state"]
+	case r.Method == http.MethodPost && comments:
+		f.comments[n] = append(f.comments[n], body["body"])
+	}
+	closed := "null"
+	if at, ok := f.closedAt[n]; ok {
+		closed = `"` + at.Format(time.RFC3339) + `"`
+	}
+	fmt.Fprintf(w, `{"number":%d,"state":%q,"closed_at":%s}`, n, f.states[n], closed)
+}
+
+func TestRunSyncGitHub(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { syncRepo, syncDryRun = "", false }()
+	gh := &fakeIssues{states: map[int]string{1: "open", 2: "open", 3: "closed"}, closedAt: map[int]time.Time{}, comments: map[int][]string{}}
+	fakeGitHub(t, gh.ServeHTTP)
+	orig := newGitHubClient
+	newGitHubClient = func() *github.Client {
+		c := orig()
+		c.Token = "secret"
+		return c
+	}
+
+	mk := func(id string, n int, status ticket.Status) {
+		tk := mkTicket(t, id, id, status)
+		tk.ExternalRef = fmt.Sprintf("gh-%d", n)
+		require.NoError(t, Store.Save(tk))
+	}
+	mk("kt-a", 1, ticket.StatusOpen)
+	mk("kt-b", 2, ticket.StatusOpen)
+	mk("kt-c", 3, ticket.StatusOpen) // disagrees before any sync
+	mkTicket(t, "kt-local", "No ref", ticket.StatusOpen)
+	other := mkTicket(t, "kt-other", "Other repository", ticket.StatusClosed)
+	other.ExternalRef = "gh:acme/web#1"
+	require.NoError(t, Store.Save(other))
+
+	syncRepo = "acme/app"
+	require.NoError(t, runSyncGitHub(nil, nil))
+
+	// Local close and note push; remote close pulls
+	require.NoError(t, Store.Update("kt-a", func(tk *ticket.Ticket) error {
+		tk.SetStatus(ticket.StatusClosed)
+		appendNote(tk, "fixed in abc123")
+		return nil
+	}))
+	gh.states[2] = "closed"
+
+	syncDryRun = true
+	require.NoError(t, runSyncGitHub(nil, nil))
+	assert.Equal(t, "open", gh.states[1])
+	b, _ := Store.Get("kt-b")
+	assert.Equal(t, ticket.StatusOpen, b.Status)
+
+	syncDryRun = false
+	require.NoError(t, runSyncGitHub(nil, nil))
+	assert.Equal(t, "closed", gh.states[1])
+	require.Len(t, gh.comments[1], 1)
+	assert.Contains(t, gh.comments[1][0], "fixed in abc123")
+	b, _ = Store.Get("kt-b")
+	assert.Equal(t, ticket.StatusClosed, b.Status)
+	c, _ := Store.Get("kt-c")
+	assert.Equal(t, ticket.StatusOpen, c.Status, "conflicts are left alone")
+
+	// Nothing new: no repeated comments
+	require.NoError(t, runSyncGitHub(nil, nil))
+	assert.Len(t, gh.comments[1], 1)
+
+	// Both sides changed and disagree: reopened locally, reopened and
+	// closed again on GitHub
+	require.NoError(t, Store.Update("kt-b", func(tk *ticket.Ticket) error {
+		tk.SetStatus(ticket.StatusOpen)
+		return nil
+	}))
+	gh.closedAt[2] = time.Now().Add(time.Minute)
+	require.NoError(t, runSyncGitHub(nil, nil))
+	assert.Equal(t, "closed", gh.states[2])
+	b, _ = Store.Get("kt-b")
+	assert.Equal(t, ticket.StatusOpen, b.Status)
+
+	records, err := loadSyncState()
+	require.NoError(t, err)
+	assert.Equal(t, syncRecord{Issue: 1, State: "closed", Notes: 1}, syncRecord{Issue: records["kt-a"].Issue,
+		State: records["kt-a"].State, Notes: records["kt-a"].Notes})
+	assert.NotContains(t, records, "kt-c")
+}
+
+func TestSyncGitHubNeedsToken(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { syncRepo = "" }()
+	fakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {})
+	syncRepo = "acme/app"
+	assert.ErrorContains(t, runSyncGitHub(nil, nil), "GITHUB_TOKEN")
+}
+
+func TestNoteEntries(t *testing.T) {
+	notes := "legacy text\n\n**2026-01-01T00:00:00Z**\n\nfirst\n\n**2026-01-02T00:00:00Z**\n\nsecond\nline"
+	assert.Equal(t, []string{
+		"legacy text",
+		"**2026-01-01T00:00:00Z**\n\nfirst",
+		"**2026-01-02T00:00:00Z**\n\nsecond\nline",
+	}, noteEntries(notes))
+	assert.Empty(t, noteEntries(""))
+}
+
+func TestGitHubIssueNumber(t *testing.T) {
+	for ref, want := range map[string]int{
+		"gh-12":          12,
+		"gh:acme/app#12": 12,
+		"gh:Acme/App#12": 12,
+		"gh:acme/web#12": 0,
+		"gh:acme/app":    0,
+		"gh-0":           0,
+		"gl:acme/app#12": 0,
+		"JIRA-12":        0,
+	} {
+		n, ok := githubIssueNumber(ref, "acme/app")
+		assert.Equal(t, want, n, ref)
+		assert.Equal(t, want != 0, ok, ref)
+	}
+}
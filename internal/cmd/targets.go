@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Other agents get the same kt.md and slash command templates as Claude,
+// wrapped in each agent's format. They are installed for the project, apart
+// from Codex's permission, which Codex only reads from the user's rules.
+
+// installCursor writes .cursor/rules/kt.mdc (kt.md as an always-applied
+// rule), the slash commands as .cursor/commands, and allows kt in
+// .cursor/cli.json.
+func installCursor() error {
+	ktMd, err := readTemplate("kt.md")
+	if err != nil {
+		return fmt.Errorf("read template: %w", err)
+	}
+	rule := "---\ndescription: kt ticket tracker reference\nalwaysApply: true\n---\n" + string(ktMd)
+	if err := writeAgentFile(filepath.Join(".cursor", "rules", "kt.mdc"), rule); err != nil {
+		return err
+	}
+	names, err := writeAgentCommands(filepath.Join(".cursor", "commands"), ".md", func(_, content string) string {
+		return content
+	})
+	if err != nil {
+		return err
+	}
+	if _, _, err := allowKt("cursor", false); err != nil {
+		return err
+	}
+	fmt.Printf("Installed Cursor rule, %s, and kt permission (.cursor/)\n", strings.Join(names, ", "))
+	return nil
+}
+
+// installWindsurf writes .windsurf/rules/kt.md (kt.md as an always-on
+// rule) and the slash commands as .windsurf/workflows. Windsurf keeps its
+// command allow list in user settings, so that is left to the user.
+func installWindsurf() error {
+	ktMd, err := readTemplate("kt.md")
+	if err != nil {
+		return fmt.Errorf("read template: %w", err)
+	}
+	if err := writeAgentFile(filepath.Join(".windsurf", "rules", "kt.md"), "---\ntrigger: always_on\n---\n"+string(ktMd)); err != nil {
+		return err
+	}
+	names, err := writeAgentCommands(filepath.Join(".windsurf", "workflows"), ".md", func(description, content string) string {
+		return fmt.Sprintf("---\ndescription: %q\n---\n", description) + content
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Installed Windsurf rule and workflows %s (.windsurf/)\n", strings.Join(names, ", "))
+	fmt.Println("To run kt without prompting, add kt to Cascade's allow list in Windsurf settings")
+	return nil
+}
+
+// installGemini writes the slash commands as .gemini/commands TOML files,
+// and in .gemini/settings.json adds kt.md to the context files and allows
+// kt as a shell command.
+func installGemini() error {
+	names, err := writeAgentCommands(filepath.Join(".gemini", "commands"), ".toml", func(description, content string) string {
+		return "description = " + tomlString(description) + "\nprompt = " + tomlString(content) + "\n"
+	})
+	if err != nil {
+		return err
+	}
+	settings := filepath.Join(".gemini", "settings.json")
+	for _, item := range []struct{ list, value string }{
+		{"context.fileName", "GEMINI.md"}, // keep the default context file
+		{"context.fileName", "kt.md"},
+	} {
+		if _, err := addJSONListItem(settings, item.list, item.value); err != nil {
+			return err
+		}
+	}
+	if _, _, err := allowKt("gemini", false); err != nil {
+		return err
+	}
+	fmt.Printf("Installed Gemini CLI commands %s, kt.md context, and kt permission (.gemini/)\n", strings.Join(names, ", "))
+	return nil
+}
+
+// installOpenCode adds the kt section to AGENTS.md, which OpenCode reads,
+// and allows kt in the project's opencode.json.
+func installOpenCode() error {
+	if err := installInstructions([]string{"AGENTS.md"}); err != nil {
+		return err
+	}
+	path, _, err := allowKt("opencode", false)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Allowed kt in %s\n", path)
+	return nil
+}
+
+// installCodex adds the kt section to AGENTS.md, which Codex reads, and
+// allows kt with a rule in the user's Codex rules (Codex has no project
+// rules).
+func installCodex() error {
+	if err := installInstructions([]string{"AGENTS.md"}); err != nil {
+		return err
+	}
+	path, _, err := allowKt("codex", true)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Allowed kt in %s\n", path)
+	return nil
+}
+
+// writeAgentCommands writes each slash command template into dir with the
+// extension ext, formatted by format from the command's description (its
+// first line) and content. It returns the command names.
+func writeAgentCommands(dir, ext string, format func(description, content string) string) ([]string, error) {
+	var names []string
+	for _, name := range slashCommands() {
+		content, err := readTemplate(name)
+		if err != nil {
+			return nil, fmt.Errorf("read template %s: %w", name, err)
+		}
+		description, _, _ := strings.Cut(strings.TrimSpace(string(content)), "\n")
+		path := filepath.Join(dir, strings.TrimSuffix(name, ".md")+ext)
+		if err := writeAgentFile(path, format(description, string(content))); err != nil {
+			return nil, err
+		}
+		names = append(names, commandName(name))
+	}
+	return names, nil
+}
+
+// writeAgentFile writes content to path, creating its directory.
+func writeAgentFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// tomlString quotes s as a TOML string: a multi-line literal string when
+// possible, so markdown stays readable.
+func tomlString(s string) string {
+	if !strings.Contains(s, "'''") && !strings.ContainsAny(s, "\r") {
+		if strings.Contains(s, "\n") {
+			return "'''\n" + s + "'''"
+		}
+		if !strings.Contains(s, "'") {
+			return "'" + s + "'"
+		}
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallOtherAgents(t *testing.T) {
+	defer setupTestEnv(t)()
+	t.Chdir(t.TempDir())
+
+	require.NoError(t, installCursor())
+	require.NoError(t, installWindsurf())
+	require.NoError(t, installGemini())
+	require.NoError(t, installGemini(), "reinstalling is idempotent")
+
+	rule, err := os.ReadFile(".cursor/rules/kt.mdc")
+	require.NoError(t, err)
+	assert.Contains(t, string(rule), "alwaysApply: true")
+	assert.Contains(t, string(rule), "kt - ticket tracker")
+	for _, path := range []string{".cursor/commands/kt-run.md", ".windsurf/rules/kt.md", ".windsurf/workflows/kt-run-all.md"} {
+		_, err := os.Stat(path)
+		assert.NoError(t, err, path)
+	}
+
+	workflow, err := os.ReadFile(".windsurf/workflows/kt-run.md")
+	require.NoError(t, err)
+	var front struct{ Description string }
+	frontmatter, _, _ := strings.Cut(strings.TrimPrefix(string(workflow), "---\n"), "---\n")
+	require.NoError(t, yaml.Unmarshal([]byte(frontmatter), &front))
+	assert.Equal(t, "Use kt to implement all tasks.", front.Description)
+
+	toml, err := os.ReadFile(".gemini/commands/kt-create.toml")
+	require.NoError(t, err)
+	assert.Contains(t, string(toml), "description = 'Create an epic and bite-sized tasks for this plan.'\nprompt = '''\n")
+
+	var cursor, gemini map[string]map[string][]string
+	data, err := os.ReadFile(".cursor/cli.json")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &cursor))
+	assert.Equal(t, []string{"Shell(kt)"}, cursor["permissions"]["allow"])
+	data, err = os.ReadFile(filepath.Join(".gemini", "settings.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &gemini))
+	assert.Equal(t, []string{"GEMINI.md", "kt.md"}, gemini["context"]["fileName"])
+	assert.Equal(t, []string{"run_shell_command(kt)"}, gemini["tools"]["allowed"])
+}
+
+func TestTOMLString(t *testing.T) {
+	assert.Equal(t, "'plain'", tomlString("plain"))
+	assert.Equal(t, "'''\na\nb\n'''", tomlString("a\nb\n"))
+	assert.Equal(t, `"it's"`, tomlString("it's"))
+	assert.Equal(t, `"x'''y\n\"z\""`, tomlString("x'''y\n\"z\""))
+}
+
+func TestInstallUnknownTarget(t *testing.T) {
+	installTargetFlag = []string{"emacs"}
+	defer func() { installTargetFlag = nil }()
+	assert.ErrorContains(t, runInstall(nil, nil), `unknown target "emacs"`)
+}
+
+func TestUninstall(t *testing.T) {
+	defer setupTestEnv(t)()
+	t.Chdir(t.TempDir())
+	claudeDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", claudeDir)
+	defer func() { uninstallTargets, uninstallScope = nil, "all" }()
+
+	require.NoError(t, writeKtMd("kt.md"))
+	require.NoError(t, os.MkdirAll(".claude", 0755))
+	require.NoError(t, os.WriteFile(".claude/settings.local.json", []byte(`{"permissions":{"allow":["Bash(ls:*)"]}}`), 0644))
+	for _, global := range []bool{false, true} {
+		require.NoError(t, installSlashCommands(global))
+		require.NoError(t, registerKtPermission(global))
+		require.NoError(t, registerClaudeHooks(global))
+	}
+	require.NoError(t, os.WriteFile(".claude/commands/mine.md", []byte("mine"), 0644))
+	require.NoError(t, installCursor())
+	require.NoError(t, installGemini())
+
+	// Only the global Claude settings
+	uninstallTargets, uninstallScope = []string{"claude"}, "global"
+	require.NoError(t, runUninstall(nil, nil))
+	_, err := os.Stat(filepath.Join(claudeDir, "commands"))
+	assert.True(t, os.IsNotExist(err), "empty commands dir removed")
+	_, err = os.Stat(".claude/commands/kt-run.md")
+	assert.NoError(t, err, "project commands kept")
+
+	uninstallTargets, uninstallScope = nil, "all"
+	require.NoError(t, runUninstall(nil, nil))
+	for _, path := range []string{"kt.md", ".claude/commands/kt-run.md", ".cursor/rules/kt.mdc", ".gemini/commands/kt-run.toml"} {
+		_, err := os.Stat(path)
+		assert.True(t, os.IsNotExist(err), path)
+	}
+	_, err = os.Stat(".claude/commands/mine.md")
+	assert.NoError(t, err, "other commands kept")
+
+	data, err := os.ReadFile(".claude/settings.local.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"permissions":{"allow":["Bash(ls:*)"]}}`, string(data))
+	data, err = os.ReadFile(filepath.Join(claudeDir, "settings.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"permissions":{}}`, string(data))
+	data, err = os.ReadFile(".gemini/settings.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"context":{"fileName":["GEMINI.md"]},"tools":{}}`, string(data))
+}
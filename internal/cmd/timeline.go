@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/ticket"
+)
+
+// activity is one entry in a ticket's timeline (kt show --timeline).
+type activity struct {
+	At     string `json:"at"`               // RFC 3339 in UTC; empty if unknown
+	Kind   string `json:"kind"`             // status, note, session, or commit
+	Author string `json:"author,omitempty"` // commit author or agent session
+	Text   string `json:"text"`
+	Commit string `json:"commit,omitempty"`
+}
+
+// timelineTicket is a ticket as kt show --timeline --json prints it.
+type timelineTicket struct {
+	*ticket.Ticket
+	Dependents []string   `json:"dependents,omitempty"`
+	Activity   []activity `json:"activity"`
+}
+
+// timeline gathers a ticket's status changes, notes, session history, and
+// linked commits, oldest first. Status changes between created and closed
+// come from the ticket file's git history, so only committed ones show.
+func timeline(t *ticket.Ticket) []activity {
+	items := []activity{{At: t.Created, Kind: "status", Text: "created"}}
+
+	// Outside a git repository there are no entries, only the stamps
+	entries, _ := ticketLog(Store.Path(t.ID))
+	closedInGit := false
+	for i, e := range entries {
+		if e.Created {
+			continue
+		}
+		for _, c := range e.Changes {
+			if c.Field != "status" {
+				continue
+			}
+			closedInGit = closedInGit || i == 0 && c.After == string(ticket.StatusClosed)
+			items = append(items, activity{At: e.Date, Kind: "status", Author: e.Author, Text: c.Before + " → " + c.After, Commit: e.SHA})
+		}
+	}
+	if t.Closed != "" && !closedInGit {
+		items = append(items, activity{At: t.Closed, Kind: "status", Text: "closed"})
+	}
+
+	items = append(items, splitNotes(t.Notes, t.Created)...)
+	for _, e := range t.History {
+		items = append(items, activity{At: e.At, Kind: "session", Author: e.Session, Text: e.Op})
+	}
+	for _, sha := range t.Commits {
+		info, err := git.Describe(sha)
+		if err != nil {
+			items = append(items, activity{Kind: "commit", Text: "(not in this repository)", Commit: sha})
+			continue
+		}
+		items = append(items, activity{At: info.Date, Kind: "commit", Author: info.Author, Text: info.Subject, Commit: sha})
+	}
+
+	return sortActivity(items)
+}
+
+// splitNotes splits a Notes section into one entry per timestamped note.
+// Text above the first timestamp is dated created, the ticket's creation.
+func splitNotes(notes, created string) []activity {
+	var items []activity
+	add := func(at, text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			items = append(items, activity{At: at, Kind: "note", Text: text})
+		}
+	}
+	stamps := noteStamp.FindAllStringSubmatchIndex(notes, -1)
+	if len(stamps) == 0 {
+		add(created, notes)
+		return items
+	}
+	add(created, notes[:stamps[0][0]])
+	for i, m := range stamps {
+		end := len(notes)
+		if i+1 < len(stamps) {
+			end = stamps[i+1][0]
+		}
+		add(notes[m[2]:m[3]], notes[m[1]:end])
+	}
+	return items
+}
+
+// sortActivity normalizes times to UTC and sorts items oldest first,
+// keeping the given order among equal times. Items without a readable
+// time go last.
+func sortActivity(items []activity) []activity {
+	parse := func(a activity) (time.Time, bool) {
+		at, err := time.Parse(time.RFC3339, a.At)
+		return at, err == nil
+	}
+	for i := range items {
+		if at, ok := parse(items[i]); ok {
+			items[i].At = at.UTC().Format(time.RFC3339)
+		}
+	}
+	slices.SortStableFunc(items, func(a, b activity) int {
+		ta, oka := parse(a)
+		tb, okb := parse(b)
+		switch {
+		case oka && okb:
+			return ta.Compare(tb)
+		case oka:
+			return -1
+		case okb:
+			return 1
+		}
+		return 0
+	})
+	return items
+}
+
+// writeTimeline writes a ticket with its notes, history, and commits
+// merged into one Activity section.
+func writeTimeline(w io.Writer, t *ticket.Ticket, dependents []string, items []activity) {
+	rest := *t
+	rest.Notes, rest.History, rest.Commits = "", nil, nil
+	writeTicket(w, &rest, dependents)
+
+	fmt.Fprintf(w, "\n## Activity\n")
+	for _, a := range items {
+		at := a.At
+		if at == "" {
+			at = "unknown"
+		}
+		line := fmt.Sprintf("%-20s  %-7s", at, a.Kind)
+		if a.Kind != "note" {
+			line += "  " + a.Text
+		}
+		if a.Commit != "" {
+			line += "  " + paint(ansiYellow, git.Commit{SHA: a.Commit}.Short())
+		}
+		if a.Author != "" {
+			line += "  " + paint(ansiDim, a.Author)
+		}
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+		if a.Kind == "note" {
+			for l := range strings.SplitSeq(renderBody(a.Text), "\n") {
+				fmt.Fprintln(w, strings.TrimRight("  "+l, " "))
+			}
+		}
+	}
+}
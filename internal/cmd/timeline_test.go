@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitNotes(t *testing.T) {
+	notes := "Picked up from standup\n\n**2026-01-02T10:00:00Z**\n\nFirst\n\n**2026-01-03T11:00:00Z**\n\nSecond\nline"
+	assert.Equal(t, []activity{
+		{At: "2026-01-01T00:00:00Z", Kind: "note", Text: "Picked up from standup"},
+		{At: "2026-01-02T10:00:00Z", Kind: "note", Text: "First"},
+		{At: "2026-01-03T11:00:00Z", Kind: "note", Text: "Second\nline"},
+	}, splitNotes(notes, "2026-01-01T00:00:00Z"))
+	assert.Empty(t, splitNotes("", "2026-01-01T00:00:00Z"))
+}
+
+func TestRunShowTimeline(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { showTimeline, showFields, jsonFlag = false, nil, false }()
+	dir := initGitRepo(t)
+	Store = store.New(filepath.Join(dir, ".ktickets"))
+	require.NoError(t, Store.EnsureDir())
+	path := Store.Path("kt-001")
+
+	tk := mkTicket(t, "kt-001", "Timeline", ticket.StatusOpen)
+	tk.Created = "2020-01-01T00:00:00Z"
+	require.NoError(t, Store.Save(tk))
+	_, err := git.CommitPaths(dir, "add kt-001", []string{path})
+	require.NoError(t, err)
+	tk.SetStatus(ticket.StatusInProgress)
+	tk.Notes = "**2020-01-02T00:00:00Z**\n\nlooking into it"
+	tk.History = []ticket.Event{{At: "2020-01-03T00:00:00+02:00", Session: "s1", Op: "set"}}
+	require.NoError(t, Store.Save(tk))
+	committed, err := git.CommitPaths(dir, "start kt-001", []string{path})
+	require.NoError(t, err)
+	require.True(t, committed)
+	head, err := git.ResolveCommit("HEAD")
+	require.NoError(t, err)
+	tk.Commits = []string{head, "0000000000000000000000000000000000000000"}
+	require.NoError(t, Store.Save(tk))
+
+	showTimeline, jsonFlag = true, true
+	var shown struct {
+		ID       string     `json:"id"`
+		Activity []activity `json:"activity"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(showOutput(t, "kt-001")), &shown))
+	assert.Equal(t, "kt-001", shown.ID)
+	var got []string
+	for _, a := range shown.Activity {
+		got = append(got, a.Kind+": "+a.Text)
+	}
+	assert.Equal(t, []string{
+		"status: created",
+		"note: looking into it",
+		"session: set",
+		// The two commits are made now, long after the stamps above
+		"status: open → in_progress",
+		"commit: start kt-001",
+		"commit: (not in this repository)",
+	}, got)
+	assert.Equal(t, "2020-01-02T22:00:00Z", shown.Activity[2].At)
+	assert.Equal(t, "test", shown.Activity[3].Author)
+
+	jsonFlag = false
+	out := showOutput(t, "kt-001")
+	assert.Contains(t, out, "## Activity\n2020-01-01T00:00:00Z  status   created\n")
+	assert.Contains(t, out, "2020-01-02T00:00:00Z  note\n  looking into it\n")
+	assert.NotContains(t, out, "## Notes")
+	assert.NotContains(t, out, "## History")
+
+	showFields = []string{"title"}
+	assert.ErrorContains(t, runShow(nil, []string{"kt-001"}), "--timeline cannot be used")
+}
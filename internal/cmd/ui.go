@@ -0,0 +1,449 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Interactive terminal UI for browsing and triaging tickets",
+	Long: `Browse tickets in a list with a detail pane and change them with single
+keys. The list refreshes as tickets change, including from other kt
+processes. Each change is its own kt undo step.
+
+  j/k, ↑/↓   move             s  start         /  search
+  PgUp/PgDn  page             c  close         f  cycle status filter
+  g/G        top/bottom       p  pass tests    esc  clear search
+  ^u/^d      scroll detail    o  reopen        r  reload
+                              n  add note      q  quit`,
+	Args: cobra.NoArgs,
+	RunE: runUI,
+}
+
+// uiRefreshInterval is how often kt ui checks the store for changes.
+const uiRefreshInterval = time.Second
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+}
+
+func runUI(cmd *cobra.Command, args []string) error {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return fmt.Errorf("kt ui needs a terminal")
+	}
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	m := newUIModel(Store.Dir, Store.Watch(ctx, uiRefreshInterval))
+	_, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	return err
+}
+
+// uiFilters are the status filters cycled with f.
+var uiFilters = []struct {
+	name  string
+	match func(ticket.Status) bool
+}{
+	{"active", func(s ticket.Status) bool { return s != ticket.StatusClosed }},
+	{"open", func(s ticket.Status) bool { return s == ticket.StatusOpen }},
+	{"in_progress", func(s ticket.Status) bool { return s == ticket.StatusInProgress }},
+	{"closed", func(s ticket.Status) bool { return s == ticket.StatusClosed }},
+	{"all", func(ticket.Status) bool { return true }},
+}
+
+type uiMode int
+
+const (
+	uiBrowse uiMode = iota
+	uiSearch
+	uiNote
+)
+
+// uiChangedMsg reports that the store changed on disk.
+type uiChangedMsg struct{}
+
+type uiModel struct {
+	dir   string
+	watch <-chan struct{}
+
+	tickets    []*ticket.Ticket
+	byID       map[string]*ticket.Ticket
+	dependents map[string][]string
+	visible    []*ticket.Ticket
+
+	cursor       int
+	offset       int
+	detailOffset int
+	width        int
+	height       int
+
+	filter  int
+	query   string
+	mode    uiMode
+	input   string
+	message string
+}
+
+func newUIModel(dir string, watch <-chan struct{}) *uiModel {
+	m := &uiModel{dir: dir, watch: watch, width: 80, height: 24}
+	m.reload()
+	return m
+}
+
+func (m *uiModel) Init() tea.Cmd {
+	return m.waitForChange()
+}
+
+// waitForChange delivers the next store change as a uiChangedMsg.
+func (m *uiModel) waitForChange() tea.Cmd {
+	if m.watch == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, ok := <-m.watch; !ok {
+			return nil
+		}
+		return uiChangedMsg{}
+	}
+}
+
+// reload re-reads the store, keeping the selection on the same ticket.
+func (m *uiModel) reload() {
+	selected := ""
+	if t := m.selected(); t != nil {
+		selected = t.ID
+	}
+	tickets, err := store.New(m.dir).List()
+	if err != nil {
+		m.message = err.Error()
+		return
+	}
+	m.tickets = tickets
+	m.byID = make(map[string]*ticket.Ticket, len(tickets))
+	for _, t := range tickets {
+		m.byID[t.ID] = t
+	}
+	m.dependents = dependentIndex(tickets)
+	m.applyFilter(selected)
+}
+
+// applyFilter recomputes the visible tickets and moves the cursor to
+// selected if it is still visible.
+func (m *uiModel) applyFilter(selected string) {
+	match := uiFilters[m.filter].match
+	query := strings.ToLower(m.query)
+	m.visible = m.visible[:0]
+	for _, t := range m.tickets {
+		if !match(t.Status) {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(t.ID+" "+t.Title+" "+t.Assignee+" "+strings.Join(t.Labels, " ")), query) {
+			continue
+		}
+		m.visible = append(m.visible, t)
+	}
+	sort.SliceStable(m.visible, func(i, j int) bool {
+		a, b := m.visible[i], m.visible[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.Created < b.Created
+	})
+
+	cursor := min(m.cursor, max(len(m.visible)-1, 0))
+	for i, t := range m.visible {
+		if t.ID == selected {
+			cursor = i
+		}
+	}
+	m.moveTo(cursor)
+}
+
+func (m *uiModel) selected() *ticket.Ticket {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return nil
+	}
+	return m.visible[m.cursor]
+}
+
+// moveTo selects row i, clamped, scrolling the list to keep it in view.
+func (m *uiModel) moveTo(i int) {
+	i = max(0, min(i, len(m.visible)-1))
+	if i != m.cursor {
+		m.detailOffset = 0
+	}
+	m.cursor = i
+	rows := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+	m.offset = max(0, m.offset)
+}
+
+// listHeight and detailHeight split the screen between the header, the
+// list, a separator, the detail pane, and the footer.
+func (m *uiModel) listHeight() int {
+	return max(3, (m.height-3)*2/5)
+}
+
+func (m *uiModel) detailHeight() int {
+	return max(1, m.height-3-m.listHeight())
+}
+
+func (m *uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.moveTo(m.cursor)
+	case uiChangedMsg:
+		m.reload()
+		return m, m.waitForChange()
+	case tea.KeyMsg:
+		if m.mode != uiBrowse {
+			m.handleInput(msg)
+			return m, nil
+		}
+		return m, m.handleKey(msg)
+	}
+	return m, nil
+}
+
+// handleKey handles a key in browse mode.
+func (m *uiModel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	m.message = ""
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		m.moveTo(m.cursor - 1)
+	case "down", "j":
+		m.moveTo(m.cursor + 1)
+	case "pgup":
+		m.moveTo(m.cursor - m.listHeight())
+	case "pgdown":
+		m.moveTo(m.cursor + m.listHeight())
+	case "g", "home":
+		m.moveTo(0)
+	case "G", "end":
+		m.moveTo(len(m.visible) - 1)
+	case "ctrl+u":
+		m.detailOffset = max(0, m.detailOffset-m.detailHeight()/2)
+	case "ctrl+d":
+		m.detailOffset += m.detailHeight() / 2
+	case "s":
+		m.act("start", func(t *ticket.Ticket) error {
+			t.SetStatus(ticket.StatusInProgress)
+			return nil
+		})
+	case "c":
+		m.act("close", func(t *ticket.Ticket) error {
+			if err := t.CanClose(); err != nil {
+				return err
+			}
+			t.SetStatus(ticket.StatusClosed)
+			return nil
+		})
+	case "o":
+		m.act("reopen", func(t *ticket.Ticket) error {
+			t.SetStatus(ticket.StatusOpen)
+			return nil
+		})
+	case "p":
+		m.act("pass", func(t *ticket.Ticket) error {
+			t.TestsPassed = true
+			return nil
+		})
+	case "n":
+		if m.selected() != nil {
+			m.mode, m.input = uiNote, ""
+		}
+	case "/":
+		m.mode, m.input = uiSearch, m.query
+	case "f":
+		m.filter = (m.filter + 1) % len(uiFilters)
+		m.keepSelection(m.applyFilter)
+	case "esc":
+		m.query = ""
+		m.keepSelection(m.applyFilter)
+	case "r":
+		m.reload()
+	}
+	return nil
+}
+
+// keepSelection runs a refilter that should keep the current ticket selected.
+func (m *uiModel) keepSelection(refilter func(selected string)) {
+	selected := ""
+	if t := m.selected(); t != nil {
+		selected = t.ID
+	}
+	refilter(selected)
+}
+
+// handleInput edits the search or note line.
+func (m *uiModel) handleInput(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		if m.mode == uiSearch {
+			m.query = ""
+			m.keepSelection(m.applyFilter)
+		}
+		m.mode = uiBrowse
+		return
+	case tea.KeyEnter:
+		if m.mode == uiNote && strings.TrimSpace(m.input) != "" {
+			note := strings.TrimSpace(m.input)
+			m.act("add-note", func(t *ticket.Ticket) error {
+				appendNote(t, note)
+				return nil
+			})
+		}
+		m.mode = uiBrowse
+		return
+	case tea.KeyBackspace:
+		if r := []rune(m.input); len(r) > 0 {
+			m.input = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.input += string(msg.Runes)
+	case tea.KeyCtrlC:
+		m.mode = uiBrowse
+		return
+	}
+	if m.mode == uiSearch {
+		m.query = m.input
+		m.keepSelection(m.applyFilter)
+	}
+}
+
+// act applies fn to the selected ticket as its own operation, like a
+// separate kt command: one undo step, auto-commit, and notification.
+func (m *uiModel) act(op string, fn func(*ticket.Ticket) error) {
+	t := m.selected()
+	if t == nil {
+		return
+	}
+	s := store.New(m.dir)
+	s.SetOperation(op + " " + t.ID)
+	lt, err := s.GetForUpdate(t.ID)
+	if err != nil {
+		m.message = "error: " + err.Error()
+		return
+	}
+	if err := fn(lt.Ticket); err != nil {
+		lt.Release()
+		m.message = "error: " + err.Error()
+		return
+	}
+	if err := lt.SaveAndRelease(); err != nil {
+		m.message = "error: " + err.Error()
+		return
+	}
+	advanceDependents(s)
+	autoCommit(s)
+	notifyChanges(s)
+
+	m.message = fmt.Sprintf("%s: %s", op, t.ID)
+	if op == "close" {
+		if open := m.openDeps(lt.Ticket); len(open) > 0 {
+			m.message += " (open deps: " + strings.Join(open, ", ") + ")"
+		}
+	}
+	m.reload()
+}
+
+// openDeps returns the deps of t that are not closed.
+func (m *uiModel) openDeps(t *ticket.Ticket) []string {
+	var open []string
+	for _, id := range t.Deps {
+		if dep, ok := m.byID[id]; !ok || dep.Status != ticket.StatusClosed {
+			open = append(open, id)
+		}
+	}
+	return open
+}
+
+func (m *uiModel) View() string {
+	var b strings.Builder
+
+	header := fmt.Sprintf("kt  [%s] %d tickets", uiFilters[m.filter].name, len(m.visible))
+	if m.query != "" {
+		header += fmt.Sprintf("  search: %q", m.query)
+	}
+	b.WriteString(paint(ansiBold, header) + "\n")
+
+	rows := m.listHeight()
+	idWidth := 0
+	for _, t := range m.visible {
+		idWidth = max(idWidth, len(t.ID))
+	}
+	for i := m.offset; i < m.offset+rows; i++ {
+		if i < len(m.visible) {
+			b.WriteString(m.row(m.visible[i], idWidth, i == m.cursor))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(paint(ansiDim, strings.Repeat("─", max(m.width, 1))) + "\n")
+
+	var detail []string
+	if t := m.selected(); t != nil {
+		var buf bytes.Buffer
+		writeTicket(&buf, t, m.dependents[t.ID])
+		detail = strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	}
+	m.detailOffset = max(0, min(m.detailOffset, len(detail)-m.detailHeight()))
+	for i := range m.detailHeight() {
+		if j := m.detailOffset + i; j < len(detail) {
+			b.WriteString(detail[j])
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.footer())
+	return b.String()
+}
+
+// row renders one list line.
+func (m *uiModel) row(t *ticket.Ticket, idWidth int, selected bool) string {
+	marker := "  "
+	id := fmt.Sprintf("%-*s", idWidth, t.ID)
+	if selected {
+		marker = "> "
+		id = paint(ansiBold, id)
+	}
+	line := fmt.Sprintf("%s%s  %s  %s  %-7s %s", marker, id,
+		paint(priorityColor(t.Priority), fmt.Sprintf("P%d", t.Priority)),
+		paintStatus(t.Status, fmt.Sprintf("%-11s", t.Status)), t.Type, t.Title)
+	if t.Status != ticket.StatusClosed && len(m.openDeps(t)) > 0 {
+		line += paint(ansiRed, " [blocked]")
+	}
+	return line
+}
+
+func (m *uiModel) footer() string {
+	switch m.mode {
+	case uiSearch:
+		return "/" + m.input + "█"
+	case uiNote:
+		return "note: " + m.input + "█  (enter to save, esc to cancel)"
+	}
+	if m.message != "" {
+		return m.message
+	}
+	return paint(ansiDim, "j/k move  s start  c close  p pass  o reopen  n note  / search  f filter  q quit")
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// press sends keys to the model, one KeyMsg per key name.
+func press(m *uiModel, keys ...string) {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m.Update(msg)
+	}
+}
+
+func TestUIActions(t *testing.T) {
+	defer setupTestEnv(t)()
+	tk := mkTicket(t, "kt-a", "Alpha", ticket.StatusOpen)
+	tk.Tests = "- TestAlpha"
+	require.NoError(t, Store.Save(tk))
+
+	m := newUIModel(Store.Dir, nil)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	require.Len(t, m.visible, 1)
+
+	press(m, "s")
+	got, err := Store.Get("kt-a")
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusInProgress, got.Status)
+
+	// Close is refused until tests pass
+	press(m, "c")
+	assert.Contains(t, m.message, "tests not passed")
+	press(m, "p", "c")
+	got, err = Store.Get("kt-a")
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusClosed, got.Status)
+	assert.Empty(t, m.visible, "closed tickets leave the active filter")
+
+	press(m, "f", "f", "f")
+	require.Len(t, m.visible, 1)
+	press(m, "n", "looks", " ", "good", "enter")
+	got, err = Store.Get("kt-a")
+	require.NoError(t, err)
+	assert.Contains(t, got.Notes, "looks good")
+	assert.Contains(t, m.View(), "looks good")
+
+	// Each action is its own undo step
+	_, err = Store.Undo()
+	require.NoError(t, err)
+	got, err = Store.Get("kt-a")
+	require.NoError(t, err)
+	assert.Empty(t, got.Notes)
+	assert.Equal(t, ticket.StatusClosed, got.Status)
+}
+
+func TestUISearchAndRefresh(t *testing.T) {
+	defer setupTestEnv(t)()
+	mkTicket(t, "kt-a", "Fix login", ticket.StatusOpen)
+	mkTicket(t, "kt-b", "Write docs", ticket.StatusOpen)
+
+	m := newUIModel(Store.Dir, nil)
+	press(m, "j")
+	assert.Equal(t, "kt-b", m.selected().ID)
+
+	press(m, "/", "l", "o", "g")
+	require.Len(t, m.visible, 1)
+	assert.Equal(t, "kt-a", m.selected().ID)
+	press(m, "enter")
+	assert.Contains(t, m.View(), `search: "log"`)
+	press(m, "esc")
+	assert.Len(t, m.visible, 2)
+	assert.Equal(t, "kt-a", m.selected().ID, "selection survives refiltering")
+
+	// Changes from other processes show up on the next change signal
+	mkTicket(t, "kt-c", "Triage", ticket.StatusOpen)
+	m.Update(uiChangedMsg{})
+	assert.Len(t, m.visible, 3)
+	assert.Equal(t, "kt-a", m.selected().ID)
+}
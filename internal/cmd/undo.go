@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the last mutating operation",
+	Long: `Restore every ticket touched by the most recent mutating command to its
+previous state. Snapshots are kept in .ktickets/.undo/ (last 50 operations);
+running undo repeatedly walks further back.`,
+	Args: cobra.NoArgs,
+	RunE: runUndo,
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	result, err := Store.Undo()
+	if err != nil {
+		return err
+	}
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+
+	fmt.Printf("Undid: %s\n", result.Operation)
+	if len(result.Restored) > 0 {
+		fmt.Printf("Restored: %s\n", strings.Join(result.Restored, ", "))
+	}
+	if len(result.Deleted) > 0 {
+		fmt.Printf("Removed: %s\n", strings.Join(result.Deleted, ", "))
+	}
+	return nil
+}
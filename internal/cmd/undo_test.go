@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunUndo(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	tk := mkTicket(t, "kt-001", "Task", ticket.StatusOpen)
+
+	// Simulate a separate `kt close` invocation
+	Store = store.New(Store.Dir)
+	require.NoError(t, setStatusMultiple([]string{tk.ID}, ticket.StatusClosed, true))
+
+	Store = store.New(Store.Dir)
+	require.NoError(t, runUndo(nil, nil))
+
+	updated, _ := Store.Get(tk.ID)
+	assert.Equal(t, ticket.StatusOpen, updated.Status)
+}
+
+func TestRunUndoJSON(t *testing.T) {
+	defer setupTestEnv(t)()
+	jsonFlag = true
+	defer func() { jsonFlag = false }()
+
+	mkTicket(t, "kt-001", "Task", ticket.StatusOpen)
+
+	require.NoError(t, runUndo(nil, nil))
+	err := runUndo(nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nothing to undo")
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/spf13/cobra"
+)
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove kt.md, slash commands, permissions, and hooks installed by kt install",
+	Long: `Reverse kt install: remove kt.md and its section in CLAUDE.md and
+AGENTS.md, the slash commands, the kt permission and hooks from Claude's
+global and project settings, and the files and settings kt install wrote
+for other agents. Other settings are left alone.
+Use --target and --scope to remove less. Git hooks are removed with
+kt install hooks --uninstall.`,
+	Args: cobra.NoArgs,
+	RunE: runUninstall,
+}
+
+var (
+	uninstallTargets []string
+	uninstallScope   string
+)
+
+func init() {
+	uninstallCmd.Flags().StringSliceVar(&uninstallTargets, "target", nil, "Agents to remove: "+installTargetNames()+" (default: all)")
+	uninstallCmd.Flags().StringVar(&uninstallScope, "scope", "all", "Agent settings to clean: project, global, or all")
+	rootCmd.AddCommand(uninstallCmd)
+}
+
+func runUninstall(cmd *cobra.Command, args []string) error {
+	targets := uninstallTargets
+	if len(targets) == 0 {
+		for _, t := range installTargets {
+			targets = append(targets, t.name)
+		}
+	}
+	for _, name := range targets {
+		if !slices.ContainsFunc(installTargets, func(t installTarget) bool { return t.name == name }) {
+			return fmt.Errorf("unknown target %q (expected one of %s)", name, installTargetNames())
+		}
+	}
+	var scopes []bool // global?
+	switch uninstallScope {
+	case "project":
+		scopes = []bool{false}
+	case "global":
+		scopes = []bool{true}
+	case "all":
+		scopes = []bool{false, true}
+	default:
+		return fmt.Errorf("invalid --scope %q (expected project, global, or all)", uninstallScope)
+	}
+
+	u := &uninstaller{}
+	if len(uninstallTargets) == 0 {
+		u.removeFile("kt.md")
+		for _, path := range instructionFiles {
+			u.removeSection(path)
+		}
+	}
+	commands := slashCommands()
+	for _, target := range targets {
+		if p := permissionProfileFor(target); p != nil {
+			for _, global := range scopes {
+				if path := p.path(global); path != "" {
+					u.removePermission(p, path)
+				}
+			}
+		}
+		switch target {
+		case "claude":
+			for _, global := range scopes {
+				dir, settings := filepath.Join(".claude", "commands"), claudeSettingsPath(global)
+				if global {
+					dir = filepath.Join(getClaudeConfigDir(), "commands")
+				}
+				u.removeCommands(dir, commands, ".md")
+				for _, h := range claudeHooks {
+					u.removeHook(settings, h)
+				}
+			}
+		case "cursor":
+			u.removeFile(filepath.Join(".cursor", "rules", "kt.mdc"))
+			u.removeCommands(filepath.Join(".cursor", "commands"), commands, ".md")
+		case "windsurf":
+			u.removeFile(filepath.Join(".windsurf", "rules", "kt.md"))
+			u.removeCommands(filepath.Join(".windsurf", "workflows"), commands, ".md")
+		case "gemini":
+			u.removeCommands(filepath.Join(".gemini", "commands"), commands, ".toml")
+			u.removeListItem(filepath.Join(".gemini", "settings.json"), "context.fileName", "kt.md")
+		}
+	}
+
+	if len(u.removed) == 0 && u.err == nil {
+		fmt.Println("Nothing to remove")
+	}
+	return u.err
+}
+
+// uninstaller removes what kt install wrote, printing each removal and
+// keeping the first error.
+type uninstaller struct {
+	removed []string
+	err     error
+}
+
+func (u *uninstaller) fail(err error) {
+	if u.err == nil {
+		u.err = err
+	}
+}
+
+// removeFile removes path if it exists.
+func (u *uninstaller) removeFile(path string) {
+	err := os.Remove(path)
+	switch {
+	case err == nil:
+		u.removed = append(u.removed, path)
+		fmt.Printf("Removed %s\n", path)
+	case !errors.Is(err, os.ErrNotExist):
+		u.fail(err)
+	}
+}
+
+// removeCommands removes the slash command files for templates from dir,
+// and dir itself if that leaves it empty.
+func (u *uninstaller) removeCommands(dir string, templates []string, ext string) {
+	for _, name := range templates {
+		u.removeFile(filepath.Join(dir, strings.TrimSuffix(name, ".md")+ext))
+	}
+	_ = os.Remove(dir) // only succeeds if empty
+}
+
+// removeListItem removes item from the list at the dotted path in the JSON
+// file, dropping the list if it ends up empty.
+func (u *uninstaller) removeListItem(path, list, item string) {
+	removed, err := removeJSONListItem(path, list, item)
+	if err != nil {
+		u.fail(err)
+	} else if removed {
+		u.removed = append(u.removed, path)
+		fmt.Printf("Removed %s from %s in %s\n", item, list, path)
+	}
+}
+
+// removePermission removes kt's permission from an agent's settings file.
+func (u *uninstaller) removePermission(p *permissionProfile, path string) {
+	removed, err := p.remove(path)
+	if err != nil {
+		u.fail(err)
+	} else if removed {
+		u.removed = append(u.removed, path)
+		fmt.Printf("Removed kt permission from %s\n", path)
+	}
+}
+
+// removeSection removes the kt section from an agent instruction file.
+func (u *uninstaller) removeSection(path string) {
+	removed, err := removeSection(path)
+	if err != nil {
+		u.fail(err)
+	} else if removed {
+		u.removed = append(u.removed, path)
+		fmt.Printf("Removed kt section from %s\n", path)
+	}
+}
+
+// removeHook removes a Claude hook kt installed from the settings file.
+func (u *uninstaller) removeHook(path string, h claudeHook) {
+	removed, err := removeClaudeHook(path, h)
+	if err != nil {
+		u.fail(err)
+	} else if removed {
+		u.removed = append(u.removed, path)
+		fmt.Printf("Removed %s hook from %s\n", h.event, path)
+	}
+}
+
+// removeJSONListItem removes item from the list at the dotted path in the
+// JSON file, if the file has it. It reports whether the file changed.
+func removeJSONListItem(path, list, item string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read settings: %w", err)
+	}
+	settings, err := gabs.ParseJSON(data)
+	if err != nil {
+		return false, fmt.Errorf("parse %s: %w", path, err)
+	}
+	existing, ok := settings.Path(list).Data().([]any)
+	if !ok {
+		return false, nil
+	}
+	kept := slices.DeleteFunc(slices.Clone(existing), func(v any) bool { return v == item })
+	if len(kept) == len(existing) {
+		return false, nil
+	}
+	if len(kept) == 0 {
+		err = settings.DeleteP(list)
+	} else {
+		_, err = settings.SetP(kept, list)
+	}
+	if err != nil {
+		return false, fmt.Errorf("update %s: %w", list, err)
+	}
+	if err := os.WriteFile(path, settings.BytesIndent("", "  "), 0644); err != nil {
+		return false, fmt.Errorf("write settings: %w", err)
+	}
+	return true, nil
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/kostyay/kticket/internal/ticket"
@@ -13,40 +17,119 @@ import (
 const (
 	waitPollInterval      = 2 * time.Second
 	waitHeartbeatInterval = 30 * time.Second
+
+	// waitTimeoutExit is the exit status when --timeout expires, as for
+	// timeout(1).
+	waitTimeoutExit = 124
 )
 
 var waitCmd = &cobra.Command{
-	Use:   "wait <id>",
-	Short: "Block until a ticket is closed",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runWait,
+	Use:   "wait <id>...",
+	Short: "Block until tickets are closed (or match a condition)",
+	Long: `Block until every ticket given is closed (or with --any, until one is),
+printing each ticket as it closes. With --json, prints the closed ticket,
+or with several IDs the list of closed tickets.
+
+--until waits for a condition other than closed, in kt ls --filter syntax:
+status=in_progress, tests_passed=true, or assignee=ann and status=closed.
+
+With --timeout, gives up after that long and exits with status 124.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runWait,
+	// A timeout is not a usage error
+	SilenceUsage: true,
 }
 
+var (
+	waitAll     bool
+	waitAny     bool
+	waitTimeout time.Duration
+	waitUntil   string
+)
+
 func init() {
+	waitCmd.Flags().BoolVar(&waitAll, "all", false, "Wait until all tickets are closed (the default)")
+	waitCmd.Flags().BoolVar(&waitAny, "any", false, "Wait until any ticket is closed")
+	waitCmd.MarkFlagsMutuallyExclusive("all", "any")
+	waitCmd.Flags().StringVar(&waitUntil, "until", "", "Condition to wait for, e.g. status=in_progress or tests_passed=true (default: status=closed)")
+	waitCmd.Flags().DurationVar(&waitTimeout, "timeout", 0, "Give up after this long (e.g. 30m) with exit status 124 (default: wait forever)")
 	rootCmd.AddCommand(waitCmd)
 }
 
 func runWait(cmd *cobra.Command, args []string) error {
-	return runWaitWithClock(cmd.Context(), args[0], time.NewTicker, time.NewTicker)
+	ctx := cmd.Context()
+	if waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, waitTimeout)
+		defer cancel()
+	}
+	err := runWaitWithClock(ctx, args, time.NewTicker, time.NewTicker)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &exitError{code: waitTimeoutExit, err: fmt.Errorf("timed out after %s: %w", waitTimeout, err)}
+	}
+	return err
 }
 
 type tickerFactory func(d time.Duration) *time.Ticker
 
 func runWaitWithClock(
 	ctx context.Context,
-	id string,
+	ids []string,
 	pollFactory tickerFactory,
 	heartbeatFactory tickerFactory,
 ) error {
-	t, err := Store.Resolve(id)
+	until, err := ticket.ParseFilter(cmp.Or(waitUntil, "status=closed"))
 	if err != nil {
-		return err
+		return fmt.Errorf("invalid --until: %w", err)
 	}
 
-	resolvedID := t.ID
+	var pending []string
+	for _, id := range ids {
+		t, err := Store.Resolve(id)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(pending, t.ID) {
+			pending = append(pending, t.ID)
+		}
+	}
+
+	var met []*ticket.Ticket
+	// check moves tickets that now meet the condition from pending to met,
+	// reporting whether the wait is over
+	check := func() (bool, error) {
+		for _, id := range slices.Clone(pending) {
+			t, err := Store.Get(id)
+			if err != nil {
+				return false, fmt.Errorf("read ticket %s: %w", id, err)
+			}
+			if !until.Match(t) {
+				continue
+			}
+			pending = slices.DeleteFunc(pending, func(p string) bool { return p == id })
+			met = append(met, t)
+			if !IsJSON() {
+				fmt.Printf("%s → %s\n", t.ID, cmp.Or(waitUntil, string(t.Status)))
+			}
+		}
+		return len(pending) == 0 || (waitAny && len(met) > 0), nil
+	}
+	done := func() error {
+		if !IsJSON() {
+			return nil
+		}
+		if len(ids) == 1 {
+			return PrintJSON(met[0])
+		}
+		return PrintJSON(met)
+	}
 
-	if t.Status == ticket.StatusClosed {
-		return printWaitResult(t)
+	over, err := check()
+	if err != nil {
+		return err
+	}
+	if over {
+		return done()
 	}
 
 	poll := pollFactory(waitPollInterval)
@@ -57,27 +140,19 @@ func runWaitWithClock(
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return fmt.Errorf("still waiting for %s: %w", strings.Join(pending, ", "), ctx.Err())
 		case <-heartbeat.C:
 			if !IsJSON() {
-				fmt.Fprintln(os.Stderr, "waiting...")
+				fmt.Fprintf(os.Stderr, "waiting for %s...\n", strings.Join(pending, ", "))
 			}
 		case <-poll.C:
-			t, err = Store.Get(resolvedID)
+			over, err := check()
 			if err != nil {
-				return fmt.Errorf("read ticket %s: %w", resolvedID, err)
+				return err
 			}
-			if t.Status == ticket.StatusClosed {
-				return printWaitResult(t)
+			if over {
+				return done()
 			}
 		}
 	}
 }
-
-func printWaitResult(t *ticket.Ticket) error {
-	if IsJSON() {
-		return PrintJSON(t)
-	}
-	fmt.Printf("%s → %s\n", t.ID, t.Status)
-	return nil
-}
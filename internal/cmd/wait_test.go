@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -20,7 +21,7 @@ func TestRunWait_AlreadyClosed(t *testing.T) {
 	tk := mkTicket(t, "kt-done", "Done", ticket.StatusClosed)
 
 	err := runWaitWithClock(
-		context.Background(), tk.ID,
+		context.Background(), []string{tk.ID},
 		fastTicker, fastTicker,
 	)
 	require.NoError(t, err)
@@ -34,7 +35,7 @@ func TestRunWait_AlreadyClosedJSON(t *testing.T) {
 	tk := mkTicket(t, "kt-done", "Done", ticket.StatusClosed)
 
 	err := runWaitWithClock(
-		context.Background(), tk.ID,
+		context.Background(), []string{tk.ID},
 		fastTicker, fastTicker,
 	)
 	require.NoError(t, err)
@@ -57,7 +58,7 @@ func TestRunWait_BecomesClosedDuringPoll(t *testing.T) {
 	}()
 
 	err := runWaitWithClock(
-		context.Background(), tk.ID,
+		context.Background(), []string{tk.ID},
 		fastTicker, fastTicker,
 	)
 	require.NoError(t, err)
@@ -70,7 +71,7 @@ func TestRunWait_NotFound(t *testing.T) {
 	defer setupTestEnv(t)()
 
 	err := runWaitWithClock(
-		context.Background(), "kt-nonexistent",
+		context.Background(), []string{"kt-nonexistent"},
 		fastTicker, fastTicker,
 	)
 	require.Error(t, err)
@@ -89,7 +90,7 @@ func TestRunWait_ContextCancelled(t *testing.T) {
 	}()
 
 	err := runWaitWithClock(
-		ctx, tk.ID,
+		ctx, []string{tk.ID},
 		fastTicker, fastTicker,
 	)
 	require.Error(t, err)
@@ -108,9 +109,107 @@ func TestRunWait_TicketDeletedDuringPoll(t *testing.T) {
 	}()
 
 	err := runWaitWithClock(
-		context.Background(), tk.ID,
+		context.Background(), []string{tk.ID},
 		fastTicker, fastTicker,
 	)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "read ticket")
 }
+
+func TestRunWait_All(t *testing.T) {
+	defer setupTestEnv(t)()
+
+	done := mkTicket(t, "kt-a", "Done", ticket.StatusClosed)
+	open := mkTicket(t, "kt-b", "Open", ticket.StatusOpen)
+
+	s := Store
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = s.Update(open.ID, func(tk *ticket.Ticket) error {
+			tk.SetStatus(ticket.StatusClosed)
+			return nil
+		})
+	}()
+
+	start := time.Now()
+	err := runWaitWithClock(
+		context.Background(), []string{done.ID, open.ID},
+		fastTicker, fastTicker,
+	)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond, "waits for the open ticket")
+}
+
+func TestRunWait_Any(t *testing.T) {
+	defer setupTestEnv(t)()
+	waitAny = true
+	defer func() { waitAny = false }()
+
+	a := mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+	b := mkTicket(t, "kt-b", "B", ticket.StatusOpen)
+
+	s := Store
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = s.Update(b.ID, func(tk *ticket.Ticket) error {
+			tk.SetStatus(ticket.StatusClosed)
+			return nil
+		})
+	}()
+
+	err := runWaitWithClock(
+		context.Background(), []string{a.ID, b.ID},
+		fastTicker, fastTicker,
+	)
+	require.NoError(t, err)
+	still, _ := Store.Get(a.ID)
+	assert.Equal(t, ticket.StatusOpen, still.Status)
+}
+
+func TestRunWait_Timeout(t *testing.T) {
+	defer setupTestEnv(t)()
+	waitTimeout = 20 * time.Millisecond
+	defer func() { waitTimeout = 0 }()
+
+	tk := mkTicket(t, "kt-wait", "Waiting", ticket.StatusOpen)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	err := runWait(cmd, []string{tk.ID})
+	var exit *exitError
+	require.ErrorAs(t, err, &exit)
+	assert.Equal(t, waitTimeoutExit, exit.code)
+	assert.ErrorContains(t, err, "timed out after 20ms: still waiting for kt-wait")
+}
+
+func TestRunWait_Until(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { waitUntil = "" }()
+
+	tk := mkTicket(t, "kt-wait", "Waiting", ticket.StatusOpen)
+
+	s := Store
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = s.Update(tk.ID, func(tk *ticket.Ticket) error {
+			tk.TestsPassed = true
+			return nil
+		})
+	}()
+
+	waitUntil = "tests_passed=true"
+	err := runWaitWithClock(
+		context.Background(), []string{tk.ID},
+		fastTicker, fastTicker,
+	)
+	require.NoError(t, err)
+	updated, _ := Store.Get(tk.ID)
+	assert.Equal(t, ticket.StatusOpen, updated.Status, "returned without the ticket closing")
+
+	waitUntil = "colour=red"
+	err = runWaitWithClock(
+		context.Background(), []string{tk.ID},
+		fastTicker, fastTicker,
+	)
+	assert.ErrorContains(t, err, `invalid --until: unknown filter field "colour"`)
+}
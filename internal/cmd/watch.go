@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream ticket changes as NDJSON",
+	Long: `Watch the store and print one JSON object per line for each ticket change,
+whether made by kt in another process or by editing the files directly.
+
+Events: created, updated, status (with "from", the previous status), and
+deleted. Each event carries the ticket as it is now; deleted events carry
+only the ID. Changes are picked up via filesystem notifications, with
+polling every --interval as a fallback.
+
+  kt watch | jq -c 'select(.event == "status" and .ticket.status == "closed")'`,
+	Args: cobra.NoArgs,
+	RunE: runWatch,
+}
+
+var watchInterval time.Duration
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", time.Second, "Polling interval when filesystem notifications are unavailable")
+	rootCmd.AddCommand(watchCmd)
+}
+
+// watchEvent is one line of kt watch output.
+type watchEvent struct {
+	Time   string         `json:"time"`
+	Event  string         `json:"event"`
+	ID     string         `json:"id"`
+	From   ticket.Status  `json:"from,omitempty"`
+	Ticket *ticket.Ticket `json:"ticket,omitempty"`
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if err := Store.EnsureDir(); err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	enc := json.NewEncoder(os.Stdout)
+	return watchStore(ctx, Store, watchInterval, func(ev watchEvent) error {
+		return enc.Encode(ev)
+	})
+}
+
+// watchStore calls emit for every ticket change until ctx is done.
+func watchStore(ctx context.Context, s *store.Store, interval time.Duration, emit func(watchEvent) error) error {
+	changes := s.Watch(ctx, interval)
+	prev, err := ticketSnapshot(s)
+	if err != nil {
+		return err
+	}
+	for range changes {
+		cur, err := ticketSnapshot(s)
+		if err != nil {
+			Warnf("%v", err)
+			continue
+		}
+		for _, ev := range diffTickets(prev, cur) {
+			if err := emit(ev); err != nil {
+				return err
+			}
+		}
+		prev = cur
+	}
+	return nil
+}
+
+// ticketSnapshot returns all tickets by ID.
+func ticketSnapshot(s *store.Store) (map[string]*ticket.Ticket, error) {
+	tickets, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*ticket.Ticket, len(tickets))
+	for _, t := range tickets {
+		byID[t.ID] = t
+	}
+	return byID, nil
+}
+
+// diffTickets returns the events that turn prev into cur, ordered by ID.
+func diffTickets(prev, cur map[string]*ticket.Ticket) []watchEvent {
+	now := time.Now().UTC().Format(time.RFC3339)
+	var events []watchEvent
+	for id, t := range cur {
+		old, ok := prev[id]
+		switch {
+		case !ok:
+			events = append(events, watchEvent{Time: now, Event: "created", ID: id, Ticket: t})
+		case old.Status != t.Status:
+			events = append(events, watchEvent{Time: now, Event: "status", ID: id, From: old.Status, Ticket: t})
+		case !sameTicket(old, t):
+			events = append(events, watchEvent{Time: now, Event: "updated", ID: id, Ticket: t})
+		}
+	}
+	for id := range prev {
+		if _, ok := cur[id]; !ok {
+			events = append(events, watchEvent{Time: now, Event: "deleted", ID: id})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events
+}
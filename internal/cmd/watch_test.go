@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffTickets(t *testing.T) {
+	prev := map[string]*ticket.Ticket{
+		"kt-a": {ID: "kt-a", Title: "A", Status: ticket.StatusOpen},
+		"kt-b": {ID: "kt-b", Title: "B", Status: ticket.StatusOpen},
+		"kt-c": {ID: "kt-c", Title: "C", Status: ticket.StatusOpen},
+		"kt-d": {ID: "kt-d", Title: "D", Status: ticket.StatusOpen},
+	}
+	cur := map[string]*ticket.Ticket{
+		"kt-a": {ID: "kt-a", Title: "A", Status: ticket.StatusOpen},
+		"kt-b": {ID: "kt-b", Title: "B2", Status: ticket.StatusOpen},
+		"kt-c": {ID: "kt-c", Title: "C", Status: ticket.StatusClosed},
+		"kt-e": {ID: "kt-e", Title: "E", Status: ticket.StatusOpen},
+	}
+
+	events := diffTickets(prev, cur)
+	var got []string
+	for _, ev := range events {
+		got = append(got, ev.Event+" "+ev.ID)
+	}
+	assert.Equal(t, []string{"updated kt-b", "status kt-c", "deleted kt-d", "created kt-e"}, got)
+	assert.Equal(t, ticket.StatusOpen, events[1].From)
+	assert.Nil(t, events[2].Ticket)
+}
+
+func TestWatchStore(t *testing.T) {
+	defer setupTestEnv(t)()
+	mkTicket(t, "kt-a", "A", ticket.StatusOpen)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan watchEvent, 10)
+	done := make(chan error)
+	go func() {
+		done <- watchStore(ctx, Store, 10*time.Millisecond, func(ev watchEvent) error {
+			events <- ev
+			return nil
+		})
+	}()
+	next := func() watchEvent {
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(2 * time.Second):
+			t.Fatal("no event")
+			return watchEvent{}
+		}
+	}
+
+	// Give the watcher time to take its initial snapshot
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, Store.Update("kt-a", func(tk *ticket.Ticket) error {
+		tk.SetStatus(ticket.StatusInProgress)
+		return nil
+	}))
+	ev := next()
+	assert.Equal(t, "status", ev.Event)
+	assert.Equal(t, ticket.StatusOpen, ev.From)
+	assert.Equal(t, ticket.StatusInProgress, ev.Ticket.Status)
+
+	require.NoError(t, Store.Delete("kt-a"))
+	assert.Equal(t, "deleted", next().Event)
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,363 @@
+// kt web UI: a kanban board, ticket detail, and dependency graph on top of
+// the kt serve JSON API. No build step and no dependencies.
+"use strict";
+
+const baseStatuses = ["open", "in_progress", "closed"];
+const closedLimit = 50;
+const refreshMs = 15000;
+
+const state = {
+  tickets: [],
+  byId: new Map(),
+  view: "board",
+  query: "",
+  selected: null,
+  canWrite: true, // false for read-only servers and tokens
+};
+
+let token = "";
+
+// el builds a DOM element; children may be strings, nodes, or null.
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    if (k.startsWith("on")) node.addEventListener(k.slice(2), v);
+    else if (k === "class") node.className = v;
+    else node.setAttribute(k, v);
+  }
+  for (const c of children) {
+    if (c !== null && c !== undefined) node.append(c);
+  }
+  return node;
+}
+
+function svg(tag, attrs, ...children) {
+  const node = document.createElementNS("http://www.w3.org/2000/svg", tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    if (k.startsWith("on")) node.addEventListener(k.slice(2), v);
+    else node.setAttribute(k, v);
+  }
+  node.append(...children);
+  return node;
+}
+
+function setMessage(text, isError) {
+  const m = document.getElementById("message");
+  m.textContent = text;
+  m.className = isError ? "error" : "";
+}
+
+// initToken takes the token from #token=... (as printed by kt serve) or the
+// session, keeping it out of the address bar.
+function initToken() {
+  const m = location.hash.match(/token=([^&]+)/);
+  if (m) {
+    token = decodeURIComponent(m[1]);
+    sessionStorage.setItem("kt-token", token);
+    history.replaceState(null, "", location.pathname);
+  } else {
+    token = sessionStorage.getItem("kt-token") || "";
+  }
+}
+
+function askToken() {
+  const dialog = document.getElementById("login");
+  if (!dialog.open) dialog.showModal();
+}
+
+async function api(method, path, body) {
+  const res = await fetch(path, {
+    method,
+    headers: { Authorization: "Bearer " + token, "Content-Type": "application/json" },
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  const data = await res.json().catch(() => ({}));
+  if (res.status === 401) {
+    askToken();
+  }
+  if (!res.ok) {
+    throw new Error(data.error || res.statusText);
+  }
+  return data;
+}
+
+async function load() {
+  try {
+    const session = await api("GET", "/api/session");
+    state.canWrite = session.scope !== "read";
+    state.tickets = await api("GET", "/api/tickets");
+    state.byId = new Map(state.tickets.map((t) => [t.id, t]));
+    setMessage("");
+    render();
+  } catch (e) {
+    setMessage(e.message, true);
+  }
+}
+
+// mutate runs an API change, then reloads and reports errors (e.g. a
+// ticket whose tests have not passed cannot be closed).
+async function mutate(method, path, body) {
+  try {
+    await api(method, path, body);
+  } catch (e) {
+    setMessage(e.message, true);
+    return;
+  }
+  await load();
+}
+
+function isBlocked(t) {
+  return t.status !== "closed" &&
+    (t.deps || []).some((id) => state.byId.has(id) && state.byId.get(id).status !== "closed");
+}
+
+function matches(t) {
+  const q = state.query;
+  if (!q) return true;
+  return [t.id, t.title, t.assignee, t.type, t.external_ref, ...(t.labels || [])]
+    .some((v) => v && v.toLowerCase().includes(q));
+}
+
+function render() {
+  if (state.view === "board") renderBoard();
+  else renderGraph();
+  renderDetail();
+}
+
+function renderBoard() {
+  const board = document.getElementById("board");
+  const statuses = [...baseStatuses];
+  for (const t of state.tickets) {
+    if (!statuses.includes(t.status)) statuses.splice(statuses.length - 1, 0, t.status);
+  }
+
+  board.replaceChildren(...statuses.map((status) => {
+    let tickets = state.tickets.filter((t) => t.status === status && matches(t));
+    if (status === "closed") {
+      tickets.sort((a, b) => (b.closed || "").localeCompare(a.closed || ""));
+      tickets = tickets.slice(0, closedLimit);
+    } else {
+      tickets.sort((a, b) => a.priority - b.priority || a.created.localeCompare(b.created));
+    }
+
+    const column = el("div", { class: "column" },
+      el("h2", {}, status.replace("_", " ") + " ", el("span", {}, String(tickets.length))),
+      ...tickets.map(card));
+    column.addEventListener("dragover", (e) => {
+      e.preventDefault();
+      column.classList.add("over");
+    });
+    column.addEventListener("dragleave", () => column.classList.remove("over"));
+    column.addEventListener("drop", (e) => {
+      e.preventDefault();
+      column.classList.remove("over");
+      const id = e.dataTransfer.getData("text/plain");
+      const t = state.byId.get(id);
+      if (t && t.status !== status && state.canWrite) {
+        mutate("POST", `/api/tickets/${encodeURIComponent(id)}/status`, { status });
+      }
+    });
+    return column;
+  }));
+}
+
+function card(t) {
+  const classes = ["card"];
+  if (isBlocked(t)) classes.push("blocked");
+  if (t.id === state.selected) classes.push("selected");
+  const c = el("div", { class: classes.join(" "), draggable: String(state.canWrite), onclick: () => select(t.id) },
+    el("div", { class: "meta" },
+      el("span", { class: "badge p" + t.priority }, "P" + t.priority),
+      el("span", {}, t.id),
+      el("span", {}, t.type),
+      t.assignee ? el("span", {}, "@" + t.assignee) : null),
+    el("div", {}, t.title));
+  c.addEventListener("dragstart", (e) => e.dataTransfer.setData("text/plain", t.id));
+  return c;
+}
+
+// renderGraph lays out unclosed tickets (and the deps they point at) in
+// columns by dependency depth: deps on the left, dependents on the right.
+function renderGraph() {
+  const shown = new Map();
+  const add = (t) => {
+    if (shown.has(t.id)) return;
+    shown.set(t.id, t);
+    for (const id of t.deps || []) {
+      if (state.byId.has(id)) add(state.byId.get(id));
+    }
+  };
+  state.tickets.filter((t) => t.status !== "closed" && matches(t)).forEach(add);
+
+  const depth = new Map();
+  const visiting = new Set();
+  const layer = (t) => {
+    if (depth.has(t.id)) return depth.get(t.id);
+    if (visiting.has(t.id)) return 0; // cycle
+    visiting.add(t.id);
+    let d = 0;
+    for (const id of t.deps || []) {
+      if (shown.has(id)) d = Math.max(d, layer(shown.get(id)) + 1);
+    }
+    visiting.delete(t.id);
+    depth.set(t.id, d);
+    return d;
+  };
+
+  const columns = [];
+  for (const t of shown.values()) {
+    const d = layer(t);
+    (columns[d] = columns[d] || []).push(t);
+  }
+
+  const w = 200, h = 44, gapX = 60, gapY = 16, pad = 16;
+  const pos = new Map();
+  columns.forEach((col, x) => {
+    col.sort((a, b) => a.priority - b.priority || a.id.localeCompare(b.id));
+    col.forEach((t, y) => pos.set(t.id, { x: pad + x * (w + gapX), y: pad + y * (h + gapY) }));
+  });
+
+  const edges = [];
+  for (const t of shown.values()) {
+    const to = pos.get(t.id);
+    for (const id of t.deps || []) {
+      const from = pos.get(id);
+      if (!from) continue;
+      const x1 = from.x + w, y1 = from.y + h / 2, x2 = to.x, y2 = to.y + h / 2;
+      const mx = (x1 + x2) / 2;
+      edges.push(svg("path", {
+        class: "edge",
+        d: `M${x1},${y1} C${mx},${y1} ${mx},${y2} ${x2},${y2}`,
+        "marker-end": "url(#arrow)",
+      }));
+    }
+  }
+
+  const nodes = [...shown.values()].map((t) => {
+    const p = pos.get(t.id);
+    const classes = ["node", t.status === "closed" ? "closed" : "", isBlocked(t) ? "blocked" : ""];
+    const title = t.title.length > 28 ? t.title.slice(0, 27) + "…" : t.title;
+    return svg("g", { class: classes.join(" "), transform: `translate(${p.x},${p.y})`, onclick: () => select(t.id) },
+      svg("rect", { width: w, height: h }),
+      svg("text", { x: 8, y: 17 }, `P${t.priority} ${t.id} · ${t.status}`),
+      svg("text", { x: 8, y: 34 }, title));
+  });
+
+  const height = Math.max(0, ...columns.map((c) => c.length)) * (h + gapY) + pad * 2;
+  const graph = document.getElementById("graph-svg");
+  graph.setAttribute("width", columns.length * (w + gapX) + pad * 2);
+  graph.setAttribute("height", height);
+  graph.replaceChildren(
+    svg("defs", {},
+      svg("marker", { id: "arrow", viewBox: "0 0 10 10", refX: 10, refY: 5, markerWidth: 6, markerHeight: 6, orient: "auto" },
+        svg("path", { d: "M0,0 L10,5 L0,10 z", fill: "currentColor" }))),
+    ...edges, ...nodes);
+  if (shown.size === 0) setMessage("No open tickets");
+}
+
+function select(id) {
+  state.selected = id;
+  render();
+}
+
+function refs(ids) {
+  if (!ids || ids.length === 0) return null;
+  return el("span", {}, ...ids.map((id) => el("a", { class: "ref", onclick: () => select(id) }, id)));
+}
+
+function renderDetail() {
+  const detail = document.getElementById("detail");
+  const t = state.byId.get(state.selected);
+  if (!t) {
+    detail.hidden = true;
+    return;
+  }
+  const path = `/api/tickets/${encodeURIComponent(t.id)}`;
+  const dependents = state.tickets.filter((o) => (o.deps || []).includes(t.id)).map((o) => o.id);
+  const children = state.tickets.filter((o) => o.parent === t.id).map((o) => o.id);
+
+  const rows = [
+    ["Status", t.status],
+    ["Type", t.type],
+    ["Priority", "P" + t.priority],
+    ["Assignee", t.assignee],
+    ["Parent", refs(t.parent ? [t.parent] : [])],
+    ["Children", refs(children)],
+    ["Deps", refs(t.deps)],
+    ["Dependents", refs(dependents)],
+    ["Links", refs(t.links)],
+    ...Object.entries(t.relations || {}).map(([type, ids]) => [type, refs(ids)]),
+    ["Labels", (t.labels || []).join(", ")],
+    ["External ref", t.external_ref],
+    ["Due", t.due],
+    ["Created", t.created],
+    ["Closed", t.closed],
+    ["Tests passed", t.tests ? String(t.tests_passed) : ""],
+  ].filter(([, v]) => v);
+
+  const sections = [
+    ["Description", t.description],
+    ["Design", t.design],
+    ["Acceptance Criteria", t.acceptance_criteria],
+    ["Tests", t.tests],
+    ["Notes", t.notes],
+  ].filter(([, v]) => v);
+
+  const actions = [];
+  if (state.canWrite) {
+    if (t.status !== "in_progress") {
+      actions.push(el("button", { onclick: () => mutate("POST", path + "/status", { status: "in_progress" }) }, "Start"));
+    }
+    if (t.tests && !t.tests_passed) {
+      actions.push(el("button", { onclick: () => mutate("PATCH", path, { tests_passed: "true" }) }, "Pass tests"));
+    }
+    if (t.status !== "closed") {
+      actions.push(el("button", { onclick: () => mutate("POST", path + "/status", { status: "closed" }) }, "Close"));
+    } else {
+      actions.push(el("button", { onclick: () => mutate("POST", path + "/status", { status: "open" }) }, "Reopen"));
+    }
+  }
+
+  detail.replaceChildren(
+    el("button", { class: "close", title: "Close", onclick: () => select(null) }, "×"),
+    el("h2", {}, t.title),
+    el("div", { class: "meta" }, t.id),
+    el("div", { class: "actions" }, ...actions),
+    el("dl", {}, ...rows.flatMap(([k, v]) => [el("dt", {}, k), el("dd", {}, v)])),
+    ...sections.flatMap(([k, v]) => [el("h3", {}, k), el("pre", {}, v)]));
+  detail.hidden = false;
+}
+
+function init() {
+  initToken();
+
+  for (const button of document.querySelectorAll("nav button")) {
+    button.addEventListener("click", () => {
+      state.view = button.dataset.view;
+      for (const b of document.querySelectorAll("nav button")) b.classList.toggle("active", b === button);
+      for (const v of document.querySelectorAll(".view")) v.hidden = v.id !== state.view;
+      setMessage("");
+      render();
+    });
+  }
+  document.getElementById("search").addEventListener("input", (e) => {
+    state.query = e.target.value.trim().toLowerCase();
+    render();
+  });
+  document.addEventListener("keydown", (e) => {
+    if (e.key === "Escape" && state.selected) select(null);
+  });
+  document.getElementById("login").addEventListener("close", () => {
+    token = document.getElementById("token").value.trim();
+    sessionStorage.setItem("kt-token", token);
+    load();
+  });
+
+  setInterval(() => {
+    if (document.visibilityState === "visible" && token) load();
+  }, refreshMs);
+  if (token) load();
+  else askToken();
+}
+
+init();
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var worktreeCmd = &cobra.Command{
+	Use:   "worktree <id>",
+	Short: "Create a git worktree and branch for a ticket",
+	Long: `Create a git worktree next to the repository (<repo>-<id>) on a branch
+named after the ticket, and print its path. Running it again for the same
+ticket just prints the path. With shared_worktrees in config.yml (or
+KTICKET_SHARED_WORKTREES=true), kt commands run inside the worktree use the
+main repository's tickets, so several agents can work on separate tickets
+at once; otherwise they use the tickets checked out in the worktree. kt
+worktree says which on stderr.
+
+  cd "$(kt worktree kt-a1b2)"
+  kt worktree rm kt-a1b2`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorktree,
+}
+
+var worktreeRmCmd = &cobra.Command{
+	Use:   "rm <id>",
+	Short: "Remove a ticket's worktree (the branch is kept)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWorktreeRm,
+}
+
+var worktreeRmForce bool
+
+func init() {
+	worktreeRmCmd.Flags().BoolVarP(&worktreeRmForce, "force", "f", false, "Remove even with uncommitted changes")
+	worktreeCmd.AddCommand(worktreeRmCmd)
+	rootCmd.AddCommand(worktreeCmd)
+}
+
+type worktreeResult struct {
+	ID     string `json:"id"`
+	Path   string `json:"path"`
+	Branch string `json:"branch"`
+	Store  string `json:"store,omitempty"` // the tickets kt uses inside the worktree
+}
+
+// ticketWorktree returns the main repository root and the worktree path and
+// branch for a ticket.
+func ticketWorktree(arg string) (string, worktreeResult, error) {
+	t, err := Store.Resolve(arg)
+	if err != nil {
+		return "", worktreeResult{}, err
+	}
+	root, err := config.FindMainGitRoot()
+	if err != nil {
+		return "", worktreeResult{}, err
+	}
+	path := filepath.Join(filepath.Dir(root), filepath.Base(root)+"-"+t.ID)
+	return root, worktreeResult{ID: t.ID, Path: path, Branch: t.ID}, nil
+}
+
+func runWorktree(cmd *cobra.Command, args []string) error {
+	root, result, err := ticketWorktree(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(result.Path); os.IsNotExist(err) {
+		if err := git.AddWorktree(root, result.Path, result.Branch); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	result.Store = filepath.Join(result.Path, config.DefaultDir)
+	if config.SharesStore(result.Path) {
+		result.Store = filepath.Join(root, config.DefaultDir)
+	}
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	fmt.Fprintf(os.Stderr, "kt in this worktree uses the tickets in %s\n", result.Store)
+	fmt.Println(result.Path)
+	return nil
+}
+
+func runWorktreeRm(cmd *cobra.Command, args []string) error {
+	root, result, err := ticketWorktree(args[0])
+	if err != nil {
+		return err
+	}
+	if err := git.RemoveWorktree(root, result.Path, worktreeRmForce); err != nil {
+		return err
+	}
+
+	if IsJSON() {
+		return PrintJSON(result)
+	}
+	fmt.Printf("Removed %s (branch %s kept)\n", result.Path, result.Branch)
+	return nil
+}
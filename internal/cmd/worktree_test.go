@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/git"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWorktree(t *testing.T) {
+	defer setupTestEnv(t)()
+	defer func() { worktreeRmForce = false }()
+	dir := initGitRepo(t)
+	gitCommit(t, "initial")
+	tk := mkTicket(t, "kt-a1b2", "Login", ticket.StatusOpen)
+	path := dir + "-" + tk.ID
+
+	require.NoError(t, runWorktree(nil, []string{"a1b2"}))
+	branch, err := git.RunIn(path, "symbolic-ref", "--short", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, tk.ID, branch)
+
+	// Inside the worktree, tickets are the worktree's own unless shared
+	t.Chdir(path)
+	root, err := config.FindGitRoot()
+	require.NoError(t, err)
+	assert.Equal(t, path, root)
+	t.Setenv(config.EnvSharedWorktrees, "true")
+	root, err = config.FindGitRoot()
+	require.NoError(t, err)
+	assert.Equal(t, dir, root)
+
+	// Running again reuses the worktree
+	require.NoError(t, runWorktree(nil, []string{"a1b2"}))
+
+	t.Chdir(dir)
+	require.NoError(t, os.WriteFile(filepath.Join(path, "wip.txt"), []byte("x"), 0644))
+	require.Error(t, runWorktreeRm(nil, []string{"a1b2"}))
+	worktreeRmForce = true
+	require.NoError(t, runWorktreeRm(nil, []string{"a1b2"}))
+	assert.NoDirExists(t, path)
+}
@@ -12,19 +12,92 @@ const (
 
 	// EnvDir is the environment variable to override the directory.
 	EnvDir = "KTICKET_DIR"
+
+	// EnvNoPager disables paging of long output when set to any value.
+	EnvNoPager = "KTICKET_NO_PAGER"
+
+	// EnvRender sets whether kt show renders markdown (true/false).
+	// Defaults to rendering on a terminal only.
+	EnvRender = "KTICKET_RENDER"
+
+	// EnvAutoCommit commits ticket changes to git after each mutating
+	// command when set to true.
+	EnvAutoCommit = "KTICKET_AUTO_COMMIT"
+
+	// EnvBranchID lets commands that take a ticket ID default to the ticket
+	// named in the current git branch when set to true.
+	EnvBranchID = "KTICKET_BRANCH_ID"
+
+	// EnvNotify holds webhook rules for ticket events, e.g.
+	// "p0_opened=slack:https://hooks.slack.com/...".
+	EnvNotify = "KTICKET_NOTIFY"
+
+	// EnvServeToken is the bearer token kt serve requires from clients.
+	EnvServeToken = "KTICKET_SERVE_TOKEN"
+
+	// EnvJSON, EnvNoColor, EnvFormat, EnvStore, EnvGlobal,
+	// EnvLockTimeout, and EnvStrictParse set the global flags of the same
+	// names when the flag is not given.
+	EnvJSON        = "KTICKET_JSON"
+	EnvNoColor     = "KTICKET_NO_COLOR"
+	EnvFormat      = "KTICKET_FORMAT"
+	EnvStore       = "KTICKET_STORE"
+	EnvGlobal      = "KTICKET_GLOBAL"
+	EnvLockTimeout = "KTICKET_LOCK_TIMEOUT"
+	EnvStrictParse = "KTICKET_STRICT_PARSE"
+
+	// EnvSession names the agent session running kt (the --session flag),
+	// recorded on each ticket it changes.
+	EnvSession = "KTICKET_SESSION"
+
+	// EnvAssignee is the default assignee for new tickets, ahead of
+	// defaults.assignee in config.yml.
+	EnvAssignee = "KTICKET_ASSIGNEE"
+
+	// EnvNoDaemon makes kt read tickets from disk even when a kt daemon is
+	// running, when set to any value.
+	EnvNoDaemon = "KTICKET_NO_DAEMON"
+
+	// EnvSharedWorktrees makes kt in a linked git worktree use the main
+	// worktree's tickets (true/false), overriding shared_worktrees in
+	// config.yml.
+	EnvSharedWorktrees = "KTICKET_SHARED_WORKTREES"
+
+	// EnvTrustHooks lets the hooks and agent commands in the project's
+	// config.yml run without kt config trust, e.g. in CI (true/false).
+	EnvTrustHooks = "KTICKET_TRUST_HOOKS"
 )
 
+// GlobalDir returns the personal tickets directory, ~/.ktickets, for tasks
+// outside any repository.
+func GlobalDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, DefaultDir), nil
+}
+
 // Dir returns the tickets directory.
 // Checks KTICKET_DIR env var first, then resolves relative to git root,
 // falls back to DefaultDir in cwd if not in a git repo.
 func Dir() string {
+	dir, err := FindDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v; using ./%s (kt --global uses ~/%s)\n", err, DefaultDir, DefaultDir)
+	}
+	return dir
+}
+
+// FindDir is Dir without the warning: outside a git repository it returns
+// DefaultDir along with the error.
+func FindDir() (string, error) {
 	if dir := os.Getenv(EnvDir); dir != "" {
-		return dir
+		return dir, nil
 	}
 	gitRoot, err := FindGitRoot()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "warning: %v; using ./%s\n", err, DefaultDir)
-		return DefaultDir
+		return DefaultDir, err
 	}
-	return filepath.Join(gitRoot, DefaultDir)
+	return filepath.Join(gitRoot, DefaultDir), nil
 }
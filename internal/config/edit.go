@@ -0,0 +1,129 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// Set sets the dotted key (e.g. defaults.type or hooks.closed) in the
+// config file at path to value, creating the file if needed. The value is
+// read as YAML, so "true", "3", and "[a, b]" are typed. Comments and the
+// order of other settings are kept. The file is only written if the
+// result is a valid configuration.
+func Set(path, key, value string) error {
+	parts := strings.Split(key, ".")
+	for _, part := range parts {
+		if part == "" || strings.ContainsAny(part, "'\n") {
+			return fmt.Errorf("invalid key %q", key)
+		}
+	}
+	var v any = value
+	if err := yaml.Unmarshal([]byte(value), &v); err != nil || v == nil {
+		v = value
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	data, err = setKey(data, parts, v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := parse(path, data, &Project{}); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// setKey returns the YAML document data with the key at parts set to v. It
+// replaces the deepest existing node on the path, or appends the key as a
+// new top-level setting.
+func setKey(data []byte, parts []string, v any) ([]byte, error) {
+	if len(bytes.TrimSpace(data)) > 0 {
+		file, err := parser.ParseBytes(data, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		for i := len(parts); i > 0; i-- {
+			path, err := yaml.PathString("$." + "'" + strings.Join(parts[:i], "'.'") + "'")
+			if err != nil {
+				return nil, err
+			}
+			if _, err := path.FilterFile(file); err != nil {
+				continue
+			}
+			rest, err := yaml.Marshal(nest(parts[i:], v))
+			if err != nil {
+				return nil, err
+			}
+			if i == len(parts) {
+				err = path.ReplaceWithReader(file, bytes.NewReader(rest))
+			} else {
+				err = path.MergeFromReader(file, bytes.NewReader(rest))
+			}
+			if err != nil {
+				return nil, err
+			}
+			return []byte(strings.TrimRight(file.String(), "\n") + "\n"), nil
+		}
+	}
+
+	add, err := yaml.Marshal(nest(parts, v))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	return append(data, add...), nil
+}
+
+// nest wraps v in one single-key map per key in parts.
+func nest(parts []string, v any) any {
+	for i := len(parts) - 1; i >= 0; i-- {
+		v = map[string]any{parts[i]: v}
+	}
+	return v
+}
+
+// Settings returns p's non-empty settings keyed by dotted name, e.g.
+// "defaults.type". Lists are kept whole.
+func (p *Project) Settings() (map[string]any, error) {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]any
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	settings := map[string]any{}
+	var flatten func(prefix string, m map[string]any)
+	flatten = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			if sub, ok := v.(map[string]any); ok {
+				flatten(prefix+k+".", sub)
+			} else {
+				settings[prefix+k] = v
+			}
+		}
+	}
+	flatten("", tree)
+	return settings, nil
+}
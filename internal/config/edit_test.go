@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet(t *testing.T) {
+	dir := writeProject(t, "# team settings\ndefaults:\n  type: bug\nhooks:\n  \"*\": echo all\n")
+	path := filepath.Join(dir, ProjectFile)
+
+	require.NoError(t, Set(path, "defaults.priority", "1"))
+	require.NoError(t, Set(path, "defaults.type", "feature"))
+	require.NoError(t, Set(path, "hooks.*", "echo star"))
+	require.NoError(t, Set(path, "types", "[spike, research]"))
+	require.NoError(t, Set(path, "integrations.auto_commit", "true"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# team settings
+defaults:
+  type: feature
+  priority: 1
+hooks:
+  "*": echo star
+types:
+- spike
+- research
+integrations:
+  auto_commit: true
+`, string(data))
+
+	assert.ErrorContains(t, Set(path, "defaults.priority", "9"), "invalid defaults.priority")
+	assert.ErrorContains(t, Set(path, "defualts.type", "bug"), "unknown field")
+	assert.ErrorContains(t, Set(path, "defaults..type", "bug"), "invalid key")
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, data, after, "invalid settings are not written")
+}
+
+func TestSetNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kt", ProjectFile)
+	require.NoError(t, Set(path, "editor", "code --wait"))
+
+	p, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "code --wait", p.Editor)
+
+	settings, err := p.Settings()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"editor": "code --wait"}, settings)
+}
@@ -4,22 +4,55 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
-// FindGitRoot walks from cwd upward looking for a .git directory.
+// FindGitRoot walks from cwd upward looking for a .git directory. Inside a
+// linked worktree it returns the worktree's own root, unless SharesStore
+// says worktrees share the main worktree's ticket directory.
 func FindGitRoot() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return "", err
 	}
-	return findGitRootFrom(cwd)
+	return findGitRootFrom(cwd, SharesStore)
 }
 
-func findGitRootFrom(dir string) (string, error) {
+// FindMainGitRoot is FindGitRoot, except that inside a linked worktree it
+// always returns the main worktree's root.
+func FindMainGitRoot() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return findGitRootFrom(cwd, func(string) bool { return true })
+}
+
+// SharesStore reports whether kt in the linked worktree at root uses the
+// main worktree's tickets instead of the worktree's own: EnvSharedWorktrees
+// if set, else shared_worktrees in the user's config.yml or the worktree's.
+func SharesStore(root string) bool {
+	p, err := Load(filepath.Join(root, DefaultDir))
+	if err != nil {
+		p = &Project{}
+	}
+	return envBool(EnvSharedWorktrees, p.SharedWorktrees)
+}
+
+// findGitRootFrom is FindGitRoot from dir. follow says whether to resolve a
+// linked worktree at the given root to the main worktree's.
+func findGitRootFrom(dir string, follow func(root string) bool) (string, error) {
 	for {
-		if info, err := os.Stat(filepath.Join(dir, ".git")); err == nil && info.IsDir() {
+		info, err := os.Stat(filepath.Join(dir, ".git"))
+		if err == nil && info.IsDir() {
 			return dir, nil
 		}
+		if err == nil && info.Mode().IsRegular() {
+			if !follow(dir) {
+				return dir, nil
+			}
+			return linkedRoot(dir), nil
+		}
 		parent := filepath.Dir(dir)
 		if parent == dir {
 			return "", errors.New("not a git repository (or any parent up to /)")
@@ -27,3 +60,33 @@ func findGitRootFrom(dir string) (string, error) {
 		dir = parent
 	}
 }
+
+// linkedRoot resolves a directory whose .git is a file ("gitdir: <path>").
+// For a linked worktree that is the main worktree's root; for anything else
+// (e.g. a submodule) it is dir itself.
+func linkedRoot(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return dir
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return dir
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	common, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return dir
+	}
+	commonDir := strings.TrimSpace(string(common))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
+	}
+	commonDir = filepath.Clean(commonDir)
+	if filepath.Base(commonDir) != ".git" {
+		return dir // bare repository: no main worktree
+	}
+	return filepath.Dir(commonDir)
+}
@@ -13,7 +13,7 @@ func TestFindGitRootFromRepoRoot(t *testing.T) {
 	root := t.TempDir()
 	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o755))
 
-	got, err := findGitRootFrom(root)
+	got, err := findGitRootFrom(root, SharesStore)
 	require.NoError(t, err)
 	assert.Equal(t, root, got)
 }
@@ -25,7 +25,7 @@ func TestFindGitRootFromSubdir(t *testing.T) {
 	sub := filepath.Join(root, "a", "b", "c")
 	require.NoError(t, os.MkdirAll(sub, 0o755))
 
-	got, err := findGitRootFrom(sub)
+	got, err := findGitRootFrom(sub, SharesStore)
 	require.NoError(t, err)
 	assert.Equal(t, root, got)
 }
@@ -33,7 +33,45 @@ func TestFindGitRootFromSubdir(t *testing.T) {
 func TestFindGitRootNotFound(t *testing.T) {
 	dir := t.TempDir()
 
-	_, err := findGitRootFrom(dir)
+	_, err := findGitRootFrom(dir, SharesStore)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "not a git repository")
 }
+
+func TestFindGitRootFromLinkedWorktree(t *testing.T) {
+	root := t.TempDir()
+	gitDir := filepath.Join(root, ".git", "worktrees", "wt")
+	require.NoError(t, os.MkdirAll(gitDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "commondir"), []byte("../..\n"), 0o644))
+
+	wt := filepath.Join(t.TempDir(), "wt")
+	require.NoError(t, os.Mkdir(wt, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+gitDir+"\n"), 0o644))
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(EnvSharedWorktrees, "")
+	got, err := findGitRootFrom(wt, SharesStore)
+	require.NoError(t, err)
+	assert.Equal(t, wt, got, "the worktree's own tickets by default")
+
+	// Opting in through the worktree's config.yml shares the main worktree's
+	require.NoError(t, os.MkdirAll(filepath.Join(wt, DefaultDir), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(wt, DefaultDir, ProjectFile), []byte("shared_worktrees: true\n"), 0o644))
+	got, err = findGitRootFrom(wt, SharesStore)
+	require.NoError(t, err)
+	assert.Equal(t, root, got)
+
+	t.Setenv(EnvSharedWorktrees, "false")
+	got, err = findGitRootFrom(wt, SharesStore)
+	require.NoError(t, err)
+	assert.Equal(t, wt, got, "the variable wins")
+}
+
+func TestFindGitRootFromSubmodule(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git"), []byte("gitdir: ../.git/modules/sub\n"), 0o644))
+
+	got, err := findGitRootFrom(dir, SharesStore)
+	require.NoError(t, err)
+	assert.Equal(t, dir, got)
+}
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// ProjectFile is the project config file, kept in the tickets directory so
+// it is committed along with the tickets.
+const ProjectFile = "config.yml"
+
+// Project is the configuration from config.yml: the user's file (see
+// UserFile) overlaid by the project's. Every setting is optional. Settings
+// that also have an environment variable are read through methods, where
+// the variable wins.
+type Project struct {
+	Defaults  Defaults  `yaml:"defaults,omitempty"`
+	Require   Require   `yaml:"require,omitempty"`
+	Retention Retention `yaml:"retention,omitempty"`
+
+	// Statuses and Types are project-specific additions to the built-in
+	// ones. When Statuses is set, kt status accepts only known statuses.
+	Statuses []string `yaml:"statuses,omitempty"`
+	Types    []string `yaml:"types,omitempty"`
+
+	// AdvanceFrom is a status for tickets waiting on their deps. When the
+	// last of them closes, such a ticket is set to open. It is added to
+	// Statuses.
+	AdvanceFrom string `yaml:"advance_from,omitempty"`
+
+	// StrictParse makes kt refuse ticket files with unknown frontmatter
+	// keys, unknown statuses or types, or malformed timestamps, instead of
+	// accepting or skipping them (like --strict-parse).
+	StrictParse bool `yaml:"strict_parse,omitempty"`
+
+	// EpicParents requires a ticket's parent to be an epic, and epics to
+	// have no parent.
+	EpicParents bool `yaml:"epic_parents,omitempty"`
+
+	// SharedWorktrees makes kt in a linked git worktree use the main
+	// worktree's tickets, so agents in separate worktrees share one set.
+	// Otherwise each worktree has the tickets checked out in it.
+	SharedWorktrees bool `yaml:"shared_worktrees,omitempty"`
+
+	// IDPrefix replaces the prefix derived from the repository name.
+	IDPrefix string `yaml:"id_prefix,omitempty"`
+
+	// IDStrategy is how new ticket IDs are made: IDHash (the default) or
+	// IDSequential.
+	IDStrategy string `yaml:"id_strategy,omitempty"`
+
+	// IDLength is the number of hex characters in hash IDs (default 4).
+	// Busy stores can lengthen it to make collisions rarer.
+	IDLength int `yaml:"id_length,omitempty"`
+
+	// Hooks maps a ticket event (ticket_created, ticket_closed, ..., or *
+	// for all) to a shell command run after each command that causes it.
+	Hooks map[string]string `yaml:"hooks,omitempty"`
+
+	Integrations Integrations `yaml:"integrations,omitempty"`
+
+	Import Import `yaml:"import,omitempty"`
+
+	Agent Agent `yaml:"agent,omitempty"`
+
+	// Stores names ticket stores for kt --store, mapping each name to a
+	// repository or tickets directory.
+	Stores map[string]string `yaml:"stores,omitempty"`
+
+	// Aliases maps a new command name to the kt arguments it stands for,
+	// e.g. buglist: ls --status open --filter type=bug.
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+
+	// Personal preferences, read from the user's file alone (see
+	// PersonalKeys).
+	Editor  string `yaml:"editor,omitempty"`   // for kt edit; takes precedence over $EDITOR
+	NoColor bool   `yaml:"no_color,omitempty"` // like --no-color
+	Output  string `yaml:"output,omitempty"`   // "json" makes --json the default
+}
+
+// PersonalKeys are the settings Load takes only from the user's file. A
+// project's config.yml is committed by others, and an editor it named
+// would run on kt edit without the trust hooks need.
+var PersonalKeys = []string{"editor", "no_color", "output"}
+
+// ID strategies for Project.IDStrategy.
+const (
+	IDHash       = "hash"       // e.g. kt-a1b2, from a hash of time and PID
+	IDSequential = "sequential" // e.g. kt-042, from a counter in the store
+)
+
+// Defaults are the values kt create uses for options not given. The text
+// ones are templates for the ticket's sections.
+type Defaults struct {
+	Type     string `yaml:"type,omitempty"`
+	Priority *int   `yaml:"priority,omitempty"`
+	Assignee string `yaml:"assignee,omitempty"`
+
+	Description string `yaml:"description,omitempty"`
+	Design      string `yaml:"design,omitempty"`
+	Acceptance  string `yaml:"acceptance,omitempty"`
+	Tests       string `yaml:"tests,omitempty"`
+}
+
+// Require is what kt create demands of new tickets, so tickets written
+// by agents meet the team's standards. Sections filled from Defaults count.
+type Require struct {
+	MaxTitle    int      `yaml:"max_title,omitempty"`   // longest title, in characters
+	Description []string `yaml:"description,omitempty"` // types that need a description
+	Acceptance  []string `yaml:"acceptance,omitempty"`  // types that need acceptance criteria, e.g. [feature]
+	Tests       []string `yaml:"tests,omitempty"`       // types that need a tests section, e.g. [bug]
+}
+
+// Retention is how long kt gc keeps closed tickets, as ages like 180d
+// (see ParseAge). Empty keeps them forever.
+type Retention struct {
+	Closed   string `yaml:"closed,omitempty"`   // archive tickets closed this long ago
+	Archived string `yaml:"archived,omitempty"` // delete archived tickets closed this long ago
+}
+
+// ParseAge parses a duration, additionally accepting d (days) and w
+// (weeks), e.g. 36h, 7d, or 2w. Negative ages are invalid.
+func ParseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid age %q (expected e.g. 24h, 7d, 2w)", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (expected e.g. 24h, 7d, 2w)", s)
+	}
+	return d, nil
+}
+
+// Integrations configures git and external services.
+type Integrations struct {
+	AutoCommit    bool   `yaml:"auto_commit,omitempty"`    // overridden by KTICKET_AUTO_COMMIT
+	BranchID      bool   `yaml:"branch_id,omitempty"`      // overridden by KTICKET_BRANCH_ID
+	Notify        string `yaml:"notify,omitempty"`         // overridden by KTICKET_NOTIFY
+	GitHubRepo    string `yaml:"github_repo,omitempty"`    // default --repo for import/sync github
+	GitLabProject string `yaml:"gitlab_project,omitempty"` // default --project for import gitlab
+}
+
+// Import configures kt import github and gitlab.
+type Import struct {
+	// TypeMap maps issue labels to ticket types on top of the built-in
+	// rules (bug, enhancement, ...), e.g. incident: bug. --type-map rules
+	// override it.
+	TypeMap map[string]string `yaml:"type_map,omitempty"`
+}
+
+// Agent configures kt run: the coding agent it hands tickets to and the
+// check that must pass before it closes them.
+type Agent struct {
+	Command string `yaml:"command,omitempty" json:"command,omitempty"` // shell command; gets the prompt on stdin (default: DefaultAgentCommand)
+	Verify  string `yaml:"verify,omitempty" json:"verify,omitempty"`   // shell command, e.g. go test ./...
+}
+
+// DefaultAgentCommand is the agent kt run uses when none is configured.
+const DefaultAgentCommand = "claude -p"
+
+// UserFile returns the path of the user's config file,
+// $XDG_CONFIG_HOME/kt/config.yml or ~/.config/kt/config.yml, or "" if
+// neither location is known.
+func UserFile() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "kt", ProjectFile)
+}
+
+// Load reads the user's config file and then config.yml from the tickets
+// directory dir, so project settings override personal ones. Nested
+// settings merge key by key; lists and maps are replaced whole. The
+// PersonalKeys come from the user's file alone.
+func Load(dir string) (*Project, error) {
+	var p Project
+	if path := UserFile(); path != "" {
+		if err := loadFile(path, &p); err != nil {
+			return nil, err
+		}
+	}
+	user := p
+	if err := loadFile(filepath.Join(dir, ProjectFile), &p); err != nil {
+		return nil, err
+	}
+	p.Editor, p.NoColor, p.Output = user.Editor, user.NoColor, user.Output
+	return &p, nil
+}
+
+// LoadProject reads config.yml from the tickets directory dir alone. A
+// missing file is an empty configuration.
+func LoadProject(dir string) (*Project, error) {
+	return LoadFile(filepath.Join(dir, ProjectFile))
+}
+
+// LoadFile reads the config file at path alone. A missing file is an empty
+// configuration.
+func LoadFile(path string) (*Project, error) {
+	var p Project
+	if err := loadFile(path, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// loadFile overlays p with the settings in the config file at path, if it
+// exists.
+func loadFile(path string, p *Project) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return parse(path, data, p)
+}
+
+// parse overlays p with the settings in data, read from path, and
+// validates them.
+func parse(path string, data []byte, p *Project) error {
+	if err := yaml.UnmarshalWithOptions(data, p, yaml.Strict()); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if pr := p.Defaults.Priority; pr != nil && (*pr < 0 || *pr > 4) {
+		return fmt.Errorf("%s: invalid defaults.priority %d (expected 0-4)", path, *pr)
+	}
+	if p.Require.MaxTitle < 0 {
+		return fmt.Errorf("%s: invalid require.max_title %d", path, p.Require.MaxTitle)
+	}
+	for key, age := range map[string]string{"closed": p.Retention.Closed, "archived": p.Retention.Archived} {
+		if d, err := ParseAge(age); age != "" && (err != nil || d == 0) {
+			return fmt.Errorf("%s: invalid retention.%s %q (expected an age like 180d)", path, key, age)
+		}
+	}
+	switch p.IDStrategy {
+	case "", IDHash, IDSequential:
+	default:
+		return fmt.Errorf("%s: invalid id_strategy %q (expected %s or %s)", path, p.IDStrategy, IDHash, IDSequential)
+	}
+	if p.IDLength != 0 && (p.IDLength < 4 || p.IDLength > 64) {
+		return fmt.Errorf("%s: invalid id_length %d (expected 4-64)", path, p.IDLength)
+	}
+	switch p.AdvanceFrom {
+	case "open", "in_progress", "closed":
+		return fmt.Errorf("%s: invalid advance_from %q (expected a status of its own, like waiting)", path, p.AdvanceFrom)
+	}
+	switch p.Output {
+	case "", "text", "json":
+	default:
+		return fmt.Errorf("%s: invalid output %q (expected text or json)", path, p.Output)
+	}
+	return nil
+}
+
+// AutoCommit reports whether ticket changes are committed to git.
+func (p *Project) AutoCommit() bool {
+	return envBool(EnvAutoCommit, p.integrations().AutoCommit)
+}
+
+// BranchID reports whether a missing ticket ID defaults to the current
+// branch's ticket.
+func (p *Project) BranchID() bool {
+	return envBool(EnvBranchID, p.integrations().BranchID)
+}
+
+// Notify returns the webhook rules for ticket events.
+func (p *Project) Notify() string {
+	if v := os.Getenv(EnvNotify); v != "" {
+		return v
+	}
+	return p.integrations().Notify
+}
+
+// Commands returns the shell commands p runs: its hooks, keyed by event,
+// and its agent commands, keyed agent.command and agent.verify. These are
+// what kt config trust covers.
+func (p *Project) Commands() map[string]string {
+	commands := maps.Clone(p.Hooks)
+	if commands == nil {
+		commands = make(map[string]string)
+	}
+	if p.Agent.Command != "" {
+		commands["agent.command"] = p.Agent.Command
+	}
+	if p.Agent.Verify != "" {
+		commands["agent.verify"] = p.Agent.Verify
+	}
+	return commands
+}
+
+// integrations returns p's integrations, allowing a nil p.
+func (p *Project) integrations() Integrations {
+	if p == nil {
+		return Integrations{}
+	}
+	return p.Integrations
+}
+
+// envBool returns the boolean in env if it is set, else def.
+func envBool(env string, def bool) bool {
+	if v, err := strconv.ParseBool(os.Getenv(env)); err == nil {
+		return v
+	}
+	return def
+}
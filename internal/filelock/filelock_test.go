@@ -189,3 +189,46 @@ func TestConcurrentExclusiveLocks(t *testing.T) {
 	// All goroutines should have completed
 	assert.Len(t, order, goroutines)
 }
+
+func TestExclusiveAcrossRelease(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "counter.lock")
+	counter := filepath.Join(t.TempDir(), "counter")
+	require.NoError(t, os.WriteFile(counter, []byte{0}, 0644))
+
+	// Release removes the lock file while others wait on it (readers
+	// release often); the read-modify-write below must still never
+	// interleave.
+	const goroutines, rounds = 8, 20
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range rounds * 2 {
+				lock, err := AcquireShared(lockPath)
+				require.NoError(t, err)
+				require.NoError(t, lock.Release())
+			}
+		}()
+	}
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range rounds {
+				lock, err := Acquire(lockPath)
+				require.NoError(t, err)
+				data, err := os.ReadFile(counter)
+				require.NoError(t, err)
+				require.Len(t, data, 1, "another holder is mid-write")
+				require.NoError(t, os.WriteFile(counter, []byte{data[0] + 1}, 0644))
+				require.NoError(t, lock.Release())
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, byte(goroutines*rounds), data[0])
+}
@@ -0,0 +1,282 @@
+// Package git wraps the git command line for the commands that integrate
+// with the repository the tickets live in.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Commit is a commit's full SHA and message.
+type Commit struct {
+	SHA     string `json:"sha"`
+	Message string `json:"message"`
+}
+
+// Subject returns the first line of the commit message.
+func (c Commit) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return subject
+}
+
+// Short returns the abbreviated SHA.
+func (c Commit) Short() string {
+	if len(c.SHA) > 7 {
+		return c.SHA[:7]
+	}
+	return c.SHA
+}
+
+// Run executes git in the current directory and returns trimmed stdout.
+func Run(args ...string) (string, error) {
+	return RunIn("", args...)
+}
+
+// RunIn executes git in dir (the current directory if empty) and returns
+// trimmed stdout.
+func RunIn(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Log returns commits reachable from HEAD, newest first. If since is set,
+// only commits after it (since..HEAD) are returned.
+func Log(since string) ([]Commit, error) {
+	rev := "HEAD"
+	if since != "" {
+		rev = since + "..HEAD"
+	}
+	// Records separated by RS, SHA and body by US
+	out, err := Run("log", "--format=%H%x1f%B%x1e", rev)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, rec := range strings.Split(out, "\x1e") {
+		rec = strings.TrimSpace(rec)
+		if rec == "" {
+			continue
+		}
+		sha, msg, _ := strings.Cut(rec, "\x1f")
+		commits = append(commits, Commit{SHA: sha, Message: strings.TrimSpace(msg)})
+	}
+	return commits, nil
+}
+
+// ResolveCommit returns the full SHA of a commit-ish (abbreviated SHA,
+// branch, tag, HEAD~1, ...).
+func ResolveCommit(rev string) (string, error) {
+	sha, err := Run("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown commit %q", rev)
+	}
+	return sha, nil
+}
+
+// FileAt returns the contents of the file at path as of commit rev, and
+// false if the file did not exist in that commit.
+func FileAt(rev, path string) ([]byte, bool, error) {
+	dir, name := filepath.Split(path)
+	if _, err := RunIn(dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		return nil, false, fmt.Errorf("unknown commit %q", rev)
+	}
+	return show(dir, rev+":./"+name)
+}
+
+// show returns the contents of the blob named by obj (rev:path), and
+// false if there is none.
+func show(dir, obj string) ([]byte, bool, error) {
+	if _, err := RunIn(dir, "cat-file", "-e", obj); err != nil {
+		return nil, false, nil
+	}
+	out, err := RunIn(dir, "show", obj)
+	if err != nil {
+		return nil, false, err
+	}
+	return []byte(out + "\n"), true, nil
+}
+
+// CommitInfo is who made a commit, when, and its subject line.
+type CommitInfo struct {
+	SHA     string `json:"sha"`
+	Author  string `json:"author"`
+	Date    string `json:"date"` // author date, RFC 3339
+	Subject string `json:"subject"`
+}
+
+// infoFormat is the git log format parsed by parseInfo.
+const infoFormat = "%H%x1f%an%x1f%aI%x1f%s"
+
+// parseInfo parses a line in infoFormat.
+func parseInfo(line string) (CommitInfo, bool) {
+	fields := strings.Split(line, "\x1f")
+	if len(fields) != 4 {
+		return CommitInfo{}, false
+	}
+	return CommitInfo{SHA: fields[0], Author: fields[1], Date: fields[2], Subject: fields[3]}, true
+}
+
+// Describe returns the author, date, and subject of commit rev.
+func Describe(rev string) (CommitInfo, error) {
+	out, err := Run("show", "--no-patch", "--format="+infoFormat, rev+"^{commit}")
+	if err != nil {
+		return CommitInfo{}, fmt.Errorf("unknown commit %q", rev)
+	}
+	info, ok := parseInfo(out)
+	if !ok {
+		return CommitInfo{}, fmt.Errorf("unexpected git show output for %s", rev)
+	}
+	return info, nil
+}
+
+// FileCommit is a commit that changed a file.
+type FileCommit struct {
+	CommitInfo
+	Path string `json:"-"` // the file's path from the repository root in this commit
+}
+
+// FileLog returns the commits that changed the file at path, newest
+// first, following it across renames.
+func FileLog(path string) ([]FileCommit, error) {
+	dir, name := filepath.Split(path)
+	// Records start with RS; header fields are separated by US, then come the file names
+	out, err := RunIn(dir, "log", "--follow", "--name-only", "--format=%x1e"+infoFormat, "--", name)
+	if err != nil {
+		return nil, err
+	}
+	var commits []FileCommit
+	for _, rec := range strings.Split(out, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(rec), "\n")
+		info, ok := parseInfo(lines[0])
+		if !ok || len(lines) < 2 {
+			continue
+		}
+		commits = append(commits, FileCommit{CommitInfo: info, Path: strings.TrimSpace(lines[len(lines)-1])})
+	}
+	return commits, nil
+}
+
+// LastChange returns the latest commit that changed the file at path, and
+// false if none has (or path is not in a git repository).
+func LastChange(path string) (CommitInfo, bool) {
+	dir, name := filepath.Split(path)
+	out, err := RunIn(dir, "log", "-1", "--format="+infoFormat, "--", name)
+	if err != nil {
+		return CommitInfo{}, false
+	}
+	return parseInfo(out)
+}
+
+// Contents returns the contents of the file at path from the repository root
+// in commit c, and false if c deleted it. dir is any directory in the
+// repository.
+func (c FileCommit) Contents(dir string) ([]byte, bool, error) {
+	return show(dir, c.SHA+":"+c.Path)
+}
+
+// CurrentBranch returns the checked-out branch name, or an error when HEAD
+// is detached.
+func CurrentBranch() (string, error) {
+	return Run("symbolic-ref", "--short", "HEAD")
+}
+
+// HooksDir returns the repository's hooks directory.
+func HooksDir() (string, error) {
+	return Run("rev-parse", "--path-format=absolute", "--git-path", "hooks")
+}
+
+// CommitPaths stages paths (including deletions) and commits only those
+// paths with msg. Paths git has never seen and that no longer exist are
+// skipped. Returns false if there was nothing to commit.
+func CommitPaths(dir, msg string, paths []string) (bool, error) {
+	var keep []string
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			keep = append(keep, p)
+		} else if _, err := RunIn(dir, "ls-files", "--error-unmatch", "--", p); err == nil {
+			keep = append(keep, p)
+		}
+	}
+	if len(keep) == 0 {
+		return false, nil
+	}
+
+	if _, err := RunIn(dir, append([]string{"add", "-A", "--"}, keep...)...); err != nil {
+		return false, err
+	}
+	if _, err := RunIn(dir, append([]string{"diff", "--cached", "--quiet", "--"}, keep...)...); err == nil {
+		return false, nil // staged content matches HEAD
+	}
+	if _, err := RunIn(dir, append([]string{"commit", "-q", "-m", msg, "--"}, keep...)...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// AddWorktree creates a worktree at path checked out on branch, creating
+// the branch from HEAD if it does not exist. dir is any directory in the
+// repository.
+func AddWorktree(dir, path, branch string) error {
+	args := []string{"worktree", "add", "-q", path, branch}
+	if _, err := RunIn(dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err != nil {
+		args = []string{"worktree", "add", "-q", "-b", branch, path}
+	}
+	_, err := RunIn(dir, args...)
+	return err
+}
+
+// RemoveWorktree removes the worktree at path. Unless force is set, git
+// refuses when it has uncommitted changes. The branch is kept.
+func RemoveWorktree(dir, path string, force bool) error {
+	args := []string{"worktree", "remove", path}
+	if force {
+		args = []string{"worktree", "remove", "--force", path}
+	}
+	_, err := RunIn(dir, args...)
+	return err
+}
+
+// Author is a commit author and their number of commits.
+type Author struct {
+	Name    string `json:"name"`
+	Commits int    `json:"commits"`
+}
+
+// Authors returns the authors of non-merge commits touching paths, most
+// active first.
+func Authors(dir string, paths []string) ([]Author, error) {
+	out, err := RunIn(dir, append([]string{"shortlog", "-sn", "--no-merges", "HEAD", "--"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+	var authors []Author
+	for _, line := range strings.Split(out, "\n") {
+		count, name, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			continue
+		}
+		authors = append(authors, Author{Name: name, Commits: n})
+	}
+	return authors, nil
+}
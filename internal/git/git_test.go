@@ -0,0 +1,236 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initRepo creates a repository in a temp dir and changes into it.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	_, err := Run("init", "-q", "-b", "main")
+	require.NoError(t, err)
+	return dir
+}
+
+func commit(t *testing.T, msg string) string {
+	t.Helper()
+	_, err := Run("commit", "-q", "--allow-empty", "-m", msg)
+	require.NoError(t, err)
+	sha, err := Run("rev-parse", "HEAD")
+	require.NoError(t, err)
+	return sha
+}
+
+func TestLog(t *testing.T) {
+	initRepo(t)
+	first := commit(t, "first")
+	commit(t, "second\n\nfixes kt-1234")
+
+	commits, err := Log("")
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "second", commits[0].Subject())
+	assert.Equal(t, "second\n\nfixes kt-1234", commits[0].Message)
+	assert.Equal(t, first, commits[1].SHA)
+	assert.Len(t, commits[1].Short(), 7)
+
+	commits, err = Log(first)
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+
+	_, err = Log("no-such-ref")
+	assert.Error(t, err)
+}
+
+func TestHooksDir(t *testing.T) {
+	dir := initRepo(t)
+	hooks, err := HooksDir()
+	require.NoError(t, err)
+	want, _ := filepath.EvalSymlinks(filepath.Join(dir, ".git", "hooks"))
+	got, _ := filepath.EvalSymlinks(hooks)
+	assert.Equal(t, want, got)
+}
+
+func TestCurrentBranch(t *testing.T) {
+	initRepo(t)
+	commit(t, "first")
+
+	branch, err := CurrentBranch()
+	require.NoError(t, err)
+	assert.Equal(t, "main", branch)
+
+	_, err = Run("checkout", "-q", "--detach")
+	require.NoError(t, err)
+	_, err = CurrentBranch()
+	assert.Error(t, err)
+}
+
+func TestCommitPaths(t *testing.T) {
+	dir := initRepo(t)
+	commit(t, "first")
+
+	a := filepath.Join(dir, "a.md")
+	b := filepath.Join(dir, "b.md")
+	other := filepath.Join(dir, "other.txt")
+	require.NoError(t, os.WriteFile(a, []byte("a"), 0644))
+	require.NoError(t, os.WriteFile(b, []byte("b"), 0644))
+	require.NoError(t, os.WriteFile(other, []byte("x"), 0644))
+
+	ok, err := CommitPaths(dir, "kt: add", []string{a, b, filepath.Join(dir, "gone.md")})
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	files, err := Run("show", "--name-only", "--format=%s", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "kt: add\n\na.md\nb.md", files)
+
+	// Unrelated untracked file stays out of the commit
+	status, err := Run("status", "--porcelain")
+	require.NoError(t, err)
+	assert.Equal(t, "?? other.txt", status)
+
+	// Deletions are committed; unchanged paths are a no-op
+	require.NoError(t, os.Remove(a))
+	ok, err = CommitPaths(dir, "kt: rm", []string{a})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = CommitPaths(dir, "kt: noop", []string{b})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestFileAt(t *testing.T) {
+	dir := initRepo(t)
+	sub := filepath.Join(dir, "sub")
+	require.NoError(t, os.Mkdir(sub, 0755))
+	path := filepath.Join(sub, "a.md")
+	require.NoError(t, os.WriteFile(path, []byte("one\n"), 0644))
+	_, err := CommitPaths(dir, "add", []string{path})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("two\n"), 0644))
+	_, err = CommitPaths(dir, "edit", []string{path})
+	require.NoError(t, err)
+
+	data, ok, err := FileAt("HEAD~1", path)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "one\n", string(data))
+
+	_, ok, err = FileAt("HEAD", filepath.Join(sub, "b.md"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = FileAt("nope", path)
+	assert.ErrorContains(t, err, "unknown commit")
+}
+
+func TestFileLog(t *testing.T) {
+	dir := initRepo(t)
+	a := filepath.Join(dir, "a.md")
+	b := filepath.Join(dir, "b.md")
+	require.NoError(t, os.WriteFile(a, []byte("one\ntwo\nthree\n"), 0644))
+	_, err := CommitPaths(dir, "add a", []string{a})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(a, []byte("one\ntwo\nthree\nfour\n"), 0644))
+	_, err = CommitPaths(dir, "edit a", []string{a})
+	require.NoError(t, err)
+	_, err = Run("mv", "a.md", "b.md")
+	require.NoError(t, err)
+	_, err = Run("commit", "-q", "-m", "rename")
+	require.NoError(t, err)
+
+	commits, err := FileLog(b)
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+	assert.Equal(t, []string{"rename", "edit a", "add a"}, []string{commits[0].Subject, commits[1].Subject, commits[2].Subject})
+	assert.Equal(t, "b.md", commits[0].Path)
+	assert.Equal(t, "a.md", commits[2].Path)
+	assert.Equal(t, "test", commits[0].Author)
+
+	data, ok, err := commits[2].Contents(dir)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "one\ntwo\nthree\n", string(data))
+}
+
+func TestResolveCommit(t *testing.T) {
+	initRepo(t)
+	sha := commit(t, "first")
+
+	got, err := ResolveCommit(sha[:7])
+	require.NoError(t, err)
+	assert.Equal(t, sha, got)
+
+	_, err = ResolveCommit("no-such-ref")
+	assert.EqualError(t, err, `unknown commit "no-such-ref"`)
+}
+
+func TestDescribe(t *testing.T) {
+	initRepo(t)
+	sha := commit(t, "first\n\nbody")
+
+	info, err := Describe(sha[:7])
+	require.NoError(t, err)
+	assert.Equal(t, sha, info.SHA)
+	assert.Equal(t, "test", info.Author)
+	assert.Equal(t, "first", info.Subject)
+	assert.NotEmpty(t, info.Date)
+
+	_, err = Describe("no-such-ref")
+	assert.EqualError(t, err, `unknown commit "no-such-ref"`)
+}
+
+func TestWorktree(t *testing.T) {
+	dir := initRepo(t)
+	commit(t, "first")
+	path := filepath.Join(t.TempDir(), "wt")
+
+	require.NoError(t, AddWorktree(dir, path, "kt-a1b2"))
+	branch, err := RunIn(path, "symbolic-ref", "--short", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "kt-a1b2", branch)
+
+	// Dirty worktrees need force
+	require.NoError(t, os.WriteFile(filepath.Join(path, "wip.txt"), []byte("x"), 0644))
+	require.Error(t, RemoveWorktree(dir, path, false))
+	require.NoError(t, RemoveWorktree(dir, path, true))
+	assert.NoDirExists(t, path)
+
+	// Re-adding reuses the existing branch
+	require.NoError(t, AddWorktree(dir, path, "kt-a1b2"))
+}
+
+func TestAuthors(t *testing.T) {
+	dir := initRepo(t)
+	write := func(name, author string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(author+name), 0644))
+		_, err := Run("add", name)
+		require.NoError(t, err)
+		_, err = Run("-c", "user.name="+author, "commit", "-q", "--author", author+" <"+author+"@example.com>", "-m", name)
+		require.NoError(t, err)
+	}
+	write("a.go", "alice")
+	write("a.go", "bob")
+	write("a_test.go", "bob")
+	write("b.go", "alice")
+
+	authors, err := Authors(dir, []string{"a.go", "a_test.go"})
+	require.NoError(t, err)
+	assert.Equal(t, []Author{{Name: "bob", Commits: 2}, {Name: "alice", Commits: 1}}, authors)
+
+	authors, err = Authors(dir, []string{"missing.go"})
+	require.NoError(t, err)
+	assert.Empty(t, authors)
+}
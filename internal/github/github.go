@@ -0,0 +1,183 @@
+// Package github is a minimal client for the GitHub Issues REST API.
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the GitHub REST API endpoint.
+const DefaultBaseURL = "https://api.github.com"
+
+// Issue is a GitHub issue. Pull requests, which the issues API also
+// returns, have PullRequest set.
+type Issue struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	State       string    `json:"state"`
+	Labels      []Label   `json:"labels"`
+	Assignee    *User     `json:"assignee"`
+	CreatedAt   time.Time `json:"created_at"`
+	ClosedAt    time.Time `json:"closed_at"`
+	PullRequest *struct{} `json:"pull_request,omitempty"`
+}
+
+// Label is an issue label.
+type Label struct {
+	Name string `json:"name"`
+}
+
+// User is a GitHub account.
+type User struct {
+	Login string `json:"login"`
+}
+
+// Client calls the GitHub API.
+type Client struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+// NewClient returns a client for api.github.com authenticated with
+// GITHUB_TOKEN or GH_TOKEN, if set.
+func NewClient() *Client {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	return &Client{BaseURL: DefaultBaseURL, Token: token, HTTP: http.DefaultClient}
+}
+
+var repoPattern = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+
+// ValidateRepo checks that repo has the form owner/name.
+func ValidateRepo(repo string) error {
+	if !repoPattern.MatchString(repo) {
+		return fmt.Errorf("invalid repo %q (expected owner/name)", repo)
+	}
+	return nil
+}
+
+// Issues returns the repository's issues in state (open, closed, or all),
+// excluding pull requests.
+func (c *Client) Issues(repo, state string) ([]Issue, error) {
+	if err := ValidateRepo(repo); err != nil {
+		return nil, err
+	}
+	q := url.Values{"state": {state}, "per_page": {"100"}}
+	next := fmt.Sprintf("%s/repos/%s/issues?%s", c.BaseURL, repo, q.Encode())
+
+	var issues []Issue
+	for next != "" {
+		var page []Issue
+		resp, err := c.do(http.MethodGet, next, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, is := range page {
+			if is.PullRequest == nil {
+				issues = append(issues, is)
+			}
+		}
+		next = nextLink(resp.Header.Get("Link"))
+	}
+	return issues, nil
+}
+
+// Issue returns a single issue.
+func (c *Client) Issue(repo string, number int) (*Issue, error) {
+	if err := ValidateRepo(repo); err != nil {
+		return nil, err
+	}
+	var is Issue
+	if _, err := c.do(http.MethodGet, fmt.Sprintf("%s/repos/%s/issues/%d", c.BaseURL, repo, number), nil, &is); err != nil {
+		return nil, err
+	}
+	return &is, nil
+}
+
+// SetState opens or closes an issue (state is "open" or "closed").
+func (c *Client) SetState(repo string, number int, state string) error {
+	if err := ValidateRepo(repo); err != nil {
+		return err
+	}
+	_, err := c.do(http.MethodPatch, fmt.Sprintf("%s/repos/%s/issues/%d", c.BaseURL, repo, number),
+		map[string]string{"state": state}, nil)
+	return err
+}
+
+// Comment adds a comment to an issue.
+func (c *Client) Comment(repo string, number int, body string) error {
+	if err := ValidateRepo(repo); err != nil {
+		return err
+	}
+	_, err := c.do(http.MethodPost, fmt.Sprintf("%s/repos/%s/issues/%d/comments", c.BaseURL, repo, number),
+		map[string]string{"body": body}, nil)
+	return err
+}
+
+// do sends a request with an optional JSON body and decodes a JSON response
+// into out, if non-nil.
+func (c *Client) do(method, u string, body any, out any) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = strings.NewReader(string(data))
+	}
+	req, err := http.NewRequest(method, u, r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("github: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = resp.Status
+		}
+		return nil, fmt.Errorf("github: %s %s: %s", method, req.URL.Path, apiErr.Message)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, fmt.Errorf("github: decode response: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextLink returns the rel="next" URL from a Link header, or "".
+func nextLink(header string) string {
+	if m := nextLinkPattern.FindStringSubmatch(header); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssues(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/acme/app/issues", r.URL.Path)
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/acme/app/issues?state=open&page=2>; rel="next"`, srv.URL))
+			fmt.Fprint(w, `[{"number":1,"title":"Bug","state":"open","labels":[{"name":"bug"}],"assignee":{"login":"alice"}},
+				{"number":2,"title":"PR","state":"open","pull_request":{}}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"number":3,"title":"Later","state":"open","closed_at":null}]`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL, Token: "secret", HTTP: srv.Client()}
+	issues, err := c.Issues("acme/app", "open")
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	assert.Equal(t, 1, issues[0].Number)
+	assert.Equal(t, "alice", issues[0].Assignee.Login)
+	assert.Equal(t, []Label{{Name: "bug"}}, issues[0].Labels)
+	assert.Equal(t, 3, issues[1].Number)
+}
+
+func TestIssuesError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Not Found"}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL, HTTP: srv.Client()}
+	_, err := c.Issues("acme/missing", "open")
+	assert.EqualError(t, err, "github: GET /repos/acme/missing/issues: Not Found")
+
+	_, err = c.Issues("not a repo", "open")
+	assert.EqualError(t, err, `invalid repo "not a repo" (expected owner/name)`)
+}
+
+func TestIssueUpdates(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{"number":5,"title":"T","state":"closed","closed_at":"2026-01-05T00:00:00Z"}`)
+			return
+		}
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		fmt.Fprint(w, `{}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL, HTTP: srv.Client()}
+	is, err := c.Issue("acme/app", 5)
+	require.NoError(t, err)
+	assert.Equal(t, "closed", is.State)
+	assert.Equal(t, 2026, is.ClosedAt.Year())
+
+	require.NoError(t, c.SetState("acme/app", 5, "open"))
+	require.NoError(t, c.Comment("acme/app", 5, "hi"))
+	assert.Equal(t, []string{
+		"GET /repos/acme/app/issues/5 ",
+		`PATCH /repos/acme/app/issues/5 {"state":"open"}`,
+		`POST /repos/acme/app/issues/5/comments {"body":"hi"}`,
+	}, calls)
+}
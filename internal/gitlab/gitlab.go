@@ -0,0 +1,102 @@
+// Package gitlab is a minimal client for the GitLab Issues REST API.
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is gitlab.com.
+const DefaultBaseURL = "https://gitlab.com"
+
+// Issue is a GitLab issue. IID is the project-scoped number shown in the UI.
+type Issue struct {
+	IID         int       `json:"iid"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	State       string    `json:"state"` // opened or closed
+	Labels      []string  `json:"labels"`
+	Assignee    *User     `json:"assignee"`
+	CreatedAt   time.Time `json:"created_at"`
+	ClosedAt    time.Time `json:"closed_at"`
+}
+
+// User is a GitLab account.
+type User struct {
+	Username string `json:"username"`
+}
+
+// Client calls the GitLab API.
+type Client struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+// NewClient returns a client for GITLAB_URL (default gitlab.com)
+// authenticated with GITLAB_TOKEN, if set.
+func NewClient() *Client {
+	base := os.Getenv("GITLAB_URL")
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	return &Client{BaseURL: strings.TrimRight(base, "/"), Token: os.Getenv("GITLAB_TOKEN"), HTTP: http.DefaultClient}
+}
+
+// Issues returns a project's issues in state (opened, closed, or all).
+// project is the full path, e.g. group/subgroup/name.
+func (c *Client) Issues(project, state string) ([]Issue, error) {
+	if !strings.Contains(project, "/") {
+		return nil, fmt.Errorf("invalid project %q (expected group/name)", project)
+	}
+	var issues []Issue
+	for page := "1"; page != ""; {
+		q := url.Values{"state": {state}, "per_page": {"100"}, "page": {page}}
+		u := fmt.Sprintf("%s/api/v4/projects/%s/issues?%s", c.BaseURL, url.PathEscape(project), q.Encode())
+		req, err := http.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if c.Token != "" {
+			req.Header.Set("PRIVATE-TOKEN", c.Token)
+		}
+
+		resp, err := c.HTTP.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("gitlab: %w", err)
+		}
+		var batch []Issue
+		err = decode(resp, &batch)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, batch...)
+		page = resp.Header.Get("X-Next-Page")
+	}
+	return issues, nil
+}
+
+// decode checks the response status and decodes its JSON body into out.
+func decode(resp *http.Response, out any) error {
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message any `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		msg := resp.Status
+		if apiErr.Message != nil {
+			msg = fmt.Sprint(apiErr.Message)
+		}
+		return fmt.Errorf("gitlab: %s: %s", resp.Request.URL.Path, msg)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("gitlab: decode response: %w", err)
+	}
+	return nil
+}
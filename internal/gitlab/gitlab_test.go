@@ -0,0 +1,50 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects/acme%2Fapp/issues", r.URL.EscapedPath())
+		assert.Equal(t, "opened", r.URL.Query().Get("state"))
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("X-Next-Page", "2")
+			fmt.Fprint(w, `[{"iid":1,"title":"Bug","state":"opened","labels":["bug"],"assignee":{"username":"alice"}}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"iid":2,"title":"Later","state":"opened","closed_at":null}]`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL, Token: "secret", HTTP: srv.Client()}
+	issues, err := c.Issues("acme/app", "opened")
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	assert.Equal(t, 1, issues[0].IID)
+	assert.Equal(t, "alice", issues[0].Assignee.Username)
+	assert.Equal(t, []string{"bug"}, issues[0].Labels)
+	assert.Equal(t, 2, issues[1].IID)
+}
+
+func TestIssuesError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"404 Project Not Found"}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL, HTTP: srv.Client()}
+	_, err := c.Issues("acme/missing", "opened")
+	assert.ErrorContains(t, err, "404 Project Not Found")
+
+	_, err = c.Issues("nogroup", "opened")
+	assert.EqualError(t, err, `invalid project "nogroup" (expected group/name)`)
+}
@@ -0,0 +1,122 @@
+// Package markdown renders the subset of markdown used in ticket bodies
+// for display in a terminal.
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ANSI escape codes.
+const (
+	reset     = "\033[0m"
+	bold      = "\033[1m"
+	dim       = "\033[2m"
+	italic    = "\033[3m"
+	underline = "\033[4m"
+	cyan      = "\033[36m"
+	green     = "\033[32m"
+)
+
+// Renderer converts markdown to terminal text. With Color unset, only the
+// structural changes (bullets, checkboxes, stripped markup) are applied.
+type Renderer struct {
+	Color bool
+}
+
+var (
+	headingRe  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	bulletRe   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	checkboxRe = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	numberedRe = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	quoteRe    = regexp.MustCompile(`^>\s?(.*)$`)
+	ruleRe     = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	codeRe     = regexp.MustCompile("`([^`]+)`")
+	boldRe     = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	italicRe   = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b`)
+	linkRe     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// Render converts src line by line.
+func (r Renderer) Render(src string) string {
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(src, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			out = append(out, "    "+r.style(dim, line))
+			continue
+		}
+		out = append(out, r.line(line))
+	}
+	return strings.Join(out, "\n")
+}
+
+func (r Renderer) line(line string) string {
+	if m := headingRe.FindStringSubmatch(line); m != nil {
+		text := r.inline(m[2])
+		if len(m[1]) <= 2 {
+			return r.style(bold+underline, text)
+		}
+		return r.style(bold, text)
+	}
+	if ruleRe.MatchString(line) {
+		return r.style(dim, strings.Repeat("─", 40))
+	}
+	if m := quoteRe.FindStringSubmatch(line); m != nil {
+		return r.style(dim, "│ ") + r.style(italic, r.inline(m[1]))
+	}
+	if m := bulletRe.FindStringSubmatch(line); m != nil {
+		indent, item := m[1], m[2]
+		if c := checkboxRe.FindStringSubmatch(item); c != nil {
+			if c[1] == " " {
+				return indent + "☐ " + r.inline(c[2])
+			}
+			return indent + r.style(green, "☑") + " " + r.style(dim, r.inline(c[2]))
+		}
+		return indent + "• " + r.inline(item)
+	}
+	if m := numberedRe.FindStringSubmatch(line); m != nil {
+		return m[1] + m[2] + ". " + r.inline(m[3])
+	}
+	return r.inline(line)
+}
+
+// inline renders code spans, emphasis, and links. Code spans are replaced
+// first so markup inside them is left alone.
+func (r Renderer) inline(s string) string {
+	var codes []string
+	s = codeRe.ReplaceAllStringFunc(s, func(m string) string {
+		codes = append(codes, r.style(cyan, codeRe.FindStringSubmatch(m)[1]))
+		return "\x00" + strconv.Itoa(len(codes)-1) + "\x00"
+	})
+
+	s = linkRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := linkRe.FindStringSubmatch(m)
+		return r.style(underline, sub[1]) + " (" + sub[2] + ")"
+	})
+	s = boldRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := boldRe.FindStringSubmatch(m)
+		return r.style(bold, sub[1]+sub[2])
+	})
+	s = italicRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := italicRe.FindStringSubmatch(m)
+		return r.style(italic, sub[1]+sub[2])
+	})
+
+	for i, c := range codes {
+		s = strings.Replace(s, "\x00"+strconv.Itoa(i)+"\x00", c, 1)
+	}
+	return s
+}
+
+func (r Renderer) style(code, s string) string {
+	if !r.Color || s == "" {
+		return s
+	}
+	return code + s + reset
+}
@@ -0,0 +1,43 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderPlain(t *testing.T) {
+	r := Renderer{}
+	tests := []struct {
+		in, want string
+	}{
+		{"# Title", "Title"},
+		{"- item", "• item"},
+		{"  * nested", "  • nested"},
+		{"- [ ] todo", "☐ todo"},
+		{"- [x] done", "☑ done"},
+		{"1) first", "1. first"},
+		{"> quoted", "│ quoted"},
+		{"Use **bold** and *em* and _em_", "Use bold and em and em"},
+		{"See [docs](https://example.com)", "See docs (https://example.com)"},
+		{"Keep `**raw**` code", "Keep **raw** code"},
+		{"snake_case_name stays", "snake_case_name stays"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.Render(tt.in))
+		})
+	}
+}
+
+func TestRenderCodeFence(t *testing.T) {
+	got := Renderer{}.Render("before\n```go\nx := *p\n```\nafter")
+	assert.Equal(t, "before\n    x := *p\nafter", got)
+}
+
+func TestRenderColor(t *testing.T) {
+	got := Renderer{Color: true}.Render("## Heading with `code`")
+	assert.True(t, strings.HasPrefix(got, bold+underline))
+	assert.Contains(t, got, cyan+"code"+reset)
+}
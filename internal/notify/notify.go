@@ -0,0 +1,253 @@
+// Package notify derives events from ticket changes and posts them to
+// Slack and Discord incoming webhooks.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+)
+
+// EventType names a kind of ticket event.
+type EventType string
+
+const (
+	EventCreated       EventType = "ticket_created"
+	EventClosed        EventType = "ticket_closed"
+	EventReopened      EventType = "reopened"
+	EventStatus        EventType = "status" // any other status change
+	EventDeleted       EventType = "deleted"
+	EventP0Opened      EventType = "p0_opened"
+	EventEpicCompleted EventType = "epic_completed" // last child of an epic closed
+	EventTestsPassed   EventType = "tests_passed"
+)
+
+// EventTypes lists every event type.
+var EventTypes = []EventType{EventCreated, EventClosed, EventReopened, EventStatus,
+	EventDeleted, EventP0Opened, EventEpicCompleted, EventTestsPassed}
+
+// eventAliases maps the names created and closed events had before, still
+// accepted in rules and hooks, to their event types.
+var eventAliases = map[string]EventType{"created": EventCreated, "closed": EventClosed}
+
+// KnownEvent reports whether name is an event type or an old name of one.
+func KnownEvent(name string) bool {
+	_, alias := eventAliases[name]
+	return alias || slices.Contains(EventTypes, EventType(name))
+}
+
+// Names returns t's name followed by the old names it still answers to.
+func (t EventType) Names() []string {
+	names := []string{string(t)}
+	for alias, typ := range eventAliases {
+		if typ == t {
+			names = append(names, alias)
+		}
+	}
+	return names
+}
+
+// Matches reports whether a rule or hook key, "*" or a name of an event
+// type, selects events of type t.
+func (t EventType) Matches(key string) bool {
+	return key == "*" || slices.Contains(t.Names(), key)
+}
+
+// Event is something that happened to a ticket.
+type Event struct {
+	Type   EventType
+	Ticket *ticket.Ticket
+}
+
+// Text renders the event as a one-line chat message.
+func (e Event) Text() string {
+	t := e.Ticket
+	switch e.Type {
+	case EventCreated:
+		return fmt.Sprintf("%s created: %s (%s, P%d)", t.ID, t.Title, t.Type, t.Priority)
+	case EventClosed:
+		return fmt.Sprintf("%s closed: %s", t.ID, t.Title)
+	case EventP0Opened:
+		return fmt.Sprintf(":rotating_light: P0 %s opened: %s", t.ID, t.Title)
+	case EventEpicCompleted:
+		return fmt.Sprintf(":tada: Epic %s completed: %s", t.ID, t.Title)
+	case EventStatus:
+		return fmt.Sprintf("%s is now %s: %s", t.ID, t.Status, t.Title)
+	case EventTestsPassed:
+		return fmt.Sprintf("%s tests passed: %s", t.ID, t.Title)
+	}
+	return fmt.Sprintf("%s %s: %s", t.ID, e.Type, t.Title)
+}
+
+// Events derives events from an operation's changes. tickets is the store
+// after the operation, used to tell whether an epic's children are done.
+func Events(changes []store.Change, tickets []*ticket.Ticket) []Event {
+	var events []Event
+	var closedParents []string
+	for _, c := range changes {
+		before, after := c.Before, c.After
+		switch {
+		case after == nil:
+			events = append(events, Event{EventDeleted, before})
+			continue
+		case before == nil:
+			events = append(events, Event{EventCreated, after})
+		case before.Status != after.Status && after.Status == ticket.StatusClosed:
+			events = append(events, Event{EventClosed, after})
+			if after.Parent != "" {
+				closedParents = append(closedParents, after.Parent)
+			}
+		case before.Status == ticket.StatusClosed && after.Status != ticket.StatusClosed:
+			events = append(events, Event{EventReopened, after})
+		case before.Status != after.Status:
+			events = append(events, Event{EventStatus, after})
+		}
+		if before != nil && !before.TestsPassed && after.TestsPassed {
+			events = append(events, Event{EventTestsPassed, after})
+		}
+
+		wasP0 := before != nil && before.Status != ticket.StatusClosed && before.Priority == 0
+		if after.Priority == 0 && after.Status != ticket.StatusClosed && !wasP0 {
+			events = append(events, Event{EventP0Opened, after})
+		}
+	}
+
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(closedParents))) {
+		if epic := epicCompleted(id, tickets); epic != nil {
+			events = append(events, Event{EventEpicCompleted, epic})
+		}
+	}
+	return events
+}
+
+// epicCompleted returns the epic with the given ID if all its children are
+// closed.
+func epicCompleted(id string, tickets []*ticket.Ticket) *ticket.Ticket {
+	var epic *ticket.Ticket
+	for _, t := range tickets {
+		if t.ID == id {
+			epic = t
+		} else if t.Parent == id && t.Status != ticket.StatusClosed {
+			return nil
+		}
+	}
+	if epic == nil || epic.Type != ticket.TypeEpic {
+		return nil
+	}
+	return epic
+}
+
+// Rule sends events of one type (or "*" for all) to a webhook.
+type Rule struct {
+	Event   string // a name of an EventType or "*"
+	Service string // slack or discord
+	URL     string
+}
+
+// ParseRules parses comma-separated event=service:url rules, e.g.
+// "p0_opened=slack:https://hooks.slack.com/...,*=discord:https://...".
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+	for _, spec := range strings.Split(s, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		event, target, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid notify rule %q (expected event=slack|discord:https://...)", spec)
+		}
+		if event != "*" && !KnownEvent(event) {
+			return nil, fmt.Errorf("unknown notify event %q", event)
+		}
+		r, err := ParseTarget(target)
+		if err != nil {
+			return nil, err
+		}
+		r.Event = event
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// ParseTarget parses a webhook target, service:url, into a rule for all
+// events.
+func ParseTarget(s string) (Rule, error) {
+	service, url, ok := strings.Cut(s, ":")
+	if !ok || !strings.HasPrefix(url, "http") {
+		return Rule{}, fmt.Errorf("invalid webhook %q (expected slack|discord:https://...)", s)
+	}
+	if service != "slack" && service != "discord" {
+		return Rule{}, fmt.Errorf("unknown notify service %q (expected slack or discord)", service)
+	}
+	return Rule{Event: "*", Service: service, URL: url}, nil
+}
+
+// Notifier posts events to the webhooks whose rules match them.
+type Notifier struct {
+	Rules []Rule
+	HTTP  *http.Client
+}
+
+// New returns a Notifier with a short HTTP timeout, so a slow webhook does
+// not hold up the command.
+func New(rules []Rule) *Notifier {
+	return &Notifier{Rules: rules, HTTP: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// Send posts each event to every matching webhook and returns the joined
+// delivery errors.
+func (n *Notifier) Send(events []Event) error {
+	var errs []error
+	for _, e := range events {
+		for _, r := range n.Rules {
+			if !e.Type.Matches(r.Event) {
+				continue
+			}
+			if err := n.post(r, e.Text()); err != nil {
+				errs = append(errs, fmt.Errorf("notify %s (%s): %w", r.Service, e.Type, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Post posts a message to every rule's webhook, whatever its event, and
+// returns the joined delivery errors.
+func (n *Notifier) Post(text string) error {
+	var errs []error
+	for _, r := range n.Rules {
+		if err := n.post(r, text); err != nil {
+			errs = append(errs, fmt.Errorf("notify %s: %w", r.Service, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) post(r Rule, text string) error {
+	payload := map[string]string{"text": text}
+	if r.Service == "discord" {
+		payload = map[string]string{"content": text}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := n.HTTP.Post(r.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
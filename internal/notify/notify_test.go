@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/store"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tk(id string, status ticket.Status, priority int) *ticket.Ticket {
+	return &ticket.Ticket{ID: id, Title: "T " + id, Status: status, Priority: priority, Type: ticket.TypeTask}
+}
+
+func TestEvents(t *testing.T) {
+	epic := &ticket.Ticket{ID: "kt-epic", Title: "Epic", Status: ticket.StatusOpen, Type: ticket.TypeEpic}
+	child := tk("kt-child", ticket.StatusClosed, 2)
+	child.Parent = epic.ID
+	doneSibling := tk("kt-sib", ticket.StatusClosed, 2)
+	doneSibling.Parent = epic.ID
+	childBefore := *child
+	childBefore.Status = ticket.StatusInProgress
+	passed := tk("kt-pass", ticket.StatusOpen, 2)
+	passed.TestsPassed = true
+
+	changes := []store.Change{
+		{Before: nil, After: tk("kt-new", ticket.StatusOpen, 0)},
+		{Before: &childBefore, After: child},
+		{Before: tk("kt-re", ticket.StatusClosed, 2), After: tk("kt-re", ticket.StatusOpen, 2)},
+		{Before: tk("kt-st", ticket.StatusOpen, 2), After: tk("kt-st", ticket.StatusInProgress, 2)},
+		{Before: tk("kt-del", ticket.StatusOpen, 2), After: nil},
+		{Before: tk("kt-p0", ticket.StatusOpen, 0), After: tk("kt-p0", ticket.StatusInProgress, 0)},
+		{Before: tk("kt-pass", ticket.StatusOpen, 2), After: passed},
+	}
+	events := Events(changes, []*ticket.Ticket{epic, child, doneSibling})
+
+	var got []string
+	for _, e := range events {
+		got = append(got, string(e.Type)+" "+e.Ticket.ID)
+	}
+	assert.Equal(t, []string{
+		"ticket_created kt-new",
+		"p0_opened kt-new",
+		"ticket_closed kt-child",
+		"reopened kt-re",
+		"status kt-st",
+		"deleted kt-del",
+		"status kt-p0", // already P0 and open: no second p0_opened
+		"tests_passed kt-pass",
+		"epic_completed kt-epic",
+	}, got)
+
+	// An epic with open children is not complete
+	open := tk("kt-open", ticket.StatusOpen, 2)
+	open.Parent = epic.ID
+	events = Events(changes[1:2], []*ticket.Ticket{epic, child, open})
+	require.Len(t, events, 1)
+	assert.Equal(t, EventClosed, events[0].Type)
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("p0_opened=slack:https://hooks.slack.com/a, *=discord:https://discord.com/api/webhooks/b")
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Event: "p0_opened", Service: "slack", URL: "https://hooks.slack.com/a"},
+		{Event: "*", Service: "discord", URL: "https://discord.com/api/webhooks/b"},
+	}, rules)
+
+	for _, bad := range []string{"p0_opened", "nope=slack:https://x", "closed=teams:https://x", "closed=slack:x"} {
+		_, err := ParseRules(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestEventNames(t *testing.T) {
+	for _, name := range []string{"ticket_created", "ticket_closed", "tests_passed", "created", "closed"} {
+		assert.True(t, KnownEvent(name), name)
+	}
+	assert.False(t, KnownEvent("nope"))
+
+	assert.Equal(t, []string{"ticket_closed", "closed"}, EventClosed.Names())
+	assert.True(t, EventClosed.Matches("closed"), "old name")
+	assert.True(t, EventClosed.Matches("ticket_closed"))
+	assert.True(t, EventClosed.Matches("*"))
+	assert.False(t, EventClosed.Matches("created"))
+	assert.Equal(t, []string{"tests_passed"}, EventTestsPassed.Names())
+}
+
+func TestParseTarget(t *testing.T) {
+	r, err := ParseTarget("discord:https://discord.com/api/webhooks/b")
+	require.NoError(t, err)
+	assert.Equal(t, Rule{Event: "*", Service: "discord", URL: "https://discord.com/api/webhooks/b"}, r)
+
+	for _, bad := range []string{"https://x", "teams:https://x", "slack:x"} {
+		_, err := ParseTarget(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var got []map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		got = append(got, body)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	n := New([]Rule{
+		{Event: "closed", Service: "slack", URL: srv.URL + "/slack"}, // old name of ticket_closed
+		{Event: "*", Service: "discord", URL: srv.URL + "/discord"},
+	})
+	closed := Event{EventClosed, tk("kt-a", ticket.StatusClosed, 2)}
+	created := Event{EventCreated, tk("kt-b", ticket.StatusOpen, 1)}
+	require.NoError(t, n.Send([]Event{closed, created}))
+	assert.Equal(t, []map[string]string{
+		{"text": "kt-a closed: T kt-a"},
+		{"content": "kt-a closed: T kt-a"},
+		{"content": "kt-b created: T kt-b (task, P1)"},
+	}, got)
+
+	n = New([]Rule{{Event: "*", Service: "slack", URL: srv.URL + "/fail"}})
+	assert.ErrorContains(t, n.Send([]Event{closed}), "404")
+
+	got = nil
+	n = New([]Rule{{Event: "closed", Service: "slack", URL: srv.URL + "/slack"}})
+	require.NoError(t, n.Post("digest"))
+	assert.Equal(t, []map[string]string{{"text": "digest"}}, got, "Post ignores the rule's event")
+}
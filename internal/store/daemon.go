@@ -0,0 +1,208 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/ticket"
+)
+
+// daemonTimeout bounds a client's exchange with the daemon, after which
+// it falls back to reading the files.
+const daemonTimeout = 2 * time.Second
+
+// DaemonStatus describes a running daemon.
+type DaemonStatus struct {
+	Running  bool   `json:"running"`
+	PID      int    `json:"pid,omitempty"`
+	Started  string `json:"started,omitempty"`
+	Tickets  int    `json:"tickets,omitempty"`
+	Requests int64  `json:"requests,omitempty"`
+}
+
+type daemonRequest struct {
+	Op string `json:"op"` // list, status, reindex, or stop
+}
+
+type daemonResponse struct {
+	Tickets json.RawMessage `json:"tickets,omitempty"`
+	Status  *DaemonStatus   `json:"status,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// DaemonSocket returns the path of the daemon's unix socket.
+func (s *Store) DaemonSocket() string {
+	return filepath.Join(s.Dir, ".daemon.sock")
+}
+
+// ServeDaemon keeps an in-memory index of the store and answers List calls
+// from other kt processes over a unix socket until ctx is done or a client
+// asks it to stop.
+func (s *Store) ServeDaemon(ctx context.Context) error {
+	if err := s.EnsureDir(); err != nil {
+		return err
+	}
+	sock := s.DaemonSocket()
+	if _, err := s.askDaemon("status"); err == nil {
+		return fmt.Errorf("daemon already running on %s", sock)
+	}
+	_ = os.Remove(sock) // left behind by a daemon that crashed
+
+	ix := newIndex(s)
+	if _, _, err := ix.tickets(); err != nil {
+		return err
+	}
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+	// Catch edits that don't touch the directory, e.g. editors that
+	// rewrite a file in place.
+	go func() {
+		for range s.Watch(ctx, time.Second) {
+			ix.invalidate()
+		}
+	}()
+
+	status := DaemonStatus{Running: true, PID: os.Getpid(), Started: time.Now().UTC().Format(time.RFC3339)}
+	var requests atomic.Int64
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		requests.Add(1)
+		go func() {
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(daemonTimeout))
+
+			var req daemonRequest
+			var resp daemonResponse
+			if err := json.NewDecoder(conn).Decode(&req); err != nil {
+				resp.Error = err.Error()
+			} else {
+				switch req.Op {
+				case "list":
+					data, _, err := ix.tickets()
+					if err != nil {
+						resp.Error = err.Error()
+					}
+					resp.Tickets = data
+				case "status":
+					_, count, err := ix.tickets()
+					if err != nil {
+						resp.Error = err.Error()
+					}
+					st := status
+					st.Tickets, st.Requests = count, requests.Load()
+					resp.Status = &st
+				case "reindex":
+					ix.reset()
+					_, count, err := ix.tickets()
+					if err != nil {
+						resp.Error = err.Error()
+					}
+					resp.Status = &DaemonStatus{Running: true, Tickets: count}
+				case "stop":
+					cancel()
+				default:
+					resp.Error = fmt.Sprintf("unknown op %q", req.Op)
+				}
+			}
+			_ = json.NewEncoder(conn).Encode(resp)
+		}()
+	}
+}
+
+// DaemonStatus reports whether a daemon is serving this store.
+func (s *Store) DaemonStatus() (*DaemonStatus, error) {
+	resp, err := s.askDaemon("status")
+	if err != nil || resp.Status == nil {
+		return &DaemonStatus{}, nil
+	}
+	return resp.Status, nil
+}
+
+// StopDaemon asks the daemon serving this store to exit.
+func (s *Store) StopDaemon() error {
+	if _, err := s.askDaemon("stop"); err != nil {
+		return errors.New("daemon not running")
+	}
+	return nil
+}
+
+// listFromDaemon fetches all tickets from a running daemon. It reports
+// false when there is no usable daemon, so the caller reads the files.
+func (s *Store) listFromDaemon() ([]*ticket.Ticket, bool) {
+	if os.Getenv(config.EnvNoDaemon) != "" {
+		return nil, false
+	}
+	resp, err := s.askDaemon("list")
+	if err != nil || resp.Tickets == nil {
+		return nil, false
+	}
+	var sent []daemonTicket
+	if err := json.Unmarshal(resp.Tickets, &sent); err != nil {
+		return nil, false
+	}
+	tickets := make([]*ticket.Ticket, 0, len(sent))
+	for _, d := range sent {
+		if d.Ticket == nil {
+			return nil, false
+		}
+		d.Ticket.Schema = d.Schema
+		// As Parse would, reading the file here
+		if d.Schema != ticket.SchemaVersion && ticket.SchemaMismatch != nil {
+			ticket.SchemaMismatch(d.Ticket)
+		}
+		tickets = append(tickets, d.Ticket)
+	}
+	return tickets, true
+}
+
+// daemonTicket is a ticket as the daemon sends it, with the Schema that
+// the ticket's own JSON leaves out.
+type daemonTicket struct {
+	*ticket.Ticket
+	Schema int `json:"schema,omitempty"`
+}
+
+// askDaemon sends one request to the daemon.
+func (s *Store) askDaemon(op string) (*daemonResponse, error) {
+	conn, err := net.DialTimeout("unix", s.DaemonSocket(), daemonTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(daemonTimeout))
+
+	if err := json.NewEncoder(conn).Encode(daemonRequest{Op: op}); err != nil {
+		return nil, err
+	}
+	var resp daemonResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startTestDaemon(t *testing.T, s *Store) <-chan error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- s.ServeDaemon(context.Background()) }()
+	require.Eventually(t, func() bool {
+		st, _ := s.DaemonStatus()
+		return st.Running
+	}, 2*time.Second, 5*time.Millisecond)
+	return done
+}
+
+func TestDaemon(t *testing.T) {
+	// Keep the socket path short enough for unix sockets
+	dir, err := os.MkdirTemp("", "kt")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	s := New(dir)
+
+	createTestTicket(s, "kt-d1", "First", ticket.StatusOpen)
+	done := startTestDaemon(t, s)
+
+	other := New(dir)
+	assert.Error(t, other.ServeDaemon(context.Background()), "one daemon per store")
+
+	before, _ := s.DaemonStatus()
+	tickets, err := other.List()
+	require.NoError(t, err)
+	require.Len(t, tickets, 1)
+	after, _ := s.DaemonStatus()
+	assert.Equal(t, before.Requests+2, after.Requests, "List is answered by the daemon")
+
+	// Writes are visible to the next List without waiting for the watcher
+	createTestTicket(s, "kt-d2", "Second", ticket.StatusOpen)
+	require.NoError(t, s.Update("kt-d1", func(tk *ticket.Ticket) error {
+		tk.Title = "First, renamed"
+		return nil
+	}))
+	tickets, err = other.List()
+	require.NoError(t, err)
+	require.Len(t, tickets, 2)
+	got := map[string]string{}
+	for _, tk := range tickets {
+		got[tk.ID] = tk.Title
+		assert.Equal(t, ticket.SchemaVersion, tk.Schema, "the daemon passes the schema on")
+	}
+	assert.Equal(t, map[string]string{"kt-d1": "First, renamed", "kt-d2": "Second"}, got)
+
+	n, ok, err := other.Reindex()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, n)
+
+	require.NoError(t, s.StopDaemon())
+	require.NoError(t, <-done)
+	_, err = os.Stat(s.DaemonSocket())
+	assert.True(t, os.IsNotExist(err), "socket is removed")
+
+	// Without a daemon, List reads the files
+	tickets, err = other.List()
+	require.NoError(t, err)
+	assert.Len(t, tickets, 2)
+	assert.Error(t, s.StopDaemon())
+	_, ok, err = s.Reindex()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
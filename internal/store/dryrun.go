@@ -0,0 +1,47 @@
+package store
+
+// FileChange is a change to a file in the store that a dry run skipped.
+type FileChange struct {
+	Action string `json:"action"` // create, update, delete, or archive
+	Path   string `json:"path"`
+	To     string `json:"to,omitempty"` // where archive would move the file
+}
+
+// SetDryRun makes the store leave its files alone: writes, deletes,
+// archiving, and lock pruning are recorded (see Planned) but not done, and
+// nothing is journaled for undo.
+func (s *Store) SetDryRun(on bool) {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	s.journal.dryRun = on
+}
+
+// DryRun reports whether SetDryRun is on.
+func (s *Store) DryRun() bool {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	return s.journal.dryRun
+}
+
+// Planned returns the changes skipped in dry-run mode, in order.
+func (s *Store) Planned() []FileChange {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	return append([]FileChange{}, s.journal.planned...)
+}
+
+// plan records a change skipped in dry-run mode.
+func (s *Store) plan(c FileChange) {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	s.journal.planned = append(s.journal.planned, c)
+}
+
+// planWrite records that a ticket would be written.
+func (s *Store) planWrite(id string) {
+	action := "update"
+	if !fileExists(s.Path(id)) {
+		action = "create"
+	}
+	s.plan(FileChange{Action: action, Path: s.Path(id)})
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/filelock"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-a", "A", ticket.StatusOpen)
+	createTestTicket(s, "kt-b", "B", ticket.StatusClosed)
+	createTestTicket(s, "kt-c", "C", ticket.StatusClosed)
+	require.NoError(t, os.WriteFile(s.lockPath("kt-stale"), nil, 0644))
+	held, err := filelock.Acquire(s.lockPath("kt-held"))
+	require.NoError(t, err)
+	defer held.Release()
+	before, err := os.ReadFile(s.Path("kt-a"))
+	require.NoError(t, err)
+
+	ops := len(s.undoOps())
+	s = New(s.Dir)
+	s.SetDryRun(true)
+	require.NoError(t, s.Update("kt-a", func(t *ticket.Ticket) error {
+		t.Title = "Changed"
+		return nil
+	}))
+	require.NoError(t, s.Save(&ticket.Ticket{ID: "kt-new", Status: ticket.StatusOpen, Title: "New"}))
+	require.NoError(t, s.Delete("kt-b"))
+	require.NoError(t, s.Archive("kt-c"))
+	n, err := s.PruneLocks()
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.Equal(t, []FileChange{
+		{Action: "update", Path: s.Path("kt-a")},
+		{Action: "create", Path: s.Path("kt-new")},
+		{Action: "delete", Path: s.Path("kt-b")},
+		{Action: "archive", Path: s.Path("kt-c"), To: s.ArchivePath("kt-c")},
+		{Action: "delete", Path: s.lockPath("kt-stale")},
+	}, s.Planned())
+
+	after, err := os.ReadFile(s.Path("kt-a"))
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+	for _, path := range []string{s.Path("kt-b"), s.Path("kt-c"), s.lockPath("kt-stale")} {
+		assert.FileExists(t, path)
+	}
+	assert.NoFileExists(t, s.Path("kt-new"))
+	assert.NoDirExists(t, s.ArchiveDir())
+	assert.Empty(t, s.Changed())
+	assert.Len(t, s.undoOps(), ops, "nothing to undo")
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kostyay/kticket/internal/filelock"
+	"github.com/kostyay/kticket/internal/ticket"
+)
+
+// ArchiveDir returns the directory archived tickets are moved to. Like the
+// live tickets it is committed, but List and Resolve do not see it.
+func (s *Store) ArchiveDir() string {
+	return filepath.Join(s.Dir, "archive")
+}
+
+// ArchivePath returns the file path of an archived ticket.
+func (s *Store) ArchivePath(id string) string {
+	return filepath.Join(s.ArchiveDir(), id+".md")
+}
+
+// Archive moves a ticket out of the store into ArchiveDir. Undo brings the
+// ticket back but leaves the archived copy.
+func (s *Store) Archive(id string) error {
+	lock, err := filelock.Acquire(s.lockPath(id))
+	if err != nil {
+		return fmt.Errorf("acquire lock: %w", err)
+	}
+	defer func() { _ = lock.Release() }()
+
+	data, err := os.ReadFile(s.Path(id))
+	if err != nil {
+		return err
+	}
+	if s.DryRun() {
+		s.plan(FileChange{Action: "archive", Path: s.Path(id), To: s.ArchivePath(id)})
+		return nil
+	}
+	if err := os.MkdirAll(s.ArchiveDir(), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.ArchivePath(id), data, 0644); err != nil {
+		return err
+	}
+	s.markArchived(id)
+	return s.removeTicket(id)
+}
+
+// ListArchived returns the tickets in ArchiveDir, sorted by ID. Files that
+// do not parse are skipped.
+func (s *Store) ListArchived() ([]*ticket.Ticket, error) {
+	paths, err := filepath.Glob(filepath.Join(s.ArchiveDir(), "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	tickets := make([]*ticket.Ticket, 0, len(paths))
+	for _, path := range paths {
+		if t, err := ticket.ParseFile(path); err == nil {
+			tickets = append(tickets, t)
+		}
+	}
+	return tickets, nil
+}
+
+// RemoveArchived deletes an archived ticket for good: undo does not bring
+// it back.
+func (s *Store) RemoveArchived(id string) error {
+	if s.DryRun() {
+		if !fileExists(s.ArchivePath(id)) {
+			return &os.PathError{Op: "remove", Path: s.ArchivePath(id), Err: os.ErrNotExist}
+		}
+		s.plan(FileChange{Action: "delete", Path: s.ArchivePath(id)})
+		return nil
+	}
+	if err := os.Remove(s.ArchivePath(id)); err != nil {
+		return err
+	}
+	s.markArchived(id)
+	return nil
+}
+
+// markArchived records that a file in ArchiveDir was written or removed.
+func (s *Store) markArchived(id string) {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	if s.journal.archived == nil {
+		s.journal.archived = make(map[string]bool)
+	}
+	s.journal.archived[id] = true
+}
+
+// reserveTTL is how long an ID reserved by NewHashID stays reserved if no
+// ticket is saved with it, e.g. because kt create failed.
+const reserveTTL = time.Hour
+
+// PruneLocks removes lock files no process holds, left behind by kt
+// processes that were killed, and expired ID reservations, and returns
+// how many it removed.
+func (s *Store) PruneLocks() (int, error) {
+	paths, err := filepath.Glob(filepath.Join(s.Dir, ".locks", "*.lock"))
+	if err != nil {
+		return 0, err
+	}
+	pruned := 0
+	for _, path := range paths {
+		if s.DryRun() {
+			held, err := filelock.Held(path)
+			if err != nil {
+				return pruned, err
+			}
+			if !held {
+				s.plan(FileChange{Action: "delete", Path: path})
+				pruned++
+			}
+			continue
+		}
+		lock, err := filelock.TryAcquire(path)
+		if err != nil {
+			return pruned, err
+		}
+		if lock == nil {
+			continue // in use
+		}
+		if err := lock.Release(); err != nil { // removes the file
+			return pruned, err
+		}
+		pruned++
+	}
+	reserved, err := filepath.Glob(filepath.Join(s.Dir, ".locks", "*.new"))
+	if err != nil {
+		return pruned, err
+	}
+	for _, path := range reserved {
+		info, err := os.Stat(path)
+		if err != nil || time.Since(info.ModTime()) < reserveTTL {
+			continue
+		}
+		if s.DryRun() {
+			s.plan(FileChange{Action: "delete", Path: path})
+		} else if err := os.Remove(path); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	if _, err := s.askDaemon("status"); err != nil {
+		// left behind by a daemon that crashed
+		if !s.DryRun() && os.Remove(s.DaemonSocket()) == nil {
+			pruned++
+		} else if s.DryRun() && fileExists(s.DaemonSocket()) {
+			s.plan(FileChange{Action: "delete", Path: s.DaemonSocket()})
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// Reindex makes a running daemon rescan every ticket file and returns how
+// many tickets it indexed. It reports false when no daemon is running, or
+// in dry-run mode.
+func (s *Store) Reindex() (int, bool, error) {
+	if _, err := s.askDaemon("status"); err != nil || s.DryRun() {
+		return 0, false, nil
+	}
+	resp, err := s.askDaemon("reindex")
+	if err != nil {
+		return 0, true, fmt.Errorf("daemon reindex: %w", err)
+	}
+	return resp.Status.Tickets, true, nil
+}
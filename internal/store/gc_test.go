@@ -0,0 +1,75 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kostyay/kticket/internal/filelock"
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneLocks(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-a", "A", ticket.StatusOpen)
+	for _, id := range []string{"kt-a", "kt-gone"} {
+		require.NoError(t, os.WriteFile(s.lockPath(id), nil, 0644)) // left by a killed process
+	}
+	held, err := filelock.Acquire(s.lockPath("kt-held"))
+	require.NoError(t, err)
+	defer held.Release()
+	require.NoError(t, os.WriteFile(s.DaemonSocket(), nil, 0644))
+	require.NoError(t, os.WriteFile(s.reservePath("kt-new"), nil, 0644))
+	require.NoError(t, os.WriteFile(s.reservePath("kt-stale"), nil, 0644))
+	old := time.Now().Add(-2 * reserveTTL)
+	require.NoError(t, os.Chtimes(s.reservePath("kt-stale"), old, old))
+
+	n, err := s.PruneLocks()
+	require.NoError(t, err)
+	assert.Equal(t, 4, n, "two lock files, the stale reservation, and the socket")
+	left, _ := filepath.Glob(filepath.Join(s.Dir, ".locks", "*.lock"))
+	assert.Equal(t, []string{s.lockPath("kt-held")}, left)
+	left, _ = filepath.Glob(filepath.Join(s.Dir, ".locks", "*.new"))
+	assert.Equal(t, []string{s.reservePath("kt-new")}, left)
+}
+
+func TestArchive(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-old", "Old", ticket.StatusClosed)
+
+	s = New(s.Dir)
+	require.NoError(t, s.Archive("kt-old"))
+	_, err := os.Stat(s.Path("kt-old"))
+	assert.True(t, os.IsNotExist(err))
+	archived, err := ticket.ParseFile(s.ArchivePath("kt-old"))
+	require.NoError(t, err)
+	assert.Equal(t, "Old", archived.Title)
+
+	tickets, err := s.List()
+	require.NoError(t, err)
+	assert.Empty(t, tickets)
+	assert.Equal(t, []string{s.ArchivePath("kt-old"), s.Path("kt-old")}, s.Changed(), "both files are committed")
+	assert.Empty(t, s.Changes(), "archiving is not a deletion")
+}
+
+func TestRemoveArchived(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-b", "B", ticket.StatusClosed)
+	createTestTicket(s, "kt-a", "A", ticket.StatusClosed)
+	require.NoError(t, s.Archive("kt-b"))
+	require.NoError(t, s.Archive("kt-a"))
+
+	archived, err := s.ListArchived()
+	require.NoError(t, err)
+	require.Len(t, archived, 2)
+	assert.Equal(t, "kt-a", archived[0].ID)
+
+	s = New(s.Dir)
+	require.NoError(t, s.RemoveArchived("kt-a"))
+	assert.NoFileExists(t, s.ArchivePath("kt-a"))
+	assert.Equal(t, []string{s.ArchivePath("kt-a")}, s.Changed(), "the deletion is committed")
+	assert.ErrorIs(t, s.RemoveArchived("kt-a"), os.ErrNotExist)
+}
@@ -5,32 +5,184 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kostyay/kticket/internal/config"
+	"github.com/kostyay/kticket/internal/filelock"
+	"github.com/kostyay/kticket/internal/ticket"
 )
 
-// GenerateID creates a unique ticket ID based on the git root directory name.
-// Falls back to cwd name if not in a git repo.
-func GenerateID() (string, error) {
-	dir, err := projectDirName()
+// DefaultIDLength is the number of hex characters in a hash ID.
+const DefaultIDLength = 4
+
+// idAttempts is how many IDs NewHashID tries at each length before adding
+// a character.
+const idAttempts = 10
+
+// GenerateID creates a ticket ID with the given prefix, or if prefix is
+// empty, one based on the git root directory name (or the cwd name if not
+// in a git repo), and a hash of length hex characters (DefaultIDLength if
+// length is 0). It does not check the ID is free; see Store.NewHashID.
+func GenerateID(prefix string, length int) (string, error) {
+	prefix, err := idPrefix(prefix)
+	if err != nil {
+		return "", err
+	}
+	if length <= 0 {
+		length = DefaultIDLength
+	}
+	sum := fmt.Sprintf("%x", sha256.Sum256(fmt.Appendf(nil, "%d%d", os.Getpid(), time.Now().UnixNano())))
+	return fmt.Sprintf("%s-%s", prefix, sum[:min(length, len(sum))]), nil
+}
+
+// NewHashID returns a hash ID, as GenerateID, that no ticket in the store
+// or its archive has. It checks under the store lock, regenerating on a
+// collision, and lengthens the hash if a length keeps colliding. The ID
+// stays reserved until a ticket with it is saved, so another process
+// cannot be given it in between.
+func (s *Store) NewHashID(prefix string, length int) (string, error) {
+	if err := s.EnsureDir(); err != nil {
+		return "", err
+	}
+	lock, err := filelock.Acquire(s.storeLockPath())
+	if err != nil {
+		return "", fmt.Errorf("acquire store lock: %w", err)
+	}
+	defer func() { _ = lock.Release() }()
+
+	return s.newHashID(prefix, length, func(id string) bool {
+		if s.DryRun() {
+			return false
+		}
+		f, err := os.OpenFile(s.reservePath(id), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err != nil {
+			return true // reserved by another process
+		}
+		return f.Close() != nil
+	})
+}
+
+// NewHashID is Store.NewHashID within the transaction, also avoiding IDs
+// of tickets saved in it but not yet written.
+func (tx *Tx) NewHashID(prefix string, length int) (string, error) {
+	return tx.store.newHashID(prefix, length, func(id string) bool {
+		_, ok := tx.tickets[id]
+		return ok
+	})
+}
+
+// newHashID generates IDs until one has no ticket file and is not taken,
+// checking taken last so it may reserve the ID. Callers must hold the
+// store lock.
+func (s *Store) newHashID(prefix string, length int, taken func(id string) bool) (string, error) {
+	if length <= 0 {
+		length = DefaultIDLength
+	}
+	for n := length; n <= sha256.Size*2; n++ {
+		for range idAttempts {
+			id, err := GenerateID(prefix, n)
+			if err != nil {
+				return "", err
+			}
+			if !fileExists(s.Path(id)) && !fileExists(s.ArchivePath(id)) && !fileExists(s.reservePath(id)) && !taken(id) {
+				return id, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("could not generate a unique ID")
+}
+
+// Unreserve gives up an ID from Store.NewHashID whose ticket is not going
+// to be saved after all, so it does not stay taken until PruneLocks.
+func (s *Store) Unreserve(id string) {
+	_ = os.Remove(s.reservePath(id))
+}
+
+// reservePath returns the path of the file reserving a hash ID from
+// Store.NewHashID until the ticket is first saved.
+func (s *Store) reservePath(id string) string {
+	return filepath.Join(s.Dir, ".locks", id+".new")
+}
+
+// fileExists reports whether anything exists at path.
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// NextSequentialID returns the next ID in the store's sequence, like
+// kt-042 (with the prefix derived as in GenerateID if empty). The last
+// number is kept in a counter file, updated under the store lock.
+func (s *Store) NextSequentialID(prefix string) (string, error) {
+	if err := s.EnsureDir(); err != nil {
+		return "", err
+	}
+	lock, err := filelock.Acquire(s.storeLockPath())
 	if err != nil {
+		return "", fmt.Errorf("acquire store lock: %w", err)
+	}
+	defer func() { _ = lock.Release() }()
+
+	return s.nextSequentialID(prefix)
+}
+
+// NextSequentialID is Store.NextSequentialID within the transaction.
+func (tx *Tx) NextSequentialID(prefix string) (string, error) {
+	return tx.store.nextSequentialID(prefix)
+}
+
+// nextSequentialID advances the counter. Callers must hold the store lock.
+// Numbers already taken by ticket files are skipped, so a counter file that
+// is missing or behind (e.g. after a merge) cannot cause collisions.
+func (s *Store) nextSequentialID(prefix string) (string, error) {
+	prefix, err := idPrefix(prefix)
+	if err != nil {
+		return "", err
+	}
+	last := 0
+	if data, err := os.ReadFile(s.counterPath()); err == nil {
+		last, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+	} else if !os.IsNotExist(err) {
 		return "", err
 	}
+	matches, _ := filepath.Glob(filepath.Join(s.Dir, prefix+"-*.md"))
+	for _, path := range matches {
+		num := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix+"-"), ".md")
+		if n, err := strconv.Atoi(num); err == nil && n > last {
+			last = n
+		}
+	}
 
-	prefix := extractPrefix(dir)
+	next := last + 1
+	if err := ticket.WriteRaw(s.counterPath(), []byte(strconv.Itoa(next)+"\n")); err != nil {
+		return "", fmt.Errorf("write counter: %w", err)
+	}
+	return fmt.Sprintf("%s-%03d", prefix, next), nil
+}
 
-	// 4-char hash from PID + timestamp
-	data := fmt.Sprintf("%d%d", os.Getpid(), time.Now().UnixNano())
-	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(data)))[:4]
+// counterPath returns the path of the sequential ID counter.
+func (s *Store) counterPath() string {
+	return filepath.Join(s.Dir, ".counter")
+}
 
-	return fmt.Sprintf("%s-%s", prefix, hash), nil
+// idPrefix returns prefix, or if it is empty, one derived from the project
+// directory name.
+func idPrefix(prefix string) (string, error) {
+	if prefix != "" {
+		return prefix, nil
+	}
+	dir, err := projectDirName()
+	if err != nil {
+		return "", err
+	}
+	return extractPrefix(dir), nil
 }
 
 // projectDirName returns the base name of the git root, or cwd as fallback.
 func projectDirName() (string, error) {
-	gitRoot, err := config.FindGitRoot()
+	gitRoot, err := config.FindMainGitRoot() // the repository's name, not a worktree's
 	if err == nil {
 		return filepath.Base(gitRoot), nil
 	}
@@ -0,0 +1,130 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kostyay/kticket/internal/filelock"
+	"github.com/kostyay/kticket/internal/ticket"
+)
+
+// racyWindow is how long after a modification a file's mtime is not
+// trusted to detect further changes, since filesystem timestamps can be
+// coarser than the interval between two writes.
+const racyWindow = 2 * time.Second
+
+// index is an in-memory copy of the store, reparsing only files whose size
+// or mtime changed.
+type index struct {
+	s *Store
+
+	mu      sync.Mutex
+	files   map[string]indexEntry // by file name
+	dirMod  time.Time
+	scanned time.Time
+	dirty   bool
+	data    []byte // JSON array of all tickets, newest first
+	count   int
+}
+
+type indexEntry struct {
+	mod    time.Time
+	size   int64
+	parsed time.Time
+	t      *ticket.Ticket
+}
+
+func newIndex(s *Store) *index {
+	return &index{s: s, files: make(map[string]indexEntry)}
+}
+
+// invalidate makes the next read rescan the store.
+func (ix *index) invalidate() {
+	ix.mu.Lock()
+	ix.dirty = true
+	ix.mu.Unlock()
+}
+
+// reset drops every parsed file, so the next read reparses them all.
+func (ix *index) reset() {
+	ix.mu.Lock()
+	ix.files = make(map[string]indexEntry)
+	ix.dirty = true
+	ix.mu.Unlock()
+}
+
+// tickets returns all tickets as a JSON array, rescanning first if the
+// store may have changed. Every save renames a file into the directory, so
+// the directory's mtime catches kt's own writes without waiting for a
+// watch signal.
+func (ix *index) tickets() ([]byte, int, error) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	info, err := os.Stat(ix.s.Dir)
+	if err != nil {
+		return nil, 0, err
+	}
+	if ix.dirty || ix.data == nil || !info.ModTime().Equal(ix.dirMod) || ix.scanned.Sub(ix.dirMod) < racyWindow {
+		if err := ix.refresh(); err != nil {
+			return nil, 0, err
+		}
+	}
+	return ix.data, ix.count, nil
+}
+
+// refresh rescans the store. Callers must hold ix.mu.
+func (ix *index) refresh() error {
+	lock, err := filelock.AcquireShared(ix.s.storeLockPath())
+	if err != nil {
+		return fmt.Errorf("acquire store lock: %w", err)
+	}
+	defer func() { _ = lock.Release() }()
+
+	start := time.Now()
+	info, err := os.Stat(ix.s.Dir)
+	if err != nil {
+		return err
+	}
+	matches, err := filepath.Glob(filepath.Join(ix.s.Dir, "*.md"))
+	if err != nil {
+		return err
+	}
+
+	files := make(map[string]indexEntry, len(matches))
+	tickets := make([]*ticket.Ticket, 0, len(matches))
+	for _, path := range matches {
+		fi, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		name := filepath.Base(path)
+		e, ok := ix.files[name]
+		if !ok || !e.mod.Equal(fi.ModTime()) || e.size != fi.Size() || e.parsed.Sub(e.mod) < racyWindow {
+			t, err := ticket.ParseFile(path)
+			if err != nil {
+				continue // skip invalid files, like load
+			}
+			e = indexEntry{mod: fi.ModTime(), size: fi.Size(), parsed: start, t: t}
+		}
+		files[name] = e
+		tickets = append(tickets, e.t)
+	}
+
+	sortTickets(tickets)
+	sent := make([]daemonTicket, len(tickets))
+	for i, t := range tickets {
+		sent[i] = daemonTicket{Ticket: t, Schema: t.Schema}
+	}
+	data, err := json.Marshal(sent)
+	if err != nil {
+		return err
+	}
+	ix.files, ix.data, ix.count = files, data, len(tickets)
+	ix.dirMod, ix.scanned, ix.dirty = info.ModTime(), start, false
+	return nil
+}
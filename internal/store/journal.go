@@ -0,0 +1,331 @@
+package store
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kostyay/kticket/internal/filelock"
+	"github.com/kostyay/kticket/internal/ticket"
+)
+
+// maxUndoOps is the number of operations kept in the undo journal.
+const maxUndoOps = 50
+
+// newMarker is the suffix recording that a ticket did not exist before an
+// operation, so undoing it deletes the file.
+const newMarker = ".new"
+
+// journal records the pre-mutation state of each ticket touched by one
+// operation (one Store instance, i.e. one CLI invocation) under .undo/<op>/.
+type journal struct {
+	mu       sync.Mutex
+	op       string
+	label    string
+	session  string
+	touched  map[string]bool
+	before   map[string][]byte // file contents before the first write; nil if new
+	changed  map[string]bool   // ticket IDs written or removed, including by Undo
+	archived map[string]bool   // ticket IDs moved to or deleted from the archive
+
+	dryRun  bool
+	planned []FileChange // changes skipped in dry-run mode
+}
+
+// Change is a ticket before and after an operation's writes. Before is nil
+// for a created ticket and After is nil for a deleted one.
+type Change struct {
+	Before *ticket.Ticket
+	After  *ticket.Ticket
+}
+
+// UndoResult describes what Undo restored.
+type UndoResult struct {
+	Operation string   `json:"operation,omitempty"`
+	Restored  []string `json:"restored,omitempty"`
+	Deleted   []string `json:"deleted,omitempty"`
+}
+
+// undoDir returns the undo journal directory.
+func (s *Store) undoDir() string {
+	return filepath.Join(s.Dir, ".undo")
+}
+
+// SetOperation labels the current operation in the undo journal (e.g. "close kt-a1b2").
+func (s *Store) SetOperation(label string) {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	s.journal.label = label
+}
+
+// SetSession names the agent session performing this operation. Each
+// ticket it writes records the session and operation in its history.
+func (s *Store) SetSession(session string) {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	s.journal.session = session
+}
+
+// Operation returns the label set by SetOperation.
+func (s *Store) Operation() string {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	return s.journal.label
+}
+
+// Changed returns the paths of ticket files this Store has written or
+// removed, sorted.
+func (s *Store) Changed() []string {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	paths := make([]string, 0, len(s.journal.changed)+len(s.journal.archived))
+	for id := range s.journal.changed {
+		paths = append(paths, s.Path(id))
+	}
+	for id := range s.journal.archived {
+		paths = append(paths, s.ArchivePath(id))
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Changes returns the tickets this Store has written or removed (not
+// counting Undo or Archive), as they were before the first write and as
+// they are now, sorted by ID.
+func (s *Store) Changes() []Change {
+	s.journal.mu.Lock()
+	ids := make([]string, 0, len(s.journal.before))
+	for id := range s.journal.before {
+		if !s.journal.archived[id] {
+			ids = append(ids, id)
+		}
+	}
+	before := maps.Clone(s.journal.before)
+	s.journal.mu.Unlock()
+	sort.Strings(ids)
+
+	changes := make([]Change, 0, len(ids))
+	for _, id := range ids {
+		var c Change
+		if data := before[id]; data != nil {
+			c.Before, _ = ticket.Parse(data)
+		}
+		c.After, _ = ticket.ParseFile(s.Path(id))
+		if c.Before != nil || c.After != nil {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// markChanged records that a ticket file was written or removed.
+func (s *Store) markChanged(id string) {
+	s.journal.mu.Lock()
+	defer s.journal.mu.Unlock()
+	if s.journal.changed == nil {
+		s.journal.changed = make(map[string]bool)
+	}
+	s.journal.changed[id] = true
+}
+
+// record snapshots a ticket's current file before it is modified.
+// Only the first snapshot per ticket per operation is kept.
+func (s *Store) record(id string) error {
+	j := &s.journal
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.touched[id] {
+		return nil
+	}
+	if j.op == "" {
+		j.op = fmt.Sprintf("%s-%d", time.Now().UTC().Format("20060102T150405.000000000"), os.Getpid())
+		j.touched = make(map[string]bool)
+		j.before = make(map[string][]byte)
+		if err := os.MkdirAll(filepath.Join(s.undoDir(), j.op), 0755); err != nil {
+			return fmt.Errorf("create undo dir: %w", err)
+		}
+		// Keep the journal out of git: it is local, per-checkout state
+		_ = os.WriteFile(filepath.Join(s.undoDir(), ".gitignore"), []byte("*\n"), 0644)
+		if j.label != "" {
+			_ = os.WriteFile(filepath.Join(s.undoDir(), j.op, "label"), []byte(j.label), 0644)
+		}
+		s.pruneUndo()
+	}
+
+	opDir := filepath.Join(s.undoDir(), j.op)
+	data, err := os.ReadFile(s.Path(id))
+	switch {
+	case err == nil:
+		err = os.WriteFile(filepath.Join(opDir, id+".md"), data, 0644)
+	case os.IsNotExist(err):
+		data = nil
+		err = os.WriteFile(filepath.Join(opDir, id+newMarker), nil, 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("record undo: %w", err)
+	}
+	j.before[id] = data
+
+	j.touched[id] = true
+	return nil
+}
+
+// pruneUndo removes the oldest operations beyond maxUndoOps.
+func (s *Store) pruneUndo() {
+	ops := s.undoOps()
+	for len(ops) > maxUndoOps {
+		_ = os.RemoveAll(filepath.Join(s.undoDir(), ops[0]))
+		ops = ops[1:]
+	}
+}
+
+// undoOps returns recorded operation IDs, oldest first.
+func (s *Store) undoOps() []string {
+	entries, err := os.ReadDir(s.undoDir())
+	if err != nil {
+		return nil
+	}
+	var ops []string
+	for _, e := range entries {
+		if e.IsDir() {
+			ops = append(ops, e.Name())
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// writeTicket journals and writes a ticket. Caller must hold the ticket lock.
+func (s *Store) writeTicket(t *ticket.Ticket) error {
+	if s.DryRun() {
+		s.planWrite(t.ID)
+		return nil
+	}
+	if err := s.record(t.ID); err != nil {
+		return err
+	}
+	s.journal.mu.Lock()
+	session, label := s.journal.session, s.journal.label
+	s.journal.mu.Unlock()
+	if session != "" {
+		t.Touch(session, historyOp(label))
+	}
+	s.markChanged(t.ID)
+	if err := ticket.WriteFile(s.Path(t.ID), t); err != nil {
+		return err
+	}
+	_ = os.Remove(s.reservePath(t.ID)) // the ID is taken by the file now
+	return nil
+}
+
+// maxHistoryOp is the longest operation label kept in ticket history, so
+// note text and the like do not bloat the frontmatter.
+const maxHistoryOp = 60
+
+// historyOp shortens an operation label for ticket history.
+func historyOp(label string) string {
+	label = strings.Join(strings.Fields(label), " ")
+	if r := []rune(label); len(r) > maxHistoryOp {
+		return string(r[:maxHistoryOp-1]) + "…"
+	}
+	return label
+}
+
+// removeTicket journals and deletes a ticket file. Caller must hold the ticket lock.
+func (s *Store) removeTicket(id string) error {
+	if s.DryRun() {
+		if !fileExists(s.Path(id)) {
+			return &os.PathError{Op: "remove", Path: s.Path(id), Err: os.ErrNotExist}
+		}
+		s.plan(FileChange{Action: "delete", Path: s.Path(id)})
+		return nil
+	}
+	if err := s.record(id); err != nil {
+		return err
+	}
+	s.markChanged(id)
+	return os.Remove(s.Path(id))
+}
+
+// Undo restores every ticket touched by the most recent operation to its
+// prior state and removes that operation from the journal.
+func (s *Store) Undo() (*UndoResult, error) {
+	lock, err := filelock.Acquire(s.storeLockPath())
+	if err != nil {
+		return nil, fmt.Errorf("acquire store lock: %w", err)
+	}
+	defer func() { _ = lock.Release() }()
+
+	ops := s.undoOps()
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+	op := ops[len(ops)-1]
+	opDir := filepath.Join(s.undoDir(), op)
+
+	entries, err := os.ReadDir(opDir)
+	if err != nil {
+		return nil, fmt.Errorf("read undo journal: %w", err)
+	}
+
+	result := &UndoResult{Operation: op}
+	if label, err := os.ReadFile(filepath.Join(opDir, "label")); err == nil {
+		result.Operation = string(label)
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case strings.HasSuffix(name, newMarker):
+			id := strings.TrimSuffix(name, newMarker)
+			if err := s.restore(id, nil); err != nil {
+				return nil, err
+			}
+			result.Deleted = append(result.Deleted, id)
+		case strings.HasSuffix(name, ".md"):
+			id := strings.TrimSuffix(name, ".md")
+			data, err := os.ReadFile(filepath.Join(opDir, name))
+			if err != nil {
+				return nil, fmt.Errorf("read undo snapshot: %w", err)
+			}
+			if err := s.restore(id, data); err != nil {
+				return nil, err
+			}
+			result.Restored = append(result.Restored, id)
+		}
+	}
+
+	if err := os.RemoveAll(opDir); err != nil {
+		return nil, fmt.Errorf("remove undo journal: %w", err)
+	}
+	return result, nil
+}
+
+// restore writes a snapshot back (or deletes the ticket if data is nil)
+// without journaling, so undo does not create its own undo entry.
+func (s *Store) restore(id string, data []byte) error {
+	lock, err := filelock.Acquire(s.lockPath(id))
+	if err != nil {
+		return fmt.Errorf("acquire lock: %w", err)
+	}
+	defer func() { _ = lock.Release() }()
+
+	s.markChanged(id)
+	path := s.Path(id)
+	if data == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("restore %s: %w", id, err)
+		}
+		return nil
+	}
+	if err := ticket.WriteRaw(path, data); err != nil {
+		return fmt.Errorf("restore %s: %w", id, err)
+	}
+	return nil
+}
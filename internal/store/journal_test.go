@@ -0,0 +1,173 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndoRestoresModified(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-a", "Original", ticket.StatusOpen)
+
+	// Second invocation modifies the ticket
+	s2 := New(s.Dir)
+	s2.SetOperation("close kt-a")
+	require.NoError(t, s2.Update("kt-a", func(tk *ticket.Ticket) error {
+		tk.Status = ticket.StatusClosed
+		return nil
+	}))
+	require.NoError(t, s2.Update("kt-a", func(tk *ticket.Ticket) error {
+		tk.Title = "Changed twice"
+		return nil
+	}))
+
+	result, err := New(s.Dir).Undo()
+	require.NoError(t, err)
+	assert.Equal(t, "close kt-a", result.Operation)
+	assert.Equal(t, []string{"kt-a"}, result.Restored)
+
+	got, err := s.Get("kt-a")
+	require.NoError(t, err)
+	assert.Equal(t, ticket.StatusOpen, got.Status)
+	assert.Equal(t, "Original", got.Title)
+}
+
+func TestUndoRemovesCreatedAndRestoresDeleted(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-old", "Old", ticket.StatusClosed)
+
+	s2 := New(s.Dir)
+	createTestTicket(s2, "kt-new", "New", ticket.StatusOpen)
+	require.NoError(t, s2.Delete("kt-old"))
+
+	result, err := New(s.Dir).Undo()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-new"}, result.Deleted)
+	assert.Equal(t, []string{"kt-old"}, result.Restored)
+
+	_, err = s.Get("kt-new")
+	assert.Error(t, err)
+	_, err = s.Get("kt-old")
+	assert.NoError(t, err)
+}
+
+func TestUndoWalksBack(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-a", "A", ticket.StatusOpen)
+
+	for _, title := range []string{"B", "C"} {
+		s := New(s.Dir)
+		require.NoError(t, s.Update("kt-a", func(tk *ticket.Ticket) error {
+			tk.Title = title
+			return nil
+		}))
+	}
+
+	_, err := New(s.Dir).Undo()
+	require.NoError(t, err)
+	got, _ := s.Get("kt-a")
+	assert.Equal(t, "B", got.Title)
+
+	_, err = New(s.Dir).Undo()
+	require.NoError(t, err)
+	got, _ = s.Get("kt-a")
+	assert.Equal(t, "A", got.Title)
+}
+
+func TestUndoNothing(t *testing.T) {
+	s := setupTestStore(t)
+	_, err := s.Undo()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nothing to undo")
+}
+
+func TestUndoPrune(t *testing.T) {
+	s := setupTestStore(t)
+	for i := 0; i < maxUndoOps+5; i++ {
+		require.NoError(t, os.MkdirAll(filepath.Join(s.undoDir(), fmt.Sprintf("20000101T000000.%09d-1", i)), 0755))
+	}
+	createTestTicket(New(s.Dir), "kt-a", "A", ticket.StatusOpen)
+	assert.Len(t, s.undoOps(), maxUndoOps)
+}
+
+func TestChanged(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-a", "A", ticket.StatusOpen)
+
+	s2 := New(s.Dir)
+	s2.SetOperation("close kt-a")
+	assert.Empty(t, s2.Changed())
+	require.NoError(t, s2.Update("kt-a", func(tk *ticket.Ticket) error {
+		tk.Status = ticket.StatusClosed
+		return nil
+	}))
+	require.NoError(t, s2.Save(&ticket.Ticket{ID: "kt-b", Status: ticket.StatusOpen, Title: "B"}))
+	require.NoError(t, s2.Delete("kt-b"))
+
+	assert.Equal(t, "close kt-a", s2.Operation())
+	assert.Equal(t, []string{s.Path("kt-a"), s.Path("kt-b")}, s2.Changed())
+}
+
+func TestChanges(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-a", "A", ticket.StatusOpen)
+	createTestTicket(s, "kt-gone", "Gone", ticket.StatusOpen)
+
+	s2 := New(s.Dir)
+	assert.Empty(t, s2.Changes())
+	require.NoError(t, s2.Update("kt-a", func(tk *ticket.Ticket) error {
+		tk.Status = ticket.StatusClosed
+		return nil
+	}))
+	require.NoError(t, s2.Update("kt-a", func(tk *ticket.Ticket) error {
+		tk.Title = "A2"
+		return nil
+	}))
+	require.NoError(t, s2.Save(&ticket.Ticket{ID: "kt-b", Status: ticket.StatusOpen, Title: "B"}))
+	require.NoError(t, s2.Delete("kt-gone"))
+
+	changes := s2.Changes()
+	require.Len(t, changes, 3)
+	assert.Equal(t, ticket.StatusOpen, changes[0].Before.Status, "before is the state prior to the first write")
+	assert.Equal(t, "A2", changes[0].After.Title)
+	assert.Nil(t, changes[1].Before)
+	assert.Equal(t, "kt-b", changes[1].After.ID)
+	assert.Equal(t, "kt-gone", changes[2].Before.ID)
+	assert.Nil(t, changes[2].After)
+}
+
+func TestSessionHistory(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-a", "A", ticket.StatusOpen)
+	got, err := s.Get("kt-a")
+	require.NoError(t, err)
+	assert.Empty(t, got.History, "no session, no history")
+
+	s2 := New(s.Dir)
+	s2.SetOperation("start kt-a")
+	s2.SetSession("agent-1")
+	require.NoError(t, s2.Update("kt-a", func(tk *ticket.Ticket) error {
+		tk.SetStatus(ticket.StatusInProgress)
+		return nil
+	}))
+	got, err = s.Get("kt-a")
+	require.NoError(t, err)
+	require.Len(t, got.History, 1)
+	assert.Equal(t, "agent-1", got.History[0].Session)
+	assert.Equal(t, "start kt-a", got.History[0].Op)
+	assert.Equal(t, "agent-1", got.ClaimedBy)
+}
+
+func TestHistoryOp(t *testing.T) {
+	assert.Equal(t, "add-note kt-a hi there", historyOp("add-note kt-a hi\n  there"))
+	long := historyOp("add-note kt-a " + strings.Repeat("x", 100))
+	assert.Len(t, []rune(long), maxHistoryOp)
+	assert.True(t, strings.HasSuffix(long, "…"))
+}
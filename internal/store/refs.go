@@ -0,0 +1,111 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/kostyay/kticket/internal/ticket"
+)
+
+// ErrReferenced is returned (as a *RefError) when removing a ticket that
+// an open ticket still refers to.
+var ErrReferenced = errors.New("referenced")
+
+// RefError reports an open ticket that refers to one being removed.
+type RefError struct {
+	Verb string // the refused operation, e.g. delete or purge
+	ID   string // the ticket being removed
+	By   string // the open ticket referring to it
+	How  string // e.g. "depends on it"
+}
+
+func (e *RefError) Error() string {
+	return fmt.Sprintf("cannot %s %s: ticket %s %s", e.Verb, e.ID, e.By, e.How)
+}
+
+func (e *RefError) Unwrap() error { return ErrReferenced }
+
+// CheckRefs returns a *RefError if an open ticket outside removed has one
+// of the removed tickets as parent, dep, or link. Closed tickets may keep
+// references to removed ones. verb names the operation in the error.
+func CheckRefs(verb string, tickets []*ticket.Ticket, removed []string) error {
+	for _, t := range tickets {
+		if t.Status == ticket.StatusClosed || slices.Contains(removed, t.ID) {
+			continue
+		}
+		if slices.Contains(removed, t.Parent) {
+			return &RefError{verb, t.Parent, t.ID, "has it as parent"}
+		}
+		for _, dep := range t.Deps {
+			if slices.Contains(removed, dep) {
+				return &RefError{verb, dep, t.ID, "depends on it"}
+			}
+		}
+		for _, link := range t.LinkedIDs() {
+			if slices.Contains(removed, link) {
+				return &RefError{verb, link, t.ID, "links to it"}
+			}
+		}
+	}
+	return nil
+}
+
+// Referrers returns the open tickets outside removed that CheckRefs would
+// refuse on account of.
+func Referrers(tickets []*ticket.Ticket, removed []string) []*ticket.Ticket {
+	var refs []*ticket.Ticket
+	for _, t := range tickets {
+		if CheckRefs("", []*ticket.Ticket{t}, removed) != nil {
+			refs = append(refs, t)
+		}
+	}
+	return refs
+}
+
+// Delete removes tickets from disk. It refuses with a *RefError if an open
+// ticket that is not being deleted refers to one of them, and otherwise
+// removes the references closed tickets have to them, as purge does.
+func (s *Store) Delete(ids ...string) error {
+	return s.Transaction(func(tx *Tx) error {
+		for _, id := range ids {
+			if _, err := tx.Get(id); err != nil {
+				return err
+			}
+		}
+		if err := CheckRefs("delete", tx.List(), ids); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			tx.Delete(id)
+		}
+		for _, id := range ids {
+			tx.RewriteRefs(id, "")
+		}
+		return nil
+	})
+}
+
+// DeleteCascade removes tickets from disk along with every parent, dep,
+// and link reference to them in the remaining tickets. It returns the IDs
+// of the tickets whose references it removed.
+func (s *Store) DeleteCascade(ids ...string) ([]string, error) {
+	var cleaned []string
+	err := s.Transaction(func(tx *Tx) error {
+		for _, id := range ids {
+			if _, err := tx.Get(id); err != nil {
+				return err
+			}
+		}
+		for _, id := range ids {
+			tx.Delete(id)
+		}
+		for _, id := range ids {
+			cleaned = append(cleaned, tx.RewriteRefs(id, "")...)
+		}
+		slices.Sort(cleaned)
+		cleaned = slices.Compact(cleaned)
+		return nil
+	})
+	return cleaned, err
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteRefusesReferenced(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-a", "A", ticket.StatusOpen)
+	b := createTestTicket(s, "kt-b", "B", ticket.StatusOpen)
+	b.Deps = []string{"kt-a"}
+	require.NoError(t, s.Save(b))
+	done := createTestTicket(s, "kt-done", "Done", ticket.StatusClosed)
+	done.Parent = "kt-b"
+	require.NoError(t, s.Save(done))
+
+	err := s.Delete("kt-a")
+	var refErr *RefError
+	require.True(t, errors.As(err, &refErr), "got %v", err)
+	assert.ErrorIs(t, err, ErrReferenced)
+	assert.Equal(t, "cannot delete kt-a: ticket kt-b depends on it", err.Error())
+	_, err = s.Get("kt-a")
+	assert.NoError(t, err, "nothing deleted")
+
+	require.NoError(t, s.Delete("kt-a", "kt-b"), "referenced only from tickets being deleted and closed ones")
+	tickets, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, tickets, 1)
+	assert.Empty(t, tickets[0].Parent, "closed tickets lose their references")
+
+	assert.ErrorIs(t, s.Delete("kt-missing"), ErrNotFound)
+}
+
+func TestDeleteCascade(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-a", "A", ticket.StatusOpen)
+	b := createTestTicket(s, "kt-b", "B", ticket.StatusOpen)
+	b.Deps = []string{"kt-a", "kt-c"}
+	b.Parent = "kt-a"
+	require.NoError(t, s.Save(b))
+	c := createTestTicket(s, "kt-c", "C", ticket.StatusOpen)
+	c.Relations = map[ticket.LinkType][]string{ticket.LinkBlocks: {"kt-a"}}
+	require.NoError(t, s.Save(c))
+
+	s = New(s.Dir)
+	cleaned, err := s.DeleteCascade("kt-a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-b", "kt-c"}, cleaned)
+
+	got, err := s.Get("kt-b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-c"}, got.Deps)
+	assert.Empty(t, got.Parent)
+	got, err = s.Get("kt-c")
+	require.NoError(t, err)
+	assert.Empty(t, got.Relations)
+
+	_, err = s.Undo()
+	require.NoError(t, err)
+	got, err = s.Get("kt-b")
+	require.NoError(t, err)
+	assert.Equal(t, "kt-a", got.Parent, "undo restores the references")
+}
@@ -1,10 +1,12 @@
 package store
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/kostyay/kticket/internal/config"
@@ -12,8 +14,16 @@ import (
 	"github.com/kostyay/kticket/internal/ticket"
 )
 
+// Errors returned (wrapped) when resolving a ticket ID.
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrAmbiguous = errors.New("ambiguous")
+)
+
 type Store struct {
 	Dir string
+
+	journal journal
 }
 
 // New creates a new Store with the given directory.
@@ -40,15 +50,30 @@ func (s *Store) EnsureDir() error {
 	return os.MkdirAll(s.Dir, 0755)
 }
 
-// List returns all tickets in the store.
-// Uses shared store lock to allow concurrent reads.
+// List returns all tickets in the store, from a running kt daemon if there
+// is one. Uses shared store lock to allow concurrent reads. Files that do
+// not parse are skipped, unless ticket.Strict is set, when they are
+// reported as an error (and the daemon, which skips them, is not asked).
 func (s *Store) List() ([]*ticket.Ticket, error) {
+	if !ticket.Strict {
+		if tickets, ok := s.listFromDaemon(); ok {
+			return tickets, nil
+		}
+	}
+
 	lock, err := filelock.AcquireShared(s.storeLockPath())
 	if err != nil {
 		return nil, fmt.Errorf("acquire store lock: %w", err)
 	}
 	defer func() { _ = lock.Release() }()
 
+	return s.load()
+}
+
+// load parses every ticket file without locking, newest first. Files that
+// do not parse are skipped, or with ticket.Strict, reported.
+// Callers must hold the store lock.
+func (s *Store) load() ([]*ticket.Ticket, error) {
 	pattern := filepath.Join(s.Dir, "*.md")
 	matches, err := filepath.Glob(pattern)
 	if err != nil {
@@ -56,20 +81,28 @@ func (s *Store) List() ([]*ticket.Ticket, error) {
 	}
 
 	tickets := make([]*ticket.Ticket, 0, len(matches))
+	var invalid []error
 	for _, path := range matches {
 		t, err := ticket.ParseFile(path)
 		if err != nil {
-			continue // skip invalid files
+			invalid = append(invalid, fmt.Errorf("%s: %w", filepath.Base(path), err))
+			continue
 		}
 		tickets = append(tickets, t)
 	}
+	if ticket.Strict && len(invalid) > 0 {
+		return nil, fmt.Errorf("invalid ticket files:\n%w", errors.Join(invalid...))
+	}
+
+	sortTickets(tickets)
+	return tickets, nil
+}
 
-	// Sort by created date (newest first)
+// sortTickets orders tickets by created date (newest first).
+func sortTickets(tickets []*ticket.Ticket) {
 	sort.Slice(tickets, func(i, j int) bool {
 		return tickets[i].Created > tickets[j].Created
 	})
-
-	return tickets, nil
 }
 
 // Get retrieves a ticket by exact ID.
@@ -85,7 +118,8 @@ func (s *Store) Get(id string) (*ticket.Ticket, error) {
 	return ticket.ParseFile(path)
 }
 
-// Resolve finds a ticket by partial ID match.
+// Resolve finds a ticket by partial ID (see matchID), or failing that by
+// its external-ref (e.g. gh-123, matched exactly but case-insensitively).
 // Uses appropriate locking for safe concurrent access.
 func (s *Store) Resolve(partial string) (*ticket.Ticket, error) {
 	// Try exact match first (Get handles its own locking)
@@ -99,26 +133,122 @@ func (s *Store) Resolve(partial string) (*ticket.Ticket, error) {
 		return nil, fmt.Errorf("acquire store lock: %w", err)
 	}
 
-	pattern := filepath.Join(s.Dir, "*"+partial+"*.md")
-	matches, err := filepath.Glob(pattern)
-	_ = storeLock.Release() // Release early, we have the matches
+	matches, err := filepath.Glob(filepath.Join(s.Dir, "*.md"))
 	if err != nil {
+		_ = storeLock.Release()
 		return nil, err
 	}
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = strings.TrimSuffix(filepath.Base(m), ".md")
+	}
+	candidates := matchID(partial, ids)
+	if len(candidates) == 0 {
+		all, err := s.load()
+		_ = storeLock.Release()
+		if err != nil {
+			return nil, err
+		}
+		t, err := pickRefMatch(partial, externalRefMatches(all, partial))
+		if err != nil {
+			return nil, err
+		}
+		return s.Get(t.ID)
+	}
+	_ = storeLock.Release() // Release early, we have the matches
+
+	if len(candidates) == 1 {
+		return s.Get(candidates[0]) // Use Get for proper locking
+	}
+	tickets := make([]*ticket.Ticket, len(candidates))
+	for i, id := range candidates {
+		if tickets[i], err = s.Get(id); err != nil {
+			tickets[i] = &ticket.Ticket{ID: id} // listed without a title
+		}
+	}
+	return nil, ambiguousError(fmt.Sprintf("ID %q", partial), tickets)
+}
 
-	switch len(matches) {
+// matchID returns the IDs partial could mean. Rules are tried in turn, and
+// the first that matches any ID decides: the exact ID, the ID in another
+// case, an ID whose part after the prefix is partial (so A1B2 means
+// kt-a1b2 even if kt-ffa1b2 exists), the sequential ID with that number (so
+// 42 means kt-042 rather than also kt-142 or kt-420), IDs ending in
+// partial, and IDs containing it, all but the first ignoring case. Several
+// matches, sorted, mean partial is ambiguous.
+func matchID(partial string, ids []string) []string {
+	lower := strings.ToLower(partial)
+	num, numErr := strconv.Atoi(partial)
+	rules := []func(id string) bool{
+		func(id string) bool { return id == partial },
+		func(id string) bool { return strings.EqualFold(id, partial) },
+		func(id string) bool { return strings.EqualFold(idSuffix(id), partial) },
+		func(id string) bool {
+			n, err := strconv.Atoi(idSuffix(id))
+			return numErr == nil && err == nil && n == num
+		},
+		func(id string) bool { return strings.HasSuffix(strings.ToLower(id), lower) },
+		func(id string) bool { return strings.Contains(strings.ToLower(id), lower) },
+	}
+	for _, rule := range rules {
+		var matches []string
+		for _, id := range ids {
+			if rule(id) {
+				matches = append(matches, id)
+			}
+		}
+		if len(matches) > 0 {
+			sort.Strings(matches)
+			return matches
+		}
+	}
+	return nil
+}
+
+// idSuffix returns the part of an ID after its prefix, e.g. a1b2 for
+// kt-a1b2.
+func idSuffix(id string) string {
+	return id[strings.LastIndex(id, "-")+1:]
+}
+
+// pickRefMatch returns the one ticket found by external-ref, or a
+// not-found or ambiguous error.
+func pickRefMatch(ref string, tickets []*ticket.Ticket) (*ticket.Ticket, error) {
+	switch len(tickets) {
 	case 0:
-		return nil, fmt.Errorf("ticket %q not found", partial)
+		return nil, fmt.Errorf("ticket %q %w", ref, ErrNotFound)
 	case 1:
-		id := strings.TrimSuffix(filepath.Base(matches[0]), ".md")
-		return s.Get(id) // Use Get for proper locking
-	default:
-		ids := make([]string, len(matches))
-		for i, m := range matches {
-			ids[i] = strings.TrimSuffix(filepath.Base(m), ".md")
+		return tickets[0], nil
+	}
+	return nil, ambiguousError(fmt.Sprintf("external-ref %q", ref), tickets)
+}
+
+// ambiguousError reports that what (e.g. ID "ab") matches each of tickets,
+// listing their IDs and titles.
+func ambiguousError(what string, tickets []*ticket.Ticket) error {
+	width := 0
+	for _, t := range tickets {
+		width = max(width, len(t.ID))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s matches %d tickets:", what, len(tickets))
+	for _, t := range tickets {
+		fmt.Fprintf(&b, "\n  %-*s  %s", width, t.ID, t.Title)
+	}
+	return fmt.Errorf("%w %s", ErrAmbiguous, b.String())
+}
+
+// externalRefMatches returns the tickets whose external-ref equals ref,
+// ignoring case, sorted by ID.
+func externalRefMatches(tickets []*ticket.Ticket, ref string) []*ticket.Ticket {
+	var matches []*ticket.Ticket
+	for _, t := range tickets {
+		if t.ExternalRef != "" && strings.EqualFold(t.ExternalRef, ref) {
+			matches = append(matches, t)
 		}
-		return nil, fmt.Errorf("ambiguous ID %q matches multiple tickets: %v", partial, ids)
 	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	return matches
 }
 
 // Save writes a ticket to disk.
@@ -134,21 +264,7 @@ func (s *Store) Save(t *ticket.Ticket) error {
 	}
 	defer func() { _ = lock.Release() }()
 
-	path := filepath.Join(s.Dir, t.ID+".md")
-	return ticket.WriteFile(path, t)
-}
-
-// Delete removes a ticket from disk.
-// Uses exclusive lock to prevent concurrent access.
-func (s *Store) Delete(id string) error {
-	lock, err := filelock.Acquire(s.lockPath(id))
-	if err != nil {
-		return fmt.Errorf("acquire lock: %w", err)
-	}
-	defer func() { _ = lock.Release() }()
-
-	path := filepath.Join(s.Dir, id+".md")
-	return os.Remove(path)
+	return s.writeTicket(t)
 }
 
 // Path returns the file path for a ticket ID.
@@ -179,8 +295,7 @@ func (lt *LockedTicket) SaveAndRelease() error {
 	}
 	defer lt.Release()
 
-	path := lt.store.Path(lt.Ticket.ID)
-	return ticket.WriteFile(path, lt.Ticket)
+	return lt.store.writeTicket(lt.Ticket)
 }
 
 // GetForUpdate retrieves a ticket with an exclusive lock for modification.
@@ -227,6 +342,5 @@ func (s *Store) Update(id string, fn func(*ticket.Ticket) error) error {
 		return err
 	}
 
-	path := s.Path(lt.Ticket.ID)
-	return ticket.WriteFile(path, lt.Ticket)
+	return s.writeTicket(lt.Ticket)
 }
@@ -35,15 +35,51 @@ func TestExtractPrefix(t *testing.T) {
 }
 
 func TestGenerateID(t *testing.T) {
-	id1, err := GenerateID()
+	id1, err := GenerateID("", 0)
 	require.NoError(t, err)
 	assert.NotEmpty(t, id1)
 	assert.Contains(t, id1, "-")
 
 	// Generate another - should be different
-	id2, err := GenerateID()
+	id2, err := GenerateID("", 0)
 	require.NoError(t, err)
 	assert.NotEqual(t, id1, id2)
+
+	id3, err := GenerateID("web", 0)
+	require.NoError(t, err)
+	assert.Regexp(t, `^web-[0-9a-f]{4}$`, id3)
+
+	id4, err := GenerateID("web", 8)
+	require.NoError(t, err)
+	assert.Regexp(t, `^web-[0-9a-f]{8}$`, id4)
+}
+
+func TestNewHashID(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "web-0001", "Existing", ticket.StatusOpen)
+
+	id, err := s.NewHashID("web", 6)
+	require.NoError(t, err)
+	assert.Regexp(t, `^web-[0-9a-f]{6}$`, id)
+	assert.NoFileExists(t, s.Path(id))
+	assert.FileExists(t, s.reservePath(id), "reserved until saved")
+	require.NoError(t, s.Save(&ticket.Ticket{ID: id, Title: "New", Status: ticket.StatusOpen}))
+	assert.NoFileExists(t, s.reservePath(id))
+
+	id, err = s.NewHashID("web", 6)
+	require.NoError(t, err)
+	s.Unreserve(id)
+	assert.NoFileExists(t, s.reservePath(id), "given up")
+
+	// A length where every ID is taken gives way to a longer one.
+	var tried []string
+	id, err = s.newHashID("web", 4, func(id string) bool {
+		tried = append(tried, id)
+		return len(id) == len("web-0000")
+	})
+	require.NoError(t, err)
+	assert.Regexp(t, `^web-[0-9a-f]{5}$`, id)
+	assert.Len(t, tried, idAttempts+1)
 }
 
 func setupTestStore(t *testing.T) *Store {
@@ -66,6 +102,30 @@ func createTestTicket(s *Store, id, title string, status ticket.Status) *ticket.
 	return t
 }
 
+func TestNextSequentialID(t *testing.T) {
+	s := setupTestStore(t)
+	id, err := s.NextSequentialID("kt")
+	require.NoError(t, err)
+	assert.Equal(t, "kt-001", id)
+
+	id, err = s.NextSequentialID("kt")
+	require.NoError(t, err)
+	assert.Equal(t, "kt-002", id)
+
+	// Existing tickets are skipped even if the counter is behind
+	createTestTicket(s, "kt-041", "Merged", ticket.StatusOpen)
+	id, err = s.NextSequentialID("kt")
+	require.NoError(t, err)
+	assert.Equal(t, "kt-042", id)
+
+	err = s.Transaction(func(tx *Tx) error {
+		id, err = tx.NextSequentialID("kt")
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "kt-043", id)
+}
+
 func TestStoreEnsureDir(t *testing.T) {
 	dir := t.TempDir()
 	ticketsDir := filepath.Join(dir, "nested", ".ktickets")
@@ -120,6 +180,21 @@ func TestStoreList(t *testing.T) {
 	assert.Len(t, tickets, 3)
 }
 
+func TestStoreListStrict(t *testing.T) {
+	defer func() { ticket.Strict = false }()
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-001", "First", ticket.StatusOpen)
+	require.NoError(t, os.WriteFile(s.Path("kt-bad"), []byte("no frontmatter"), 0644))
+
+	tickets, err := s.List()
+	require.NoError(t, err)
+	assert.Len(t, tickets, 1, "invalid files are skipped")
+
+	ticket.Strict = true
+	_, err = s.List()
+	assert.ErrorContains(t, err, "kt-bad.md: ")
+}
+
 func TestStoreListEmpty(t *testing.T) {
 	s := setupTestStore(t)
 	_ = s.EnsureDir()
@@ -158,6 +233,54 @@ func TestStoreResolveAmbiguous(t *testing.T) {
 	assert.Contains(t, err.Error(), "ambiguous")
 }
 
+func TestStoreResolveNumber(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-042", "Forty-two", ticket.StatusOpen)
+	createTestTicket(s, "kt-142", "One forty-two", ticket.StatusOpen)
+
+	resolved, err := s.Resolve("42")
+	require.NoError(t, err)
+	assert.Equal(t, "kt-042", resolved.ID)
+
+	_, err = s.Resolve("4")
+	assert.ErrorIs(t, err, ErrAmbiguous)
+}
+
+func TestStoreResolveCaseAndSuffix(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-a1b2", "Short", ticket.StatusOpen)
+	createTestTicket(s, "kt-ffa1b2", "Long", ticket.StatusOpen)
+	createTestTicket(s, "kt-c3d4e5", "Other", ticket.StatusOpen)
+	createTestTicket(s, "kt-99d4e5", "Another", ticket.StatusOpen)
+
+	for _, partial := range []string{"kt-a1b2", "KT-A1B2", "a1b2", "A1B2"} {
+		got, err := s.Resolve(partial)
+		require.NoError(t, err, partial)
+		assert.Equal(t, "kt-a1b2", got.ID, partial)
+	}
+
+	got, err := s.Resolve("FA1B2")
+	require.NoError(t, err)
+	assert.Equal(t, "kt-ffa1b2", got.ID)
+
+	got, err = s.Resolve("3d4e5")
+	require.NoError(t, err)
+	assert.Equal(t, "kt-c3d4e5", got.ID, "suffix beats substring")
+
+	_, err = s.Resolve("d4e5")
+	assert.ErrorIs(t, err, ErrAmbiguous)
+	assert.EqualError(t, err, "ambiguous ID \"d4e5\" matches 2 tickets:\n  kt-99d4e5  Another\n  kt-c3d4e5  Other")
+
+	require.NoError(t, s.Transaction(func(tx *Tx) error {
+		got, err := tx.Resolve("A1B2")
+		require.NoError(t, err)
+		assert.Equal(t, "kt-a1b2", got.ID)
+		_, err = tx.Resolve("D4E5")
+		assert.ErrorContains(t, err, "kt-99d4e5  Another")
+		return nil
+	}))
+}
+
 func TestStoreResolveNotFound(t *testing.T) {
 	s := setupTestStore(t)
 	_ = s.EnsureDir()
@@ -258,6 +381,37 @@ func TestResolveForUpdate(t *testing.T) {
 	assert.Equal(t, 5, updated.Priority)
 }
 
+func TestResolveExternalRef(t *testing.T) {
+	s := setupTestStore(t)
+	gh := createTestTicket(s, "kt-a1", "Imported", ticket.StatusOpen)
+	gh.ExternalRef = "gh-123"
+	require.NoError(t, s.Save(gh))
+
+	got, err := s.Resolve("GH-123")
+	require.NoError(t, err)
+	assert.Equal(t, "kt-a1", got.ID)
+
+	// External refs match exactly, not partially
+	_, err = s.Resolve("gh-12")
+	assert.ErrorContains(t, err, "not found")
+
+	dup := createTestTicket(s, "kt-b2", "Duplicate", ticket.StatusOpen)
+	dup.ExternalRef = "gh-123"
+	require.NoError(t, s.Save(dup))
+	_, err = s.Resolve("gh-123")
+	assert.EqualError(t, err, "ambiguous external-ref \"gh-123\" matches 2 tickets:\n  kt-a1  Imported\n  kt-b2  Duplicate")
+
+	require.NoError(t, s.Transaction(func(tx *Tx) error {
+		_, err := tx.Resolve("gh-123")
+		assert.ErrorContains(t, err, "ambiguous external-ref")
+		tx.Delete("kt-b2")
+		got, err := tx.Resolve("gh-123")
+		require.NoError(t, err)
+		assert.Equal(t, "kt-a1", got.ID)
+		return nil
+	}))
+}
+
 func TestUpdate(t *testing.T) {
 	s := setupTestStore(t)
 	createTestTicket(s, "kt-atomic", "Atomic Test", ticket.StatusOpen)
@@ -0,0 +1,216 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/kostyay/kticket/internal/filelock"
+	"github.com/kostyay/kticket/internal/ticket"
+)
+
+// Tx is a store-wide transaction for operations spanning many tickets.
+// It holds the exclusive store lock, so concurrent List/Resolve calls
+// block until it finishes. Changes are written only if the transaction
+// function returns nil.
+type Tx struct {
+	store   *Store
+	tickets map[string]*ticket.Ticket
+	dirty   map[string]bool
+	deleted map[string]bool
+}
+
+// Transaction runs fn with exclusive access to the whole store.
+// Tickets returned by the Tx may be modified in place and passed to Save.
+func (s *Store) Transaction(fn func(tx *Tx) error) error {
+	if err := s.EnsureDir(); err != nil {
+		return err
+	}
+
+	lock, err := filelock.Acquire(s.storeLockPath())
+	if err != nil {
+		return fmt.Errorf("acquire store lock: %w", err)
+	}
+	defer func() { _ = lock.Release() }()
+
+	all, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	tx := &Tx{
+		store:   s,
+		tickets: make(map[string]*ticket.Ticket, len(all)),
+		dirty:   make(map[string]bool),
+		deleted: make(map[string]bool),
+	}
+	for _, t := range all {
+		tx.tickets[t.ID] = t
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.commit()
+}
+
+// List returns all tickets visible in the transaction, newest first.
+func (tx *Tx) List() []*ticket.Ticket {
+	out := make([]*ticket.Ticket, 0, len(tx.tickets))
+	for _, t := range tx.tickets {
+		out = append(out, t)
+	}
+	sortTickets(out)
+	return out
+}
+
+// Get returns a ticket by exact ID.
+func (tx *Tx) Get(id string) (*ticket.Ticket, error) {
+	t, ok := tx.tickets[id]
+	if !ok {
+		return nil, fmt.Errorf("ticket %q %w", id, ErrNotFound)
+	}
+	return t, nil
+}
+
+// Resolve finds a ticket by partial ID, with the same rules as Store.Resolve.
+func (tx *Tx) Resolve(partial string) (*ticket.Ticket, error) {
+	ids := make([]string, 0, len(tx.tickets))
+	for id := range tx.tickets {
+		ids = append(ids, id)
+	}
+	candidates := matchID(partial, ids)
+	switch len(candidates) {
+	case 0:
+		return pickRefMatch(partial, externalRefMatches(tx.List(), partial))
+	case 1:
+		return tx.tickets[candidates[0]], nil
+	}
+	tickets := make([]*ticket.Ticket, len(candidates))
+	for i, id := range candidates {
+		tickets[i] = tx.tickets[id]
+	}
+	return nil, ambiguousError(fmt.Sprintf("ID %q", partial), tickets)
+}
+
+// Save stages a ticket for writing on commit.
+func (tx *Tx) Save(t *ticket.Ticket) {
+	tx.tickets[t.ID] = t
+	tx.dirty[t.ID] = true
+	delete(tx.deleted, t.ID)
+}
+
+// Delete stages a ticket for removal on commit.
+func (tx *Tx) Delete(id string) {
+	delete(tx.tickets, id)
+	delete(tx.dirty, id)
+	tx.deleted[id] = true
+}
+
+// RewriteRefs replaces every deps/links/relations/parent reference to oldID with newID
+// across all tickets (duplicates and self-references are dropped). An empty newID
+// removes the references.
+// Returns the IDs of tickets that changed, which are staged for saving.
+func (tx *Tx) RewriteRefs(oldID, newID string) []string {
+	var changed []string
+	for _, t := range tx.List() {
+		if t.ID == oldID {
+			continue
+		}
+		modified := false
+		if t.Parent == oldID {
+			t.Parent = newID
+			if t.Parent == t.ID {
+				t.Parent = ""
+			}
+			modified = true
+		}
+		if deps, ok := replaceRef(t.Deps, oldID, newID, t.ID); ok {
+			t.Deps = deps
+			modified = true
+		}
+		if links, ok := replaceRef(t.Links, oldID, newID, t.ID); ok {
+			t.Links = links
+			modified = true
+		}
+		for lt, ids := range t.Relations {
+			if replaced, ok := replaceRef(ids, oldID, newID, t.ID); ok {
+				t.Relations[lt] = replaced
+				if len(replaced) == 0 {
+					delete(t.Relations, lt)
+				}
+				modified = true
+			}
+		}
+		if modified {
+			tx.Save(t)
+			changed = append(changed, t.ID)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// replaceRef swaps oldID for newID in refs, dropping duplicates and selfID,
+// or drops oldID if newID is empty.
+func replaceRef(refs []string, oldID, newID, selfID string) ([]string, bool) {
+	if !slices.Contains(refs, oldID) {
+		return refs, false
+	}
+	out := make([]string, 0, len(refs))
+	for _, r := range refs {
+		if r == oldID {
+			r = newID
+		}
+		if r == "" || r == selfID || slices.Contains(out, r) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, true
+}
+
+// commit writes staged changes, taking each ticket's lock while writing.
+func (tx *Tx) commit() error {
+	ids := make([]string, 0, len(tx.dirty))
+	for id := range tx.dirty {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := tx.write(tx.tickets[id]); err != nil {
+			return fmt.Errorf("save %s: %w", id, err)
+		}
+	}
+	for id := range tx.deleted {
+		if err := tx.remove(id); err != nil {
+			return fmt.Errorf("delete %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func (tx *Tx) write(t *ticket.Ticket) error {
+	lock, err := filelock.Acquire(tx.store.lockPath(t.ID))
+	if err != nil {
+		return fmt.Errorf("acquire lock: %w", err)
+	}
+	defer func() { _ = lock.Release() }()
+
+	return tx.store.writeTicket(t)
+}
+
+func (tx *Tx) remove(id string) error {
+	lock, err := filelock.Acquire(tx.store.lockPath(id))
+	if err != nil {
+		return fmt.Errorf("acquire lock: %w", err)
+	}
+	defer func() { _ = lock.Release() }()
+
+	if err := tx.store.removeTicket(id); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionCommit(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-a", "A", ticket.StatusOpen)
+	createTestTicket(s, "kt-b", "B", ticket.StatusOpen)
+
+	err := s.Transaction(func(tx *Tx) error {
+		a, err := tx.Resolve("a")
+		require.NoError(t, err)
+		a.Title = "A updated"
+		tx.Save(a)
+		tx.Delete("kt-b")
+		tx.Save(&ticket.Ticket{ID: "kt-c", Status: ticket.StatusOpen, Title: "C"})
+		return nil
+	})
+	require.NoError(t, err)
+
+	a, err := s.Get("kt-a")
+	require.NoError(t, err)
+	assert.Equal(t, "A updated", a.Title)
+
+	_, err = s.Get("kt-b")
+	assert.Error(t, err)
+
+	_, err = s.Get("kt-c")
+	assert.NoError(t, err)
+}
+
+func TestTransactionRollback(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-a", "A", ticket.StatusOpen)
+
+	err := s.Transaction(func(tx *Tx) error {
+		a, _ := tx.Get("kt-a")
+		a.Title = "changed"
+		tx.Save(a)
+		return errors.New("boom")
+	})
+	require.Error(t, err)
+
+	a, _ := s.Get("kt-a")
+	assert.Equal(t, "A", a.Title)
+}
+
+func TestTxResolve(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-abc1", "One", ticket.StatusOpen)
+	createTestTicket(s, "kt-abc2", "Two", ticket.StatusOpen)
+
+	err := s.Transaction(func(tx *Tx) error {
+		_, err := tx.Resolve("abc")
+		assert.ErrorContains(t, err, "ambiguous")
+		_, err = tx.Resolve("zzz")
+		assert.ErrorContains(t, err, "not found")
+		got, err := tx.Resolve("abc2")
+		require.NoError(t, err)
+		assert.Equal(t, "kt-abc2", got.ID)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestTxRewriteRefs(t *testing.T) {
+	s := setupTestStore(t)
+	createTestTicket(s, "kt-old", "Old", ticket.StatusOpen)
+	createTestTicket(s, "kt-new", "New", ticket.StatusOpen)
+	child := createTestTicket(s, "kt-child", "Child", ticket.StatusOpen)
+	child.Parent = "kt-old"
+	child.Deps = []string{"kt-old", "kt-new"}
+	child.Links = []string{"kt-old"}
+	require.NoError(t, s.Save(child))
+	target := createTestTicket(s, "kt-x", "X", ticket.StatusOpen)
+	target.Deps = []string{"kt-new"}
+	require.NoError(t, s.Save(target))
+
+	var changed []string
+	err := s.Transaction(func(tx *Tx) error {
+		changed = tx.RewriteRefs("kt-old", "kt-new")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-child"}, changed)
+
+	got, _ := s.Get("kt-child")
+	assert.Equal(t, "kt-new", got.Parent)
+	assert.Equal(t, []string{"kt-new"}, got.Deps)
+	assert.Equal(t, []string{"kt-new"}, got.Links)
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch signals on the returned channel when a ticket file is added,
+// removed, or modified, by this or any other process. It reacts to
+// filesystem notifications where available and also polls every interval,
+// which covers filesystems without notification support and a store
+// directory that does not exist yet. Signals are coalesced while one is
+// pending. The channel is closed when ctx is done.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	last := s.fingerprint()
+
+	// A nil channel never receives, leaving only the ticker.
+	var events <-chan fsnotify.Event
+	var errs <-chan error
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err := watcher.Add(s.Dir); err == nil {
+			events, errs = watcher.Events, watcher.Errors
+		}
+	}
+
+	go func() {
+		defer close(ch)
+		if watcher != nil {
+			defer watcher.Close()
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case ev, ok := <-events:
+				if !ok {
+					events = nil
+					continue
+				}
+				if filepath.Ext(ev.Name) != ".md" {
+					continue
+				}
+			case _, ok := <-errs:
+				if !ok {
+					errs = nil
+				}
+				continue
+			}
+			if fp := s.fingerprint(); fp != last {
+				last = fp
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// fingerprint summarizes the name, size, and modification time of every
+// ticket file.
+func (s *Store) fingerprint() string {
+	matches, _ := filepath.Glob(filepath.Join(s.Dir, "*.md"))
+	h := sha256.New()
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(h, "%s %d %d\n", filepath.Base(path), info.Size(), info.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kostyay/kticket/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	s := setupTestStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := s.Watch(ctx, 5*time.Millisecond)
+
+	wait := func() bool {
+		select {
+		case _, ok := <-changes:
+			return ok
+		case <-time.After(2 * time.Second):
+			return false
+		}
+	}
+
+	createTestTicket(s, "kt-w1", "Watched", ticket.StatusOpen)
+	assert.True(t, wait(), "create is signaled")
+
+	require.NoError(t, s.Update("kt-w1", func(tk *ticket.Ticket) error {
+		tk.Title = "Watched, with a longer title"
+		return nil
+	}))
+	assert.True(t, wait(), "update is signaled")
+
+	require.NoError(t, s.Delete("kt-w1"))
+	assert.True(t, wait(), "delete is signaled")
+
+	cancel()
+	for range changes {
+	}
+}
+
+func TestWatchNotify(t *testing.T) {
+	s := setupTestStore(t)
+	require.NoError(t, s.EnsureDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Polling is effectively off, so the signal must come from fsnotify
+	changes := s.Watch(ctx, time.Hour)
+
+	createTestTicket(s, "kt-n1", "Notified", ticket.StatusOpen)
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no signal for create")
+	}
+}
@@ -0,0 +1,39 @@
+package ticket
+
+// FieldChange is a field whose value differs between two versions of a
+// ticket, in the string form Field returns.
+type FieldChange struct {
+	Field  string `json:"field"` // the JSON key, e.g. acceptance_criteria
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// diffFields are the fields Diff compares, in the order it reports them:
+// the frontmatter kt show leads with, then the body sections.
+var diffFields = []string{
+	"title", "status", "resolution", "type", "priority", "assignee", "claimed_by",
+	"parent", "deps", "links", "labels", "due", "estimate", "recurrence",
+	"external_ref", "commits", "tests_passed", "closed",
+	"description", "design", "acceptance", "tests", "notes",
+}
+
+// Diff returns the fields that differ from before to after. A nil ticket
+// has every field empty, so a created ticket lists all its set fields.
+func Diff(before, after *Ticket) []FieldChange {
+	if before == nil {
+		before = &Ticket{}
+	}
+	if after == nil {
+		after = &Ticket{}
+	}
+	var changes []FieldChange
+	for _, name := range diffFields {
+		a, _ := before.Field(name)
+		b, _ := after.Field(name)
+		if a != b {
+			key, _ := FieldKey(name)
+			changes = append(changes, FieldChange{Field: key, Before: a, After: b})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,22 @@
+package ticket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	before := &Ticket{ID: "kt-1", Title: "Same", Status: StatusOpen, Priority: 2, Labels: []string{"api"}, AcceptanceCriteria: "- [ ] a"}
+	after := &Ticket{ID: "kt-1", Title: "Same", Status: StatusClosed, Priority: 2, Labels: []string{"api", "ui"}, AcceptanceCriteria: "- [x] a"}
+
+	assert.Equal(t, []FieldChange{
+		{Field: "status", Before: "open", After: "closed"},
+		{Field: "labels", Before: "api", After: "api,ui"},
+		{Field: "acceptance_criteria", Before: "- [ ] a", After: "- [x] a"},
+	}, Diff(before, after))
+	assert.Empty(t, Diff(after, after))
+
+	created := Diff(nil, &Ticket{Title: "New", Status: StatusOpen, Priority: 0})
+	assert.Equal(t, []FieldChange{{Field: "title", After: "New"}, {Field: "status", After: "open"}}, created)
+}
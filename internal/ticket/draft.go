@@ -0,0 +1,73 @@
+package ticket
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// DraftKeys are the frontmatter keys a draft may set. The rest (id,
+// status, timestamps, history, ...) belong to kt, not to whoever writes a
+// new ticket.
+var DraftKeys = []string{"type", "priority", "assignee", "external-ref", "parent", "labels", "due", "estimate", "recurrence", "deps", "links"}
+
+// ParseDraft parses a new ticket written as markdown: a "# Title" line and
+// sections as in a ticket file, optionally preceded by frontmatter setting
+// DraftKeys. It also returns the frontmatter keys given, in order, so a
+// value set to zero (priority: 0) can be told from one left out.
+func ParseDraft(data []byte) (*Ticket, []string, error) {
+	t := &Ticket{}
+	body := bytes.TrimLeft(data, " \t\r\n")
+	var keys []string
+	if bytes.HasPrefix(body, []byte("---")) {
+		frontmatter, rest, err := splitFrontmatter(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		if keys, err = draftKeys(frontmatter); err != nil {
+			return nil, nil, err
+		}
+		if err := yaml.UnmarshalWithOptions(frontmatter, t, yaml.Strict()); err != nil {
+			return nil, nil, fmt.Errorf("parse frontmatter: %w", err)
+		}
+		body = rest
+	}
+
+	if t.Type != "" && !slices.Contains(Types, t.Type) {
+		return nil, nil, fmt.Errorf("invalid type %q (expected one of %v)", t.Type, Types)
+	}
+	if t.Priority < 0 || t.Priority > 4 {
+		return nil, nil, fmt.Errorf("invalid priority %d (expected 0-4)", t.Priority)
+	}
+	if t.Due != "" && !validTime(DueLayout, t.Due) {
+		return nil, nil, fmt.Errorf("invalid due %q (expected YYYY-MM-DD)", t.Due)
+	}
+	if t.Recurrence != "" {
+		if _, err := ParseRecurrence(t.Recurrence); err != nil {
+			return nil, nil, err
+		}
+	}
+	parseBody(t, body)
+	return t, keys, nil
+}
+
+// draftKeys returns the keys of a draft's frontmatter, rejecting any
+// outside DraftKeys.
+func draftKeys(frontmatter []byte) ([]string, error) {
+	var fields yaml.MapSlice
+	if err := yaml.Unmarshal(frontmatter, &fields); err != nil {
+		return nil, fmt.Errorf("parse frontmatter: %w", err)
+	}
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		key := fmt.Sprint(f.Key)
+		if !slices.Contains(DraftKeys, key) {
+			return nil, fmt.Errorf("frontmatter key %q cannot be set on a new ticket (allowed: %s)", key, strings.Join(DraftKeys, ", "))
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,50 @@
+package ticket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDraft(t *testing.T) {
+	draft, keys, err := ParseDraft([]byte(`
+---
+type: bug
+priority: 0
+labels: [auth]
+---
+# Login fails
+
+Users get a 500.
+
+## Acceptance Criteria
+
+- [ ] Login works
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"type", "priority", "labels"}, keys)
+	assert.Equal(t, TypeBug, draft.Type)
+	assert.Equal(t, []string{"auth"}, draft.Labels)
+	assert.Equal(t, "Login fails", draft.Title)
+	assert.Equal(t, "Users get a 500.", draft.Description)
+	assert.Equal(t, "- [ ] Login works", draft.AcceptanceCriteria)
+
+	draft, keys, err = ParseDraft([]byte("# Just a title\n\nAnd a description.\n"))
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	assert.Equal(t, "Just a title", draft.Title)
+	assert.Equal(t, "And a description.", draft.Description)
+
+	for input, want := range map[string]string{
+		"---\nstatus: closed\n---\n# T\n":    `frontmatter key "status" cannot be set`,
+		"---\ntype: saga\n---\n# T\n":        `invalid type "saga"`,
+		"---\ndue: tomorrow\n---\n# T\n":     `invalid due "tomorrow"`,
+		"---\npriority: high\n---\n# T\n":    "parse frontmatter",
+		"---\npriority: 99\n---\n# T\n":      "invalid priority 99 (expected 0-4)",
+		"---\nrecurrence: often\n---\n# T\n": "recurrence",
+	} {
+		_, _, err := ParseDraft([]byte(input))
+		assert.ErrorContains(t, err, want, input)
+	}
+}
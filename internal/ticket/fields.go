@@ -0,0 +1,193 @@
+package ticket
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Statuses lists the built-in ticket statuses.
+var Statuses = []Status{StatusOpen, StatusInProgress, StatusClosed}
+
+// Types lists the built-in ticket types.
+var Types = []Type{TypeBug, TypeFeature, TypeTask, TypeEpic, TypeChore}
+
+// DueLayout is the date format used for the due field.
+const DueLayout = "2006-01-02"
+
+// normalizeField maps user-facing field names to canonical keys.
+// Accepts both dash and underscore spellings (external-ref, external_ref).
+func normalizeField(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.ReplaceAll(name, "-", "_")
+	switch name {
+	case "acceptance_criteria":
+		return "acceptance"
+	case "label":
+		return "labels"
+	}
+	return name
+}
+
+// SetField assigns a ticket field from its string representation.
+// Relationship fields (deps, links, commits) are managed by dedicated commands.
+func (t *Ticket) SetField(name, value string) error {
+	value = strings.TrimSpace(value)
+	switch normalizeField(name) {
+	case "title":
+		if value == "" {
+			return fmt.Errorf("title cannot be empty")
+		}
+		t.Title = value
+	case "status":
+		t.SetStatus(Status(value))
+	case "type":
+		if !slices.Contains(Types, Type(value)) {
+			return fmt.Errorf("invalid type %q (expected one of %v)", value, Types)
+		}
+		t.Type = Type(value)
+	case "priority":
+		p, err := strconv.Atoi(value)
+		if err != nil || p < 0 || p > 4 {
+			return fmt.Errorf("invalid priority %q (expected 0-4)", value)
+		}
+		t.Priority = p
+	case "assignee":
+		t.Assignee = value
+	case "external_ref":
+		t.ExternalRef = value
+	case "parent":
+		t.Parent = value
+	case "due":
+		if value != "" {
+			if _, err := time.Parse(DueLayout, value); err != nil {
+				return fmt.Errorf("invalid due date %q (expected YYYY-MM-DD)", value)
+			}
+		}
+		t.Due = value
+	case "estimate":
+		if value == "" {
+			t.Estimate = 0
+			break
+		}
+		e, err := strconv.ParseFloat(value, 64)
+		if err != nil || e < 0 {
+			return fmt.Errorf("invalid estimate %q (expected a non-negative number)", value)
+		}
+		t.Estimate = e
+	case "labels":
+		t.Labels = splitList(value)
+	case "recurrence":
+		if value != "" {
+			if _, err := ParseRecurrence(value); err != nil {
+				return err
+			}
+		}
+		t.Recurrence = value
+	case "tests_passed":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid tests_passed %q (expected true|false)", value)
+		}
+		t.TestsPassed = b
+	case "description":
+		t.Description = value
+	case "design":
+		t.Design = value
+	case "acceptance":
+		t.AcceptanceCriteria = value
+	case "tests":
+		t.Tests = value
+	default:
+		return fmt.Errorf("unknown field %q", name)
+	}
+	return nil
+}
+
+// FieldKey returns the key of a field accepted by Field in a ticket's
+// JSON form, e.g. acceptance_criteria for acceptance. Returns false for
+// unknown fields.
+func FieldKey(name string) (string, bool) {
+	key := normalizeField(name)
+	if _, ok := (&Ticket{}).Field(key); !ok {
+		return "", false
+	}
+	if key == "acceptance" {
+		return "acceptance_criteria", true
+	}
+	return key, true
+}
+
+// Field returns the string representation of a ticket field.
+// List fields are joined with commas. Returns false for unknown fields.
+func (t *Ticket) Field(name string) (string, bool) {
+	switch normalizeField(name) {
+	case "id":
+		return t.ID, true
+	case "title":
+		return t.Title, true
+	case "status":
+		return string(t.Status), true
+	case "type":
+		return string(t.Type), true
+	case "priority":
+		return strconv.Itoa(t.Priority), true
+	case "assignee":
+		return t.Assignee, true
+	case "claimed_by":
+		return t.ClaimedBy, true
+	case "resolution":
+		return t.Resolution, true
+	case "external_ref":
+		return t.ExternalRef, true
+	case "parent":
+		return t.Parent, true
+	case "created":
+		return t.Created, true
+	case "closed":
+		return t.Closed, true
+	case "due":
+		return t.Due, true
+	case "estimate":
+		if t.Estimate == 0 {
+			return "", true
+		}
+		return strconv.FormatFloat(t.Estimate, 'g', -1, 64), true
+	case "labels":
+		return strings.Join(t.Labels, ","), true
+	case "recurrence":
+		return t.Recurrence, true
+	case "deps":
+		return strings.Join(t.Deps, ","), true
+	case "links":
+		return strings.Join(t.Links, ","), true
+	case "commits":
+		return strings.Join(t.Commits, ","), true
+	case "tests_passed":
+		return strconv.FormatBool(t.TestsPassed), true
+	case "description":
+		return t.Description, true
+	case "design":
+		return t.Design, true
+	case "acceptance":
+		return t.AcceptanceCriteria, true
+	case "tests":
+		return t.Tests, true
+	case "notes":
+		return t.Notes, true
+	}
+	return "", false
+}
+
+// splitList splits a comma-separated value, dropping empty entries.
+func splitList(value string) []string {
+	var out []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
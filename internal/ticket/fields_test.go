@@ -0,0 +1,102 @@
+package ticket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetField(t *testing.T) {
+	tk := &Ticket{ID: "kt-1", Title: "Old", Type: TypeTask, Priority: 2}
+
+	require.NoError(t, tk.SetField("title", "New title"))
+	require.NoError(t, tk.SetField("priority", "0"))
+	require.NoError(t, tk.SetField("type", "bug"))
+	require.NoError(t, tk.SetField("assignee", "alice"))
+	require.NoError(t, tk.SetField("external-ref", "gh-42"))
+	require.NoError(t, tk.SetField("due", "2026-03-01"))
+	require.NoError(t, tk.SetField("labels", "backend, api,,"))
+	require.NoError(t, tk.SetField("estimate", "2.5"))
+	require.NoError(t, tk.SetField("tests_passed", "true"))
+
+	assert.Equal(t, "New title", tk.Title)
+	assert.Equal(t, 0, tk.Priority)
+	assert.Equal(t, TypeBug, tk.Type)
+	assert.Equal(t, "alice", tk.Assignee)
+	assert.Equal(t, "gh-42", tk.ExternalRef)
+	assert.Equal(t, "2026-03-01", tk.Due)
+	assert.Equal(t, []string{"backend", "api"}, tk.Labels)
+	assert.Equal(t, 2.5, tk.Estimate)
+	assert.True(t, tk.TestsPassed)
+
+	// Empty value clears
+	require.NoError(t, tk.SetField("labels", ""))
+	assert.Empty(t, tk.Labels)
+}
+
+func TestSetFieldErrors(t *testing.T) {
+	tk := &Ticket{ID: "kt-1", Title: "Title"}
+
+	tests := []struct {
+		field, value, errContains string
+	}{
+		{"priority", "5", "invalid priority"},
+		{"priority", "high", "invalid priority"},
+		{"type", "story", "invalid type"},
+		{"due", "tomorrow", "invalid due date"},
+		{"estimate", "-1", "invalid estimate"},
+		{"tests_passed", "maybe", "invalid tests_passed"},
+		{"title", "  ", "title cannot be empty"},
+		{"bogus", "x", "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field+"="+tt.value, func(t *testing.T) {
+			err := tk.SetField(tt.field, tt.value)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+	assert.Equal(t, "Title", tk.Title)
+}
+
+func TestField(t *testing.T) {
+	tk := &Ticket{
+		ID:          "kt-1",
+		Status:      StatusOpen,
+		Priority:    3,
+		Labels:      []string{"a", "b"},
+		ExternalRef: "gh-1",
+	}
+
+	v, ok := tk.Field("priority")
+	assert.True(t, ok)
+	assert.Equal(t, "3", v)
+
+	v, ok = tk.Field("labels")
+	assert.True(t, ok)
+	assert.Equal(t, "a,b", v)
+
+	v, ok = tk.Field("external-ref")
+	assert.True(t, ok)
+	assert.Equal(t, "gh-1", v)
+
+	_, ok = tk.Field("nope")
+	assert.False(t, ok)
+}
+
+func TestFieldKey(t *testing.T) {
+	for in, want := range map[string]string{
+		"title":               "title",
+		"Acceptance-Criteria": "acceptance_criteria",
+		"acceptance":          "acceptance_criteria",
+		"external-ref":        "external_ref",
+		"label":               "labels",
+	} {
+		got, ok := FieldKey(in)
+		assert.True(t, ok, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, ok := FieldKey("history")
+	assert.False(t, ok)
+}
@@ -0,0 +1,81 @@
+package ticket
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Filter is a conjunction of field conditions, e.g. "status=open and priority=4".
+type Filter []condition
+
+type condition struct {
+	field  string
+	values []string
+	negate bool
+}
+
+var andSplit = regexp.MustCompile(`(?i)\s+and\s+`)
+
+// listFields are matched by membership rather than equality.
+var listFields = []string{"labels", "deps", "links"}
+
+// ParseFilter parses a filter expression.
+// Clauses are joined with "and"; each clause is field=value or field!=value.
+// A comma-separated value matches any of the alternatives (status=open,in_progress).
+func ParseFilter(expr string) (Filter, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+
+	var f Filter
+	probe := &Ticket{}
+	for _, clause := range andSplit.Split(expr, -1) {
+		var c condition
+		field, value, ok := strings.Cut(clause, "!=")
+		if ok {
+			c.negate = true
+		} else if field, value, ok = strings.Cut(clause, "="); !ok {
+			return nil, fmt.Errorf("invalid filter clause %q (expected field=value or field!=value)", clause)
+		}
+		c.field = normalizeField(field)
+		if _, known := probe.Field(c.field); !known {
+			return nil, fmt.Errorf("unknown filter field %q", strings.TrimSpace(field))
+		}
+		for _, v := range strings.Split(value, ",") {
+			c.values = append(c.values, strings.TrimSpace(v))
+		}
+		f = append(f, c)
+	}
+	return f, nil
+}
+
+// Match reports whether the ticket satisfies every condition.
+// An empty filter matches all tickets.
+func (f Filter) Match(t *Ticket) bool {
+	for _, c := range f {
+		if c.match(t) == c.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (c condition) match(t *Ticket) bool {
+	actual, _ := t.Field(c.field)
+	if slices.Contains(listFields, c.field) {
+		have := splitList(actual)
+		for _, v := range c.values {
+			if v == "" && len(have) == 0 {
+				return true
+			}
+			if slices.Contains(have, v) {
+				return true
+			}
+		}
+		return false
+	}
+	return slices.Contains(c.values, actual)
+}
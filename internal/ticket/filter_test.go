@@ -0,0 +1,45 @@
+package ticket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	open := &Ticket{ID: "kt-1", Status: StatusOpen, Priority: 4, Labels: []string{"api"}}
+	closed := &Ticket{ID: "kt-2", Status: StatusClosed, Priority: 1}
+
+	tests := []struct {
+		expr             string
+		openOK, closedOK bool
+	}{
+		{"", true, true},
+		{"status=open", true, false},
+		{"status=open AND priority=4", true, false},
+		{"status=open and priority=3", false, false},
+		{"status!=closed", true, false},
+		{"status=open,closed", true, true},
+		{"labels=api", true, false},
+		{"labels=", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.openOK, f.Match(open))
+			assert.Equal(t, tt.closedOK, f.Match(closed))
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	_, err := ParseFilter("status")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid filter clause")
+
+	_, err = ParseFilter("color=red")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown filter field")
+}
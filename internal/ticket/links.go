@@ -0,0 +1,130 @@
+package ticket
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// LinkType is the kind of relationship between two tickets.
+type LinkType string
+
+const (
+	LinkRelatesTo    LinkType = "relates-to"
+	LinkBlocks       LinkType = "blocks"
+	LinkBlockedBy    LinkType = "blocked-by"
+	LinkDuplicates   LinkType = "duplicates"
+	LinkDuplicatedBy LinkType = "duplicated-by"
+	LinkCausedBy     LinkType = "caused-by"
+	LinkCauses       LinkType = "causes"
+)
+
+// linkInverses maps each link type to the type recorded on the other ticket.
+var linkInverses = map[LinkType]LinkType{
+	LinkRelatesTo:    LinkRelatesTo,
+	LinkBlocks:       LinkBlockedBy,
+	LinkBlockedBy:    LinkBlocks,
+	LinkDuplicates:   LinkDuplicatedBy,
+	LinkDuplicatedBy: LinkDuplicates,
+	LinkCausedBy:     LinkCauses,
+	LinkCauses:       LinkCausedBy,
+}
+
+// LinkTypes lists the link types accepted by ParseLinkType, in display order.
+var LinkTypes = []LinkType{
+	LinkRelatesTo, LinkBlocks, LinkBlockedBy, LinkDuplicates,
+	LinkDuplicatedBy, LinkCausedBy, LinkCauses,
+}
+
+// ParseLinkType validates a link type name.
+func ParseLinkType(s string) (LinkType, error) {
+	lt := LinkType(s)
+	if _, ok := linkInverses[lt]; !ok {
+		return "", fmt.Errorf("invalid link type %q (expected one of %v)", s, LinkTypes)
+	}
+	return lt, nil
+}
+
+// Inverse returns the link type seen from the other ticket.
+func (lt LinkType) Inverse() LinkType {
+	return linkInverses[lt]
+}
+
+// TypedLink is a relationship from a ticket to another ticket.
+type TypedLink struct {
+	Type LinkType `json:"type"`
+	ID   string   `json:"id"`
+}
+
+// AddLink records a relationship to id. Plain relates-to links are kept in
+// Links for compatibility; other types live in Relations.
+// Returns false if the link already exists.
+func (t *Ticket) AddLink(lt LinkType, id string) bool {
+	if lt == LinkRelatesTo {
+		if slices.Contains(t.Links, id) {
+			return false
+		}
+		t.Links = append(t.Links, id)
+		return true
+	}
+	if slices.Contains(t.Relations[lt], id) {
+		return false
+	}
+	if t.Relations == nil {
+		t.Relations = make(map[LinkType][]string)
+	}
+	t.Relations[lt] = append(t.Relations[lt], id)
+	return true
+}
+
+// RemoveLinks removes every relationship (of any type) to id.
+// Returns false if there was none.
+func (t *Ticket) RemoveLinks(id string) bool {
+	removed := false
+	match := func(s string) bool {
+		if s == id {
+			removed = true
+			return true
+		}
+		return false
+	}
+	t.Links = slices.DeleteFunc(t.Links, match)
+	for lt, ids := range t.Relations {
+		t.Relations[lt] = slices.DeleteFunc(ids, match)
+		if len(t.Relations[lt]) == 0 {
+			delete(t.Relations, lt)
+		}
+	}
+	if len(t.Relations) == 0 {
+		t.Relations = nil
+	}
+	return removed
+}
+
+// TypedLinks returns all relationships, plain links first as relates-to,
+// then typed relations in LinkTypes order.
+func (t *Ticket) TypedLinks() []TypedLink {
+	var out []TypedLink
+	for _, id := range t.Links {
+		out = append(out, TypedLink{Type: LinkRelatesTo, ID: id})
+	}
+	for _, lt := range LinkTypes[1:] {
+		ids := slices.Clone(t.Relations[lt])
+		sort.Strings(ids)
+		for _, id := range ids {
+			out = append(out, TypedLink{Type: lt, ID: id})
+		}
+	}
+	return out
+}
+
+// LinkedIDs returns every ticket ID referenced by any relationship.
+func (t *Ticket) LinkedIDs() []string {
+	var ids []string
+	for _, l := range t.TypedLinks() {
+		if !slices.Contains(ids, l.ID) {
+			ids = append(ids, l.ID)
+		}
+	}
+	return ids
+}
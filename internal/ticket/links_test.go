@@ -0,0 +1,57 @@
+package ticket
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLinkType(t *testing.T) {
+	lt, err := ParseLinkType("blocks")
+	require.NoError(t, err)
+	assert.Equal(t, LinkBlocks, lt)
+	assert.Equal(t, LinkBlockedBy, lt.Inverse())
+	assert.Equal(t, LinkRelatesTo, LinkRelatesTo.Inverse())
+
+	_, err = ParseLinkType("depends")
+	assert.Error(t, err)
+}
+
+func TestAddRemoveLinks(t *testing.T) {
+	tk := &Ticket{ID: "kt-a"}
+
+	assert.True(t, tk.AddLink(LinkRelatesTo, "kt-b"))
+	assert.False(t, tk.AddLink(LinkRelatesTo, "kt-b"))
+	assert.True(t, tk.AddLink(LinkBlocks, "kt-c"))
+	assert.True(t, tk.AddLink(LinkCausedBy, "kt-b"))
+
+	assert.Equal(t, []string{"kt-b"}, tk.Links)
+	assert.Equal(t, []TypedLink{
+		{Type: LinkRelatesTo, ID: "kt-b"},
+		{Type: LinkBlocks, ID: "kt-c"},
+		{Type: LinkCausedBy, ID: "kt-b"},
+	}, tk.TypedLinks())
+	assert.Equal(t, []string{"kt-b", "kt-c"}, tk.LinkedIDs())
+
+	assert.True(t, tk.RemoveLinks("kt-b"))
+	assert.Empty(t, tk.Links)
+	assert.Equal(t, []string{"kt-c"}, tk.LinkedIDs())
+	assert.False(t, tk.RemoveLinks("kt-b"))
+
+	assert.True(t, tk.RemoveLinks("kt-c"))
+	assert.Nil(t, tk.Relations)
+}
+
+func TestRelationsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kt-a.md")
+	tk := &Ticket{ID: "kt-a", Status: StatusOpen, Type: TypeTask, Title: "A", Links: []string{"kt-b"}}
+	tk.AddLink(LinkDuplicates, "kt-c")
+	require.NoError(t, WriteFile(path, tk))
+
+	got, err := ParseFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kt-b"}, got.Links)
+	assert.Equal(t, []string{"kt-c"}, got.Relations[LinkDuplicates])
+}
@@ -0,0 +1,55 @@
+package ticket
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Lint parses a ticket file leniently and reports every problem in it:
+// frontmatter keys that are not ticket fields, a missing id or title, a
+// priority outside 0-4, and what Validate reports. The ticket is nil only
+// if data does not parse at all, which is then the one problem.
+func Lint(data []byte) (*Ticket, []error) {
+	t, err := parse(data, false)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var errs []error
+	frontmatter, _, _ := splitFrontmatter(data)
+	var keys map[string]any
+	if err := yaml.Unmarshal(frontmatter, &keys); err == nil {
+		for _, k := range slices.Sorted(maps.Keys(keys)) {
+			if !slices.Contains(frontmatterKeys, k) {
+				errs = append(errs, fmt.Errorf("unknown field %q", k))
+			}
+		}
+	}
+	if t.ID == "" {
+		errs = append(errs, fmt.Errorf("missing id"))
+	}
+	if t.Title == "" {
+		errs = append(errs, fmt.Errorf("missing title (a # heading after the frontmatter)"))
+	}
+	if t.Priority < 0 || t.Priority > 4 {
+		errs = append(errs, fmt.Errorf("invalid priority %d (expected 0-4)", t.Priority))
+	}
+	return t, append(errs, t.validate()...)
+}
+
+// frontmatterKeys are the YAML keys of the ticket fields.
+var frontmatterKeys = func() []string {
+	var keys []string
+	typ := reflect.TypeFor[Ticket]()
+	for i := range typ.NumField() {
+		if name, _, _ := strings.Cut(typ.Field(i).Tag.Get("yaml"), ","); name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}()
@@ -0,0 +1,42 @@
+package ticket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	valid := "---\nid: kt-1\nstatus: open\ncreated: 2026-01-09T10:00:00Z\ntype: task\npriority: 2\ntests_passed: false\n---\n# Title\n"
+	tk, errs := Lint([]byte(valid))
+	require.NotNil(t, tk)
+	assert.Empty(t, errs)
+
+	tk, errs = Lint([]byte("---\nstatus: opne\nowner: ann\ncreated: yesterday\ntype: task\npriority: 7\n---\nno heading\n"))
+	require.NotNil(t, tk, "lenient parse still succeeds")
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	assert.Equal(t, []string{
+		`unknown field "owner"`,
+		"missing id",
+		"missing title (a # heading after the frontmatter)",
+		"invalid priority 7 (expected 0-4)",
+		`invalid status "opne" (expected one of [open in_progress closed])`,
+		`invalid created "yesterday" (expected RFC 3339, like 2026-01-02T15:04:05Z)`,
+	}, got)
+
+	tk, errs = Lint([]byte("# No frontmatter\n"))
+	assert.Nil(t, tk)
+	assert.Len(t, errs, 1)
+}
+
+func TestLintIgnoresStrict(t *testing.T) {
+	defer func() { Strict = false }()
+	Strict = true
+	tk, errs := Lint([]byte("---\nid: kt-1\nstatus: opne\ncreated: 2026-01-09T10:00:00Z\ntype: task\npriority: 2\n---\n# Title\n"))
+	require.NotNil(t, tk)
+	assert.Len(t, errs, 1)
+}
@@ -0,0 +1,109 @@
+package ticket
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence is how often a recurring ticket comes back: every N units.
+type Recurrence struct {
+	N    int
+	Unit string // day, week, month, or year
+}
+
+var (
+	everyPattern = regexp.MustCompile(`^every\s+(?:(\d+)\s+)?(day|week|month|year)s?$`)
+	rruleFreqs   = map[string]string{"DAILY": "day", "WEEKLY": "week", "MONTHLY": "month", "YEARLY": "year"}
+	namedRecurs  = map[string]string{"daily": "day", "weekly": "week", "monthly": "month", "yearly": "year", "annually": "year"}
+)
+
+// ParseRecurrence parses a recurrence: daily, weekly, monthly, yearly,
+// "every 2 weeks", or an RRULE with FREQ and INTERVAL, like
+// FREQ=WEEKLY;INTERVAL=2.
+func ParseRecurrence(s string) (Recurrence, error) {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	if unit, ok := namedRecurs[lower]; ok {
+		return Recurrence{1, unit}, nil
+	}
+	if m := everyPattern.FindStringSubmatch(lower); m != nil {
+		n := 1
+		if m[1] != "" {
+			n, _ = strconv.Atoi(m[1])
+		}
+		if n > 0 {
+			return Recurrence{n, m[2]}, nil
+		}
+	}
+	if rule, ok := strings.CutPrefix(strings.ToUpper(s), "RRULE:"); ok || strings.HasPrefix(rule, "FREQ=") {
+		return parseRRule(s, rule)
+	}
+	return Recurrence{}, fmt.Errorf("invalid recurrence %q (expected daily, weekly, monthly, yearly, every N days|weeks|months|years, or FREQ=WEEKLY;INTERVAL=N)", s)
+}
+
+// parseRRule parses the FREQ and INTERVAL parts of an RRULE.
+func parseRRule(s, rule string) (Recurrence, error) {
+	r := Recurrence{N: 1}
+	for _, part := range strings.Split(rule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "FREQ":
+			r.Unit = rruleFreqs[value]
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Recurrence{}, fmt.Errorf("invalid recurrence %q: bad INTERVAL", s)
+			}
+			r.N = n
+		default:
+			return Recurrence{}, fmt.Errorf("invalid recurrence %q: only FREQ and INTERVAL are supported", s)
+		}
+	}
+	if r.Unit == "" {
+		return Recurrence{}, fmt.Errorf("invalid recurrence %q: FREQ must be DAILY, WEEKLY, MONTHLY, or YEARLY", s)
+	}
+	return r, nil
+}
+
+// Next returns the occurrence after t.
+func (r Recurrence) Next(t time.Time) time.Time {
+	switch r.Unit {
+	case "week":
+		return t.AddDate(0, 0, 7*r.N)
+	case "month":
+		return t.AddDate(0, r.N, 0)
+	case "year":
+		return t.AddDate(r.N, 0, 0)
+	}
+	return t.AddDate(0, 0, r.N)
+}
+
+// NextOccurrence returns the date a closed recurring ticket is due again:
+// the first occurrence after the day it was closed, counting from its due
+// date (or, without one, from the day it was closed). It returns false if
+// the ticket is open or does not recur.
+func (t *Ticket) NextOccurrence() (time.Time, bool) {
+	if t.Status != StatusClosed || t.Recurrence == "" {
+		return time.Time{}, false
+	}
+	r, err := ParseRecurrence(t.Recurrence)
+	if err != nil {
+		return time.Time{}, false
+	}
+	closed, err := time.Parse(time.RFC3339, t.Closed)
+	if err != nil {
+		return time.Time{}, false
+	}
+	closedDay, _ := time.Parse(DueLayout, closed.UTC().Format(DueLayout))
+	next := closedDay
+	if due, err := time.Parse(DueLayout, t.Due); err == nil {
+		next = due
+	}
+	for !next.After(closedDay) {
+		next = r.Next(next)
+	}
+	return next, true
+}
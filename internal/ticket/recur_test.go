@@ -0,0 +1,52 @@
+package ticket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	for in, want := range map[string]Recurrence{
+		"weekly":                       {1, "week"},
+		"Daily":                        {1, "day"},
+		"annually":                     {1, "year"},
+		"every month":                  {1, "month"},
+		"every 2 weeks":                {2, "week"},
+		"every 3 days":                 {3, "day"},
+		"FREQ=MONTHLY;INTERVAL=3":      {3, "month"},
+		"RRULE:FREQ=YEARLY":            {1, "year"},
+		"rrule:freq=weekly;interval=2": {2, "week"},
+	} {
+		got, err := ParseRecurrence(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "sometimes", "every 0 days", "FREQ=HOURLY", "FREQ=WEEKLY;BYDAY=MO", "FREQ=DAILY;INTERVAL=x"} {
+		_, err := ParseRecurrence(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	closed := &Ticket{Status: StatusClosed, Recurrence: "weekly", Closed: "2026-03-04T18:00:00Z"}
+	next, ok := closed.NextOccurrence()
+	require.True(t, ok)
+	assert.Equal(t, "2026-03-11", next.Format(DueLayout))
+
+	// Counts from the due date, skipping occurrences already past.
+	closed.Due = "2026-02-20"
+	next, _ = closed.NextOccurrence()
+	assert.Equal(t, "2026-03-06", next.Format(DueLayout))
+
+	closed.Recurrence = "every 2 months"
+	next, _ = closed.NextOccurrence()
+	assert.Equal(t, "2026-04-20", next.Format(DueLayout))
+
+	_, ok = (&Ticket{Status: StatusOpen, Recurrence: "weekly"}).NextOccurrence()
+	assert.False(t, ok)
+	_, ok = (&Ticket{Status: StatusClosed, Closed: time.Now().Format(time.RFC3339)}).NextOccurrence()
+	assert.False(t, ok)
+}
@@ -3,10 +3,14 @@ package ticket
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/goccy/go-yaml"
 )
@@ -29,19 +33,33 @@ const (
 	TypeChore   Type = "chore"
 )
 
+// ResolutionDuplicate marks a ticket closed as a duplicate of another.
+const ResolutionDuplicate = "duplicate"
+
 type Ticket struct {
 	// Frontmatter fields (YAML)
-	ID          string   `yaml:"id" json:"id"`
-	Status      Status   `yaml:"status" json:"status"`
-	Deps        []string `yaml:"deps,omitempty" json:"deps,omitempty"`
-	Links       []string `yaml:"links,omitempty" json:"links,omitempty"`
-	Created     string   `yaml:"created" json:"created"`
-	Type        Type     `yaml:"type" json:"type"`
-	Priority    int      `yaml:"priority" json:"priority"`
-	Assignee    string   `yaml:"assignee,omitempty" json:"assignee,omitempty"`
-	ExternalRef string   `yaml:"external-ref,omitempty" json:"external_ref,omitempty"`
-	Parent      string   `yaml:"parent,omitempty" json:"parent,omitempty"`
-	TestsPassed bool     `yaml:"tests_passed" json:"tests_passed"`
+	Schema      int                   `yaml:"schema,omitempty" json:"-"` // SchemaVersion when written; 0 before there was one
+	ID          string                `yaml:"id" json:"id"`
+	Status      Status                `yaml:"status" json:"status"`
+	Deps        []string              `yaml:"deps,omitempty" json:"deps,omitempty"`
+	Links       []string              `yaml:"links,omitempty" json:"links,omitempty"`
+	Relations   map[LinkType][]string `yaml:"relations,omitempty" json:"relations,omitempty"`
+	Commits     []string              `yaml:"commits,omitempty" json:"commits,omitempty"`
+	Created     string                `yaml:"created" json:"created"`
+	Closed      string                `yaml:"closed,omitempty" json:"closed,omitempty"`
+	Type        Type                  `yaml:"type" json:"type"`
+	Priority    int                   `yaml:"priority" json:"priority"`
+	Assignee    string                `yaml:"assignee,omitempty" json:"assignee,omitempty"`
+	ExternalRef string                `yaml:"external-ref,omitempty" json:"external_ref,omitempty"`
+	Parent      string                `yaml:"parent,omitempty" json:"parent,omitempty"`
+	Labels      []string              `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Due         string                `yaml:"due,omitempty" json:"due,omitempty"`
+	Estimate    float64               `yaml:"estimate,omitempty" json:"estimate,omitempty"`
+	Recurrence  string                `yaml:"recurrence,omitempty" json:"recurrence,omitempty"`
+	TestsPassed bool                  `yaml:"tests_passed" json:"tests_passed"`
+	Resolution  string                `yaml:"resolution,omitempty" json:"resolution,omitempty"`
+	ClaimedBy   string                `yaml:"claimed-by,omitempty" json:"claimed_by,omitempty"`
+	History     []Event               `yaml:"history,omitempty" json:"history,omitempty"`
 
 	// Parsed from markdown body
 	Title              string `yaml:"-" json:"title"`
@@ -52,6 +70,54 @@ type Ticket struct {
 	Notes              string `yaml:"-" json:"notes,omitempty"`
 }
 
+// Event records one mutation of a ticket by an agent session.
+type Event struct {
+	At      string `yaml:"at" json:"at"`
+	Session string `yaml:"session" json:"session"`
+	Op      string `yaml:"op,omitempty" json:"op,omitempty"`
+}
+
+// MaxHistory is the number of events kept in a ticket's history.
+const MaxHistory = 20
+
+// Touch records that session performed op on the ticket, keeping the last
+// MaxHistory events, and claims the ticket for session if it is in
+// progress and unclaimed.
+func (t *Ticket) Touch(session, op string) {
+	t.History = append(t.History, Event{At: time.Now().UTC().Format(time.RFC3339), Session: session, Op: op})
+	if n := len(t.History) - MaxHistory; n > 0 {
+		t.History = slices.Delete(t.History, 0, n)
+	}
+	if t.Status == StatusInProgress && t.ClaimedBy == "" {
+		t.ClaimedBy = session
+	}
+}
+
+// SetStatus changes the status, stamping Closed when the ticket is closed
+// and clearing it when it is reopened. Leaving in_progress releases the
+// ticket's claim.
+func (t *Ticket) SetStatus(s Status) {
+	if s != StatusInProgress {
+		t.ClaimedBy = ""
+	}
+	if s == StatusClosed && (t.Status != StatusClosed || t.Closed == "") {
+		t.Closed = time.Now().UTC().Format(time.RFC3339)
+	} else if s != StatusClosed {
+		t.Closed = ""
+	}
+	t.Status = s
+}
+
+// AddCommit records a commit SHA on the ticket. Returns false if it was
+// already recorded.
+func (t *Ticket) AddCommit(sha string) bool {
+	if slices.Contains(t.Commits, sha) {
+		return false
+	}
+	t.Commits = append(t.Commits, sha)
+	return true
+}
+
 // CanClose checks if the ticket can be closed based on test requirements.
 func (t *Ticket) CanClose() error {
 	if t.Tests != "" && !t.TestsPassed {
@@ -60,6 +126,20 @@ func (t *Ticket) CanClose() error {
 	return nil
 }
 
+// SchemaVersion is the version of the ticket file format. Marshal writes it
+// as schema in the frontmatter; it goes up when the format changes in a way
+// older versions of kt would misread.
+const SchemaVersion = 1
+
+// SchemaMismatch, if set, is called by Parse for each ticket whose file has
+// a schema other than SchemaVersion.
+var SchemaMismatch func(t *Ticket)
+
+// Strict makes Parse reject what it otherwise accepts: frontmatter keys
+// that are not ticket fields, statuses and types not in Statuses and
+// Types, and malformed timestamps and due dates.
+var Strict bool
+
 // ParseFile reads a ticket from a markdown file with YAML frontmatter.
 func ParseFile(path string) (*Ticket, error) {
 	data, err := os.ReadFile(path)
@@ -71,20 +151,75 @@ func ParseFile(path string) (*Ticket, error) {
 
 // Parse parses a ticket from raw markdown bytes.
 func Parse(data []byte) (*Ticket, error) {
+	return parse(data, Strict)
+}
+
+// parse is Parse, strict or not regardless of Strict.
+func parse(data []byte, strict bool) (*Ticket, error) {
 	frontmatter, body, err := splitFrontmatter(data)
 	if err != nil {
 		return nil, err
 	}
 
 	t := &Ticket{}
-	if err := yaml.Unmarshal(frontmatter, t); err != nil {
+	var opts []yaml.DecodeOption
+	if strict {
+		opts = append(opts, yaml.Strict())
+	}
+	if err := yaml.UnmarshalWithOptions(frontmatter, t, opts...); err != nil {
 		return nil, fmt.Errorf("parse frontmatter: %w", err)
 	}
+	if strict {
+		if err := t.Validate(); err != nil {
+			return nil, err
+		}
+	}
 
 	parseBody(t, body)
+	if t.Schema != SchemaVersion && SchemaMismatch != nil {
+		SchemaMismatch(t)
+	}
 	return t, nil
 }
 
+// Validate reports frontmatter values Strict parsing rejects: a schema
+// newer than SchemaVersion, a status or type outside Statuses and Types,
+// and timestamps that are not RFC 3339 (or, for due, not in DueLayout).
+func (t *Ticket) Validate() error {
+	return errors.Join(t.validate()...)
+}
+
+func (t *Ticket) validate() []error {
+	var errs []error
+	if t.Schema > SchemaVersion {
+		errs = append(errs, fmt.Errorf("schema %d is newer than this kt supports (%d); upgrade kt", t.Schema, SchemaVersion))
+	}
+	if !slices.Contains(Statuses, t.Status) {
+		errs = append(errs, fmt.Errorf("invalid status %q (expected one of %v)", t.Status, Statuses))
+	}
+	if !slices.Contains(Types, t.Type) {
+		errs = append(errs, fmt.Errorf("invalid type %q (expected one of %v)", t.Type, Types))
+	}
+	stamps := map[string]string{"created": t.Created, "closed": t.Closed}
+	for i, e := range t.History {
+		stamps[fmt.Sprintf("history[%d].at", i)] = e.At
+	}
+	for _, field := range slices.Sorted(maps.Keys(stamps)) {
+		if v := stamps[field]; (v != "" || field == "created") && !validTime(time.RFC3339, v) {
+			errs = append(errs, fmt.Errorf("invalid %s %q (expected RFC 3339, like 2026-01-02T15:04:05Z)", field, v))
+		}
+	}
+	if t.Due != "" && !validTime(DueLayout, t.Due) {
+		errs = append(errs, fmt.Errorf("invalid due %q (expected YYYY-MM-DD)", t.Due))
+	}
+	return errs
+}
+
+func validTime(layout, v string) bool {
+	_, err := time.Parse(layout, v)
+	return err == nil
+}
+
 // WriteFile writes a ticket to a markdown file.
 func WriteFile(path string, t *Ticket) error {
 	data, err := Marshal(t)
@@ -94,6 +229,11 @@ func WriteFile(path string, t *Ticket) error {
 	return atomicWrite(path, data, 0644)
 }
 
+// WriteRaw atomically writes raw ticket file contents to path.
+func WriteRaw(path string, data []byte) error {
+	return atomicWrite(path, data, 0644)
+}
+
 func atomicWrite(path string, data []byte, perm os.FileMode) error {
 	dir := filepath.Dir(path)
 	tmp, err := os.CreateTemp(dir, ".kt-*.tmp")
@@ -128,7 +268,9 @@ func Marshal(t *Ticket) ([]byte, error) {
 
 	// Write frontmatter
 	buf.WriteString("---\n")
-	fm, err := yaml.Marshal(t)
+	versioned := *t
+	versioned.Schema = SchemaVersion
+	fm, err := yaml.Marshal(&versioned)
 	if err != nil {
 		return nil, fmt.Errorf("marshal frontmatter: %w", err)
 	}
@@ -232,8 +374,19 @@ func parseBody(t *Ticket, body []byte) {
 		sectionContent.Reset()
 	}
 
+	fenced := false
 	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+		}
+
+		// Headings in code blocks are content
+		if fenced {
+			sectionContent.WriteString(line)
+			sectionContent.WriteString("\n")
+			continue
+		}
 
 		// Check for section headers
 		if strings.HasPrefix(trimmed, "# ") && currentSection == "" {
@@ -3,6 +3,7 @@ package ticket
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -88,6 +89,17 @@ tests_passed: false
 	assert.Empty(t, ticket.Description)
 }
 
+func TestParseFencedHeadings(t *testing.T) {
+	input := "---\nid: kt-1234\nstatus: open\ntype: task\n---\n# Fenced\n\n## Design\n\n```md\n## Not a section\n# Nor a title\n```\n\n## Tests\n\n- TestFenced\n"
+
+	ticket, err := Parse([]byte(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Fenced", ticket.Title)
+	assert.Equal(t, "```md\n## Not a section\n# Nor a title\n```", ticket.Design)
+	assert.Equal(t, "- TestFenced", ticket.Tests)
+}
+
 func TestMarshalRoundtrip(t *testing.T) {
 	original := &Ticket{
 		ID:                 "kt-test",
@@ -221,3 +233,97 @@ id: [invalid
 		assert.Error(t, err)
 	})
 }
+
+func TestParseStrict(t *testing.T) {
+	defer func() { Strict = false }()
+	valid := "---\nid: kt-1\nstatus: open\ncreated: 2026-01-09T10:00:00Z\ntype: task\npriority: 2\ntests_passed: false\n---\n# Title\n"
+	bad := map[string]string{
+		"unknown key":   strings.Replace(valid, "priority: 2", "priority: 2\nowner: ann", 1),
+		"status":        strings.Replace(valid, "status: open", "status: opne", 1),
+		"type":          strings.Replace(valid, "type: task", "type: story", 1),
+		"created":       strings.Replace(valid, "2026-01-09T10:00:00Z", "yesterday", 1),
+		"due":           strings.Replace(valid, "priority: 2", "priority: 2\ndue: 03/01/2026", 1),
+		"history[0].at": strings.Replace(valid, "priority: 2", "priority: 2\nhistory:\n  - at: noon\n    session: s1", 1),
+	}
+	for name, input := range bad {
+		_, err := Parse([]byte(input))
+		assert.NoError(t, err, "lenient by default: %s", name)
+	}
+
+	Strict = true
+	_, err := Parse([]byte(valid))
+	require.NoError(t, err)
+	for name, input := range bad {
+		_, err := Parse([]byte(input))
+		assert.Error(t, err, name)
+		if name != "unknown key" {
+			assert.ErrorContains(t, err, name)
+		}
+	}
+}
+
+func TestSetStatusClosedTimestamp(t *testing.T) {
+	tk := &Ticket{ID: "kt-1", Status: StatusOpen}
+
+	tk.SetStatus(StatusClosed)
+	assert.Equal(t, StatusClosed, tk.Status)
+	assert.NotEmpty(t, tk.Closed)
+
+	// Closing again keeps the original timestamp
+	tk.Closed = "2026-01-01T00:00:00Z"
+	tk.SetStatus(StatusClosed)
+	assert.Equal(t, "2026-01-01T00:00:00Z", tk.Closed)
+
+	tk.SetStatus(StatusOpen)
+	assert.Empty(t, tk.Closed)
+}
+
+func TestTouchClaims(t *testing.T) {
+	tk := &Ticket{ID: "kt-1", Status: StatusOpen}
+	tk.Touch("agent-1", "create kt-1")
+	assert.Empty(t, tk.ClaimedBy, "open tickets are not claimed")
+
+	tk.SetStatus(StatusInProgress)
+	tk.Touch("agent-1", "start kt-1")
+	tk.Touch("agent-2", "add-note kt-1")
+	assert.Equal(t, "agent-1", tk.ClaimedBy, "first session to work on it keeps the claim")
+	require.Len(t, tk.History, 3)
+	assert.Equal(t, Event{At: tk.History[2].At, Session: "agent-2", Op: "add-note kt-1"}, tk.History[2])
+
+	tk.SetStatus(StatusClosed)
+	assert.Empty(t, tk.ClaimedBy, "leaving in_progress releases the claim")
+
+	for range MaxHistory {
+		tk.Touch("agent-1", "edit kt-1")
+	}
+	assert.Len(t, tk.History, MaxHistory)
+	assert.Equal(t, "edit kt-1", tk.History[0].Op, "oldest events are dropped")
+}
+
+func TestSchemaVersion(t *testing.T) {
+	defer func() { Strict, SchemaMismatch = false, nil }()
+	tk := &Ticket{ID: "kt-1", Status: StatusOpen, Created: "2026-01-09T10:00:00Z", Type: TypeTask, Title: "T"}
+	data, err := Marshal(tk)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "---\nschema: 1\nid: kt-1\n"), string(data))
+	assert.Zero(t, tk.Schema, "Marshal leaves the ticket alone")
+
+	var mismatched []int
+	SchemaMismatch = func(t *Ticket) { mismatched = append(mismatched, t.Schema) }
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, parsed.Schema)
+	legacy := strings.Replace(string(data), "schema: 1\n", "", 1)
+	_, err = Parse([]byte(legacy))
+	require.NoError(t, err)
+	newer := strings.Replace(string(data), "schema: 1", "schema: 2", 1)
+	_, err = Parse([]byte(newer))
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, mismatched)
+
+	Strict = true
+	_, err = Parse([]byte(newer))
+	assert.ErrorContains(t, err, "schema 2 is newer")
+	_, err = Parse([]byte(legacy))
+	assert.NoError(t, err, "older files are still read")
+}